@@ -0,0 +1,202 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"sort"
+	"time"
+)
+
+// BenchmarkConfig is configuration for BenchmarkDevice
+type BenchmarkConfig struct {
+	UnitID uint8
+	// FunctionCode is function code used to read registers. Either packet.FunctionReadHoldingRegisters (used when left
+	// empty) or packet.FunctionReadInputRegisters.
+	FunctionCode uint8
+	// Address is address of first register to read. Registers starting from that address should be readable.
+	Address uint16
+	IsRTU   bool
+
+	// Requests is amount of requests sent for latency measurement and for each step of rate measurement. Defaults to 20.
+	Requests int
+	// Timeout is timeout for single request. Defaults to 1 second.
+	Timeout time.Duration
+	// StartRate is request rate (requests per second) rate measurement starts with. Rate is doubled on each step until
+	// requests start to fail or MaxRate is reached. Defaults to 10.
+	StartRate float64
+	// MaxRate is maximum request rate (requests per second) that is tried. Defaults to 1000.
+	MaxRate float64
+}
+
+// BenchmarkResult is result of BenchmarkDevice
+type BenchmarkResult struct {
+	// Requests is amount of requests sent for latency measurement
+	Requests int
+	// Failures is amount of failed requests during latency measurement
+	Failures int
+
+	LatencyMin time.Duration
+	LatencyAvg time.Duration
+	LatencyP50 time.Duration
+	LatencyP90 time.Duration
+	LatencyP99 time.Duration
+	LatencyMax time.Duration
+
+	// MaxRequestRate is highest tried request rate (requests per second) device sustained without failures. 0 when
+	// device failed already at StartRate.
+	MaxRequestRate float64
+	// SuggestedRequestInterval is minimal interval between requests based on MaxRequestRate
+	SuggestedRequestInterval time.Duration
+
+	// MaxQuantity is largest register quantity in single request that device responded successfully. This is suggested
+	// maximum quantity per request when splitting fields into requests for this device.
+	MaxQuantity uint16
+}
+
+// BenchmarkDevice probes device with read register requests and measures request latency distribution, maximum
+// request rate device can sustain before requests start to fail (time out) and maximum register quantity per request
+// device responds to. Results can be used to tune polling interval and request splitting for that device.
+//
+// Note: rate measurement is limited by latency as requests are sent sequentially.
+func BenchmarkDevice(ctx context.Context, client Doer, conf BenchmarkConfig) (BenchmarkResult, error) {
+	if conf.FunctionCode == 0 {
+		conf.FunctionCode = packet.FunctionReadHoldingRegisters
+	}
+	if conf.FunctionCode != packet.FunctionReadHoldingRegisters && conf.FunctionCode != packet.FunctionReadInputRegisters {
+		return BenchmarkResult{}, errors.New("benchmark function code must be FC3 or FC4")
+	}
+	if conf.Requests <= 0 {
+		conf.Requests = 20
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = 1 * time.Second
+	}
+	if conf.StartRate <= 0 {
+		conf.StartRate = 10
+	}
+	if conf.MaxRate <= 0 {
+		conf.MaxRate = 1000
+	}
+
+	result := BenchmarkResult{Requests: conf.Requests}
+	latencies := make([]time.Duration, 0, conf.Requests)
+	for i := 0; i < conf.Requests; i++ {
+		took, err := benchmarkRequest(ctx, client, conf, 1)
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err != nil {
+			result.Failures++
+			continue
+		}
+		latencies = append(latencies, took)
+	}
+	if len(latencies) == 0 {
+		return result, errors.New("benchmark failed as all latency measurement requests failed")
+	}
+	result.setLatencies(latencies)
+
+	maxQuantity, err := benchmarkMaxQuantity(ctx, client, conf)
+	if err != nil {
+		return result, err
+	}
+	result.MaxQuantity = maxQuantity
+
+	for rate := conf.StartRate; rate <= conf.MaxRate; rate *= 2 {
+		ok, err := benchmarkRate(ctx, client, conf, rate)
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			break
+		}
+		result.MaxRequestRate = rate
+	}
+	if result.MaxRequestRate > 0 {
+		result.SuggestedRequestInterval = time.Duration(float64(time.Second) / result.MaxRequestRate)
+	}
+	return result, nil
+}
+
+func (r *BenchmarkResult) setLatencies(latencies []time.Duration) {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	percentile := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1))]
+	}
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	r.LatencyMin = latencies[0]
+	r.LatencyAvg = total / time.Duration(len(latencies))
+	r.LatencyP50 = percentile(0.5)
+	r.LatencyP90 = percentile(0.9)
+	r.LatencyP99 = percentile(0.99)
+	r.LatencyMax = latencies[len(latencies)-1]
+}
+
+// benchmarkMaxQuantity finds largest quantity device responds to with binary search
+func benchmarkMaxQuantity(ctx context.Context, client Doer, conf BenchmarkConfig) (uint16, error) {
+	low, high := uint16(0), packet.MaxRegistersInReadResponse
+	if rest := uint32(65536) - uint32(conf.Address); rest < uint32(high) {
+		high = uint16(rest)
+	}
+	for low < high {
+		quantity := low + (high-low+1)/2
+		_, err := benchmarkRequest(ctx, client, conf, quantity)
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err != nil {
+			high = quantity - 1
+		} else {
+			low = quantity
+		}
+	}
+	return low, nil
+}
+
+// benchmarkRate sends requests with given rate and reports if all of them succeeded and device kept up with the rate
+func benchmarkRate(ctx context.Context, client Doer, conf BenchmarkConfig, rate float64) (bool, error) {
+	interval := time.Duration(float64(time.Second) / rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for i := 0; i < conf.Requests; i++ {
+		if _, err := benchmarkRequest(ctx, client, conf, 1); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return false, ctxErr
+			}
+			return false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	// when requests take longer than interval ticks are dropped and actual rate stays below requested rate
+	actualRate := float64(conf.Requests) / time.Since(start).Seconds()
+	return actualRate >= rate*0.9, nil
+}
+
+func benchmarkRequest(ctx context.Context, client Doer, conf BenchmarkConfig, quantity uint16) (time.Duration, error) {
+	req, err := readRequest{
+		isRTU:        conf.IsRTU,
+		unitID:       conf.UnitID,
+		functionCode: conf.FunctionCode,
+		startAddress: conf.Address,
+		quantity:     quantity,
+	}.newRequest()
+	if err != nil {
+		return 0, err
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, conf.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.Do(reqCtx, req)
+	return time.Since(start), err
+}
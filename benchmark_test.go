@@ -0,0 +1,47 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBenchmarkDevice(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		if req.(*packet.ReadHoldingRegistersRequestTCP).Quantity > 50 {
+			return nil, &ClientError{Err: errors.New("total read timeout exceeded")}
+		}
+		return device.Do(ctx, req)
+	})
+
+	result, err := BenchmarkDevice(context.Background(), client, BenchmarkConfig{
+		UnitID:    1,
+		Requests:  5,
+		StartRate: 100,
+		MaxRate:   400,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.Requests)
+	assert.Equal(t, 0, result.Failures)
+	assert.Equal(t, uint16(50), result.MaxQuantity)
+	assert.True(t, result.MaxRequestRate >= 100) // exact rate depends on how loaded machine running tests is
+	assert.Equal(t, time.Duration(float64(time.Second)/result.MaxRequestRate), result.SuggestedRequestInterval)
+	assert.True(t, result.LatencyMin <= result.LatencyP50)
+	assert.True(t, result.LatencyP50 <= result.LatencyMax)
+}
+
+func TestBenchmarkDevice_allRequestsFail(t *testing.T) {
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		return nil, errors.New("no response")
+	})
+
+	result, err := BenchmarkDevice(context.Background(), client, BenchmarkConfig{Requests: 2})
+
+	assert.EqualError(t, err, "benchmark failed as all latency measurement requests failed")
+	assert.Equal(t, 2, result.Failures)
+}
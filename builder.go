@@ -4,6 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/aldas/go-modbus-client/packet"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -37,12 +41,48 @@ const (
 	// FieldTypeCoil represents single discrete/coil value (used by FC1/FC2).
 	FieldTypeCoil FieldType = 14
 
-	maxFieldTypeValue = uint8(14)
+	// FieldTypeFloat16 represents single register (16 bit) as IEEE 754 half-precision float. Extracted value is float32.
+	// Use `Field.ByteOrder` to indicate byte order of register data.
+	FieldTypeFloat16 FieldType = 15
+
+	// FieldTypeUint48 represents 3 registers (48 bit) as uint64 value. Use `Field.ByteOrder` to indicate byte and word order of register data.
+	FieldTypeUint48 FieldType = 16
+	// FieldTypeInt48 represents 3 registers (48 bit) as int64 value. Use `Field.ByteOrder` to indicate byte and word order of register data.
+	FieldTypeInt48 FieldType = 17
+
+	// FieldTypeBCD represents binary-coded decimal value (every 4 bits is one decimal digit) as uint32 value. Use
+	// `Field.Length` to set digit count (1-8, defaults to 4). Up to 4 digits take 1 register and up to 8 digits take 2
+	// registers. Use `Field.ByteOrder` to indicate word order of 2 register values.
+	FieldTypeBCD FieldType = 18
+
+	// FieldTypeUnixTime32 represents 2 registers (32 bit) as unsigned seconds since epoch. Extracted value is time.Time.
+	// Use `Field.TimeEpoch` and `Field.TimeZone` to configure epoch and time zone of the device clock and
+	// `Field.ByteOrder` to indicate byte and word order of register data.
+	FieldTypeUnixTime32 FieldType = 19
+	// FieldTypeUnixTime64 represents 4 registers (64 bit) as signed seconds since epoch. Extracted value is time.Time.
+	// Use `Field.TimeEpoch` and `Field.TimeZone` to configure epoch and time zone of the device clock and
+	// `Field.ByteOrder` to indicate byte and word order of register data.
+	FieldTypeUnixTime64 FieldType = 20
+
+	maxFieldTypeValue = uint8(20)
+)
+
+const (
+	// StringPaddingNull pads string shorter than field length with null (0x0) bytes when value is marshalled.
+	StringPaddingNull StringPadding = 0
+	// StringPaddingSpace pads string shorter than field length with space (0x20) bytes when value is marshalled.
+	// Trailing spaces are trimmed from extracted value.
+	StringPaddingSpace StringPadding = 1
+
+	maxStringPaddingValue = uint8(1)
 )
 
 // FieldType is enum type for data types that Field can represent
 type FieldType uint8
 
+// StringPadding is enum type for characters that are used to pad string field value to field length
+type StringPadding uint8
+
 // Fields is slice of Field instances
 type Fields []Field
 
@@ -56,20 +96,82 @@ type Field struct {
 	// Address of the register (first register of that data type) or discrete/coil address in modbus. Addresses are 0-based.
 	Address uint16    `json:"address" mapstructure:"address"`
 	Type    FieldType `json:"type" mapstructure:"type"`
+	// FunctionCode is read function code the field is read with. Coil fields can be read with Read Coils (FC1) or Read
+	// Discrete Inputs (FC2) and other fields with Read Holding Registers (FC3) or Read Input Registers (FC4). Zero
+	// means FC1 for coil fields and FC3 for other fields.
+	FunctionCode uint8 `json:"function_code" mapstructure:"function_code"`
 
 	// Only relevant to register function fields
 	Bit          uint8            `json:"bit" mapstructure:"bit"`
 	FromHighByte bool             `json:"from_high_byte" mapstructure:"from_high_byte"`
 	Length       uint8            `json:"Length" mapstructure:"Length"`
 	ByteOrder    packet.ByteOrder `json:"byte_order" mapstructure:"byte_order"`
+
+	// StringPadding determines how string value shorter than Length is padded when marshalled for writing
+	StringPadding StringPadding `json:"string_padding" mapstructure:"string_padding"`
+	// StringNullTerminated ensures that marshalled string value is always followed by null (0x0) byte. This means that
+	// string value can be at most Length-1 bytes long.
+	StringNullTerminated bool `json:"string_null_terminated" mapstructure:"string_null_terminated"`
+
+	// TimeEpoch is epoch of time field types as Unix seconds (i.e. 946684800 for devices counting seconds since
+	// 2000-01-01). Zero means Unix epoch 1970-01-01 UTC.
+	TimeEpoch int64 `json:"time_epoch" mapstructure:"time_epoch"`
+	// TimeZone is IANA time zone name (i.e. `Europe/Tallinn`) of time field types for devices whose clock counts local
+	// wall clock time instead of UTC. Extracted time is in that time zone. Empty means UTC.
+	TimeZone string `json:"time_zone" mapstructure:"time_zone"`
+
+	// Scale is multiplier applied to extracted numeric value (`value*Scale+Offset`) before unit conversion. Scaled
+	// values are float64. Zero means no scaling.
+	Scale float64 `json:"scale" mapstructure:"scale"`
+	// Offset is added to extracted numeric value after Scale is applied. Scaled values are float64.
+	Offset float64 `json:"offset" mapstructure:"offset"`
+
+	// Enum maps raw integer (bit and coil values as 0 and 1) value to symbolic string value (i.e. 0 -> `OFF`,
+	// 1 -> `ON`, 2 -> `FAULT`). Extracting raw value without mapping results an error. Can not be combined with
+	// scaling or unit conversion. NB: being a map, Enum makes Field not comparable with `==`.
+	Enum map[int64]string `json:"enum" mapstructure:"enum"`
+
+	// Unit is engineering unit of the value stored in device (i.e. `W`, `°C`)
+	Unit string `json:"unit" mapstructure:"unit"`
+	// TargetUnit is engineering unit extracted value is converted to (i.e. `kW`, `°F`). Converted values are float64.
+	// See RegisterUnitConversion to add conversions not included in built-in conversion table.
+	TargetUnit string `json:"target_unit" mapstructure:"target_unit"`
+	// Processor is name of value processor applied to extracted value after unit conversion (i.e. sensor calibration
+	// curve). See RegisterValueProcessor.
+	Processor string `json:"processor" mapstructure:"processor"`
+
+	// ReadTimeout is total read timeout for requests containing this field. Fields with different read timeouts are
+	// never batched into the same request so slow registers (i.e. demand calculations) can be isolated into dedicated
+	// requests with extended timeout without inflating timeout of other requests. Zero means client default timeout.
+	// ParseFieldsJSON accepts value as duration string (i.e. "5s") or integer nanoseconds.
+	ReadTimeout time.Duration `json:"read_timeout" mapstructure:"read_timeout"`
+}
+
+// readFunctionCode returns function code the field is read with
+func (f *Field) readFunctionCode() uint8 {
+	switch {
+	case f.FunctionCode != 0:
+		return f.FunctionCode
+	case f.Type == FieldTypeCoil:
+		return packet.FunctionReadCoils
+	default:
+		return packet.FunctionReadHoldingRegisters
+	}
 }
 
 // registerSize returns how many register/words does this field would take in modbus response
 func (f *Field) registerSize() uint16 {
 	switch f.Type {
-	case FieldTypeFloat64, FieldTypeInt64, FieldTypeUint64:
+	case FieldTypeFloat64, FieldTypeInt64, FieldTypeUint64, FieldTypeUnixTime64:
 		return 4
-	case FieldTypeFloat32, FieldTypeInt32, FieldTypeUint32:
+	case FieldTypeInt48, FieldTypeUint48:
+		return 3
+	case FieldTypeBCD:
+		if f.bcdDigits() > 4 {
+			return 2
+		}
+		return 1
+	case FieldTypeFloat32, FieldTypeInt32, FieldTypeUint32, FieldTypeUnixTime32:
 		return 2
 	case FieldTypeString:
 		if f.Length%2 == 0 { // even
@@ -95,12 +197,142 @@ func (f *Field) Validate() error {
 	if f.Bit > 15 {
 		return errors.New("field bit value must be in range (0-15)")
 	}
+	switch f.FunctionCode {
+	case 0:
+	case packet.FunctionReadCoils, packet.FunctionReadDiscreteInputs:
+		if f.Type != FieldTypeCoil {
+			return errors.New("field with function code 1 or 2 must have type coil")
+		}
+	case packet.FunctionReadHoldingRegisters, packet.FunctionReadInputRegisters:
+		if f.Type == FieldTypeCoil {
+			return errors.New("field with type coil must have function code 1 or 2")
+		}
+	default:
+		return errors.New("field function code must be read function code (1-4)")
+	}
 	if f.Type == FieldTypeString && f.Length == 0 {
 		return errors.New("field with type string must have length set")
 	}
+	if f.Type == FieldTypeBCD && f.Length > maxBCDDigits {
+		return errors.New("field with type BCD must have length (digit count) in range (0-8)")
+	}
+	if _, err := loadTimeLocation(f.TimeZone); err != nil {
+		return fmt.Errorf("field time zone is invalid: %w", err)
+	}
+	if uint8(f.StringPadding) > maxStringPaddingValue {
+		return errors.New("field string padding has invalid value")
+	}
+	if f.TargetUnit != "" {
+		if _, err := ConvertUnit(0, f.Unit, f.TargetUnit); err != nil {
+			return fmt.Errorf("field unit conversion is invalid: %w", err)
+		}
+	}
+	if len(f.Enum) > 0 {
+		switch f.Type {
+		case FieldTypeFloat16, FieldTypeFloat32, FieldTypeFloat64, FieldTypeString, FieldTypeUnixTime32, FieldTypeUnixTime64:
+			return errors.New("field enum mapping is supported only for integer, bit and coil field types")
+		}
+		if f.Scale != 0 || f.Offset != 0 || f.TargetUnit != "" {
+			return errors.New("field enum mapping can not be combined with scaling or unit conversion")
+		}
+	}
+	if f.ReadTimeout < 0 {
+		return errors.New("field read timeout can not be negative")
+	}
+	if f.Processor != "" {
+		if _, ok := LookupValueProcessor(f.Processor); !ok {
+			return fmt.Errorf("field value processor is invalid: unknown value processor: %v", f.Processor)
+		}
+	}
 	return nil
 }
 
+// mapEnum maps extracted raw value to symbolic value from Enum
+func (f *Field) mapEnum(value interface{}) (interface{}, error) {
+	if len(f.Enum) == 0 {
+		return value, nil
+	}
+	var raw int64
+	switch v := value.(type) {
+	case bool:
+		if v {
+			raw = 1
+		}
+	case uint8:
+		raw = int64(v)
+	case int8:
+		raw = int64(v)
+	case uint16:
+		raw = int64(v)
+	case int16:
+		raw = int64(v)
+	case uint32:
+		raw = int64(v)
+	case int32:
+		raw = int64(v)
+	case uint64:
+		raw = int64(v)
+	case int64:
+		raw = v
+	default:
+		return nil, errors.New("enum mapping is not supported for non-integer field type")
+	}
+	mapped, ok := f.Enum[raw]
+	if !ok {
+		return nil, fmt.Errorf("enum mapping does not exist for value: %v", raw)
+	}
+	return mapped, nil
+}
+
+// scale applies Scale and Offset to extracted value
+func (f *Field) scale(value interface{}) (interface{}, error) {
+	if f.Scale == 0 && f.Offset == 0 {
+		return value, nil
+	}
+	v, ok := toFloat64(value)
+	if !ok {
+		return nil, errors.New("scaling is not supported for non-numeric field type")
+	}
+	if f.Scale != 0 {
+		v *= f.Scale
+	}
+	return v + f.Offset, nil
+}
+
+// convertUnit converts extracted value from field Unit to TargetUnit
+func (f *Field) convertUnit(value interface{}) (interface{}, error) {
+	if f.TargetUnit == "" || f.TargetUnit == f.Unit {
+		return value, nil
+	}
+	v, ok := toFloat64(value)
+	if !ok {
+		return nil, errors.New("unit conversion is not supported for non-numeric field type")
+	}
+	return ConvertUnit(v, f.Unit, f.TargetUnit)
+}
+
+const (
+	defaultBCDDigits = 4
+	maxBCDDigits     = 8
+)
+
+// bcdDigits returns digit count of BCD field
+func (f *Field) bcdDigits() uint8 {
+	if f.Length == 0 {
+		return defaultBCDDigits
+	}
+	return f.Length
+}
+
+// maxBCDValue returns largest value that fits into BCD field digit count
+func (f *Field) maxBCDValue() uint64 {
+	max := uint64(1)
+	for i := uint8(0); i < f.bcdDigits(); i++ {
+		max *= 10
+	}
+	return max - 1
+}
+
 // ExtractFrom extracts field value from given registers data
 func (f *Field) ExtractFrom(registers *packet.Registers) (interface{}, error) {
 	switch f.Type {
@@ -124,12 +356,55 @@ func (f *Field) ExtractFrom(registers *packet.Registers) (interface{}, error) {
 		return registers.Uint64WithByteOrder(f.Address, f.ByteOrder)
 	case FieldTypeInt64:
 		return registers.Int64WithByteOrder(f.Address, f.ByteOrder)
+	case FieldTypeUint48:
+		return registers.Uint48WithByteOrder(f.Address, f.ByteOrder)
+	case FieldTypeInt48:
+		return registers.Int48WithByteOrder(f.Address, f.ByteOrder)
+	case FieldTypeFloat16:
+		return registers.Float16WithByteOrder(f.Address, f.ByteOrder)
+	case FieldTypeUnixTime32:
+		v, err := registers.Uint32WithByteOrder(f.Address, f.ByteOrder)
+		if err != nil {
+			return nil, err
+		}
+		return f.timeFromSeconds(int64(v))
+	case FieldTypeUnixTime64:
+		v, err := registers.Int64WithByteOrder(f.Address, f.ByteOrder)
+		if err != nil {
+			return nil, err
+		}
+		return f.timeFromSeconds(v)
+	case FieldTypeBCD:
+		var v uint32
+		if f.registerSize() == 1 {
+			v16, err := registers.BCD16(f.Address)
+			if err != nil {
+				return nil, err
+			}
+			v = uint32(v16)
+		} else {
+			var err error
+			if v, err = registers.BCD32WithByteOrder(f.Address, f.ByteOrder); err != nil {
+				return nil, err
+			}
+		}
+		if uint64(v) > f.maxBCDValue() {
+			return nil, fmt.Errorf("BCD value has more digits than field length: %v", v)
+		}
+		return v, nil
 	case FieldTypeFloat32:
 		return registers.Float32WithByteOrder(f.Address, f.ByteOrder)
 	case FieldTypeFloat64:
 		return registers.Float64WithByteOrder(f.Address, f.ByteOrder)
 	case FieldTypeString:
-		return registers.StringWithByteOrder(f.Address, f.Length, f.ByteOrder)
+		v, err := registers.StringWithByteOrder(f.Address, f.Length, f.ByteOrder)
+		if err != nil {
+			return nil, err
+		}
+		if f.StringPadding == StringPaddingSpace {
+			return strings.TrimRight(v, " "), nil
+		}
+		return v, nil
 	}
 	return nil, errors.New("extraction failure due unknown field type")
 }
@@ -157,18 +432,102 @@ func (f *BField) ByteOrder(byteOrder packet.ByteOrder) *BField {
 	return f
 }
 
+// StringPadding sets how string value shorter than field length is padded when marshalled for writing
+func (f *BField) StringPadding(padding StringPadding) *BField {
+	f.Field.StringPadding = padding
+	return f
+}
+
+// StringNullTerminated sets marshalled string value to be always followed by null (0x0) byte
+func (f *BField) StringNullTerminated() *BField {
+	f.Field.StringNullTerminated = true
+	return f
+}
+
 // Name sets name/identifier for Field to be used to uniquely identify value when extracting values from response
 func (f *BField) Name(name string) *BField {
 	f.Field.Name = name
 	return f
 }
 
+// Scale sets multiplier applied to extracted numeric value
+func (f *BField) Scale(scale float64) *BField {
+	f.Field.Scale = scale
+	return f
+}
+
+// Offset sets value added to extracted numeric value after scaling
+func (f *BField) Offset(offset float64) *BField {
+	f.Field.Offset = offset
+	return f
+}
+
+// TimeEpoch sets epoch of time field
+func (f *BField) TimeEpoch(epoch time.Time) *BField {
+	f.Field.TimeEpoch = epoch.Unix()
+	return f
+}
+
+// TimeZone sets IANA time zone name of time field device clock
+func (f *BField) TimeZone(name string) *BField {
+	f.Field.TimeZone = name
+	return f
+}
+
+// Enum sets mapping from raw integer value to symbolic string value
+func (f *BField) Enum(values map[int64]string) *BField {
+	f.Field.Enum = values
+	return f
+}
+
+// Unit sets engineering unit of the value stored in device
+func (f *BField) Unit(unit string) *BField {
+	f.Field.Unit = unit
+	return f
+}
+
+// TargetUnit sets engineering unit extracted value is converted to
+func (f *BField) TargetUnit(unit string) *BField {
+	f.Field.TargetUnit = unit
+	return f
+}
+
+// Processor sets name of value processor applied to extracted value
+func (f *BField) Processor(name string) *BField {
+	f.Field.Processor = name
+	return f
+}
+
+// FunctionCode sets read function code (1-4) the field is read with
+func (f *BField) FunctionCode(functionCode uint8) *BField {
+	f.Field.FunctionCode = functionCode
+	return f
+}
+
+// ReadTimeout sets total read timeout for requests containing the field. Field is placed into dedicated request with
+// other fields having same read timeout.
+func (f *BField) ReadTimeout(timeout time.Duration) *BField {
+	f.Field.ReadTimeout = timeout
+	return f
+}
+
 // Builder helps to group extractable field values of different types into modbus requests with minimal amount of separate requests produced
+//
+// Builder is safe for concurrent use. Multiple goroutines can add fields at the same time.
 type Builder struct {
+	mu     sync.Mutex
 	fields Fields
 
 	serverAddress string // [network://]host:port
 	unitID        uint8
+
+	// strict validates fields when they are added and panics on invalid field
+	strict bool
+	// transactionIDGenerator is used to set transaction IDs of created TCP requests. When nil transaction IDs are
+	// random (packet.RandomTransactionID).
+	transactionIDGenerator packet.TransactionIDGenerator
+	// allowedGap is maximum amount of unused registers/coils between fields read with the same request
+	allowedGap int
 }
 
 // NewRequestBuilder creates new instance of Builder with given defaults.
@@ -181,16 +540,102 @@ func NewRequestBuilder(serverAddress string, unitID uint8) *Builder {
 	}
 }
 
+// NewStrictRequestBuilder creates new instance of Builder with given defaults that validates fields when they are
+// added with Add/AddAll and panics when field is invalid. This helps to catch misconfigured fields near the code that
+// constructed them instead of when requests are created.
+func NewStrictRequestBuilder(serverAddress string, unitID uint8) *Builder {
+	b := NewRequestBuilder(serverAddress, unitID)
+	b.strict = true
+	return b
+}
+
+// TransactionIDGenerator sets generator for transaction IDs of TCP requests created by Builder. This is useful for
+// getting deterministic transaction IDs in tests.
+func (b *Builder) TransactionIDGenerator(generator packet.TransactionIDGenerator) *Builder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.transactionIDGenerator = generator
+	return b
+}
+
+// AllowedGap sets maximum amount of unused registers (or coils) between fields that are read with the same request.
+// Fields further apart are read with separate requests. This trades request count for read size and helps with devices
+// that misbehave when unmapped registers inside the gap are read. 0 (default) means gaps are limited only by maximum
+// request quantity, AllowedGapNone means that only contiguous fields are read with the same request.
+func (b *Builder) AllowedGap(gap int) *Builder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.allowedGap = gap
+	return b
+}
+
 // AddAll adds field into Builder. AddAll does not set ServerAddress and UnitID values.
+// Strict builder panics when any of the fields is invalid.
 func (b *Builder) AddAll(fields Fields) *Builder {
+	if b.strict {
+		if err := validateFields(fields); err != nil {
+			panic(err)
+		}
+	}
+	b.mu.Lock()
 	b.fields = append(b.fields, fields...)
+	b.mu.Unlock()
 	return b
 }
 
-// Add adds field into Builder
+// AddAllWithDefaults adds fields into Builder setting ServerAddress and UnitID of fields that have them empty (0 for
+// UnitID) to Builder defaults. This is useful for fields loaded from configuration or device presets that describe
+// register map without connection details. Strict builder panics when any of the fields is invalid.
+func (b *Builder) AddAllWithDefaults(fields Fields) *Builder {
+	result := make(Fields, len(fields))
+	for i, f := range fields {
+		if f.ServerAddress == "" {
+			f.ServerAddress = b.serverAddress
+		}
+		if f.UnitID == 0 {
+			f.UnitID = b.unitID
+		}
+		result[i] = f
+	}
+	return b.AddAll(result)
+}
+
+// Add adds field into Builder. Strict builder panics when field is invalid.
 func (b *Builder) Add(field *BField) *Builder {
-	b.fields = append(b.fields, field.Field)
-	return b
+	return b.AddAll(Fields{field.Field})
+}
+
+// AddAllValidated validates and adds fields into Builder. Fields are added only when all of them are valid.
+// AddAllValidated does not set ServerAddress and UnitID values.
+func (b *Builder) AddAllValidated(fields Fields) error {
+	if err := validateFields(fields); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.fields = append(b.fields, fields...)
+	b.mu.Unlock()
+	return nil
+}
+
+// AddValidated validates and adds field into Builder
+func (b *Builder) AddValidated(field *BField) error {
+	return b.AddAllValidated(Fields{field.Field})
+}
+
+// copyFields returns copy of fields added to Builder so requests can be split while other goroutines add fields
+func (b *Builder) copyFields() Fields {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append(Fields(nil), b.fields...)
+}
+
+func validateFields(fields Fields) error {
+	for i, f := range fields {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("field validation failed. index: %v name: '%v' err: %w", i, f.Name, err)
+		}
+	}
+	return nil
 }
 
 // Bit add bit (0-15) field to Builder to be requested and extracted
@@ -340,6 +785,85 @@ func (b *Builder) Int64(registerAddress uint16) *BField {
 	}
 }
 
+// Uint48 add uint48 field to Builder to be requested and extracted
+func (b *Builder) Uint48(registerAddress uint16) *BField {
+	return &BField{
+		Field{
+			ServerAddress: b.serverAddress,
+			UnitID:        b.unitID,
+			Type:          FieldTypeUint48,
+
+			Address: registerAddress,
+		},
+	}
+}
+
+// Int48 add int48 field to Builder to be requested and extracted
+func (b *Builder) Int48(registerAddress uint16) *BField {
+	return &BField{
+		Field{
+			ServerAddress: b.serverAddress,
+			UnitID:        b.unitID,
+			Type:          FieldTypeInt48,
+
+			Address: registerAddress,
+		},
+	}
+}
+
+// BCD add binary-coded decimal field with given digit count (1-8) to Builder to be requested and extracted
+func (b *Builder) BCD(registerAddress uint16, digits uint8) *BField {
+	return &BField{
+		Field{
+			ServerAddress: b.serverAddress,
+			UnitID:        b.unitID,
+			Type:          FieldTypeBCD,
+			Length:        digits,
+
+			Address: registerAddress,
+		},
+	}
+}
+
+// UnixTime32 add 32 bit Unix time field to Builder to be requested and extracted
+func (b *Builder) UnixTime32(registerAddress uint16) *BField {
+	return &BField{
+		Field{
+			ServerAddress: b.serverAddress,
+			UnitID:        b.unitID,
+			Type:          FieldTypeUnixTime32,
+
+			Address: registerAddress,
+		},
+	}
+}
+
+// UnixTime64 add 64 bit Unix time field to Builder to be requested and extracted
+func (b *Builder) UnixTime64(registerAddress uint16) *BField {
+	return &BField{
+		Field{
+			ServerAddress: b.serverAddress,
+			UnitID:        b.unitID,
+			Type:          FieldTypeUnixTime64,
+
+			Address: registerAddress,
+		},
+	}
+}
+
+// Float16 add half-precision float field to Builder to be requested and extracted
+func (b *Builder) Float16(registerAddress uint16) *BField {
+	return &BField{
+		Field{
+			ServerAddress: b.serverAddress,
+			UnitID:        b.unitID,
+			Type:          FieldTypeFloat16,
+
+			Address: registerAddress,
+		},
+	}
+}
+
 // Float32 add float32 field to Builder to be requested and extracted
 func (b *Builder) Float32(registerAddress uint16) *BField {
 	return &BField{
@@ -391,8 +915,58 @@ type BuilderRequest struct {
 	// StartAddress is start register address for request
 	StartAddress uint16
 
+	// BatchID is sequence number (0-based) of the request among requests created by the same split
+	BatchID int
+
 	// Fields is slice of field use to construct the request and to be extracted from response
 	Fields Fields
+
+	// ReadTimeout is total read timeout for the request that fields require. Zero means client default timeout. Use
+	// ContextWithReadTimeout to pass timeout to the client.
+	ReadTimeout time.Duration
+
+	registersCache *registersCache
+}
+
+// registersCache holds Registers created from the last response so repeated extractions from same response do not
+// need to recreate and revalidate Registers
+type registersCache struct {
+	mu        sync.Mutex
+	response  packet.Response
+	registers *packet.Registers
+}
+
+// EnableRegistersCache enables caching of Registers created from the last response given to AsRegisters/ExtractFields.
+// Repeated extractions (for example with different field subsets) from the same response instance (same pointer)
+// reuse cached Registers instead of creating new instance each time.
+//
+// NB: cached Registers are shared between calls with the same response and must not be modified (i.e. with SetX
+// methods).
+func (r *BuilderRequest) EnableRegistersCache() {
+	r.registersCache = &registersCache{}
+}
+
+func (r BuilderRequest) asRegisters(response RegistersResponse) (*packet.Registers, error) {
+	if r.registersCache == nil {
+		return response.AsRegisters(r.StartAddress)
+	}
+	c := r.registersCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// only pointers are cached so comparing interfaces never compares non-comparable struct values
+	if c.response != nil && c.response == packet.Response(response) {
+		return c.registers, nil
+	}
+	regs, err := response.AsRegisters(r.StartAddress)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.ValueOf(response).Kind() == reflect.Pointer {
+		c.response = response
+		c.registers = regs
+	}
+	return regs, nil
 }
 
 // RegistersResponse is marker interface for responses returning register data
@@ -407,9 +981,47 @@ type CoilsResponse interface {
 	IsCoilSet(startAddress uint16, coilAddress uint16) (bool, error)
 }
 
-// AsRegisters returns response data as Register to more convenient access
+var (
+	_ RegistersResponse = (*packet.ReadHoldingRegistersResponseTCP)(nil)
+	_ RegistersResponse = (*packet.ReadHoldingRegistersResponseRTU)(nil)
+	_ RegistersResponse = (*packet.ReadInputRegistersResponseTCP)(nil)
+	_ RegistersResponse = (*packet.ReadInputRegistersResponseRTU)(nil)
+	_ RegistersResponse = (*packet.ReadWriteMultipleRegistersResponseTCP)(nil)
+	_ RegistersResponse = (*packet.ReadWriteMultipleRegistersResponseRTU)(nil)
+	_ RegistersResponse = (*packet.ReadHoldingRegistersResponseASCII)(nil)
+	_ RegistersResponse = (*packet.ReadInputRegistersResponseASCII)(nil)
+	_ RegistersResponse = (*packet.ReadWriteMultipleRegistersResponseASCII)(nil)
+
+	_ CoilsResponse = (*packet.ReadCoilsResponseTCP)(nil)
+	_ CoilsResponse = (*packet.ReadCoilsResponseRTU)(nil)
+	_ CoilsResponse = (*packet.ReadDiscreteInputsResponseTCP)(nil)
+	_ CoilsResponse = (*packet.ReadDiscreteInputsResponseRTU)(nil)
+	_ CoilsResponse = (*packet.ReadCoilsResponseASCII)(nil)
+	_ CoilsResponse = (*packet.ReadDiscreteInputsResponseASCII)(nil)
+)
+
+// AsRegisters returns response data as Register to more convenient access. Works for Read Holding Registers (FC3),
+// Read Input Registers (FC4) and Read / Write Multiple registers (FC23) responses.
 func (r BuilderRequest) AsRegisters(response RegistersResponse) (*packet.Registers, error) {
-	return response.AsRegisters(r.StartAddress)
+	return r.asRegisters(response)
+}
+
+// AsCoils returns response data as slice of coil states. First element is state of coil at request start address and
+// slice length is equal to requested quantity. Works for Read Coils (FC1) and Read Discrete Inputs (FC2) responses.
+func (r BuilderRequest) AsCoils(response CoilsResponse) ([]bool, error) {
+	req, ok := asReadRequest(r.Request)
+	if !ok || !req.isCoils() {
+		return nil, errors.New("request is not read coils or read discrete inputs request")
+	}
+	coils := make([]bool, req.quantity)
+	for i := range coils {
+		isSet, err := response.IsCoilSet(req.startAddress, req.startAddress+uint16(i))
+		if err != nil {
+			return nil, err
+		}
+		coils[i] = isSet
+	}
+	return coils, nil
 }
 
 // FieldValue is concrete value extracted from register data using field data type and byte order
@@ -417,26 +1029,110 @@ type FieldValue struct {
 	Field Field
 	Value interface{}
 	Error error
+	// Request describes request/response value was extracted from. Useful for debugging suspicious values without
+	// re-deriving how fields were split into requests.
+	Request RequestInfo
+}
+
+// RequestInfo describes wire request that field values were extracted from
+type RequestInfo struct {
+	// BatchID is sequence number (0-based) of the request among requests created by the same split
+	BatchID       int
+	ServerAddress string
+	UnitID        uint8
+	FunctionCode  uint8
+	// Protocol is protocol of the request (`tcp`, `rtu` or `ascii`)
+	Protocol     string
+	StartAddress uint16
+	Quantity     uint16
+}
+
+// Info returns RequestInfo describing the request
+func (r BuilderRequest) Info() RequestInfo {
+	info := RequestInfo{
+		BatchID:       r.BatchID,
+		ServerAddress: r.ServerAddress,
+		UnitID:        r.UnitID,
+		StartAddress:  r.StartAddress,
+	}
+	if r.Request != nil {
+		info.FunctionCode = r.Request.FunctionCode()
+	}
+	if req, ok := asReadRequest(r.Request); ok {
+		info.Quantity = req.quantity
+		switch {
+		case req.isASCII:
+			info.Protocol = "ascii"
+		case req.isRTU:
+			info.Protocol = "rtu"
+		default:
+			info.Protocol = "tcp"
+		}
+	}
+	return info
 }
 
 // ErrorFieldExtractHadError is returned when ExtractFields could not extract value from Field
 var ErrorFieldExtractHadError = errors.New("field extraction had an error. check FieldValue.Error for details")
 
+// FieldExtractError is error returned when value could not be extracted for the field. It contains field name, field
+// address and address range of request/response to make debugging large configurations easier.
+type FieldExtractError struct {
+	Name    string
+	Address uint16
+	// RequestFirstAddress is first address of request the field was extracted from
+	RequestFirstAddress uint16
+	// RequestLastAddress is last address of request the field was extracted from
+	RequestLastAddress uint16
+	Err                error
+}
+
+func newFieldExtractError(f Field, firstAddr uint16, lastAddr uint16, err error) *FieldExtractError {
+	return &FieldExtractError{
+		Name:                f.Name,
+		Address:             f.Address,
+		RequestFirstAddress: firstAddr,
+		RequestLastAddress:  lastAddr,
+		Err:                 err,
+	}
+}
+
+// Error returns error message with field name, address and request address range
+func (e *FieldExtractError) Error() string {
+	return fmt.Sprintf(
+		"field '%v' addr %v: %v (request %v-%v)",
+		e.Name,
+		e.Address,
+		e.Err,
+		e.RequestFirstAddress,
+		e.RequestLastAddress,
+	)
+}
+
+// Unwrap allows unwrapping errors with errors.Is and errors.As
+func (e *FieldExtractError) Unwrap() error { return e.Err }
+
 // ExtractFields extracts Field values from given response. When continueOnExtractionErrors is true and error occurs
 // during extraction, this method does not end but continues to extract all Fields and returns ErrorFieldExtractHadError
 // at the end. To distinguish errors check FieldValue.Error field.
 func (r BuilderRequest) ExtractFields(response packet.Response, continueOnExtractionErrors bool) ([]FieldValue, error) {
+	return r.ExtractFieldsSubset(response, r.Fields, continueOnExtractionErrors)
+}
+
+// ExtractFieldsSubset extracts given Field values from given response. Fields should be subset of request Fields.
+// See ExtractFields for error handling semantics.
+func (r BuilderRequest) ExtractFieldsSubset(response packet.Response, fields Fields, continueOnExtractionErrors bool) ([]FieldValue, error) {
 	switch resp := response.(type) {
 	case RegistersResponse:
-		return r.extractRegisterFields(resp, continueOnExtractionErrors)
+		return r.extractRegisterFields(resp, fields, continueOnExtractionErrors)
 	case CoilsResponse:
-		return r.extractCoilFields(resp, continueOnExtractionErrors)
+		return r.extractCoilFields(resp, fields, continueOnExtractionErrors)
 	}
 	return nil, errors.New("can not extract fields from unsupported response type")
 }
 
-func (r BuilderRequest) extractRegisterFields(response RegistersResponse, continueOnExtractionErrors bool) ([]FieldValue, error) {
-	regs, err := response.AsRegisters(r.StartAddress)
+func (r BuilderRequest) extractRegisterFields(response RegistersResponse, fields Fields, continueOnExtractionErrors bool) ([]FieldValue, error) {
+	regs, err := r.asRegisters(response)
 	if err != nil {
 		return nil, err
 	}
@@ -444,21 +1140,39 @@ func (r BuilderRequest) extractRegisterFields(response RegistersResponse, contin
 	hadErrors := false
 	capacity := 0
 	if continueOnExtractionErrors {
-		capacity = len(r.Fields)
+		capacity = len(fields)
 	}
+	info := r.Info()
 	result := make([]FieldValue, 0, capacity)
-	for _, f := range r.Fields {
+	for _, f := range fields {
 		vTmp, err := f.ExtractFrom(regs)
+		if err == nil {
+			vTmp, err = f.mapEnum(vTmp)
+		}
+		if err == nil {
+			vTmp, err = f.scale(vTmp)
+		}
+		if err == nil {
+			vTmp, err = f.convertUnit(vTmp)
+		}
+		if err == nil {
+			vTmp, err = f.process(vTmp)
+		}
+		if err != nil {
+			firstAddr, lastAddr := regs.AddressRange()
+			err = newFieldExtractError(f, firstAddr, lastAddr, err)
+		}
 		if err != nil && !continueOnExtractionErrors {
-			return nil, fmt.Errorf("field extraction failed. name: %v err: %w", f.Name, err)
+			return nil, fmt.Errorf("field extraction failed. %w", err)
 		}
 		if !hadErrors && err != nil {
 			hadErrors = true
 		}
 		tmp := FieldValue{
-			Field: f,
-			Value: vTmp,
-			Error: err,
+			Field:   f,
+			Value:   vTmp,
+			Error:   err,
+			Request: info,
 		}
 		result = append(result, tmp)
 	}
@@ -468,26 +1182,41 @@ func (r BuilderRequest) extractRegisterFields(response RegistersResponse, contin
 	return result, nil
 }
 
-func (r BuilderRequest) extractCoilFields(response CoilsResponse, continueOnExtractionErrors bool) ([]FieldValue, error) {
+func (r BuilderRequest) extractCoilFields(response CoilsResponse, fields Fields, continueOnExtractionErrors bool) ([]FieldValue, error) {
 	hadErrors := false
 	capacity := 0
 	if continueOnExtractionErrors {
-		capacity = len(r.Fields)
+		capacity = len(fields)
 	}
+	info := r.Info()
 	result := make([]FieldValue, 0, capacity)
-	for _, f := range r.Fields {
-		vTmp, err := response.IsCoilSet(r.StartAddress, f.Address)
-
+	for _, f := range fields {
+		isSet, err := response.IsCoilSet(r.StartAddress, f.Address)
+		var vTmp interface{} = isSet
+		if err == nil {
+			vTmp, err = f.mapEnum(vTmp)
+		}
+		if err == nil {
+			vTmp, err = f.process(vTmp)
+		}
+		if err != nil {
+			lastAddr := r.StartAddress
+			if req, ok := asReadRequest(r.Request); ok && req.quantity > 0 {
+				lastAddr = req.startAddress + req.quantity - 1
+			}
+			err = newFieldExtractError(f, r.StartAddress, lastAddr, err)
+		}
 		if err != nil && !continueOnExtractionErrors {
-			return nil, fmt.Errorf("field extraction failed. name: %v err: %w", f.Name, err)
+			return nil, fmt.Errorf("field extraction failed. %w", err)
 		}
 		if !hadErrors && err != nil {
 			hadErrors = true
 		}
 		tmp := FieldValue{
-			Field: f,
-			Value: vTmp,
-			Error: err,
+			Field:   f,
+			Value:   vTmp,
+			Error:   err,
+			Request: info,
 		}
 		result = append(result, tmp)
 	}
@@ -497,42 +1226,106 @@ func (r BuilderRequest) extractCoilFields(response CoilsResponse, continueOnExtr
 	return result, nil
 }
 
-// ReadHoldingRegistersTCP combines fields into TCP Read Holding Registers (FC3) requests
+func (b *Builder) split(funcType splitToFuncType) ([]BuilderRequest, error) {
+	b.mu.Lock()
+	generator := b.transactionIDGenerator
+	limits := splitLimits{allowedGap: b.allowedGap}
+	b.mu.Unlock()
+
+	reqs, err := splitWithLimits(b.copyFields(), funcType, limits)
+	if err != nil {
+		return nil, err
+	}
+	setTransactionIDs(reqs, generator)
+	return reqs, nil
+}
+
+// SplitTCP combines all fields into TCP read requests. Function code of each request is decided by the function code
+// fields are read with (see Field.FunctionCode) so single split can result FC1, FC2, FC3 and FC4 requests. Unlike
+// function specific methods (i.e. ReadHoldingRegistersTCP) no field is left out - field that can not be read with
+// any read function results an error.
+func (b *Builder) SplitTCP() ([]BuilderRequest, error) {
+	return b.split(splitToMixedTCP)
+}
+
+// SplitRTU combines all fields into RTU read requests. See SplitTCP for how function codes are decided.
+func (b *Builder) SplitRTU() ([]BuilderRequest, error) {
+	return b.split(splitToMixedRTU)
+}
+
+// SplitASCII combines all fields into ASCII read requests. See SplitTCP for how function codes are decided.
+func (b *Builder) SplitASCII() ([]BuilderRequest, error) {
+	return b.split(splitToMixedASCII)
+}
+
+// ReadHoldingRegistersTCP combines fields into TCP Read Holding Registers (FC3) requests. Coil fields and fields with
+// different Field.FunctionCode are silently left out. Use SplitTCP to get error for fields that can not be read.
 func (b *Builder) ReadHoldingRegistersTCP() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC3TCP)
+	return b.split(splitToFC3TCP)
 }
 
-// ReadHoldingRegistersRTU combines fields into RTU Read Holding Registers (FC3) requests
+// ReadHoldingRegistersRTU combines fields into RTU Read Holding Registers (FC3) requests. Fields that can not be read
+// with this function are silently left out (see ReadHoldingRegistersTCP).
 func (b *Builder) ReadHoldingRegistersRTU() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC3RTU)
+	return b.split(splitToFC3RTU)
 }
 
-// ReadInputRegistersTCP combines fields into TCP Read Input Registers (FC4) requests
+// ReadHoldingRegistersASCII combines fields into ASCII Read Holding Registers (FC3) requests. Fields that can not be
+// read with this function are silently left out (see ReadHoldingRegistersTCP).
+func (b *Builder) ReadHoldingRegistersASCII() ([]BuilderRequest, error) {
+	return b.split(splitToFC3ASCII)
+}
+
+// ReadInputRegistersTCP combines fields into TCP Read Input Registers (FC4) requests. Coil fields and fields with
+// different Field.FunctionCode are silently left out. Use SplitTCP to get error for fields that can not be read.
 func (b *Builder) ReadInputRegistersTCP() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC4TCP)
+	return b.split(splitToFC4TCP)
 }
 
-// ReadInputRegistersRTU combines fields into RTU Read Input Registers (FC4) requests
+// ReadInputRegistersRTU combines fields into RTU Read Input Registers (FC4) requests. Fields that can not be read with
+// this function are silently left out (see ReadInputRegistersTCP).
 func (b *Builder) ReadInputRegistersRTU() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC4RTU)
+	return b.split(splitToFC4RTU)
+}
+
+// ReadInputRegistersASCII combines fields into ASCII Read Input Registers (FC4) requests. Fields that can not be read
+// with this function are silently left out (see ReadInputRegistersTCP).
+func (b *Builder) ReadInputRegistersASCII() ([]BuilderRequest, error) {
+	return b.split(splitToFC4ASCII)
 }
 
-// ReadCoilsTCP combines fields into TCP Read Coils (FC1) requests
+// ReadCoilsTCP combines fields into TCP Read Coils (FC1) requests. Register fields and coil fields with different
+// Field.FunctionCode are silently left out. Use SplitTCP to get error for fields that can not be read.
 func (b *Builder) ReadCoilsTCP() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC1TCP)
+	return b.split(splitToFC1TCP)
 }
 
-// ReadCoilsRTU combines fields into RTU Read Coils (FC1) requests
+// ReadCoilsRTU combines fields into RTU Read Coils (FC1) requests. Fields that can not be read with this function are
+// silently left out (see ReadCoilsTCP).
 func (b *Builder) ReadCoilsRTU() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC1RTU)
+	return b.split(splitToFC1RTU)
 }
 
-// ReadDiscreteInputsTCP combines fields into TCP Read Discrete Inputs (FC2) requests
+// ReadCoilsASCII combines fields into ASCII Read Coils (FC1) requests. Fields that can not be read with this function
+// are silently left out (see ReadCoilsTCP).
+func (b *Builder) ReadCoilsASCII() ([]BuilderRequest, error) {
+	return b.split(splitToFC1ASCII)
+}
+
+// ReadDiscreteInputsTCP combines fields into TCP Read Discrete Inputs (FC2) requests. Register fields and coil fields
+// with different Field.FunctionCode are silently left out. Use SplitTCP to get error for fields that can not be read.
 func (b *Builder) ReadDiscreteInputsTCP() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC2TCP)
+	return b.split(splitToFC2TCP)
 }
 
-// ReadDiscreteInputsRTU combines fields into RTU Read Discrete Inputs (FC2) requests
+// ReadDiscreteInputsRTU combines fields into RTU Read Discrete Inputs (FC2) requests. Fields that can not be read with
+// this function are silently left out (see ReadDiscreteInputsTCP).
 func (b *Builder) ReadDiscreteInputsRTU() ([]BuilderRequest, error) {
-	return split(b.fields, splitToFC2RTU)
+	return b.split(splitToFC2RTU)
+}
+
+// ReadDiscreteInputsASCII combines fields into ASCII Read Discrete Inputs (FC2) requests. Fields that can not be read
+// with this function are silently left out (see ReadDiscreteInputsTCP).
+func (b *Builder) ReadDiscreteInputsASCII() ([]BuilderRequest, error) {
+	return b.split(splitToFC2ASCII)
 }
@@ -2,6 +2,7 @@ package modbus_test
 
 import (
 	"context"
+	"encoding/binary"
 	"github.com/aldas/go-modbus-client"
 	"github.com/aldas/go-modbus-client/modbustest"
 	"github.com/aldas/go-modbus-client/packet"
@@ -19,7 +20,7 @@ func TestExternalUsage(t *testing.T) {
 			return nil, false
 		}
 		resp := packet.ReadHoldingRegistersResponseTCP{
-			MBAPHeader: packet.MBAPHeader{TransactionID: 123, ProtocolID: 0},
+			MBAPHeader: packet.MBAPHeader{TransactionID: binary.BigEndian.Uint16(received), ProtocolID: 0},
 			ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
 				UnitID:          0,
 				RegisterByteLen: 10,
@@ -2,10 +2,13 @@ package modbus
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"github.com/aldas/go-modbus-client/modbustest"
 	"github.com/aldas/go-modbus-client/packet"
 	"github.com/stretchr/testify/assert"
+	"sync"
 	"testing"
 	"time"
 )
@@ -18,7 +21,7 @@ func TestBuilder_ReadCoilsTCP(t *testing.T) {
 	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
 		receivedChan <- received
 		resp := packet.ReadCoilsResponseTCP{
-			MBAPHeader: packet.MBAPHeader{TransactionID: 123, ProtocolID: 0},
+			MBAPHeader: packet.MBAPHeader{TransactionID: binary.BigEndian.Uint16(received), ProtocolID: 0},
 			ReadCoilsResponse: packet.ReadCoilsResponse{
 				UnitID:          0,
 				CoilsByteLength: 1,
@@ -99,7 +102,7 @@ func TestBuilder_ReadDiscreteInputsTCP(t *testing.T) {
 	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
 		receivedChan <- received
 		resp := packet.ReadCoilsResponseTCP{
-			MBAPHeader: packet.MBAPHeader{TransactionID: 123, ProtocolID: 0},
+			MBAPHeader: packet.MBAPHeader{TransactionID: binary.BigEndian.Uint16(received), ProtocolID: 0},
 			ReadCoilsResponse: packet.ReadCoilsResponse{
 				UnitID:          0,
 				CoilsByteLength: 1,
@@ -180,7 +183,7 @@ func TestBuilder_ReadHoldingRegistersTCP(t *testing.T) {
 	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
 		receivedChan <- received
 		resp := packet.ReadHoldingRegistersResponseTCP{
-			MBAPHeader: packet.MBAPHeader{TransactionID: 123, ProtocolID: 0},
+			MBAPHeader: packet.MBAPHeader{TransactionID: binary.BigEndian.Uint16(received), ProtocolID: 0},
 			ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
 				UnitID:          0,
 				RegisterByteLen: 2,
@@ -221,6 +224,17 @@ func TestBuilder_ReadHoldingRegistersTCP(t *testing.T) {
 
 }
 
+func TestBuilder_TransactionIDGenerator(t *testing.T) {
+	b := NewRequestBuilder("localhost:502", 1).TransactionIDGenerator(packet.NewSequentialTransactionIDGenerator(10))
+
+	reqs, err := b.Add(b.Uint16(1)).Add(b.Uint16(200)).ReadHoldingRegistersTCP()
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+	assert.Equal(t, uint16(10), reqs[0].Request.(*packet.ReadHoldingRegistersRequestTCP).TransactionID)
+	assert.Equal(t, uint16(11), reqs[1].Request.(*packet.ReadHoldingRegistersRequestTCP).TransactionID)
+}
+
 func TestBuilder_ReadHoldingRegistersRTU(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
@@ -261,6 +275,51 @@ func TestBuilder_ReadHoldingRegistersRTU(t *testing.T) {
 	assert.Equal(t, []byte{0x0, 0x3, 0x0, 0x12, 0x0, 0x4, 0xe5, 0xdd}, received)
 }
 
+func TestBuilder_ReadHoldingRegistersASCII(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	receivedChan := make(chan []byte, 1)
+	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
+		receivedChan <- received
+		resp := packet.ReadHoldingRegistersResponseASCII{
+			ReadHoldingRegistersResponseRTU: packet.ReadHoldingRegistersResponseRTU{
+				ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+					UnitID:          0,
+					RegisterByteLen: 8,
+					Data:            []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0xca, 0xfe},
+				},
+			},
+		}
+		return resp.Bytes(), true
+	}
+	addr, err := modbustest.RunServerOnRandomPort(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewRequestBuilder(addr, 1)
+
+	reqs, err := b.Add(b.Int64(18).UnitID(0)).ReadHoldingRegistersASCII()
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 1)
+
+	client := NewASCIIClient()
+	err = client.Connect(context.Background(), addr)
+	assert.NoError(t, err)
+
+	request := reqs[0]
+	resp, err := client.Do(context.Background(), request)
+	assert.NoError(t, err)
+
+	received := <-receivedChan
+	assert.Equal(t, []byte(":000300120004E7\r\n"), received)
+
+	values, err := request.ExtractFields(resp, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0xcafe), values[0].Value)
+}
+
 func TestBuilder_ReadInputRegistersTCP(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
@@ -269,7 +328,7 @@ func TestBuilder_ReadInputRegistersTCP(t *testing.T) {
 	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
 		receivedChan <- received
 		resp := packet.ReadInputRegistersResponseTCP{
-			MBAPHeader: packet.MBAPHeader{TransactionID: 123, ProtocolID: 0},
+			MBAPHeader: packet.MBAPHeader{TransactionID: binary.BigEndian.Uint16(received), ProtocolID: 0},
 			ReadInputRegistersResponse: packet.ReadInputRegistersResponse{
 				UnitID:          0,
 				RegisterByteLen: 2,
@@ -342,6 +401,43 @@ func TestBuilder_ReadInputRegistersRTU(t *testing.T) {
 	assert.Equal(t, []byte{0x0, 0x4, 0x0, 0x12, 0x0, 0x4, 0x50, 0x1d}, received)
 }
 
+func TestBuilder_SplitTCP(t *testing.T) {
+	b := NewRequestBuilder(":502", 1)
+
+	b.Add(b.Uint16(10)).
+		Add(b.Uint16(20).FunctionCode(packet.FunctionReadInputRegisters)).
+		Add(b.Coil(5)).
+		Add(b.Coil(6).FunctionCode(packet.FunctionReadDiscreteInputs)).
+		Add(b.Float32(11))
+
+	reqs, err := b.SplitTCP()
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 4)
+
+	assert.Equal(t, packet.FunctionReadHoldingRegisters, reqs[0].Request.FunctionCode())
+	assert.Equal(t, uint16(10), reqs[0].StartAddress)
+	assert.Len(t, reqs[0].Fields, 2)
+
+	assert.Equal(t, packet.FunctionReadInputRegisters, reqs[1].Request.FunctionCode())
+	assert.Equal(t, uint16(20), reqs[1].StartAddress)
+
+	assert.Equal(t, packet.FunctionReadCoils, reqs[2].Request.FunctionCode())
+	assert.Equal(t, uint16(5), reqs[2].StartAddress)
+
+	assert.Equal(t, packet.FunctionReadDiscreteInputs, reqs[3].Request.FunctionCode())
+	assert.Equal(t, uint16(6), reqs[3].StartAddress)
+}
+
+func TestBuilder_SplitRTU_errorOnUnassignableField(t *testing.T) {
+	b := NewRequestBuilder(":502", 1)
+
+	reqs, err := b.Add(b.Uint16(10)).
+		Add(b.Coil(5).FunctionCode(packet.FunctionReadInputRegisters)).
+		SplitRTU()
+	assert.EqualError(t, err, "field with type coil must have function code 1 or 2")
+	assert.Nil(t, reqs)
+}
+
 func TestField_ModbusAddress(t *testing.T) {
 	given := &BField{}
 
@@ -389,6 +485,87 @@ func TestBuilder_Add(t *testing.T) {
 	assert.Equal(t, uint8(1), b.fields[0].UnitID)
 }
 
+func TestNewStrictRequestBuilder_Add(t *testing.T) {
+	b := NewStrictRequestBuilder(":5020", 2)
+	b.Add(b.Uint16(10).Name("ok"))
+	assert.Len(t, b.fields, 1)
+
+	assert.PanicsWithError(t, "field validation failed. index: 0 name: 'invalid' err: field with type string must have length set", func() {
+		b.Add(b.String(12, 0).Name("invalid"))
+	})
+	assert.Len(t, b.fields, 1)
+}
+
+func TestBuilder_AddAllValidated(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        Fields
+		expectLen   int
+		expectError string
+	}{
+		{
+			name: "ok",
+			when: Fields{
+				{ServerAddress: ":502", UnitID: 1, Address: 100, Type: FieldTypeUint16, Name: "f1"},
+				{ServerAddress: ":502", UnitID: 1, Address: 101, Type: FieldTypeInt16, Name: "f2"},
+			},
+			expectLen: 2,
+		},
+		{
+			name: "nok, nothing is added when one field is invalid",
+			when: Fields{
+				{ServerAddress: ":502", UnitID: 1, Address: 100, Type: FieldTypeUint16, Name: "f1"},
+				{ServerAddress: ":502", UnitID: 1, Address: 101, Type: FieldTypeBit, Bit: 16, Name: "f2"},
+			},
+			expectLen:   0,
+			expectError: "field validation failed. index: 1 name: 'f2' err: field bit value must be in range (0-15)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewRequestBuilder("", 0)
+
+			err := b.AddAllValidated(tc.when)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, b.fields, tc.expectLen)
+		})
+	}
+}
+
+func TestBuilder_AddValidated(t *testing.T) {
+	b := NewRequestBuilder("", 2)
+
+	err := b.AddValidated(b.Uint16(10))
+
+	assert.EqualError(t, err, "field validation failed. index: 0 name: '' err: field server address can not be empty")
+	assert.Len(t, b.fields, 0)
+}
+
+func TestBuilder_Add_concurrent(t *testing.T) {
+	b := NewRequestBuilder(":5020", 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b.Add(b.Uint16(uint16(i)))
+			_, err := b.ReadHoldingRegistersTCP()
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	reqs, err := b.ReadHoldingRegistersTCP()
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 1)
+	assert.Len(t, reqs[0].Fields, 10)
+}
+
 func TestBuilder_Bit(t *testing.T) {
 	b := NewRequestBuilder(":5020", 2)
 
@@ -604,6 +781,22 @@ func TestBuilder_Coil(t *testing.T) {
 	assert.Equal(t, expect, b.fields[0])
 }
 
+func TestBuilder_AddAllWithDefaults(t *testing.T) {
+	b := NewRequestBuilder(":502", 1)
+
+	given := Fields{
+		{Address: 100, Type: FieldTypeUint16, Name: "defaults"},
+		{ServerAddress: ":5020", UnitID: 2, Address: 101, Type: FieldTypeUint16, Name: "own"},
+	}
+	b.AddAllWithDefaults(given)
+
+	assert.Equal(t, Fields{
+		{ServerAddress: ":502", UnitID: 1, Address: 100, Type: FieldTypeUint16, Name: "defaults"},
+		{ServerAddress: ":5020", UnitID: 2, Address: 101, Type: FieldTypeUint16, Name: "own"},
+	}, b.fields)
+	assert.Equal(t, "", given[0].ServerAddress) // given fields are not modified
+}
+
 func TestBuilder_AddAll(t *testing.T) {
 	var testCases = []struct {
 		name   string
@@ -703,6 +896,97 @@ func TestRegisterRequest_Fields(t *testing.T) {
 	assert.Equal(t, expect, given.Fields)
 }
 
+func TestRegisterRequest_AsRegisters_responseTypes(t *testing.T) {
+	data := []byte{0xff, 0xff, 0x7f, 0xff, 0x0, 0x1}
+	var testCases = []struct {
+		name string
+		when RegistersResponse
+	}{
+		{
+			name: "ok, FC3 TCP",
+			when: &packet.ReadHoldingRegistersResponseTCP{
+				ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{UnitID: 1, RegisterByteLen: 6, Data: data},
+			},
+		},
+		{
+			name: "ok, FC4 RTU",
+			when: &packet.ReadInputRegistersResponseRTU{
+				ReadInputRegistersResponse: packet.ReadInputRegistersResponse{UnitID: 1, RegisterByteLen: 6, Data: data},
+			},
+		},
+		{
+			name: "ok, FC23 TCP",
+			when: &packet.ReadWriteMultipleRegistersResponseTCP{
+				ReadWriteMultipleRegistersResponse: packet.ReadWriteMultipleRegistersResponse{UnitID: 1, RegisterByteLen: 6, Data: data},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := BuilderRequest{UnitID: 1, StartAddress: 100}
+
+			registers, err := rr.AsRegisters(tc.when)
+			assert.NoError(t, err)
+
+			value, err := registers.Uint16(102)
+			assert.NoError(t, err)
+			assert.Equal(t, uint16(1), value)
+		})
+	}
+}
+
+func TestRegisterRequest_AsCoils(t *testing.T) {
+	fc1, _ := packet.NewReadCoilsRequestTCP(1, 10, 10)
+	fc2, _ := packet.NewReadDiscreteInputsRequestRTU(1, 10, 3)
+	fc3, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 3)
+
+	var testCases = []struct {
+		name        string
+		request     packet.Request
+		when        CoilsResponse
+		expect      []bool
+		expectError string
+	}{
+		{
+			name:    "ok, FC1 TCP",
+			request: fc1,
+			when: &packet.ReadCoilsResponseTCP{
+				ReadCoilsResponse: packet.ReadCoilsResponse{UnitID: 1, CoilsByteLength: 2, Data: []byte{0b00000101, 0b00000010}},
+			},
+			expect: []bool{true, false, true, false, false, false, false, false, false, true},
+		},
+		{
+			name:    "ok, FC2 RTU",
+			request: fc2,
+			when: &packet.ReadDiscreteInputsResponseRTU{
+				ReadDiscreteInputsResponse: packet.ReadDiscreteInputsResponse{UnitID: 1, InputsByteLength: 1, Data: []byte{0b00000110}},
+			},
+			expect: []bool{false, true, true},
+		},
+		{
+			name:    "nok, request is not coils request",
+			request: fc3,
+			when: &packet.ReadCoilsResponseTCP{
+				ReadCoilsResponse: packet.ReadCoilsResponse{UnitID: 1, CoilsByteLength: 1, Data: []byte{0x1}},
+			},
+			expectError: "request is not read coils or read discrete inputs request",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := BuilderRequest{Request: tc.request, UnitID: 1, StartAddress: 10}
+
+			coils, err := rr.AsCoils(tc.when)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expect, coils)
+		})
+	}
+}
+
 func TestRegisterRequest_AsRegisters(t *testing.T) {
 	rr := BuilderRequest{
 		Request:       nil,
@@ -729,7 +1013,58 @@ func TestRegisterRequest_AsRegisters(t *testing.T) {
 	assert.Equal(t, uint16(1), value)
 }
 
+func TestRegisterRequest_ExtractFieldsSubset_withRegistersCache(t *testing.T) {
+	f1 := Field{Name: "f1", Address: 100, Type: FieldTypeUint16}
+	f2 := Field{Name: "f2", Address: 101, Type: FieldTypeString, Length: 2}
+	rr := BuilderRequest{
+		StartAddress: 100,
+		Fields:       Fields{f1, f2},
+	}
+	rr.EnableRegistersCache()
+
+	resp := &packet.ReadHoldingRegistersResponseTCP{
+		ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+			UnitID:          1,
+			RegisterByteLen: 4,
+			Data:            []byte{0x0, 0x1, 0x69, 0x48}, // 0x69 0x48 = "Hi" in big endian register
+		},
+	}
+
+	values, err := rr.ExtractFieldsSubset(resp, Fields{f1}, false)
+	assert.NoError(t, err)
+	requestInfo := RequestInfo{StartAddress: 100}
+	assert.Equal(t, []FieldValue{{Field: f1, Value: uint16(1), Request: requestInfo}}, values)
+
+	regs1, err := rr.AsRegisters(resp)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ { // extracting string twice from same registers must give same result
+		values, err = rr.ExtractFieldsSubset(resp, Fields{f2}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []FieldValue{{Field: f2, Value: "Hi", Request: requestInfo}}, values)
+	}
+
+	regs2, err := rr.AsRegisters(resp)
+	assert.NoError(t, err)
+	assert.Same(t, regs1, regs2)
+
+	valueResp, err := rr.AsRegisters(*resp) // non-pointer responses are not cached
+	assert.NoError(t, err)
+	assert.NotSame(t, regs1, valueResp)
+
+	otherResp := *resp
+	otherResp.TransactionID = 1
+	otherResp.Data = []byte{0x0, 0x2, 0x69, 0x48}
+	regs3, err := rr.AsRegisters(&otherResp)
+	assert.NoError(t, err)
+	assert.NotSame(t, regs1, regs3)
+	v, err := regs3.Uint16(100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), v)
+}
+
 func TestRegisterRequest_ExtractFields(t *testing.T) {
+	requestInfo := RequestInfo{ServerAddress: ":502", UnitID: 1, StartAddress: 20}
 	var testCases = []struct {
 		name                           string
 		givenFields                    Fields
@@ -765,8 +1100,9 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Type:    FieldTypeInt16,
 						Name:    "f1",
 					},
-					Value: int16(1),
-					Error: nil,
+					Value:   int16(1),
+					Error:   nil,
+					Request: requestInfo,
 				},
 				{
 					Field: Field{
@@ -776,8 +1112,38 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Bit:     8,
 						Name:    "f2",
 					},
-					Value: true,
-					Error: nil,
+					Value:   true,
+					Error:   nil,
+					Request: requestInfo,
+				},
+			},
+		},
+		{
+			name: "ok, extract registers with unit conversion",
+			givenFields: Fields{
+				{
+					UnitID:     1,
+					Address:    21,
+					Type:       FieldTypeUint16,
+					Name:       "power",
+					Unit:       "W",
+					TargetUnit: "kW",
+				},
+			},
+			givenResponseData: []byte{0x0, 0x0, 0x04, 0xd2},
+			expect: []FieldValue{
+				{
+					Field: Field{
+						UnitID:     1,
+						Address:    21,
+						Type:       FieldTypeUint16,
+						Name:       "power",
+						Unit:       "W",
+						TargetUnit: "kW",
+					},
+					Value:   1.234,
+					Error:   nil,
+					Request: requestInfo,
 				},
 			},
 		},
@@ -807,8 +1173,9 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Type:    FieldTypeCoil,
 						Name:    "f1",
 					},
-					Value: true,
-					Error: nil,
+					Value:   true,
+					Error:   nil,
+					Request: requestInfo,
 				},
 				{
 					Field: Field{
@@ -817,8 +1184,9 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Type:    FieldTypeCoil,
 						Name:    "f2",
 					},
-					Value: false,
-					Error: nil,
+					Value:   false,
+					Error:   nil,
+					Request: requestInfo,
 				},
 			},
 		},
@@ -848,8 +1216,9 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Type:    FieldTypeInt16,
 						Name:    "f1",
 					},
-					Value: int16(1),
-					Error: nil,
+					Value:   int16(1),
+					Error:   nil,
+					Request: requestInfo,
 				},
 				{
 					Field: Field{
@@ -859,7 +1228,14 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Name:    "f2",
 					},
 					Value: float64(0),
-					Error: errors.New("address over startAddress+quantity bounds"),
+					Error: &FieldExtractError{
+						Name:                "f2",
+						Address:             22,
+						RequestFirstAddress: 20,
+						RequestLastAddress:  22,
+						Err:                 errors.New("address over startAddress+quantity bounds"),
+					},
+					Request: requestInfo,
 				},
 			},
 			expectErr: ErrorFieldExtractHadError.Error(),
@@ -891,8 +1267,9 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Type:    FieldTypeCoil,
 						Name:    "f1",
 					},
-					Value: true,
-					Error: nil,
+					Value:   true,
+					Error:   nil,
+					Request: requestInfo,
 				},
 				{
 					Field: Field{
@@ -902,7 +1279,14 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 						Name:    "f2",
 					},
 					Value: false,
-					Error: errors.New("bit can not be before startBit"),
+					Error: &FieldExtractError{
+						Name:                "f2",
+						Address:             0,
+						RequestFirstAddress: 20,
+						RequestLastAddress:  20,
+						Err:                 errors.New("bit can not be before startBit"),
+					},
+					Request: requestInfo,
 				},
 			},
 			expectErr: ErrorFieldExtractHadError.Error(),
@@ -926,7 +1310,7 @@ func TestRegisterRequest_ExtractFields(t *testing.T) {
 			givenResponseData:              []byte{0x0, 0x0, 0x0, 0x1, 0b00010001, 0x0},
 			whenContinueOnExtractionErrors: false,
 			expect:                         nil,
-			expectErr:                      "field extraction failed. name: f2 err: address over startAddress+quantity bounds",
+			expectErr:                      "field extraction failed. field 'f2' addr 22: address over startAddress+quantity bounds (request 20-22)",
 		},
 		{
 			name: "nok, error creating registers",
@@ -1051,6 +1435,31 @@ func TestField_registerSize(t *testing.T) {
 			when:   Field{Type: FieldTypeInt64},
 			expect: 4,
 		},
+		{
+			name:   "uint48",
+			when:   Field{Type: FieldTypeUint48},
+			expect: 3,
+		},
+		{
+			name:   "int48",
+			when:   Field{Type: FieldTypeInt48},
+			expect: 3,
+		},
+		{
+			name:   "bcd, default digits",
+			when:   Field{Type: FieldTypeBCD},
+			expect: 1,
+		},
+		{
+			name:   "bcd, 6 digits",
+			when:   Field{Type: FieldTypeBCD, Length: 6},
+			expect: 2,
+		},
+		{
+			name:   "float16",
+			when:   Field{Type: FieldTypeFloat16},
+			expect: 1,
+		},
 		{
 			name:   "float32",
 			when:   Field{Type: FieldTypeFloat32},
@@ -1154,6 +1563,51 @@ func TestField_ExtractFrom(t *testing.T) {
 			givenRegisterData: []byte{0x0, 0x0, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
 			expect:            int64(-1),
 		},
+		{
+			name:              "uint48",
+			whenType:          FieldTypeUint48,
+			givenRegisterData: []byte{0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x2},
+			expect:            uint64(0x100000002),
+		},
+		{
+			name:              "int48",
+			whenType:          FieldTypeInt48,
+			whenByteOrder:     packet.BigEndianLowWordFirst,
+			givenRegisterData: []byte{0x0, 0x0, 0xFF, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF},
+			expect:            int64(-2),
+		},
+		{
+			name:              "unix time 32",
+			whenType:          FieldTypeUnixTime32,
+			givenRegisterData: []byte{0x0, 0x0, 0x65, 0x92, 0x00, 0x80},
+			expect:            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:              "unix time 64",
+			whenType:          FieldTypeUnixTime64,
+			whenByteOrder:     packet.BigEndianLowWordFirst,
+			givenRegisterData: []byte{0x0, 0x0, 0x00, 0x80, 0x65, 0x92, 0x00, 0x00, 0x00, 0x00},
+			expect:            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:              "bcd",
+			whenType:          FieldTypeBCD,
+			givenRegisterData: []byte{0x0, 0x0, 0x01, 0x23},
+			expect:            uint32(123),
+		},
+		{
+			name:              "nok, bcd value has more digits than field length",
+			whenType:          FieldTypeBCD,
+			givenRegisterData: []byte{0x0, 0x0, 0x12, 0x34},
+			expect:            nil,
+			expectErr:         "BCD value has more digits than field length: 1234",
+		},
+		{
+			name:              "float16",
+			whenType:          FieldTypeFloat16,
+			givenRegisterData: []byte{0x0, 0x0, 0x3e, 0x00},
+			expect:            float32(1.5),
+		},
 		{
 			name:              "float32",
 			whenType:          FieldTypeFloat32,
@@ -1217,6 +1671,24 @@ func TestField_ExtractFrom(t *testing.T) {
 	}
 }
 
+func TestField_ExtractFrom_timeWithEpochAndZone(t *testing.T) {
+	f := Field{
+		Type:      FieldTypeUnixTime32,
+		Address:   0,
+		TimeEpoch: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		TimeZone:  "Europe/Tallinn",
+	}
+	registers, _ := packet.NewRegisters([]byte{0x0, 0x0, 0x0e, 0x10}, 0) // 3600 seconds
+
+	result, err := f.ExtractFrom(registers)
+
+	assert.NoError(t, err)
+	tallinn, _ := time.LoadLocation("Europe/Tallinn")
+	expect := time.Date(2000, 1, 1, 1, 0, 0, 0, tallinn)
+	assert.True(t, expect.Equal(result.(time.Time)), "got: %v", result)
+	assert.Equal(t, "Europe/Tallinn", result.(time.Time).Location().String())
+}
+
 func TestField_Validate(t *testing.T) {
 	example := Field{
 		ServerAddress: ":502",
@@ -1248,11 +1720,40 @@ func TestField_Validate(t *testing.T) {
 			given:     func(f *Field) { f.Type = 0 },
 			expectErr: "field type must be set",
 		},
+		{
+			name:      "nok, BCD length is too large",
+			given:     func(f *Field) { f.Type = FieldTypeBCD; f.Length = 9 },
+			expectErr: "field with type BCD must have length (digit count) in range (0-8)",
+		},
+		{
+			name:      "nok, time zone is invalid",
+			given:     func(f *Field) { f.Type = FieldTypeUnixTime32; f.TimeZone = "Mars/Olympus_Mons" },
+			expectErr: "field time zone is invalid: unknown time zone Mars/Olympus_Mons",
+		},
 		{
 			name:      "nok, type is invalid value",
-			given:     func(f *Field) { f.Type = 15 },
+			given:     func(f *Field) { f.Type = 21 },
 			expectErr: "field type has invalid value",
 		},
+		{
+			name:  "ok, input register function code",
+			given: func(f *Field) { f.FunctionCode = packet.FunctionReadInputRegisters },
+		},
+		{
+			name:      "nok, function code is not read function",
+			given:     func(f *Field) { f.FunctionCode = packet.FunctionWriteSingleRegister },
+			expectErr: "field function code must be read function code (1-4)",
+		},
+		{
+			name:      "nok, register field with coil function code",
+			given:     func(f *Field) { f.FunctionCode = packet.FunctionReadDiscreteInputs },
+			expectErr: "field with function code 1 or 2 must have type coil",
+		},
+		{
+			name:      "nok, coil field with register function code",
+			given:     func(f *Field) { f.Type = FieldTypeCoil; f.FunctionCode = packet.FunctionReadHoldingRegisters },
+			expectErr: "field with type coil must have function code 1 or 2",
+		},
 		{
 			name:      "nok, bit out of range",
 			given:     func(f *Field) { f.Bit = 16 },
@@ -1266,6 +1767,36 @@ func TestField_Validate(t *testing.T) {
 			},
 			expectErr: "field with type string must have length set",
 		},
+		{
+			name:      "nok, string padding is invalid value",
+			given:     func(f *Field) { f.StringPadding = 2 },
+			expectErr: "field string padding has invalid value",
+		},
+		{
+			name: "nok, unknown unit conversion",
+			given: func(f *Field) {
+				f.Unit = "W"
+				f.TargetUnit = "°F"
+			},
+			expectErr: "field unit conversion is invalid: unknown unit conversion from 'W' to '°F'",
+		},
+		{
+			name: "nok, enum mapping for float field",
+			given: func(f *Field) {
+				f.Type = FieldTypeFloat32
+				f.Enum = map[int64]string{0: "OFF"}
+			},
+			expectErr: "field enum mapping is supported only for integer, bit and coil field types",
+		},
+		{
+			name: "nok, enum mapping with scale",
+			given: func(f *Field) {
+				f.Type = FieldTypeUint16
+				f.Enum = map[int64]string{0: "OFF"}
+				f.Scale = 0.1
+			},
+			expectErr: "field enum mapping can not be combined with scaling or unit conversion",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1283,3 +1814,113 @@ func TestField_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilderRequest_Info(t *testing.T) {
+	fields := Fields{
+		{Name: "a", Address: 0, Type: FieldTypeUint16},
+		{Name: "b", Address: 200, Type: FieldTypeUint16},
+	}
+	reqs, err := FieldsToRequests(fields, RequestDefaults{ServerAddress: ":502", UnitID: 2, FunctionCode: 4, IsRTU: true})
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+
+	assert.Equal(t, RequestInfo{
+		BatchID:       1,
+		ServerAddress: ":502",
+		UnitID:        2,
+		FunctionCode:  packet.FunctionReadInputRegisters,
+		Protocol:      "rtu",
+		StartAddress:  200,
+		Quantity:      1,
+	}, reqs[1].Info())
+
+	resp := &packet.ReadInputRegistersResponseRTU{
+		ReadInputRegistersResponse: packet.ReadInputRegistersResponse{
+			UnitID:          2,
+			RegisterByteLen: 2,
+			Data:            []byte{0x0, 0x7},
+		},
+	}
+	values, err := reqs[1].ExtractFields(resp, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, values[0].Request.BatchID)
+	assert.Equal(t, uint16(200), values[0].Request.StartAddress)
+}
+
+func TestBuilderRequest_ExtractFields_withScaleAndOffset(t *testing.T) {
+	b := NewRequestBuilder("localhost:502", 1)
+	b.Add(b.Int16(20).Name("temperature").Scale(0.1).Offset(-40))
+	b.Add(b.Uint16(21).Name("power").Scale(10).Unit("W").TargetUnit("kW"))
+	b.Add(b.Uint16(22).Name("offset_only").Offset(5))
+	b.Add(b.String(23, 2).Name("label").Scale(2))
+	reqs, err := b.ReadHoldingRegistersTCP()
+	assert.NoError(t, err)
+
+	resp := &packet.ReadHoldingRegistersResponseTCP{
+		ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+			UnitID:          1,
+			RegisterByteLen: 8,
+			Data:            []byte{0x02, 0x58, 0x0, 0xc8, 0x0, 0x1, 0x41, 0x42},
+		},
+	}
+	values, err := reqs[0].ExtractFields(resp, true)
+	assert.EqualError(t, err, ErrorFieldExtractHadError.Error())
+	assert.Len(t, values, 4)
+	assert.InDelta(t, 20.0, values[0].Value, 0.000001)
+	assert.Equal(t, 2.0, values[1].Value)
+	assert.Equal(t, 6.0, values[2].Value)
+	assert.EqualError(t, values[3].Error, "field 'label' addr 23: scaling is not supported for non-numeric field type (request 20-23)")
+}
+
+func TestField_ScaleFromJSON(t *testing.T) {
+	var f Field
+	err := json.Unmarshal([]byte(`{"scale": 0.01, "offset": 1.5}`), &f)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.01, f.Scale)
+	assert.Equal(t, 1.5, f.Offset)
+}
+
+func TestBuilderRequest_ExtractFields_withEnum(t *testing.T) {
+	states := map[int64]string{0: "OFF", 1: "ON", 2: "FAULT"}
+	b := NewRequestBuilder("localhost:502", 1)
+	b.Add(b.Uint16(20).Name("state").Enum(states))
+	b.Add(b.Int16(21).Name("unknown_state").Enum(states))
+	b.Add(b.Bit(22, 0).Name("running").Enum(map[int64]string{0: "stopped", 1: "running"}))
+	reqs, err := b.ReadHoldingRegistersTCP()
+	assert.NoError(t, err)
+
+	resp := &packet.ReadHoldingRegistersResponseTCP{
+		ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+			UnitID:          1,
+			RegisterByteLen: 6,
+			Data:            []byte{0x0, 0x2, 0xff, 0xff, 0x0, 0x1},
+		},
+	}
+	values, err := reqs[0].ExtractFields(resp, true)
+	assert.EqualError(t, err, ErrorFieldExtractHadError.Error())
+	assert.Len(t, values, 3)
+	assert.Equal(t, "FAULT", values[0].Value)
+	assert.EqualError(t, values[1].Error, "field 'unknown_state' addr 21: enum mapping does not exist for value: -1 (request 20-22)")
+	assert.Equal(t, "running", values[2].Value)
+
+	cb := NewRequestBuilder("localhost:502", 1)
+	cb.Add(cb.Coil(10).Name("pump").Enum(map[int64]string{0: "OFF", 1: "ON"}))
+	coilReqs, err := cb.ReadCoilsTCP()
+	assert.NoError(t, err)
+
+	coilResp := &packet.ReadCoilsResponseTCP{
+		ReadCoilsResponse: packet.ReadCoilsResponse{UnitID: 1, CoilsByteLength: 1, Data: []byte{0x1}},
+	}
+	values, err = coilReqs[0].ExtractFields(coilResp, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "ON", values[0].Value)
+}
+
+func TestField_EnumFromJSON(t *testing.T) {
+	var f Field
+	err := json.Unmarshal([]byte(`{"enum": {"0": "OFF", "1": "ON", "-1": "ERROR"}}`), &f)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]string{0: "OFF", 1: "ON", -1: "ERROR"}, f.Enum)
+}
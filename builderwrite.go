@@ -0,0 +1,222 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"sort"
+)
+
+type builderProtocol uint8
+
+const (
+	builderProtocolTCP builderProtocol = iota
+	builderProtocolRTU
+	builderProtocolASCII
+)
+
+// WriteHoldingRegistersTCP combines given values (field name to value) of builder fields into TCP Write Multiple
+// Registers (FC16) requests. See WriteHoldingRegistersRTU for details.
+func (b *Builder) WriteHoldingRegistersTCP(values map[string]interface{}) ([]BuilderRequest, error) {
+	return b.writeRequests(values, false, builderProtocolTCP)
+}
+
+// WriteHoldingRegistersRTU combines given values (field name to value) of builder fields into RTU Write Multiple
+// Registers (FC16) requests. Values are marshalled with Field.MarshalBytes. Fields with contiguous addresses (same
+// server address and unit ID) are written with the same request. Fields that occupy only part of the register (bits and
+// bytes) can not be written as request would overwrite rest of the register, use WriteFieldsRTU for them.
+func (b *Builder) WriteHoldingRegistersRTU(values map[string]interface{}) ([]BuilderRequest, error) {
+	return b.writeRequests(values, false, builderProtocolRTU)
+}
+
+// WriteHoldingRegistersASCII combines given values (field name to value) of builder fields into ASCII Write Multiple
+// Registers (FC16) requests. See WriteHoldingRegistersRTU for details.
+func (b *Builder) WriteHoldingRegistersASCII(values map[string]interface{}) ([]BuilderRequest, error) {
+	return b.writeRequests(values, false, builderProtocolASCII)
+}
+
+// WriteCoilsTCP combines given values (field name to bool value) of builder coil fields into TCP Write Multiple Coils
+// (FC15) requests. Coils with contiguous addresses (same server address and unit ID) are written with the same request.
+func (b *Builder) WriteCoilsTCP(values map[string]interface{}) ([]BuilderRequest, error) {
+	return b.writeRequests(values, true, builderProtocolTCP)
+}
+
+// WriteCoilsRTU combines given values (field name to bool value) of builder coil fields into RTU Write Multiple Coils
+// (FC15) requests. Coils with contiguous addresses (same server address and unit ID) are written with the same request.
+func (b *Builder) WriteCoilsRTU(values map[string]interface{}) ([]BuilderRequest, error) {
+	return b.writeRequests(values, true, builderProtocolRTU)
+}
+
+// WriteCoilsASCII combines given values (field name to bool value) of builder coil fields into ASCII Write Multiple
+// Coils (FC15) requests. Coils with contiguous addresses (same server address and unit ID) are written with the same
+// request.
+func (b *Builder) WriteCoilsASCII(values map[string]interface{}) ([]BuilderRequest, error) {
+	return b.writeRequests(values, true, builderProtocolASCII)
+}
+
+func (b *Builder) writeRequests(values map[string]interface{}, forCoils bool, protocol builderProtocol) ([]BuilderRequest, error) {
+	if len(values) == 0 {
+		return nil, errors.New("write values can not be empty")
+	}
+	b.mu.Lock()
+	generator := b.transactionIDGenerator
+	b.mu.Unlock()
+
+	byName := map[string]Field{}
+	for _, f := range b.copyFields() {
+		if _, ok := values[f.Name]; !ok {
+			continue
+		}
+		if _, exists := byName[f.Name]; exists {
+			return nil, fmt.Errorf("write field name is not unique: '%v'", f.Name)
+		}
+		byName[f.Name] = f
+	}
+	toWrite := make([]FieldValue, 0, len(values))
+	for name, v := range values {
+		f, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("write field not found: '%v'", name)
+		}
+		if err := f.Validate(); err != nil {
+			return nil, fmt.Errorf("write field validation failed. name: '%v' err: %w", name, err)
+		}
+		isCoil := f.Type == FieldTypeCoil
+		if forCoils != isCoil {
+			return nil, fmt.Errorf("write field type is not supported for the request. name: '%v'", name)
+		}
+		switch f.Type {
+		case FieldTypeBit, FieldTypeByte, FieldTypeUint8, FieldTypeInt8:
+			return nil, fmt.Errorf("write field occupies only part of the register. name: '%v'", name)
+		}
+		toWrite = append(toWrite, FieldValue{Field: f, Value: v})
+	}
+	batches, err := batchWriteValues(toWrite, forCoils)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]BuilderRequest, 0, len(batches))
+	for _, batch := range batches {
+		batchValues := make([]FieldValue, len(batch))
+		for i, idx := range batch {
+			batchValues[i] = toWrite[idx]
+		}
+		req, err := newWriteBatchRequest(batchValues, forCoils, protocol)
+		if err != nil {
+			return nil, err
+		}
+		req.BatchID = len(result)
+		result = append(result, req)
+	}
+
+	setTransactionIDs(result, generator)
+	return result, nil
+}
+
+// batchWriteValues sorts given field values by server address, unit ID and address and groups contiguous values into
+// batches that fit into single write request. Batches contain indexes of given values.
+func batchWriteValues(values []FieldValue, forCoils bool) ([][]int, error) {
+	sorted := make([]int, len(values))
+	for i := range values {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		fi, fj := values[sorted[i]].Field, values[sorted[j]].Field
+		if fi.ServerAddress != fj.ServerAddress {
+			return fi.ServerAddress < fj.ServerAddress
+		}
+		if fi.UnitID != fj.UnitID {
+			return fi.UnitID < fj.UnitID
+		}
+		return fi.Address < fj.Address
+	})
+
+	maxQuantity := maxRegistersInWriteRequest
+	if forCoils {
+		maxQuantity = maxCoilsInWriteRequest
+	}
+	result := make([][]int, 0)
+	batchStart := 0
+	batchEnd := uint32(0) // address after last field of the batch
+	for i, idx := range sorted {
+		f := values[idx].Field
+		size := uint32(1)
+		if !forCoils {
+			size = uint32(f.registerSize())
+		}
+		if i > batchStart {
+			first := values[sorted[batchStart]].Field
+			sameDevice := f.ServerAddress == first.ServerAddress && f.UnitID == first.UnitID
+			if sameDevice && uint32(f.Address) < batchEnd {
+				return nil, fmt.Errorf("write fields overlap. name: '%v'", f.Name)
+			}
+			if !sameDevice || uint32(f.Address) != batchEnd || batchEnd+size-uint32(first.Address) > uint32(maxQuantity) {
+				result = append(result, sorted[batchStart:i:i])
+				batchStart = i
+			}
+		}
+		batchEnd = uint32(f.Address) + size
+	}
+	if len(sorted) > 0 {
+		result = append(result, sorted[batchStart:])
+	}
+	return result, nil
+}
+
+// newWriteBatchRequest creates write request for given contiguous field values
+func newWriteBatchRequest(values []FieldValue, forCoils bool, protocol builderProtocol) (BuilderRequest, error) {
+	first := values[0].Field
+	fields := make(Fields, len(values))
+	for i, v := range values {
+		fields[i] = v.Field
+	}
+
+	var req packet.Request
+	var err error
+	if forCoils {
+		coils := make([]bool, len(values))
+		for i, v := range values {
+			isSet, ok := v.Value.(bool)
+			if !ok {
+				return BuilderRequest{}, fmt.Errorf("write coil value must be bool. name: '%v'", v.Field.Name)
+			}
+			coils[i] = isSet
+		}
+		switch protocol {
+		case builderProtocolRTU:
+			req, err = packet.NewWriteMultipleCoilsRequestRTU(first.UnitID, first.Address, coils)
+		case builderProtocolASCII:
+			req, err = packet.NewWriteMultipleCoilsRequestASCII(first.UnitID, first.Address, coils)
+		default:
+			req, err = packet.NewWriteMultipleCoilsRequestTCP(first.UnitID, first.Address, coils)
+		}
+	} else {
+		last := values[len(values)-1].Field
+		data := make([]byte, (last.Address+last.registerSize()-first.Address)*2)
+		for _, v := range values {
+			from := (v.Field.Address - first.Address) * 2
+			to := from + v.Field.registerSize()*2
+			if err := v.Field.marshalInto(data[from:to], v.Value); err != nil {
+				return BuilderRequest{}, fmt.Errorf("write field marshalling failed. name: '%v' err: %w", v.Field.Name, err)
+			}
+		}
+		switch protocol {
+		case builderProtocolRTU:
+			req, err = packet.NewWriteMultipleRegistersRequestRTU(first.UnitID, first.Address, data)
+		case builderProtocolASCII:
+			req, err = packet.NewWriteMultipleRegistersRequestASCII(first.UnitID, first.Address, data)
+		default:
+			req, err = packet.NewWriteMultipleRegistersRequestTCP(first.UnitID, first.Address, data)
+		}
+	}
+	if err != nil {
+		return BuilderRequest{}, err
+	}
+	return BuilderRequest{
+		Request:       req,
+		ServerAddress: first.ServerAddress,
+		UnitID:        first.UnitID,
+		StartAddress:  first.Address,
+		Fields:        fields,
+	}, nil
+}
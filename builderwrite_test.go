@@ -0,0 +1,142 @@
+package modbus
+
+import (
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuilder_WriteHoldingRegistersTCP(t *testing.T) {
+	b := NewRequestBuilder("localhost:502", 1).TransactionIDGenerator(packet.NewSequentialTransactionIDGenerator(10))
+	b.Add(b.Uint16(100).Name("setpoint"))
+	b.Add(b.Int32(101).Name("mode"))
+	b.Add(b.Uint16(200).Name("limit"))
+	b.Add(b.Uint16(300).Name("not_written"))
+
+	reqs, err := b.WriteHoldingRegistersTCP(map[string]interface{}{
+		"setpoint": uint16(0x1234),
+		"mode":     int32(-2),
+		"limit":    uint16(7),
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+
+	first := reqs[0].Request.(*packet.WriteMultipleRegistersRequestTCP)
+	assert.Equal(t, uint16(10), first.TransactionID)
+	assert.Equal(t, uint16(100), first.StartAddress)
+	assert.Equal(t, []byte{0x12, 0x34, 0xff, 0xff, 0xff, 0xfe}, first.Data)
+	assert.Equal(t, 0, reqs[0].BatchID)
+	assert.Equal(t, uint16(100), reqs[0].StartAddress)
+	assert.Len(t, reqs[0].Fields, 2)
+
+	second := reqs[1].Request.(*packet.WriteMultipleRegistersRequestTCP)
+	assert.Equal(t, uint16(11), second.TransactionID)
+	assert.Equal(t, uint16(200), second.StartAddress)
+	assert.Equal(t, []byte{0x0, 0x7}, second.Data)
+	assert.Equal(t, 1, reqs[1].BatchID)
+	assert.Equal(t, "limit", reqs[1].Fields[0].Name)
+}
+
+func TestBuilder_WriteHoldingRegistersRTU_maxRegisters(t *testing.T) {
+	b := NewRequestBuilder("localhost:502", 1)
+	values := map[string]interface{}{}
+	for i := uint16(0); i < 62; i++ { // 62 * 2 registers = 124 registers, does not fit into single request
+		name := string(rune('A' + i))
+		b.Add(b.Uint32(i * 2).Name(name))
+		values[name] = uint32(i)
+	}
+
+	reqs, err := b.WriteHoldingRegistersRTU(values)
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+	assert.Len(t, reqs[0].Request.(*packet.WriteMultipleRegistersRequestRTU).Data, 122*2)
+	assert.Equal(t, uint16(122), reqs[1].StartAddress)
+}
+
+func TestBuilder_WriteCoilsTCP(t *testing.T) {
+	b := NewRequestBuilder("localhost:502", 1)
+	b.Add(b.Coil(10).Name("pump"))
+	b.Add(b.Coil(11).Name("valve"))
+	b.Add(b.Coil(20).Name("alarm"))
+
+	reqs, err := b.WriteCoilsTCP(map[string]interface{}{"pump": true, "valve": false, "alarm": true})
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+	first := reqs[0].Request.(*packet.WriteMultipleCoilsRequestTCP)
+	assert.Equal(t, uint16(10), first.StartAddress)
+	assert.Equal(t, uint16(2), first.CoilCount)
+	assert.Equal(t, []byte{0b01}, first.Data)
+	assert.Equal(t, uint16(20), reqs[1].StartAddress)
+}
+
+func TestBuilder_WriteRequests_errors(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenCoils   bool
+		when        map[string]interface{}
+		expectError string
+	}{
+		{
+			name:        "nok, empty values",
+			when:        map[string]interface{}{},
+			expectError: "write values can not be empty",
+		},
+		{
+			name:        "nok, unknown field",
+			when:        map[string]interface{}{"unknown": uint16(1)},
+			expectError: "write field not found: 'unknown'",
+		},
+		{
+			name:        "nok, partial register field",
+			when:        map[string]interface{}{"flag": true},
+			expectError: "write field occupies only part of the register. name: 'flag'",
+		},
+		{
+			name:        "nok, coil field with register write",
+			when:        map[string]interface{}{"pump": true},
+			expectError: "write field type is not supported for the request. name: 'pump'",
+		},
+		{
+			name:        "nok, register field with coil write",
+			whenCoils:   true,
+			when:        map[string]interface{}{"setpoint": uint16(1)},
+			expectError: "write field type is not supported for the request. name: 'setpoint'",
+		},
+		{
+			name:        "nok, coil value is not bool",
+			whenCoils:   true,
+			when:        map[string]interface{}{"pump": 1},
+			expectError: "write coil value must be bool. name: 'pump'",
+		},
+		{
+			name:        "nok, overlapping fields",
+			when:        map[string]interface{}{"setpoint": uint16(1), "setpoint_low": uint16(1)},
+			expectError: "write fields overlap. name: 'setpoint_low'",
+		},
+		{
+			name:        "nok, marshalling fails",
+			when:        map[string]interface{}{"setpoint": "x"},
+			expectError: "write field marshalling failed. name: 'setpoint' err: value is not numeric, got: string",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewRequestBuilder("localhost:502", 1)
+			b.Add(b.Uint32(100).Name("setpoint"))
+			b.Add(b.Uint16(101).Name("setpoint_low"))
+			b.Add(b.Bit(102, 1).Name("flag"))
+			b.Add(b.Coil(10).Name("pump"))
+
+			var err error
+			if tc.whenCoils {
+				_, err = b.WriteCoilsTCP(tc.when)
+			} else {
+				_, err = b.WriteHoldingRegistersTCP(tc.when)
+			}
+			assert.EqualError(t, err, tc.expectError)
+		})
+	}
+}
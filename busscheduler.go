@@ -0,0 +1,127 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"sync"
+)
+
+// BusScheduler coordinates access to single shared bus (i.e. RS-485 serial line used by SerialClient) by multiple
+// independent users. Each user registers its own BusHandle and sends requests through it. Only one request is on the
+// bus at the time. When bus becomes free, waiting handle with the highest priority is served next and handles with
+// same priority are served in round-robin order so no user can starve others with equal priority.
+type BusScheduler struct {
+	client Doer
+
+	mu       sync.Mutex
+	busy     bool
+	handles  []*BusHandle
+	lastUsed int
+}
+
+// BusHandle is registered user of BusScheduler. BusHandle implements Doer and can be used instead of Client.
+type BusHandle struct {
+	scheduler *BusScheduler
+	index     int
+	priority  int
+	waiting   []chan struct{}
+}
+
+// NewBusScheduler creates new instance of BusScheduler for given client
+func NewBusScheduler(client Doer) *BusScheduler {
+	return &BusScheduler{
+		client:   client,
+		lastUsed: -1,
+	}
+}
+
+// Register registers new user of the bus with given priority. Higher priority handles are served before lower
+// priority handles.
+func (s *BusScheduler) Register(priority int) *BusHandle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := &BusHandle{scheduler: s, index: len(s.handles), priority: priority}
+	s.handles = append(s.handles, h)
+	return h
+}
+
+// Do waits for its turn on the bus, sends given Modbus request to modbus server and returns parsed Response.
+func (h *BusHandle) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	if req == nil {
+		return nil, errors.New("request can not be nil")
+	}
+	if err := h.scheduler.acquire(ctx, h); err != nil {
+		return nil, err
+	}
+	defer h.scheduler.release()
+
+	return h.scheduler.client.Do(ctx, req)
+}
+
+func (s *BusScheduler) acquire(ctx context.Context, h *BusHandle) error {
+	s.mu.Lock()
+	if !s.busy {
+		s.busy = true
+		s.lastUsed = h.index
+		s.mu.Unlock()
+		return nil
+	}
+	turn := make(chan struct{})
+	h.waiting = append(h.waiting, turn)
+	s.mu.Unlock()
+
+	select {
+	case <-turn:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, w := range h.waiting {
+		if w == turn {
+			h.waiting = append(h.waiting[:i], h.waiting[i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// our turn was granted at the same time as context was cancelled. pass the bus on to the next waiter.
+	s.releaseLocked()
+	return ctx.Err()
+}
+
+func (s *BusScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked()
+}
+
+func (s *BusScheduler) releaseLocked() {
+	next := s.nextLocked()
+	if next == nil {
+		s.busy = false
+		return
+	}
+	turn := next.waiting[0]
+	next.waiting = next.waiting[1:]
+	s.lastUsed = next.index
+	close(turn) // bus stays busy and is handed over to the waiter
+}
+
+// nextLocked returns waiting handle with the highest priority. Handles with equal priority are picked in round-robin
+// order starting from handle after the last served handle.
+func (s *BusScheduler) nextLocked() *BusHandle {
+	var next *BusHandle
+	count := len(s.handles)
+	for i := 1; i <= count; i++ {
+		h := s.handles[(s.lastUsed+i+count)%count]
+		if len(h.waiting) == 0 {
+			continue
+		}
+		if next == nil || h.priority > next.priority {
+			next = h
+		}
+	}
+	return next
+}
@@ -0,0 +1,104 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBusScheduler_servesByPriorityAndRoundRobin(t *testing.T) {
+	unblock := make(chan struct{})
+	var mu sync.Mutex
+	var order []uint8
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		r := req.(*packet.ReadHoldingRegistersRequestRTU)
+		if r.UnitID == 0 {
+			<-unblock // first request keeps bus busy until all other requests are queued
+		}
+		mu.Lock()
+		order = append(order, r.UnitID)
+		mu.Unlock()
+		return nil, nil
+	})
+
+	scheduler := NewBusScheduler(client)
+	low1 := scheduler.Register(0)
+	low2 := scheduler.Register(0)
+	high := scheduler.Register(10)
+
+	var wg sync.WaitGroup
+	send := func(h *BusHandle, unitID uint8) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := packet.NewReadHoldingRegistersRequestRTU(unitID, 1, 1)
+			_, err := h.Do(context.Background(), req)
+			assert.NoError(t, err)
+		}()
+	}
+	waitQueued := func(h *BusHandle, n int) {
+		assert.Eventually(t, func() bool {
+			scheduler.mu.Lock()
+			defer scheduler.mu.Unlock()
+			return len(h.waiting) == n
+		}, time.Second, time.Millisecond)
+	}
+
+	send(low1, 0) // occupies the bus
+	assert.Eventually(t, func() bool {
+		scheduler.mu.Lock()
+		defer scheduler.mu.Unlock()
+		return scheduler.busy
+	}, time.Second, time.Millisecond)
+
+	send(low1, 11)
+	waitQueued(low1, 1)
+	send(low1, 12)
+	waitQueued(low1, 2)
+	send(low2, 21)
+	waitQueued(low2, 1)
+	send(high, 31)
+	waitQueued(high, 1)
+
+	close(unblock)
+	wg.Wait()
+
+	assert.Equal(t, []uint8{0, 31, 11, 21, 12}, order)
+}
+
+func TestBusHandle_Do_contextCancelledWhileWaiting(t *testing.T) {
+	unblock := make(chan struct{})
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		<-unblock
+		return nil, nil
+	})
+	scheduler := NewBusScheduler(client)
+	h1 := scheduler.Register(0)
+	h2 := scheduler.Register(0)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := packet.NewReadHoldingRegistersRequestRTU(1, 1, 1)
+		_, _ = h1.Do(context.Background(), req)
+	}()
+	assert.Eventually(t, func() bool {
+		scheduler.mu.Lock()
+		defer scheduler.mu.Unlock()
+		return scheduler.busy
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := packet.NewReadHoldingRegistersRequestRTU(2, 1, 1)
+	_, err := h2.Do(ctx, req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, h2.waiting, 0)
+
+	close(unblock)
+	<-done
+	assert.False(t, scheduler.busy)
+}
@@ -0,0 +1,133 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"sync"
+	"time"
+)
+
+// CachingClient sits in front of Client and deduplicates identical read requests (FC1/FC2/FC3/FC4). Response to read
+// request is cached for TTL and identical read requests (same protocol, unit ID, function code, start address and
+// quantity) within that time are answered from the cache without sending anything over the wire. Identical requests
+// arriving while the first one is still in flight wait for its response. Errors are not cached. When the request in
+// flight fails due to its caller context being canceled or timing out, waiting callers retry with their own context.
+//
+// Other requests (i.e. writes) are passed through to the client as is and clear the whole cache before and after the
+// request is sent so following reads see values written to the device. Reads in flight during the write are not cached.
+//
+// This is meant for applications where multiple independent components poll the same registers.
+//
+// NB: cached response is shared between callers and must not be modified. For Modbus TCP cached response contains
+// transaction ID of the request that was actually sent.
+type CachingClient struct {
+	client  Doer
+	ttl     time.Duration
+	timeNow func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+}
+
+type cacheKey struct {
+	isRTU        bool
+	isASCII      bool
+	unitID       uint8
+	functionCode uint8
+	startAddress uint16
+	quantity     uint16
+}
+
+type cacheEntry struct {
+	done      chan struct{}
+	completed bool
+	expiresAt time.Time
+
+	response packet.Response
+	err      error
+}
+
+// NewCachingClient creates new instance of CachingClient caching read responses for given TTL
+func NewCachingClient(client Doer, ttl time.Duration) *CachingClient {
+	return &CachingClient{
+		client:  client,
+		ttl:     ttl,
+		timeNow: time.Now,
+		entries: map[cacheKey]*cacheEntry{},
+	}
+}
+
+// Do sends given Modbus request to modbus server and returns parsed Response. Read requests are answered from the
+// cache when identical read request was done within TTL.
+func (c *CachingClient) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	if req == nil {
+		return nil, errors.New("request can not be nil")
+	}
+	info, ok := asReadRequest(req)
+	if !ok {
+		c.Invalidate()
+		resp, err := c.client.Do(ctx, req)
+		// reads sent while write was in flight could have returned values from before the write
+		c.Invalidate()
+		return resp, err
+	}
+	key := cacheKey{
+		isRTU:        info.isRTU,
+		isASCII:      info.isASCII,
+		unitID:       info.unitID,
+		functionCode: info.functionCode,
+		startAddress: info.startAddress,
+		quantity:     info.quantity,
+	}
+
+	c.mu.Lock()
+	entry, exists := c.entries[key]
+	if exists && entry.completed && !c.timeNow().Before(entry.expiresAt) {
+		exists = false
+	}
+	if exists {
+		c.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-entry.done:
+			if isContextError(entry.err) && ctx.Err() == nil {
+				// request failed because first caller context ended, this caller can still retry with its own context
+				return c.Do(ctx, req)
+			}
+			return entry.response, entry.err
+		}
+	}
+	entry = &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	resp, err := c.client.Do(ctx, req)
+
+	c.mu.Lock()
+	entry.response = resp
+	entry.err = err
+	entry.completed = true
+	entry.expiresAt = c.timeNow().Add(c.ttl)
+	if err != nil && c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+
+	return resp, err
+}
+
+// Invalidate removes all cached responses. Requests in flight are removed from the cache so their responses are not
+// cached but callers already waiting for them still receive their response.
+func (c *CachingClient) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
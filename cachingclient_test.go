@@ -0,0 +1,236 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCachingClient_Do_cachesReadsWithinTTL(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{10: 1, 11: 2}}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewCachingClient(device, time.Second)
+	client.timeNow = func() time.Time { return now }
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 2)
+	resp, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x1, 0x0, 0x2}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+
+	device.registers[10] = 5
+	now = now.Add(999 * time.Millisecond)
+	req2, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 2)
+	resp, err = client.Do(context.Background(), req2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x1, 0x0, 0x2}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+	assert.Len(t, device.requests, 1)
+
+	// different quantity is different request
+	req3, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err = client.Do(context.Background(), req3)
+	assert.NoError(t, err)
+	assert.Len(t, device.requests, 2)
+
+	now = now.Add(1 * time.Millisecond)
+	resp, err = client.Do(context.Background(), req2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x5, 0x0, 0x2}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+	assert.Len(t, device.requests, 3)
+}
+
+func TestCachingClient_Do_writeInvalidatesCache(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{10: 1}}
+	client := NewCachingClient(device, time.Hour)
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+
+	write, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	_, err = client.Do(context.Background(), write)
+	assert.NoError(t, err)
+
+	resp, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xca, 0xfe}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+	assert.Len(t, device.requests, 3)
+}
+
+func TestCachingClient_Do_readInFlightDuringWriteIsNotCached(t *testing.T) {
+	readStarted := make(chan struct{})
+	releaseRead := make(chan struct{})
+	reads := 0
+	client := NewCachingClient(doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		if req.FunctionCode() == packet.FunctionWriteSingleRegister {
+			return &packet.WriteSingleRegisterResponseRTU{}, nil
+		}
+		reads++
+		if reads == 1 {
+			close(readStarted)
+			<-releaseRead
+		}
+		return &packet.ReadHoldingRegistersResponseRTU{
+			ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{UnitID: 1, RegisterByteLen: 2, Data: []byte{0x0, byte(reads)}},
+		}, nil
+	}), time.Hour)
+
+	req, _ := packet.NewReadHoldingRegistersRequestRTU(1, 10, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resp, err := client.Do(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x0, 0x1}, resp.(*packet.ReadHoldingRegistersResponseRTU).Data)
+	}()
+	<-readStarted
+
+	write, _ := packet.NewWriteSingleRegisterRequestRTU(1, 10, []byte{0xca, 0xfe})
+	_, err := client.Do(context.Background(), write)
+	assert.NoError(t, err)
+
+	close(releaseRead)
+	<-done
+
+	resp, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x2}, resp.(*packet.ReadHoldingRegistersResponseRTU).Data)
+	assert.Equal(t, 2, reads)
+}
+
+func TestCachingClient_Do_readDuringWriteIsInvalidatedAfterWrite(t *testing.T) {
+	writeStarted := make(chan struct{})
+	releaseWrite := make(chan struct{})
+	reads := 0
+	client := NewCachingClient(doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		if req.FunctionCode() == packet.FunctionWriteSingleRegister {
+			close(writeStarted)
+			<-releaseWrite
+			return &packet.WriteSingleRegisterResponseRTU{}, nil
+		}
+		reads++
+		return &packet.ReadHoldingRegistersResponseRTU{
+			ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{UnitID: 1, RegisterByteLen: 2, Data: []byte{0x0, byte(reads)}},
+		}, nil
+	}), time.Hour)
+
+	write, _ := packet.NewWriteSingleRegisterRequestRTU(1, 10, []byte{0xca, 0xfe})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := client.Do(context.Background(), write)
+		assert.NoError(t, err)
+	}()
+	<-writeStarted
+
+	req, _ := packet.NewReadHoldingRegistersRequestRTU(1, 10, 1)
+	_, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+
+	close(releaseWrite)
+	<-done
+
+	resp, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x2}, resp.(*packet.ReadHoldingRegistersResponseRTU).Data)
+	assert.Equal(t, 2, reads)
+}
+
+func TestCachingClient_Do_errorsAreNotCached(t *testing.T) {
+	calls := 0
+	client := NewCachingClient(doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		calls++
+		return nil, errors.New("failure")
+	}), time.Hour)
+
+	req, _ := packet.NewReadCoilsRequestRTU(1, 10, 1)
+	_, err := client.Do(context.Background(), req)
+	assert.EqualError(t, err, "failure")
+	_, err = client.Do(context.Background(), req)
+	assert.EqualError(t, err, "failure")
+	assert.Equal(t, 2, calls)
+}
+
+func TestCachingClient_Do_deduplicatesInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	client := NewCachingClient(doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		calls++
+		close(started)
+		<-release
+		return &packet.ReadInputRegistersResponseRTU{
+			ReadInputRegistersResponse: packet.ReadInputRegistersResponse{UnitID: 1, RegisterByteLen: 2, Data: []byte{0x0, 0x7}},
+		}, nil
+	}), time.Hour)
+
+	req, _ := packet.NewReadInputRegistersRequestRTU(1, 10, 1)
+	var wg sync.WaitGroup
+	responses := make([]packet.Response, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[0], _ = client.Do(context.Background(), req)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[1], _ = client.Do(context.Background(), req)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+	assert.Same(t, responses[0], responses[1])
+}
+
+func TestCachingClient_Do_waiterRetriesWhenFirstCallerContextEnds(t *testing.T) {
+	started := make(chan struct{}, 2)
+	var mu sync.Mutex
+	calls := 0
+	client := NewCachingClient(doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		mu.Lock()
+		calls++
+		call := calls
+		mu.Unlock()
+		if call == 1 {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &packet.ReadCoilsResponseRTU{
+			ReadCoilsResponse: packet.ReadCoilsResponse{UnitID: 1, CoilsByteLength: 1, Data: []byte{0x1}},
+		}, nil
+	}), time.Hour)
+
+	req, _ := packet.NewReadCoilsRequestRTU(1, 10, 1)
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		_, err := client.Do(firstCtx, req)
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+	<-started
+
+	waiterDone := make(chan struct{})
+	var resp packet.Response
+	var err error
+	go func() {
+		defer close(waiterDone)
+		resp, err = client.Do(context.Background(), req)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-firstDone
+	<-waiterDone
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x1}, resp.(*packet.ReadCoilsResponseRTU).Data)
+	assert.Equal(t, 2, calls)
+}
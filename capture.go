@@ -0,0 +1,258 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// CaptureLinkTypeUser0 is pcapng link type DLT_USER0 (147). Configure Wireshark to decode it with `mbtcp` (Modbus TCP)
+// dissector: Preferences -> Protocols -> DLT_USER.
+const CaptureLinkTypeUser0 = uint16(147)
+
+const (
+	pcapngBlockSHB          = uint32(0x0A0D0D0A)
+	pcapngBlockIDB          = uint32(0x00000001)
+	pcapngBlockEPB          = uint32(0x00000006)
+	pcapngByteOrderMagic    = uint32(0x1A2B3C4D)
+	pcapngOptionEPBFlags    = uint16(2)
+	pcapngFlagInbound       = uint32(1)
+	pcapngFlagOutbound      = uint32(2)
+	pcapngFlagDirectionMask = uint32(3)
+	maxCaptureBlockLength   = 1 << 20
+)
+
+// CaptureDirection is direction of captured frame
+type CaptureDirection uint8
+
+const (
+	// CaptureDirectionSent is frame sent by the client
+	CaptureDirectionSent CaptureDirection = 1
+	// CaptureDirectionReceived is frame received by the client
+	CaptureDirectionReceived CaptureDirection = 2
+)
+
+// CaptureRecord is single captured frame
+type CaptureRecord struct {
+	Time      time.Time
+	Direction CaptureDirection
+	Data      []byte
+}
+
+// CaptureWriter writes frames sent and received by client to pcapng file with user-defined link type. CaptureWriter
+// implements ClientHooks so it can be used as ClientConfig.Hooks. Bytes received from the server are collected and
+// written as single record when response is parsed or next request is written.
+//
+// Captures can be opened with Wireshark or read with CaptureReader and replayed into parsers (i.e.
+// packet.ParseTCPResponse) when debugging vendor devices.
+type CaptureWriter struct {
+	timeNow func() time.Time
+
+	mu          sync.Mutex
+	w           io.Writer
+	err         error
+	pending     []byte
+	pendingTime time.Time
+}
+
+// NewCaptureWriter creates new CaptureWriter writing pcapng section and interface header with given link type to w
+func NewCaptureWriter(w io.Writer, linkType uint16) (*CaptureWriter, error) {
+	shb := make([]byte, 28)
+	binary.LittleEndian.PutUint32(shb[0:4], pcapngBlockSHB)
+	binary.LittleEndian.PutUint32(shb[4:8], 28)
+	binary.LittleEndian.PutUint32(shb[8:12], pcapngByteOrderMagic)
+	binary.LittleEndian.PutUint16(shb[12:14], 1)                  // major version
+	binary.LittleEndian.PutUint16(shb[14:16], 0)                  // minor version
+	binary.LittleEndian.PutUint64(shb[16:24], 0xFFFFFFFFFFFFFFFF) // section length is not specified
+	binary.LittleEndian.PutUint32(shb[24:28], 28)
+
+	idb := make([]byte, 20)
+	binary.LittleEndian.PutUint32(idb[0:4], pcapngBlockIDB)
+	binary.LittleEndian.PutUint32(idb[4:8], 20)
+	binary.LittleEndian.PutUint16(idb[8:10], linkType)
+	binary.LittleEndian.PutUint32(idb[12:16], 0) // no snap length limit
+	binary.LittleEndian.PutUint32(idb[16:20], 20)
+
+	if _, err := w.Write(append(shb, idb...)); err != nil {
+		return nil, err
+	}
+	return &CaptureWriter{timeNow: time.Now, w: w}, nil
+}
+
+// BeforeWrite captures frame sent to the server
+func (c *CaptureWriter) BeforeWrite(toWrite []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flushPending()
+	c.write(CaptureRecord{Time: c.timeNow(), Direction: CaptureDirectionSent, Data: toWrite})
+}
+
+// AfterEachRead collects bytes received from the server
+func (c *CaptureWriter) AfterEachRead(received []byte, n int, err error) {
+	if n <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.pending) == 0 {
+		c.pendingTime = c.timeNow()
+	}
+	c.pending = append(c.pending, received[:n]...)
+}
+
+// BeforeParse writes collected received bytes as single record
+func (c *CaptureWriter) BeforeParse(received []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flushPending()
+}
+
+// Flush writes collected received bytes (i.e. Modbus exception response that is not parsed) as record and returns
+// first error that occurred while writing the capture.
+func (c *CaptureWriter) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flushPending()
+	return c.err
+}
+
+// WriteRecord writes given record to the capture
+func (c *CaptureWriter) WriteRecord(record CaptureRecord) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.write(record)
+	return c.err
+}
+
+func (c *CaptureWriter) flushPending() {
+	if len(c.pending) == 0 {
+		return
+	}
+	c.write(CaptureRecord{Time: c.pendingTime, Direction: CaptureDirectionReceived, Data: c.pending})
+	c.pending = c.pending[:0]
+}
+
+func (c *CaptureWriter) write(record CaptureRecord) {
+	if c.err != nil {
+		return
+	}
+	dataLen := len(record.Data)
+	paddedLen := (dataLen + 3) &^ 3
+	blockLen := 28 + paddedLen + 12 + 4 // header + data + options + trailing length
+
+	b := make([]byte, blockLen)
+	binary.LittleEndian.PutUint32(b[0:4], pcapngBlockEPB)
+	binary.LittleEndian.PutUint32(b[4:8], uint32(blockLen))
+	binary.LittleEndian.PutUint32(b[8:12], 0) // interface ID
+	ts := uint64(record.Time.UnixMicro())
+	binary.LittleEndian.PutUint32(b[12:16], uint32(ts>>32))
+	binary.LittleEndian.PutUint32(b[16:20], uint32(ts))
+	binary.LittleEndian.PutUint32(b[20:24], uint32(dataLen))
+	binary.LittleEndian.PutUint32(b[24:28], uint32(dataLen))
+	copy(b[28:], record.Data)
+
+	opts := b[28+paddedLen:]
+	flags := pcapngFlagInbound
+	if record.Direction == CaptureDirectionSent {
+		flags = pcapngFlagOutbound
+	}
+	binary.LittleEndian.PutUint16(opts[0:2], pcapngOptionEPBFlags)
+	binary.LittleEndian.PutUint16(opts[2:4], 4)
+	binary.LittleEndian.PutUint32(opts[4:8], flags)
+	// opts[8:12] is end of options (zero code and length)
+	binary.LittleEndian.PutUint32(b[blockLen-4:], uint32(blockLen))
+
+	_, c.err = c.w.Write(b)
+}
+
+// CaptureReader reads records from pcapng capture written by CaptureWriter
+type CaptureReader struct {
+	r        io.Reader
+	linkType uint16
+}
+
+// NewCaptureReader creates new CaptureReader
+func NewCaptureReader(r io.Reader) *CaptureReader {
+	return &CaptureReader{r: r}
+}
+
+// LinkType returns link type of the capture interface. Valid after first record is read.
+func (c *CaptureReader) LinkType() uint16 {
+	return c.linkType
+}
+
+// Next returns next captured record. io.EOF is returned when there are no more records.
+func (c *CaptureReader) Next() (CaptureRecord, error) {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(c.r, header); err != nil {
+			return CaptureRecord{}, err
+		}
+		blockType := binary.LittleEndian.Uint32(header[0:4])
+		blockLen := binary.LittleEndian.Uint32(header[4:8])
+		if blockLen < 12 || blockLen%4 != 0 || blockLen > maxCaptureBlockLength {
+			return CaptureRecord{}, fmt.Errorf("invalid capture block length: %v", blockLen)
+		}
+		body := make([]byte, blockLen-8)
+		if _, err := io.ReadFull(c.r, body); err != nil {
+			return CaptureRecord{}, fmt.Errorf("failed to read capture block: %w", err)
+		}
+		body = body[:len(body)-4] // trailing block length
+
+		switch blockType {
+		case pcapngBlockSHB:
+			if len(body) < 4 || binary.LittleEndian.Uint32(body[0:4]) != pcapngByteOrderMagic {
+				return CaptureRecord{}, errors.New("capture is not little-endian pcapng")
+			}
+		case pcapngBlockIDB:
+			if len(body) >= 2 {
+				c.linkType = binary.LittleEndian.Uint16(body[0:2])
+			}
+		case pcapngBlockEPB:
+			return parseCaptureEPB(body)
+		}
+	}
+}
+
+func parseCaptureEPB(body []byte) (CaptureRecord, error) {
+	if len(body) < 20 {
+		return CaptureRecord{}, errors.New("capture packet block is too short")
+	}
+	ts := uint64(binary.LittleEndian.Uint32(body[4:8]))<<32 | uint64(binary.LittleEndian.Uint32(body[8:12]))
+	dataLen := int(binary.LittleEndian.Uint32(body[12:16]))
+	paddedLen := (dataLen + 3) &^ 3
+	if 20+dataLen > len(body) {
+		return CaptureRecord{}, errors.New("capture packet block data is out of bounds")
+	}
+	record := CaptureRecord{
+		Time:      time.UnixMicro(int64(ts)),
+		Direction: CaptureDirectionReceived,
+		Data:      append([]byte(nil), body[20:20+dataLen]...),
+	}
+	if 20+paddedLen > len(body) {
+		return record, nil
+	}
+	opts := body[20+paddedLen:]
+	for len(opts) >= 4 {
+		code := binary.LittleEndian.Uint16(opts[0:2])
+		length := int(binary.LittleEndian.Uint16(opts[2:4]))
+		if code == 0 || 4+length > len(opts) {
+			break
+		}
+		if code == pcapngOptionEPBFlags && length == 4 {
+			if binary.LittleEndian.Uint32(opts[4:8])&pcapngFlagDirectionMask == pcapngFlagOutbound {
+				record.Direction = CaptureDirectionSent
+			}
+		}
+		opts = opts[4+((length+3)&^3):]
+	}
+	return record, nil
+}
@@ -0,0 +1,80 @@
+package modbus
+
+import (
+	"bytes"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCaptureWriter_roundTrip(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 123456000).In(time.UTC)
+
+	var buf bytes.Buffer
+	w, err := NewCaptureWriter(&buf, CaptureLinkTypeUser0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	w.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	request := exampleFC1Request().Bytes()
+	response := []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1}
+	exception := []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x3, 0x1, 0x81, 0x2}
+
+	w.BeforeWrite(request)
+	w.AfterEachRead(response[:4], 4, nil)
+	w.AfterEachRead(nil, 0, nil)
+	w.AfterEachRead(response[4:], 7, nil)
+	w.BeforeParse(response)
+	w.BeforeWrite(request)
+	w.AfterEachRead(exception, 9, nil) // exception responses are not parsed
+	assert.NoError(t, w.Flush())
+
+	r := NewCaptureReader(&buf)
+	var records []CaptureRecord
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		records = append(records, record)
+	}
+
+	assert.Equal(t, CaptureLinkTypeUser0, r.LinkType())
+	assert.Equal(t, []CaptureRecord{
+		{Time: exampleNow.Local(), Direction: CaptureDirectionSent, Data: request},
+		{Time: exampleNow.Local(), Direction: CaptureDirectionReceived, Data: response},
+		{Time: exampleNow.Local(), Direction: CaptureDirectionSent, Data: request},
+		{Time: exampleNow.Local(), Direction: CaptureDirectionReceived, Data: exception},
+	}, records)
+
+	resp, err := packet.ParseTCPResponse(records[1].Data)
+	assert.NoError(t, err)
+	assert.Equal(t, exampleFC1Response(), resp)
+}
+
+func TestCaptureReader_Next_invalidBlock(t *testing.T) {
+	r := NewCaptureReader(bytes.NewReader([]byte{0x0A, 0x0D, 0x0D, 0x0A, 0x3, 0x0, 0x0, 0x0}))
+
+	_, err := r.Next()
+
+	assert.EqualError(t, err, "invalid capture block length: 3")
+}
+
+func TestCaptureReader_Next_bigEndian(t *testing.T) {
+	r := NewCaptureReader(bytes.NewReader([]byte{
+		0x0A, 0x0D, 0x0D, 0x0A, 0x10, 0x0, 0x0, 0x0,
+		0x1A, 0x2B, 0x3C, 0x4D, 0x10, 0x0, 0x0, 0x0,
+	}))
+
+	_, err := r.Next()
+
+	assert.EqualError(t, err, "capture is not little-endian pcapng")
+}
@@ -2,7 +2,9 @@ package modbus
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"github.com/aldas/go-modbus-client/packet"
 	"io"
 	"net"
@@ -25,10 +27,13 @@ const (
 	//   TCP MODBUS ADU = 253 bytes + MBAP (7 bytes) = 260 bytes.
 	tcpPacketMaxLen = 7 + 253 // 2 trans id + 2 proto + 2 pdu len + 1 unit id + 253 max data len
 	rtuPacketMaxLen = 256     // 1 unit id + 253 max data len + 2 crc
+	// asciiPacketMaxLen is maximum length in bytes that valid Modbus ASCII packet can be
+	asciiPacketMaxLen = 1 + 2*(1+253+1) + 2 // ':' + hex encoded (1 unit id + 253 max data len + 1 lrc) + CRLF
 
 	defaultWriteTimeout   = 1 * time.Second
 	defaultReadTimeout    = 2 * time.Second
 	defaultConnectTimeout = 1 * time.Second
+	defaultTCPKeepAlive   = 15 * time.Second
 )
 
 // ErrPacketTooLong is error indicating that modbus server sent amount of data that is bigger than any modbus packet could be
@@ -45,15 +50,73 @@ type Client struct {
 	writeTimeout time.Duration
 	// readTimeout is total amount of time reading the response can take before client returns error
 	readTimeout time.Duration
-
-	dialContextFunc     func(ctx context.Context, address string) (net.Conn, error)
+	// connectTimeout is maximum amount of time connecting to the server can take
+	connectTimeout time.Duration
+	// tcpKeepAlive is interval between TCP keep-alive probes
+	tcpKeepAlive time.Duration
+
+	dialContextFunc func(ctx context.Context, address string) (net.Conn, error)
+	// dialOptions are default proxy and local address options used when server address does not set them
+	dialOptions dialOptions
+	// customDial is true when dial function is given with ClientConfig.DialContextFunc
+	customDial          bool
+	dnsCache            *DNSCache
+	tlsConfig           *tls.Config
 	asProtocolErrorFunc func(data []byte) error
 	parseResponseFunc   func(data []byte) (packet.Response, error)
+	rawResponses        bool
+	validateResponses   bool
+	requestTransformer  RequestTransformer
+	isRTU               bool
+	// responseLengthFunc returns length of response frame determined from already received bytes
+	responseLengthFunc func(received []byte, expectedLen int) int
+	// transactionIDStrategy is how transaction IDs are assigned to TCP requests
+	transactionIDStrategy TransactionIDStrategy
+	// transactionIDGenerator sets transaction IDs of TCP requests. When nil request transaction ID is sent as is.
+	transactionIDGenerator packet.TransactionIDGenerator
+	// allowZeroTransactionID makes client accept responses with transaction ID 0 as response to any request
+	allowZeroTransactionID bool
+	// packetMaxLen is maximum length in bytes of valid response packet
+	packetMaxLen int
+	// pipelineWindow is maximum amount of requests DoPipelined keeps in flight
+	pipelineWindow int
+	// udpRetries is how many times request is resent over UDP when response is not received within read timeout
+	udpRetries int
+	// isUDP is true when client is connected to the server over UDP
+	isUDP           bool
+	broadcastWrites bool
+	// delayBetweenRequests is default delay between requests when server address does not set it
+	delayBetweenRequests time.Duration
+	// delayAfterWrite is minimal delay after write request from device quirks
+	delayAfterWrite time.Duration
+	requestDelay    requestDelayer
+	// configErr is configuration error returned by Connect
+	configErr error
+
+	// idleProbeInterval is how long connection can be idle before idle probe is sent
+	idleProbeInterval time.Duration
+	idleProbeRequest  packet.Request
+	// idleProbeStop stops idle probe goroutine of current connection
+	idleProbeStop chan struct{}
+	// lastActivity is time when request was last written to the connection
+	lastActivity time.Time
 
 	mu      sync.RWMutex
 	address string
 	conn    net.Conn
 	hooks   ClientHooks
+	metrics ClientMetrics
+	onEvent func(event ClientEvent)
+
+	// asyncMu guards queue of requests sent with DoAsync
+	asyncMu      sync.Mutex
+	asyncQueue   []asyncRequest
+	asyncRunning bool
+}
+
+// Doer is interface for sending Modbus request and receiving parsed response. Client and SerialClient implement it.
+type Doer interface {
+	Do(ctx context.Context, req packet.Request) (packet.Response, error)
 }
 
 // ClientHooks allows to log bytes send/received by client.
@@ -70,24 +133,115 @@ type ClientConfig struct {
 	WriteTimeout time.Duration
 	// ReadTimeout is total amount of time reading the response can take before client returns error
 	ReadTimeout time.Duration
+	// ConnectTimeout is maximum amount of time connecting to the server can take. Used only with default DialContextFunc.
+	ConnectTimeout time.Duration
+	// TCPKeepAlive is interval between TCP keep-alive probes of idle connection. Defaults to 15 seconds. Negative value
+	// disables keep-alive probes. Used only with default DialContextFunc.
+	TCPKeepAlive time.Duration
+
+	// IdleProbeInterval enables application level probing of idle connection. When no request has been sent for
+	// IdleProbeInterval, IdleProbeRequest is sent to the server. When probe gets no response (Modbus exception counts
+	// as a response) connection is considered dead (i.e. half-open connection to cellular gateway) and client
+	// reconnects to the server, so dead connection is detected before next request times out. 0 disables probing.
+	IdleProbeInterval time.Duration
+	// IdleProbeRequest is request sent as idle probe (i.e. read of a known register). Request must be of the same
+	// protocol as the client. Defaults to Read Exception Status (FC07) request to unit ID 1.
+	IdleProbeRequest packet.Request
+
+	// DNSCache is used to resolve server host name when connecting. Cache can be shared between multiple clients.
+	// Ignored when DialContextFunc is set.
+	DNSCache *DNSCache
+
+	// TLSConfig is TLS configuration used to connect to Modbus/TCP Security servers with `tls://host:802` addresses.
+	// Set Certificates for X.509 client certificate authentication and RootCAs to verify server certificate. When nil
+	// default configuration is used for `tls://` addresses. See CertificateRole.
+	TLSConfig *tls.Config
+
+	// Proxy is URL of SOCKS5 (`socks5://[user:pass@]host:port`) or HTTP CONNECT (`http://[user:pass@]host:port`)
+	// proxy connections are made through. Can be overridden per server with `proxy` server address option (see
+	// ServerAddressOptionProxy). Can not be used with DialContextFunc.
+	Proxy string
+	// LocalAddress is local IP address (optionally with port) connections are made from. Can be overridden per server
+	// with `local_address` server address option (see ServerAddressOptionLocalAddress). Can not be used with
+	// DialContextFunc.
+	LocalAddress string
 
 	DialContextFunc     func(ctx context.Context, address string) (net.Conn, error)
 	AsProtocolErrorFunc func(data []byte) error
 	ParseResponseFunc   func(data []byte) (packet.Response, error)
 
+	// RawResponses makes Do return RawResponse containing received frame. Modbus exceptions and responses that could
+	// not be parsed (i.e. unknown function codes) are not returned as errors but as RawResponse.
+	RawResponses bool
+
+	// ValidateResponses enables strict validation that received response matches the request: function code, unit ID,
+	// byte count of read responses against requested quantity and echoed address/quantity (or value) of write
+	// responses. Mismatching responses (i.e. stale responses from buggy gateways) result ErrMismatchedResponse error.
+	ValidateResponses bool
+
+	// TransactionIDStrategy is how client assigns MBAP transaction IDs to Modbus TCP requests (see
+	// TransactionIDFromRequest, TransactionIDRandom, TransactionIDSequential and TransactionIDPerConnection). Defaults
+	// to sending request with transaction ID it was created with. Responses have transaction ID request was sent with.
+	// Not used by ClientPool as pool assigns transaction IDs itself.
+	TransactionIDStrategy TransactionIDStrategy
+	// TransactionIDGenerator is custom generator for transaction IDs of Modbus TCP requests. Can not be used together
+	// with TransactionIDStrategy other than TransactionIDFromRequest, Connect returns error when both are set.
+	TransactionIDGenerator packet.TransactionIDGenerator
+	// AllowZeroTransactionID makes client accept responses with transaction ID 0 to requests with any transaction ID.
+	// This is tolerance mode for broken devices that always respond with transaction ID 0. By default responses with
+	// transaction ID not matching the outstanding request are ignored as late responses to earlier requests.
+	AllowZeroTransactionID bool
+
+	// SkipCRCVerification disables CRC verification of received packets for RTU client. This is useful for gateways
+	// that already verify CRC themselves and occasionally recalculate it wrongly.
+	SkipCRCVerification bool
+
+	// RequestTransformer is called with every request before it is sent. It can rewrite i.e. unit ID or addresses of
+	// the request. See RewriteUnitID and OffsetAddress.
+	RequestTransformer RequestTransformer
+
+	// DelayBetweenRequests is minimal delay between consecutive requests. Can be overridden per server with
+	// `delay_between_requests` server address option (see ServerAddressOptionDelayBetweenRequests).
+	DelayBetweenRequests time.Duration
+
+	// PipelineWindow is maximum amount of outstanding requests Client.DoPipelined and Client.DoAsync send over TCP
+	// connection before waiting for responses. Defaults to 1 (no pipelining). Use only with devices that tolerate
+	// pipelined requests.
+	PipelineWindow int
+
+	// UDPRetries is how many times request is resent when client is connected over UDP (`udp://` address) and
+	// response is not received within ReadTimeout. UDP does not retransmit lost datagrams itself. Responses with
+	// transaction ID not matching the request (i.e. late responses to earlier attempts) are ignored.
+	UDPRetries int
+
+	// BroadcastWrites makes client treat write requests to unit ID 0 as broadcast. Broadcast requests are sent without
+	// waiting for response as servers do not respond to them and Do returns nil response and nil error.
+	BroadcastWrites bool
+
+	// QuirksProfile is name of device quirks profile (see RegisterDeviceQuirks) applied to the client. Quirks request
+	// transformer is applied after RequestTransformer. Connect fails when profile is not registered.
+	QuirksProfile string
+
 	Hooks ClientHooks
+	// Metrics receives measurements of connection attempts and requests. See ClientStats.
+	Metrics ClientMetrics
+	// OnEvent is called synchronously with structured client events (connect, disconnect, request start/end,
+	// exception). Handler must not block and must not call Client methods. See ClientEvent.
+	OnEvent func(event ClientEvent)
 }
 
 func defaultClient(conf ClientConfig) *Client {
 	c := &Client{
-		timeNow:      time.Now,
-		writeTimeout: defaultWriteTimeout,
-		readTimeout:  defaultReadTimeout,
+		timeNow:        time.Now,
+		writeTimeout:   defaultWriteTimeout,
+		readTimeout:    defaultReadTimeout,
+		connectTimeout: defaultConnectTimeout,
 
-		dialContextFunc: dialContext,
 		// TCP is our default protocol
 		asProtocolErrorFunc: packet.AsTCPErrorPacket,
 		parseResponseFunc:   packet.ParseTCPResponse,
+		responseLengthFunc:  tcpResponseLength,
+		packetMaxLen:        tcpPacketMaxLen,
 	}
 
 	if conf.WriteTimeout > 0 {
@@ -96,8 +250,37 @@ func defaultClient(conf ClientConfig) *Client {
 	if conf.ReadTimeout > 0 {
 		c.readTimeout = conf.ReadTimeout
 	}
+	if conf.ConnectTimeout > 0 {
+		c.connectTimeout = conf.ConnectTimeout
+	}
+	c.tcpKeepAlive = defaultTCPKeepAlive
+	if conf.TCPKeepAlive != 0 {
+		c.tcpKeepAlive = conf.TCPKeepAlive
+	}
+	c.dialContextFunc = newDialContextFunc(c.connectTimeout, c.tcpKeepAlive)
+	if conf.DNSCache != nil {
+		c.dialContextFunc = conf.DNSCache.dialContextFunc(c.connectTimeout, c.tcpKeepAlive)
+	}
 	if conf.DialContextFunc != nil {
 		c.dialContextFunc = conf.DialContextFunc
+		c.customDial = true
+	}
+	c.dialContextFunc = newTLSDialContextFunc(c.dialContextFunc, conf.TLSConfig, c.connectTimeout)
+	c.dnsCache = conf.DNSCache
+	c.tlsConfig = conf.TLSConfig
+	if conf.Proxy != "" {
+		proxy, err := parseProxyURL(conf.Proxy)
+		if err != nil {
+			c.configErr = fmt.Errorf("invalid proxy: %w", err)
+		}
+		c.dialOptions.proxy = proxy
+	}
+	if conf.LocalAddress != "" {
+		local, err := parseLocalAddress(conf.LocalAddress)
+		if err != nil {
+			c.configErr = fmt.Errorf("invalid local address: %w", err)
+		}
+		c.dialOptions.localAddress = local
 	}
 	if conf.AsProtocolErrorFunc != nil {
 		c.asProtocolErrorFunc = conf.AsProtocolErrorFunc
@@ -108,6 +291,41 @@ func defaultClient(conf ClientConfig) *Client {
 	if conf.Hooks != nil {
 		c.hooks = conf.Hooks
 	}
+	c.metrics = conf.Metrics
+	c.onEvent = conf.OnEvent
+	c.rawResponses = conf.RawResponses
+	c.validateResponses = conf.ValidateResponses
+	c.transactionIDStrategy = conf.TransactionIDStrategy
+	c.transactionIDGenerator = newTransactionIDGenerator(conf.TransactionIDStrategy)
+	if conf.TransactionIDGenerator != nil {
+		if conf.TransactionIDStrategy != TransactionIDFromRequest {
+			c.configErr = errors.New("transaction ID generator and transaction ID strategy can not be used together")
+		}
+		c.transactionIDGenerator = conf.TransactionIDGenerator
+	}
+	c.allowZeroTransactionID = conf.AllowZeroTransactionID
+	c.requestTransformer = conf.RequestTransformer
+	c.delayBetweenRequests = conf.DelayBetweenRequests
+	c.requestDelay.delay = conf.DelayBetweenRequests
+	c.pipelineWindow = 1
+	if conf.PipelineWindow > 1 {
+		c.pipelineWindow = conf.PipelineWindow
+	}
+	if conf.UDPRetries > 0 {
+		c.udpRetries = conf.UDPRetries
+	}
+	c.broadcastWrites = conf.BroadcastWrites
+	c.idleProbeInterval = conf.IdleProbeInterval
+	c.idleProbeRequest = conf.IdleProbeRequest
+	if conf.QuirksProfile != "" {
+		quirks, err := lookupQuirksProfile(conf.QuirksProfile)
+		if err != nil {
+			c.configErr = err
+		}
+		c.requestTransformer = quirks.withRequestTransformer(c.requestTransformer)
+		c.delayAfterWrite = quirks.DelayAfterWrite
+		c.requestDelay.delayAfterWrite = quirks.DelayAfterWrite
+	}
 	return c
 }
 
@@ -132,8 +350,29 @@ func NewRTUClient() *Client {
 // NewRTUClientWithConfig creates new instance of Modbus Client for Modbus RTU protocol with given configuration options
 func NewRTUClientWithConfig(conf ClientConfig) *Client {
 	client := defaultClient(conf)
+	client.isRTU = true
 	client.asProtocolErrorFunc = packet.AsRTUErrorPacket
 	client.parseResponseFunc = packet.ParseRTUResponseWithCRC
+	client.responseLengthFunc = rtuResponseLength
+	if conf.SkipCRCVerification {
+		client.parseResponseFunc = packet.ParseRTUResponse
+	}
+	return client
+}
+
+// NewASCIIClient creates new instance of Modbus Client for Modbus ASCII protocol
+func NewASCIIClient() *Client {
+	return NewASCIIClientWithConfig(ClientConfig{})
+}
+
+// NewASCIIClientWithConfig creates new instance of Modbus Client for Modbus ASCII protocol with given configuration options
+func NewASCIIClientWithConfig(conf ClientConfig) *Client {
+	client := defaultClient(conf)
+	client.isRTU = true // ASCII packets do not have transaction ID either
+	client.packetMaxLen = asciiPacketMaxLen
+	client.asProtocolErrorFunc = packet.AsASCIIErrorPacket
+	client.parseResponseFunc = packet.ParseASCIIResponse
+	client.responseLengthFunc = asciiResponseLength
 	return client
 }
 
@@ -144,28 +383,76 @@ func NewClient(conf ClientConfig) *Client {
 
 // Connect opens network connection to Client to server. Context lifetime is only meant for this call.
 // ctx is to be used for to cancel connection attempt.
+//
+// Address can contain options as query part (i.e. `tcp://192.168.0.1:502?delay_between_requests=50ms`). See
+// ServerAddressOptionDelayBetweenRequests, ServerAddressOptionProxy and ServerAddressOptionLocalAddress. Addresses with
+// `tls://` scheme (i.e. `tls://192.168.0.1:802`) connect to Modbus/TCP Security servers using ClientConfig.TLSConfig.
 func (c *Client) Connect(ctx context.Context, address string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	conn, err := c.dialContextFunc(ctx, address)
+	if c.configErr != nil {
+		return c.configErr
+	}
+	if err := c.connect(ctx, address); err != nil {
+		return err
+	}
+	c.startIdleProbe(address)
+	return nil
+}
+
+// connect opens network connection to the server. Must be called with c.mu locked.
+func (c *Client) connect(ctx context.Context, address string) error {
+	address, opts, err := parseServerAddress(address)
 	if err != nil {
 		return err
 	}
+	dial := c.dialContextFunc
+	if dialOpts := c.dialOptions.merge(opts.dial); dialOpts.isSet() {
+		if c.customDial {
+			return errors.New("proxy and local address can not be used with custom DialContextFunc")
+		}
+		dial = newTLSDialContextFunc(newOptionsDialContextFunc(c.connectTimeout, c.tcpKeepAlive, c.dnsCache, dialOpts), c.tlsConfig, c.connectTimeout)
+	}
+	conn, err := dial(ctx, address)
+	if c.metrics != nil {
+		c.metrics.ConnectDone(address, err)
+	}
+	if c.onEvent != nil {
+		c.onEvent(ConnectEvent{Time: c.timeNow(), ServerAddress: address, Err: err})
+	}
+	if err != nil {
+		return newConnectError(address, c.connectTimeout, err)
+	}
 	c.conn = conn
 	c.address = address
+	c.lastActivity = c.timeNow()
+	if c.transactionIDStrategy == TransactionIDPerConnection {
+		c.transactionIDGenerator = newTransactionIDGenerator(TransactionIDPerConnection)
+	}
+	network, _ := addressExtractor(address)
+	c.isUDP = strings.HasPrefix(network, "udp")
+	c.requestDelay = requestDelayer{delay: c.delayBetweenRequests, delayAfterWrite: c.delayAfterWrite}
+	if opts.hasDelay {
+		c.requestDelay.delay = opts.delayBetweenRequests
+	}
 	return nil
 }
 
-func dialContext(ctx context.Context, address string) (net.Conn, error) {
-	dialer := &net.Dialer{
-		// Timeout is the maximum amount of time a dial will wait for a connect to complete.
-		Timeout: defaultConnectTimeout,
-		// KeepAlive specifies the interval between keep-alive probes for an active network connection.
-		KeepAlive: 15 * time.Second,
+func newDialContextFunc(
+	timeout time.Duration,
+	keepAlive time.Duration,
+) func(ctx context.Context, address string) (net.Conn, error) {
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		dialer := &net.Dialer{
+			// Timeout is the maximum amount of time a dial will wait for a connect to complete.
+			Timeout: timeout,
+			// KeepAlive specifies the interval between keep-alive probes for an active network connection.
+			KeepAlive: keepAlive,
+		}
+		network, addr := addressExtractor(address)
+		return dialer.DialContext(ctx, network, addr)
 	}
-	network, addr := addressExtractor(address)
-	return dialer.DialContext(ctx, network, addr)
 }
 
 func addressExtractor(address string) (string, string) {
@@ -181,10 +468,15 @@ func (c *Client) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.stopIdleProbe()
 	if c.conn == nil {
 		return nil
 	}
-	return c.conn.Close()
+	err := c.conn.Close()
+	if c.onEvent != nil {
+		c.onEvent(DisconnectEvent{Time: c.timeNow(), ServerAddress: c.address, Err: err})
+	}
+	return err
 }
 
 // ClientError indicates errors returned by Client that network related and are possibly retryable
@@ -198,53 +490,207 @@ func (e *ClientError) Error() string { return e.Err.Error() }
 // Unwrap allows unwrapping errors with errors.Is and errors.As
 func (e *ClientError) Unwrap() error { return e.Err }
 
+// GatewayError indicates that Modbus gateway could not route request to the target device. Gateway responds with
+// Gateway Path Unavailable (0x0A) or Gateway Target Device Failed to Respond (0x0B) exception in that case. Unlike
+// other exceptions these are routing errors and not errors reported by the device itself and are retryable.
+//
+// Err is value of type packet.ErrorResponseTCP, packet.ErrorResponseRTU or packet.ErrorResponseASCII
+type GatewayError struct {
+	Code packet.ErrCode
+	Err  error
+}
+
+// Error returns contained error message
+func (e *GatewayError) Error() string { return e.Err.Error() }
+
+// Unwrap allows unwrapping errors with errors.Is and errors.As
+func (e *GatewayError) Unwrap() error { return e.Err }
+
+// IsRetryable returns true as gateway errors are routing errors and request can be retried.
+func (e *GatewayError) IsRetryable() bool { return true }
+
+// IsPathUnavailable returns true when gateway was unable to allocate communication path to target device (0x0A)
+func (e *GatewayError) IsPathUnavailable() bool { return e.Code == packet.ErrGatewayPathUnavailable }
+
+// IsTargetFailedToRespond returns true when target device did not respond to gateway (0x0B)
+func (e *GatewayError) IsTargetFailedToRespond() bool {
+	return e.Code == packet.ErrGatewayTargetedDeviceResponse
+}
+
+// newExceptionError wraps Modbus exception packet into ClientError. Gateway exceptions are additionally wrapped into
+// GatewayError.
+func newExceptionError(errPacket error) error {
+	var code packet.ErrCode
+	switch e := errPacket.(type) {
+	case *packet.ErrorResponseTCP:
+		code = e.Code
+	case *packet.ErrorResponseRTU:
+		code = e.Code
+	case *packet.ErrorResponseASCII:
+		code = e.Code
+	}
+	if code.IsGatewayError() {
+		return &ClientError{Err: &GatewayError{Code: code, Err: errPacket}}
+	}
+	return &ClientError{Err: errPacket}
+}
+
 // Do sends given Modbus request to modbus server and returns parsed Response.
 // ctx is to be used for to cancel connection attempt.
-// On modbus exception nil is returned as response and error wraps value of type packet.ErrorResponseTCP or packet.ErrorResponseRTU
-// User errors.Is and errors.As to check if error wraps packet.ErrorResponseTCP or packet.ErrorResponseRTU
+// On modbus exception nil is returned as response and error wraps value of type packet.ErrorResponseTCP,
+// packet.ErrorResponseRTU or packet.ErrorResponseASCII
+// User errors.Is and errors.As to check if error wraps packet.ErrorResponseTCP, packet.ErrorResponseRTU or
+// packet.ErrorResponseASCII
 func (c *Client) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	return c.doRequest(ctx, req)
+}
+
+func (c *Client) doRequest(ctx context.Context, req packet.Request) (packet.Response, error) {
 	if req == nil {
 		return nil, errors.New("request can not be nil")
 	}
 	if c.conn == nil {
 		return nil, &ErrClientNotConnected
 	}
+	if c.requestTransformer != nil {
+		var err error
+		if req, err = transformRequest(c.requestTransformer, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.requestDelay.wait(ctx, c.timeNow()); err != nil {
+		return nil, err
+	}
+	data := req.Bytes()
+	c.assignTransactionID(data)
+	c.requestStart(data, req)
+	start := c.timeNow()
+	resp, err := c.roundTrip(ctx, req, data)
+	c.requestDone(data, req.FunctionCode(), start, err)
+	return resp, err
+}
 
-	resp, err := c.do(ctx, req.Bytes(), req.ExpectedResponseLength())
+func (c *Client) roundTrip(ctx context.Context, req packet.Request, data []byte) (packet.Response, error) {
+	if c.broadcastWrites && isBroadcastWrite(data, req.FunctionCode(), c.isRTU) {
+		err := c.write(data)
+		c.requestDelay.done(c.timeNow(), true)
+		return nil, err
+	}
+	resp, err := c.do(ctx, data, req.ExpectedResponseLength())
+	c.requestDelay.done(c.timeNow(), isWriteFunctionCode(req.FunctionCode()))
 	if err != nil {
 		return nil, err
 	}
 	if c.hooks != nil {
 		c.hooks.BeforeParse(resp)
 	}
+	if c.validateResponses {
+		if err := validateResponse(data, resp, c.isRTU); err != nil {
+			return nil, err
+		}
+	}
+	if c.rawResponses {
+		return newRawResponse(resp, c.parseResponseFunc), nil
+	}
 	return c.parseResponseFunc(resp)
 }
 
-func (c *Client) do(ctx context.Context, data []byte, expectedLen int) ([]byte, error) {
-	if err := c.conn.SetWriteDeadline(c.timeNow().Add(c.writeTimeout)); err != nil {
-		return nil, err
+// isBroadcastWrite checks if request is write request to broadcast unit ID 0. FC23 is not considered as it reads data.
+func isBroadcastWrite(data []byte, functionCode uint8, isRTU bool) bool {
+	return frameUnitID(data, isRTU) == 0 && isWriteFunctionCode(functionCode) &&
+		functionCode != packet.FunctionReadWriteMultipleRegisters
+}
+
+// readTimeoutContextKey is context.Context key for request read timeout override
+type readTimeoutContextKey struct{}
+
+// ContextWithReadTimeout returns context that overrides client total read timeout for requests sent with that context.
+// This allows reading slow registers (see Field.ReadTimeout and BuilderRequest.ReadTimeout) with extended timeout
+// without changing timeout of other requests. Zero or negative timeout returns given context as is.
+func ContextWithReadTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if timeout <= 0 {
+		return ctx
 	}
-	if c.hooks != nil {
-		c.hooks.BeforeWrite(data)
+	return context.WithValue(ctx, readTimeoutContextKey{}, timeout)
+}
+
+// readTimeoutFromContext returns read timeout override from context or given default timeout
+func readTimeoutFromContext(ctx context.Context, defaultTimeout time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(readTimeoutContextKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return defaultTimeout
+}
+
+// requestDone reports request measurement to metrics and emits request end events
+func (c *Client) requestDone(data []byte, functionCode uint8, start time.Time, err error) {
+	if c.metrics == nil && c.onEvent == nil {
+		return
+	}
+	now := c.timeNow()
+	m := RequestMetric{
+		ServerAddress: c.address,
+		UnitID:        frameUnitID(data, c.isRTU),
+		FunctionCode:  functionCode,
+		RoundTrip:     now.Sub(start),
+		Err:           err,
+	}
+	if c.metrics != nil {
+		c.metrics.RequestDone(m)
 	}
-	if _, err := c.conn.Write(data); err != nil {
-		return nil, &ClientError{Err: err}
+	if c.onEvent == nil {
+		return
 	}
+	c.onEvent(RequestEndEvent{
+		Time:          now,
+		ServerAddress: m.ServerAddress,
+		UnitID:        m.UnitID,
+		FunctionCode:  m.FunctionCode,
+		RoundTrip:     m.RoundTrip,
+		Err:           err,
+	})
+	if code, ok := m.ExceptionCode(); ok {
+		c.onEvent(ExceptionEvent{
+			Time:          now,
+			ServerAddress: m.ServerAddress,
+			UnitID:        m.UnitID,
+			FunctionCode:  m.FunctionCode,
+			ExceptionCode: code,
+		})
+	}
+}
 
+func (c *Client) do(ctx context.Context, data []byte, expectedLen int) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.write(data); err != nil {
+			return nil, err
+		}
+		resp, err := c.read(ctx, data, expectedLen)
+		if c.isUDP && attempt < c.udpRetries && errors.Is(err, errReadTimeoutExceeded) {
+			continue // datagram (request or response) was probably lost. retransmit
+		}
+		return resp, err
+	}
+}
+
+// read reads response to given request data from connection
+func (c *Client) read(ctx context.Context, data []byte, expectedLen int) ([]byte, error) {
 	// make buffer a little bit bigger than would be valid to see problems when somehow more bytes are sent
-	const maxBytes = tcpPacketMaxLen + 10
-	received := [maxBytes]byte{}
+	const bufferLen = asciiPacketMaxLen + 10
+	received := [bufferLen]byte{}
+	maxBytes := c.packetMaxLen + 10
 	total := 0
-	readTimeout := time.After(c.readTimeout)
+	readTimeout := time.After(readTimeoutFromContext(ctx, c.readTimeout))
 	for {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-readTimeout:
-			return nil, &ClientError{Err: errors.New("total read timeout exceeded")}
+			return nil, &ClientError{Err: errReadTimeoutExceeded}
 		default:
 		}
 
@@ -260,14 +706,35 @@ func (c *Client) do(ctx context.Context, data []byte, expectedLen int) ([]byte,
 			return nil, &ClientError{Err: err}
 		}
 		total += n
-		if total > tcpPacketMaxLen {
+		if c.isUDP && !c.isRTU && total >= 2 && !c.matchesTransactionID(data, received[:total]) {
+			total = 0 // every datagram is separate response. ignore responses to other transactions
+			continue
+		}
+		if total > c.packetMaxLen {
 			return nil, &ErrPacketTooLong
 		}
+		if !c.isUDP && !c.isRTU {
+			var waitMore bool
+			var staleErr error
+			total, waitMore, staleErr = c.dropStaleFrames(data, received[:], total, err)
+			if staleErr != nil {
+				return nil, staleErr
+			}
+			if waitMore {
+				continue
+			}
+		}
+		if !c.isRTU && total >= 2 {
+			c.restoreTransactionID(data, received[:total])
+		}
 		// check if we have exactly the error packet. Error packets are shorter than regulars packets
 		if errPacket := c.asProtocolErrorFunc(received[0:total]); errPacket != nil {
-			return nil, &ClientError{Err: errPacket}
+			if c.rawResponses {
+				break
+			}
+			return nil, newExceptionError(errPacket)
 		}
-		if total >= expectedLen {
+		if total >= c.responseLengthFunc(received[:total], expectedLen) {
 			break
 		}
 		if errors.Is(err, io.EOF) {
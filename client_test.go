@@ -3,11 +3,13 @@ package modbus
 import (
 	"context"
 	"errors"
+	"github.com/aldas/go-modbus-client/modbustest"
 	"github.com/aldas/go-modbus-client/packet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io"
 	"net"
+	"os"
 	"testing"
 	"time"
 )
@@ -128,6 +130,19 @@ func TestWithOptions(t *testing.T) {
 	assert.Equal(t, new(mockLogger), client.hooks)
 }
 
+func TestNewRTUClientWithConfig_SkipCRCVerification(t *testing.T) {
+	invalidCRC := []byte{0x10, 0x1, 0x2, 0x1, 0x2, 0xff, 0xff}
+
+	client := NewRTUClientWithConfig(ClientConfig{})
+	_, err := client.parseResponseFunc(invalidCRC)
+	assert.ErrorIs(t, err, packet.ErrInvalidCRC)
+
+	client = NewRTUClientWithConfig(ClientConfig{SkipCRCVerification: true})
+	resp, err := client.parseResponseFunc(invalidCRC)
+	assert.NoError(t, err)
+	assert.Equal(t, exampleFC1RTUResponse(), resp)
+}
+
 func TestClient_Do_receivePacketWith1Read(t *testing.T) {
 	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
 
@@ -211,6 +226,46 @@ func TestClientRTU_Do_receivePacketWith1Read(t *testing.T) {
 	conn.AssertExpectations(t)
 }
 
+func TestClient_Do_FC23ResponseDoesNotWaitForMoreBytes(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	req := &packet.ReadWriteMultipleRegistersRequestTCP{
+		MBAPHeader: packet.MBAPHeader{TransactionID: 0x1234},
+		ReadWriteMultipleRegistersRequest: packet.ReadWriteMultipleRegistersRequest{
+			UnitID:            1,
+			ReadStartAddress:  0x10,
+			ReadQuantity:      1,
+			WriteStartAddress: 0x20,
+			WriteQuantity:     1,
+			WriteData:         []byte{0xca, 0xfe},
+		},
+	}
+
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Once().Return(nil)
+	conn.On("Write", req.Bytes()).Once().Return(0, nil)
+
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(11, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x5, 0x1, 0x17, 0x2, 0xba, 0xbe})
+		}).Once() // second read would fail the test
+
+	client := NewTCPClient()
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	response, err := client.Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.IsType(t, &packet.ReadWriteMultipleRegistersResponseTCP{}, response)
+	conn.AssertExpectations(t)
+}
+
 func TestClient_Do_receivePacketWith2Reads(t *testing.T) {
 	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
 
@@ -250,6 +305,52 @@ func TestClient_Do_receivePacketWith2Reads(t *testing.T) {
 	conn.AssertExpectations(t)
 }
 
+func TestClient_Do_variableLengthResponseWith2Reads(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	conn := new(netConnMock)
+
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Once().Return(nil)
+	conn.On("Write", []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x4, 0x1, 0x18, 0x0, 0x4}).Once().Return(0, nil)
+
+	// full packet []byte{0x81, 0x80, 0x00, 0x00, 0x00, 0x0A, 0x01, 0x18, 0x00, 0x06, 0x00, 0x02, 0x01, 0xB8, 0x12, 0x84}
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(12, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x81, 0x80, 0x00, 0x00, 0x00, 0x0A, 0x01, 0x18, 0x00, 0x06, 0x00, 0x02}) // minimal response length
+		}).Once()
+
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(4, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x01, 0xB8, 0x12, 0x84}) // FIFO registers
+		}).Once()
+
+	client := NewTCPClient()
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	req := &packet.ReadFIFOQueueRequestTCP{
+		MBAPHeader:           packet.MBAPHeader{TransactionID: 0x8180},
+		ReadFIFOQueueRequest: packet.ReadFIFOQueueRequest{UnitID: 1, FIFOPointerAddress: 4},
+	}
+	response, err := client.Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &packet.ReadFIFOQueueResponseTCP{
+		MBAPHeader:            packet.MBAPHeader{TransactionID: 0x8180},
+		ReadFIFOQueueResponse: packet.ReadFIFOQueueResponse{UnitID: 1, FIFOCount: 2, Data: []byte{0x01, 0xB8, 0x12, 0x84}},
+	}, response)
+
+	conn.AssertExpectations(t)
+}
+
 func TestClient_Do_receiveErrorPacket(t *testing.T) {
 	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
 
@@ -263,7 +364,7 @@ func TestClient_Do_receiveErrorPacket(t *testing.T) {
 		Return(9, nil).
 		Run(func(args mock.Arguments) {
 			b := args.Get(0).([]byte)
-			copy(b, []byte{0x4, 0xdd, 0x0, 0x0, 0x0, 0x3, 0x1, 0x82, 0x3})
+			copy(b, []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x3, 0x1, 0x82, 0x3})
 		}).Once()
 
 	client := NewTCPClient()
@@ -275,7 +376,7 @@ func TestClient_Do_receiveErrorPacket(t *testing.T) {
 	response, err := client.Do(context.Background(), exampleFC1Request())
 
 	assert.Nil(t, response)
-	expectedErr := &packet.ErrorResponseTCP{TransactionID: 1245, UnitID: 1, Function: 2, Code: 3}
+	expectedErr := &packet.ErrorResponseTCP{TransactionID: 4660, UnitID: 1, Function: 2, Code: 3}
 	assert.EqualError(t, err, expectedErr.Error())
 
 	var target *ClientError
@@ -284,6 +385,108 @@ func TestClient_Do_receiveErrorPacket(t *testing.T) {
 	conn.AssertExpectations(t)
 }
 
+func TestClient_Do_receiveGatewayErrorPacket(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	conn := new(netConnMock)
+
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Once().Return(nil)
+	conn.On("Write", []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x6, 0x1, 0x1, 0x0, 0xc8, 0x0, 0x9}).Once().Return(0, nil)
+
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(9, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x3, 0x1, 0x81, 0xb})
+		}).Once()
+
+	client := NewTCPClient()
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	response, err := client.Do(context.Background(), exampleFC1Request())
+
+	assert.Nil(t, response)
+	expectedErr := &packet.ErrorResponseTCP{TransactionID: 4660, UnitID: 1, Function: 1, Code: 11}
+	assert.EqualError(t, err, expectedErr.Error())
+
+	var gwErr *GatewayError
+	assert.True(t, errors.As(err, &gwErr))
+	assert.True(t, gwErr.IsRetryable())
+	assert.True(t, gwErr.IsTargetFailedToRespond())
+	assert.False(t, gwErr.IsPathUnavailable())
+
+	var packetErr *packet.ErrorResponseTCP
+	assert.True(t, errors.As(err, &packetErr))
+	assert.Equal(t, packet.ErrGatewayTargetedDeviceResponse, packetErr.Code)
+
+	conn.AssertExpectations(t)
+}
+
+func TestClient_Do_rawResponses(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Once().Return(nil)
+	conn.On("Write", []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x6, 0x1, 0x1, 0x0, 0xc8, 0x0, 0x9}).Once().Return(0, nil)
+
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(9, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x3, 0x1, 0x81, 0x2})
+		}).Once()
+
+	client := NewTCPClientWithConfig(ClientConfig{RawResponses: true})
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	response, err := client.Do(context.Background(), exampleFC1Request())
+
+	assert.NoError(t, err)
+	raw, ok := response.(*RawResponse)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x3, 0x1, 0x81, 0x2}, raw.Bytes())
+	assert.Nil(t, raw.Response)
+	assert.Equal(t, uint8(0), raw.FunctionCode())
+
+	var exception *packet.ErrorResponseTCP
+	assert.True(t, errors.As(raw.ParseErr, &exception))
+	assert.Equal(t, packet.ErrIllegalDataAddress, exception.Code)
+
+	conn.AssertExpectations(t)
+}
+
+func TestNewExceptionError(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		when          error
+		expectGateway bool
+	}{
+		{name: "ok, TCP path unavailable", when: &packet.ErrorResponseTCP{Code: 10}, expectGateway: true},
+		{name: "ok, RTU target failed to respond", when: &packet.ErrorResponseRTU{Code: 11}, expectGateway: true},
+		{name: "ok, TCP illegal address is not gateway error", when: &packet.ErrorResponseTCP{Code: 2}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newExceptionError(tc.when)
+
+			var clientErr *ClientError
+			assert.True(t, errors.As(err, &clientErr))
+
+			var gwErr *GatewayError
+			assert.Equal(t, tc.expectGateway, errors.As(err, &gwErr))
+			assert.True(t, errors.Is(err, tc.when))
+		})
+	}
+}
+
 func TestClient_Do_ReadSomeBytesWithEOF(t *testing.T) {
 	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
 
@@ -578,7 +781,7 @@ func TestClient_Connect(t *testing.T) {
 			whenAddress:        "localhost:502",
 			whenDialContextErr: errors.New("dialContext error"),
 			expectAddr:         "localhost:502",
-			expectError:        "dialContext error",
+			expectError:        "failed to connect to 'localhost:502' (network: tcp, timeout: 1s, kind: unknown): dialContext error",
 		},
 	}
 
@@ -655,3 +858,72 @@ func TestAddressExtractor(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Do_readTimeoutFromContext(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	conn := new(netConnMock)
+
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Once().Return(nil)
+	conn.On("Write", []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x6, 0x1, 0x1, 0x0, 0xc8, 0x0, 0x9}).Once().Return(0, nil)
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).Return(0, os.ErrDeadlineExceeded)
+
+	client := NewTCPClientWithConfig(ClientConfig{ReadTimeout: 1 * time.Hour})
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	ctx := ContextWithReadTimeout(context.Background(), 10*time.Millisecond)
+	response, err := client.Do(ctx, exampleFC1Request())
+
+	assert.Nil(t, response)
+	assert.EqualError(t, err, "total read timeout exceeded")
+	conn.AssertExpectations(t)
+}
+
+func TestContextWithReadTimeout(t *testing.T) {
+	ctx := ContextWithReadTimeout(context.Background(), 5*time.Second)
+	assert.Equal(t, 5*time.Second, readTimeoutFromContext(ctx, time.Second))
+
+	ctx = ContextWithReadTimeout(context.Background(), 0)
+	assert.Equal(t, time.Second, readTimeoutFromContext(ctx, time.Second))
+}
+
+func TestClient_Do_responseDelayedPastReadTimeout(t *testing.T) {
+	handler := func(request []byte) []byte {
+		return []byte{request[0], request[1], 0x0, 0x0, 0x0, 0x5, request[6], 0x3, 0x2, 0x0, 0x1}
+	}
+	readTimeout := 20 * time.Millisecond
+	client := NewTCPClientWithConfig(ClientConfig{
+		ReadTimeout:     readTimeout,
+		DialContextFunc: modbustest.DialContextFunc(handler, modbustest.ResponseTiming{Delay: readTimeout + 30*time.Millisecond}),
+	})
+	assert.NoError(t, client.Connect(context.Background(), ":502"))
+	defer client.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	resp, err := client.Do(context.Background(), req)
+
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, "total read timeout exceeded")
+}
+
+func TestClient_Do_responseDribbledByteByByte(t *testing.T) {
+	handler := func(request []byte) []byte {
+		return []byte{request[0], request[1], 0x0, 0x0, 0x0, 0x5, request[6], 0x3, 0x2, 0x0, 0x1}
+	}
+	client := NewTCPClientWithConfig(ClientConfig{
+		ReadTimeout:     1 * time.Second,
+		DialContextFunc: modbustest.DialContextFunc(handler, modbustest.ResponseTiming{ChunkSize: 1, ChunkGap: 2 * time.Millisecond}),
+	})
+	assert.NoError(t, client.Connect(context.Background(), ":502"))
+	defer client.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	resp, err := client.Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x1}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+}
@@ -0,0 +1,470 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClientPoolClosed is error indicating that ClientPool has been closed
+var ErrClientPoolClosed = ClientError{Err: errors.New("client pool is closed")}
+
+// ClientPoolConfig is configuration for ClientPool
+type ClientPoolConfig struct {
+	// ClientConfig is configuration for pooled connections. AsProtocolErrorFunc and ParseResponseFunc are ignored as
+	// pool works only with Modbus TCP protocol. PipelineWindow and DelayBetweenRequests are ignored as requests are
+	// always multiplexed.
+	ClientConfig
+
+	// MaxConnectionsPerServer is maximum amount of connections opened to single server address. Defaults to 1.
+	MaxConnectionsPerServer int
+	// MaxRequestsPerConnection is amount of requests in flight over single connection before new connection to the
+	// same server is opened (up to MaxConnectionsPerServer). Defaults to 8. When all connections to the server are at
+	// capacity, requests are still sent over the least busy connection. Use MaxRequestsPerServer to limit that.
+	MaxRequestsPerConnection int
+	// MaxRequestsPerServer is maximum amount of requests in flight to single server address. Requests over the limit
+	// wait until earlier requests to the same server are done. 0 means no limit.
+	MaxRequestsPerServer int
+	// MaxRequestsPerUnit is maximum amount of requests in flight to single unit ID of the same server address. Requests
+	// over the limit wait until earlier requests to the same unit are done. This is useful for TCP to RS-485 gateways
+	// where units behind the gateway can process only one request at the time. 0 means no limit.
+	MaxRequestsPerUnit int
+}
+
+// ClientPool manages pool of Modbus TCP connections per server address and multiplexes concurrent Do calls over
+// these connections. Each request is sent with pool unique MBAP transaction ID and response is matched to the request
+// by that ID. Original transaction ID of the request is restored in the response. ClientPool is safe for concurrent use.
+//
+// Devices must support multiple outstanding requests over single connection to benefit from multiplexing. For devices
+// that do not, set MaxRequestsPerConnection to 1.
+//
+// NB: ClientHooks are called concurrently from different goroutines.
+type ClientPool struct {
+	client                   *Client
+	maxConnectionsPerServer  int
+	maxRequestsPerConnection int
+	maxRequestsPerServer     int
+	maxRequestsPerUnit       int
+	transactionID            atomic.Uint32
+
+	mu    sync.Mutex
+	conns map[string][]*pooledConn
+	// dialing is amount of connections being opened per server address. Dialing connections count towards
+	// MaxConnectionsPerServer.
+	dialing map[string]int
+	// dialed is closed and replaced when dialing a connection finishes to wake up requests waiting for a connection
+	dialed      chan struct{}
+	serverSlots map[string]chan struct{}
+	unitSlots   map[poolUnitKey]chan struct{}
+	closed      bool
+}
+
+type poolUnitKey struct {
+	address string
+	unitID  uint8
+}
+
+// NewClientPool creates new instance of ClientPool with given configuration options
+func NewClientPool(conf ClientPoolConfig) *ClientPool {
+	conf.AsProtocolErrorFunc = nil
+	conf.ParseResponseFunc = nil
+	p := &ClientPool{
+		client:                   NewTCPClientWithConfig(conf.ClientConfig),
+		maxConnectionsPerServer:  1,
+		maxRequestsPerConnection: 8,
+		maxRequestsPerServer:     conf.MaxRequestsPerServer,
+		maxRequestsPerUnit:       conf.MaxRequestsPerUnit,
+		conns:                    map[string][]*pooledConn{},
+		dialing:                  map[string]int{},
+		dialed:                   make(chan struct{}),
+		serverSlots:              map[string]chan struct{}{},
+		unitSlots:                map[poolUnitKey]chan struct{}{},
+	}
+	if conf.MaxConnectionsPerServer > 0 {
+		p.maxConnectionsPerServer = conf.MaxConnectionsPerServer
+	}
+	if conf.MaxRequestsPerConnection > 0 {
+		p.maxRequestsPerConnection = conf.MaxRequestsPerConnection
+	}
+	return p
+}
+
+// Server returns Doer that sends requests to given server address using the pool
+func (p *ClientPool) Server(address string) Doer {
+	return poolServer{pool: p, address: address}
+}
+
+type poolServer struct {
+	pool    *ClientPool
+	address string
+}
+
+func (s poolServer) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	return s.pool.Do(ctx, s.address, req)
+}
+
+// Do sends given Modbus TCP request to given server address and returns parsed Response. Connection to the server is
+// opened when there is no connection with free capacity in the pool.
+// On modbus exception nil is returned as response and error wraps value of type packet.ErrorResponseTCP
+func (p *ClientPool) Do(ctx context.Context, address string, req packet.Request) (packet.Response, error) {
+	if req == nil {
+		return nil, errors.New("request can not be nil")
+	}
+	if p.client.requestTransformer != nil {
+		var err error
+		if req, err = transformRequest(p.client.requestTransformer, req); err != nil {
+			return nil, err
+		}
+	}
+	data := req.Bytes()
+	if len(data) < 8 {
+		return nil, errors.New("client pool supports only Modbus TCP requests")
+	}
+
+	if p.maxRequestsPerUnit > 0 {
+		releaseUnit, err := p.acquireUnit(ctx, address, data[6])
+		if err != nil {
+			return nil, err
+		}
+		defer releaseUnit()
+	}
+	if p.maxRequestsPerServer > 0 {
+		releaseServer, err := p.acquireServer(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		defer releaseServer()
+	}
+	pc, err := p.acquire(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	defer pc.release()
+
+	start := p.client.timeNow()
+	resp, err := p.roundTrip(ctx, pc, data)
+	if p.client.metrics != nil {
+		p.client.metrics.RequestDone(RequestMetric{
+			ServerAddress: address,
+			UnitID:        frameUnitID(data, false),
+			FunctionCode:  req.FunctionCode(),
+			RoundTrip:     p.client.timeNow().Sub(start),
+			Err:           err,
+		})
+	}
+	return resp, err
+}
+
+func (p *ClientPool) roundTrip(ctx context.Context, pc *pooledConn, data []byte) (packet.Response, error) {
+	originalTransactionID := binary.BigEndian.Uint16(data[0:2])
+	transactionID, resultCh, err := pc.register(p.nextTransactionID)
+	if err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint16(data[0:2], transactionID)
+	if err := pc.write(data); err != nil {
+		pc.unregister(transactionID)
+		return nil, err
+	}
+
+	readTimeout := time.NewTimer(readTimeoutFromContext(ctx, p.client.readTimeout))
+	defer readTimeout.Stop()
+	select {
+	case <-ctx.Done():
+		pc.unregister(transactionID)
+		return nil, ctx.Err()
+	case <-readTimeout.C:
+		pc.unregister(transactionID)
+		return nil, &ClientError{Err: errReadTimeoutExceeded}
+	case result := <-resultCh:
+		if result.err != nil {
+			return nil, result.err
+		}
+		binary.BigEndian.PutUint16(result.frame[0:2], originalTransactionID)
+		return p.client.parseFrame(data, result.frame)
+	}
+}
+
+// Close closes all connections in the pool. Requests in flight fail with an error.
+func (p *ClientPool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	conns := p.conns
+	p.conns = map[string][]*pooledConn{}
+	p.mu.Unlock()
+
+	var errs []error
+	for _, serverConns := range conns {
+		for _, pc := range serverConns {
+			if err := pc.conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *ClientPool) nextTransactionID() uint16 {
+	return uint16(p.transactionID.Add(1))
+}
+
+// acquire returns connection to given server with the least requests in flight. New connection is opened when all
+// existing connections are at full capacity and connection limit is not reached. Connection is dialed without holding
+// the pool lock so slow dials do not block requests to other servers.
+func (p *ClientPool) acquire(ctx context.Context, address string) (*pooledConn, error) {
+	p.mu.Lock()
+	for {
+		if p.closed {
+			p.mu.Unlock()
+			return nil, &ErrClientPoolClosed
+		}
+		var best *pooledConn
+		for _, pc := range p.conns[address] {
+			if best == nil || pc.inFlight < best.inFlight {
+				best = pc
+			}
+		}
+		canDial := len(p.conns[address])+p.dialing[address] < p.maxConnectionsPerServer
+		if best != nil && (best.inFlight < p.maxRequestsPerConnection || !canDial) {
+			best.inFlight++
+			p.mu.Unlock()
+			return best, nil
+		}
+		if canDial {
+			break
+		}
+		// there are no connections yet and connection limit is reached by connections being dialed
+		dialed := p.dialed
+		p.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-dialed:
+		}
+		p.mu.Lock()
+	}
+	p.dialing[address]++ // reserve connection slot for the dial
+	p.mu.Unlock()
+
+	conn, err := p.client.dialContextFunc(ctx, address)
+	if p.client.metrics != nil {
+		p.client.metrics.ConnectDone(address, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.dialing[address]--
+	if p.dialing[address] == 0 {
+		delete(p.dialing, address)
+	}
+	close(p.dialed)
+	p.dialed = make(chan struct{})
+	if err != nil {
+		return nil, newConnectError(address, p.client.connectTimeout, err)
+	}
+	if p.closed {
+		_ = conn.Close()
+		return nil, &ErrClientPoolClosed
+	}
+	pc := &pooledConn{
+		pool:    p,
+		address: address,
+		conn:    conn,
+		pending: map[uint16]chan pooledResult{},
+	}
+	p.conns[address] = append(p.conns[address], pc)
+	go pc.readLoop()
+	pc.inFlight++
+	return pc, nil
+}
+
+// acquireUnit waits until request to given unit of the server can be sent without exceeding MaxRequestsPerUnit and
+// returns function that must be called when request is done.
+func (p *ClientPool) acquireUnit(ctx context.Context, address string, unitID uint8) (func(), error) {
+	key := poolUnitKey{address: address, unitID: unitID}
+	p.mu.Lock()
+	slots, ok := p.unitSlots[key]
+	if !ok {
+		slots = make(chan struct{}, p.maxRequestsPerUnit)
+		p.unitSlots[key] = slots
+	}
+	p.mu.Unlock()
+
+	return acquireSlot(ctx, slots)
+}
+
+// acquireServer waits until request to given server can be sent without exceeding MaxRequestsPerServer and returns
+// function that must be called when request is done.
+func (p *ClientPool) acquireServer(ctx context.Context, address string) (func(), error) {
+	p.mu.Lock()
+	slots, ok := p.serverSlots[address]
+	if !ok {
+		slots = make(chan struct{}, p.maxRequestsPerServer)
+		p.serverSlots[address] = slots
+	}
+	p.mu.Unlock()
+
+	return acquireSlot(ctx, slots)
+}
+
+func acquireSlot(ctx context.Context, slots chan struct{}) (func(), error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	}
+}
+
+// remove removes failed connection from the pool
+func (p *ClientPool) remove(pc *pooledConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conns := p.conns[pc.address]
+	for i, c := range conns {
+		if c == pc {
+			p.conns[pc.address] = append(conns[:i:i], conns[i+1:]...)
+			break
+		}
+	}
+	if len(p.conns[pc.address]) == 0 {
+		delete(p.conns, pc.address)
+	}
+}
+
+type pooledResult struct {
+	frame []byte
+	err   error
+}
+
+// pooledConn is single connection in ClientPool. Requests are written by Do callers and responses are read and
+// dispatched to waiting callers by connection read loop.
+type pooledConn struct {
+	pool    *ClientPool
+	address string
+	conn    net.Conn
+	// inFlight is amount of Do calls using this connection. Guarded by pool mutex.
+	inFlight int
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[uint16]chan pooledResult
+	err     error
+}
+
+func (pc *pooledConn) release() {
+	pc.pool.mu.Lock()
+	pc.inFlight--
+	pc.pool.mu.Unlock()
+}
+
+// register reserves transaction ID not used by other requests in flight on this connection
+func (pc *pooledConn) register(nextTransactionID func() uint16) (uint16, chan pooledResult, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.err != nil {
+		return 0, nil, pc.err
+	}
+	transactionID := nextTransactionID()
+	for _, exists := pc.pending[transactionID]; exists; _, exists = pc.pending[transactionID] {
+		transactionID = nextTransactionID()
+	}
+	ch := make(chan pooledResult, 1)
+	pc.pending[transactionID] = ch
+	return transactionID, ch, nil
+}
+
+func (pc *pooledConn) unregister(transactionID uint16) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	delete(pc.pending, transactionID)
+}
+
+func (pc *pooledConn) write(data []byte) error {
+	pc.writeMu.Lock()
+	defer pc.writeMu.Unlock()
+
+	c := pc.pool.client
+	if err := pc.conn.SetWriteDeadline(c.timeNow().Add(c.writeTimeout)); err != nil {
+		return &ClientError{Err: err}
+	}
+	if c.hooks != nil {
+		c.hooks.BeforeWrite(data)
+	}
+	if _, err := pc.conn.Write(data); err != nil {
+		pc.fail(&ClientError{Err: err})
+		return &ClientError{Err: err}
+	}
+	return nil
+}
+
+// readLoop reads frames from connection and dispatches them to requests waiting for them until connection fails
+func (pc *pooledConn) readLoop() {
+	hooks := pc.pool.client.hooks
+	received := make([]byte, tcpPacketMaxLen)
+	var buffer []byte
+	for {
+		n, err := pc.conn.Read(received)
+		if hooks != nil {
+			hooks.AfterEachRead(received[:n], n, err)
+		}
+		buffer = append(buffer, received[:n]...)
+		for len(buffer) >= 6 {
+			frameLen := 6 + int(binary.BigEndian.Uint16(buffer[4:6]))
+			if frameLen > tcpPacketMaxLen {
+				pc.fail(&ErrPacketTooLong)
+				return
+			}
+			if len(buffer) < frameLen {
+				break
+			}
+			frame := append([]byte(nil), buffer[:frameLen]...)
+			buffer = buffer[frameLen:]
+			pc.dispatch(frame)
+		}
+		if err != nil {
+			pc.fail(&ClientError{Err: err})
+			return
+		}
+	}
+}
+
+func (pc *pooledConn) dispatch(frame []byte) {
+	transactionID := binary.BigEndian.Uint16(frame[0:2])
+	pc.mu.Lock()
+	ch, ok := pc.pending[transactionID]
+	delete(pc.pending, transactionID)
+	pc.mu.Unlock()
+	if ok {
+		ch <- pooledResult{frame: frame}
+	}
+	// frames with unknown transaction ID are late responses to timed out requests and are ignored
+}
+
+// fail closes connection, removes it from the pool and fails all requests waiting for responses
+func (pc *pooledConn) fail(err error) {
+	pc.mu.Lock()
+	if pc.err != nil {
+		pc.mu.Unlock()
+		return
+	}
+	pc.err = err
+	pending := pc.pending
+	pc.pending = map[uint16]chan pooledResult{}
+	pc.mu.Unlock()
+
+	_ = pc.conn.Close()
+	pc.pool.remove(pc)
+	for _, ch := range pending {
+		ch <- pooledResult{err: err}
+	}
+}
@@ -0,0 +1,359 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// poolTestServer reads given amount of FC3 requests from connection and responds to them in reverse order. Register
+// value in response is start address of the request.
+func poolTestServer(t *testing.T, conn net.Conn, requests int, received chan<- []byte) {
+	var reqs [][]byte
+	for i := 0; i < requests; i++ {
+		b := make([]byte, 12)
+		if _, err := io.ReadFull(conn, b); err != nil {
+			t.Error(err)
+			return
+		}
+		received <- b
+		reqs = append(reqs, b)
+	}
+	for i := len(reqs) - 1; i >= 0; i-- {
+		r := reqs[i]
+		resp := []byte{r[0], r[1], 0x0, 0x0, 0x0, 0x5, r[6], 0x3, 0x2, r[8], r[9]}
+		if _, err := conn.Write(resp); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
+func TestClientPool_Do_multiplexesConcurrentRequests(t *testing.T) {
+	dials := 0
+	received := make(chan []byte, 2)
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				dials++
+				client, server := net.Pipe()
+				go poolTestServer(t, server, 2, received)
+				return client, nil
+			},
+		},
+	})
+	defer pool.Close()
+
+	results := make([]packet.Response, 2)
+	errs := make([]error, 2)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := packet.NewReadHoldingRegistersRequestTCP(1, uint16(100+i), 1)
+			req.TransactionID = 0x1234
+			results[i], errs[i] = pool.Do(context.Background(), "localhost:502", req)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, dials)
+	assert.NotEqual(t, binary.BigEndian.Uint16((<-received)[0:2]), binary.BigEndian.Uint16((<-received)[0:2]))
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, errs[i])
+		resp := results[i].(*packet.ReadHoldingRegistersResponseTCP)
+		assert.Equal(t, uint16(0x1234), resp.TransactionID)
+		assert.Equal(t, []byte{0x0, byte(100 + i)}, resp.Data)
+	}
+}
+
+func TestClientPool_Server(t *testing.T) {
+	received := make(chan []byte, 1)
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				assert.Equal(t, "localhost:502", address)
+				client, server := net.Pipe()
+				go poolTestServer(t, server, 1, received)
+				return client, nil
+			},
+		},
+	})
+	defer pool.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	resp, err := pool.Server("localhost:502").Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 10}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+}
+
+func TestClientPool_Do_opensNewConnectionWhenFull(t *testing.T) {
+	dials := 0
+	received := make(chan []byte, 2)
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				dials++
+				client, server := net.Pipe()
+				go poolTestServer(t, server, 1, received)
+				return client, nil
+			},
+		},
+		MaxConnectionsPerServer:  2,
+		MaxRequestsPerConnection: 1,
+	})
+	defer pool.Close()
+
+	wg := sync.WaitGroup{}
+	errs := make([]error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+			_, errs[i] = pool.Do(context.Background(), "localhost:502", req)
+		}(i)
+	}
+	<-received
+	<-received
+	wg.Wait()
+
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Equal(t, 2, dials)
+}
+
+func TestClientPool_Do_dialDoesNotBlockOtherServers(t *testing.T) {
+	dialStarted := make(chan struct{})
+	releaseDial := make(chan struct{})
+	var mu sync.Mutex
+	dials := map[string]int{}
+	received := make(chan []byte, 3)
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				mu.Lock()
+				dials[address]++
+				mu.Unlock()
+				requests := 1
+				if address == "slow:502" {
+					close(dialStarted)
+					<-releaseDial
+					requests = 2
+				}
+				client, server := net.Pipe()
+				go poolTestServer(t, server, requests, received)
+				return client, nil
+			},
+		},
+	})
+	defer pool.Close()
+
+	wg := sync.WaitGroup{}
+	errs := make([]error, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+		_, errs[0] = pool.Do(context.Background(), "slow:502", req)
+	}()
+	<-dialStarted
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 11, 1)
+		_, errs[1] = pool.Do(context.Background(), "slow:502", req) // waits for dial in progress
+	}()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 20, 1)
+	resp, err := pool.Do(context.Background(), "fast:502", req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 20}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+
+	close(releaseDial)
+	wg.Wait()
+
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Equal(t, map[string]int{"slow:502": 1, "fast:502": 1}, dials)
+}
+
+func TestClientPool_Do_connectionFailureFailsRequest(t *testing.T) {
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				client, server := net.Pipe()
+				go func() {
+					b := make([]byte, 12)
+					_, _ = io.ReadFull(server, b)
+					_ = server.Close()
+				}()
+				return client, nil
+			},
+		},
+	})
+	defer pool.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	resp, err := pool.Do(context.Background(), "localhost:502", req)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Len(t, pool.conns, 0)
+}
+
+func TestClientPool_Do_readTimeout(t *testing.T) {
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			ReadTimeout: 10 * time.Millisecond,
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				client, server := net.Pipe()
+				go func() { _, _ = io.Copy(io.Discard, server) }()
+				return client, nil
+			},
+		},
+	})
+	defer pool.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err := pool.Do(context.Background(), "localhost:502", req)
+
+	assert.EqualError(t, err, "total read timeout exceeded")
+}
+
+func TestClientPool_Do_dialError(t *testing.T) {
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				return nil, errors.New("dial failed")
+			},
+		},
+	})
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err := pool.Do(context.Background(), "localhost:502", req)
+
+	assert.ErrorContains(t, err, "dial failed")
+}
+
+func TestClientPool_Do_closed(t *testing.T) {
+	pool := NewClientPool(ClientPoolConfig{})
+	assert.NoError(t, pool.Close())
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err := pool.Do(context.Background(), "localhost:502", req)
+
+	assert.ErrorIs(t, err, &ErrClientPoolClosed)
+}
+
+func TestClientPool_Do_maxRequestsPerUnit(t *testing.T) {
+	received := make(chan []byte, 3)
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				client, server := net.Pipe()
+				go func() {
+					for {
+						r := make([]byte, 12)
+						if _, err := io.ReadFull(server, r); err != nil {
+							return
+						}
+						received <- r
+						resp := []byte{r[0], r[1], 0x0, 0x0, 0x0, 0x5, r[6], 0x3, 0x2, r[8], r[9]}
+						if _, err := server.Write(resp); err != nil {
+							return
+						}
+					}
+				}()
+				return client, nil
+			},
+		},
+		MaxRequestsPerUnit: 1,
+	})
+	defer pool.Close()
+
+	// occupy the only slot of unit 1 so next request to unit 1 has to wait
+	releaseUnit, err := pool.acquireUnit(context.Background(), "localhost:502", 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err = pool.Do(ctx, "localhost:502", req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// other units are not limited
+	req2, _ := packet.NewReadHoldingRegistersRequestTCP(2, 20, 1)
+	resp, err := pool.Do(context.Background(), "localhost:502", req2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 20}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+
+	releaseUnit()
+	resp, err = pool.Do(context.Background(), "localhost:502", req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 10}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+	assert.Len(t, received, 2)
+}
+
+func TestClientPool_Do_maxRequestsPerServer(t *testing.T) {
+	received := make(chan []byte, 3)
+	pool := NewClientPool(ClientPoolConfig{
+		ClientConfig: ClientConfig{
+			DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				client, server := net.Pipe()
+				go func() {
+					for {
+						r := make([]byte, 12)
+						if _, err := io.ReadFull(server, r); err != nil {
+							return
+						}
+						received <- r
+						resp := []byte{r[0], r[1], 0x0, 0x0, 0x0, 0x5, r[6], 0x3, 0x2, r[8], r[9]}
+						if _, err := server.Write(resp); err != nil {
+							return
+						}
+					}
+				}()
+				return client, nil
+			},
+		},
+		MaxRequestsPerConnection: 1,
+		MaxRequestsPerServer:     1,
+	})
+	defer pool.Close()
+
+	// occupy the only slot of the server so next request to the server has to wait
+	releaseServer, err := pool.acquireServer(context.Background(), "localhost:502")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err = pool.Do(ctx, "localhost:502", req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// other servers are not limited
+	req2, _ := packet.NewReadHoldingRegistersRequestTCP(1, 20, 1)
+	resp, err := pool.Do(context.Background(), "localhost:503", req2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 20}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+
+	releaseServer()
+	resp, err = pool.Do(context.Background(), "localhost:502", req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 10}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+	assert.Len(t, received, 2)
+}
@@ -0,0 +1,198 @@
+// Command modbus-gen generates typed Go struct and decode function from JSON field configuration so applications get
+// compile-time safe access to extracted field values instead of working with FieldValue slices.
+//
+// Usage:
+//
+//	modbus-gen -in fields.json -package meter -type VictronMeter -out victronmeter_gen.go
+//
+// Input is JSON array of field objects (see modbus.ParseFieldsJSONStrict). Every field must have unique name.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/aldas/go-modbus-client"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	in := flag.String("in", "", "path to JSON field configuration file")
+	out := flag.String("out", "", "path to generated Go file. Defaults to standard output")
+	pkg := flag.String("package", "main", "package name of generated file")
+	typeName := flag.String("type", "", "name of generated struct type")
+	flag.Parse()
+
+	if *in == "" || *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fields, err := modbus.ParseFieldsJSONStrict(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(fields, *pkg, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		_, _ = os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type genField struct {
+	name   string // field name in configuration
+	goName string
+	goType string
+}
+
+// generate creates Go source with struct type and Decode<type> function for given fields
+func generate(fields modbus.Fields, pkg string, typeName string) ([]byte, error) {
+	if !isIdentifier(typeName) {
+		return nil, fmt.Errorf("invalid type name: '%v'", typeName)
+	}
+	genFields := make([]genField, 0, len(fields))
+	usesTime := false
+	names := map[string]bool{}
+	goNames := map[string]string{}
+	for i, f := range fields {
+		if f.Name == "" {
+			return nil, fmt.Errorf("field name can not be empty. index: %v", i)
+		}
+		if names[f.Name] {
+			return nil, fmt.Errorf("field name is not unique: '%v'", f.Name)
+		}
+		names[f.Name] = true
+
+		goName := toGoName(f.Name)
+		if other, ok := goNames[goName]; ok {
+			return nil, fmt.Errorf("fields '%v' and '%v' result same Go field name: %v", other, f.Name, goName)
+		}
+		goNames[goName] = f.Name
+
+		goType, err := goTypeOf(f)
+		if err != nil {
+			return nil, fmt.Errorf("field '%v': %w", f.Name, err)
+		}
+		if goType == "time.Time" {
+			usesTime = true
+		}
+		genFields = append(genFields, genField{name: f.Name, goName: goName, goType: goType})
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by modbus-gen. DO NOT EDIT.\n\npackage %v\n\n", pkg)
+	b.WriteString("import (\n\"fmt\"\n\"github.com/aldas/go-modbus-client\"\n")
+	if usesTime {
+		b.WriteString("\"time\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "// %v contains extracted field values\ntype %v struct {\n", typeName, typeName)
+	for _, f := range genFields {
+		fmt.Fprintf(&b, "%v %v\n", f.goName, f.goType)
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// Decode%v fills %v from extracted field values. Values are matched by field name and values of\n", typeName, typeName)
+	b.WriteString("// unknown fields are ignored. Value with extraction error results an error.\n")
+	fmt.Fprintf(&b, "func Decode%v(values []modbus.FieldValue) (%v, error) {\n", typeName, typeName)
+	fmt.Fprintf(&b, "var result %v\n", typeName)
+	b.WriteString("for _, v := range values {\n")
+	b.WriteString("if v.Error != nil {\nreturn result, fmt.Errorf(\"field '%v' has error: %w\", v.Field.Name, v.Error)\n}\n")
+	b.WriteString("switch v.Field.Name {\n")
+	for _, f := range genFields {
+		fmt.Fprintf(&b, "case %q:\n", f.name)
+		if f.goType == "interface{}" {
+			fmt.Fprintf(&b, "result.%v = v.Value\n", f.goName)
+			continue
+		}
+		fmt.Fprintf(&b, "x, ok := v.Value.(%v)\n", f.goType)
+		b.WriteString("if !ok {\nreturn result, fmt.Errorf(\"field '%v' has unexpected value type: %T\", v.Field.Name, v.Value)\n}\n")
+		fmt.Fprintf(&b, "result.%v = x\n", f.goName)
+	}
+	b.WriteString("}\n}\nreturn result, nil\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// goTypeOf returns Go type of the value that extraction of given field results
+func goTypeOf(f modbus.Field) (string, error) {
+	switch {
+	case f.Processor != "":
+		return "interface{}", nil // processor can return any type
+	case len(f.Enum) > 0:
+		return "string", nil
+	case f.Scale != 0 || f.Offset != 0 || (f.TargetUnit != "" && f.TargetUnit != f.Unit):
+		return "float64", nil
+	}
+	switch f.Type {
+	case modbus.FieldTypeBit, modbus.FieldTypeCoil:
+		return "bool", nil
+	case modbus.FieldTypeByte, modbus.FieldTypeUint8:
+		return "uint8", nil
+	case modbus.FieldTypeInt8:
+		return "int8", nil
+	case modbus.FieldTypeUint16:
+		return "uint16", nil
+	case modbus.FieldTypeInt16:
+		return "int16", nil
+	case modbus.FieldTypeUint32, modbus.FieldTypeBCD:
+		return "uint32", nil
+	case modbus.FieldTypeInt32:
+		return "int32", nil
+	case modbus.FieldTypeUint48, modbus.FieldTypeUint64:
+		return "uint64", nil
+	case modbus.FieldTypeInt48, modbus.FieldTypeInt64:
+		return "int64", nil
+	case modbus.FieldTypeFloat16, modbus.FieldTypeFloat32:
+		return "float32", nil
+	case modbus.FieldTypeFloat64:
+		return "float64", nil
+	case modbus.FieldTypeString:
+		return "string", nil
+	case modbus.FieldTypeUnixTime32, modbus.FieldTypeUnixTime64:
+		return "time.Time", nil
+	}
+	return "", errors.New("unknown field type")
+}
+
+// toGoName converts field name (i.e. `ac_l1_voltage`, `ac-l1 voltage`) to exported Go identifier (`AcL1Voltage`)
+func toGoName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, p := range parts {
+		runes := []rune(p)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	result := b.String()
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "F" + result
+	}
+	return result
+}
+
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
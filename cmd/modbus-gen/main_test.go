@@ -0,0 +1,131 @@
+package main
+
+import (
+	"github.com/aldas/go-modbus-client"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	fields := modbus.Fields{
+		{Name: "ac_l1_voltage", Address: 10, Type: modbus.FieldTypeUint16, Scale: 0.1},
+		{Name: "state", Address: 11, Type: modbus.FieldTypeUint16, Enum: map[int64]string{0: "OFF"}},
+		{Name: "calibrated", Address: 12, Type: modbus.FieldTypeInt16, Processor: "curve"},
+	}
+
+	src, err := generate(fields, "meter", "Meter")
+
+	assert.NoError(t, err)
+	assert.Equal(t, `// Code generated by modbus-gen. DO NOT EDIT.
+
+package meter
+
+import (
+	"fmt"
+	"github.com/aldas/go-modbus-client"
+)
+
+// Meter contains extracted field values
+type Meter struct {
+	AcL1Voltage float64
+	State       string
+	Calibrated  interface{}
+}
+
+// DecodeMeter fills Meter from extracted field values. Values are matched by field name and values of
+// unknown fields are ignored. Value with extraction error results an error.
+func DecodeMeter(values []modbus.FieldValue) (Meter, error) {
+	var result Meter
+	for _, v := range values {
+		if v.Error != nil {
+			return result, fmt.Errorf("field '%v' has error: %w", v.Field.Name, v.Error)
+		}
+		switch v.Field.Name {
+		case "ac_l1_voltage":
+			x, ok := v.Value.(float64)
+			if !ok {
+				return result, fmt.Errorf("field '%v' has unexpected value type: %T", v.Field.Name, v.Value)
+			}
+			result.AcL1Voltage = x
+		case "state":
+			x, ok := v.Value.(string)
+			if !ok {
+				return result, fmt.Errorf("field '%v' has unexpected value type: %T", v.Field.Name, v.Value)
+			}
+			result.State = x
+		case "calibrated":
+			result.Calibrated = v.Value
+		}
+	}
+	return result, nil
+}
+`, string(src))
+}
+
+func TestGenerate_timeField(t *testing.T) {
+	fields := modbus.Fields{
+		{Name: "clock", Address: 10, Type: modbus.FieldTypeUnixTime32},
+	}
+
+	src, err := generate(fields, "meter", "Meter")
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "\t\"time\"\n")
+	assert.Contains(t, string(src), "Clock time.Time\n")
+	assert.Contains(t, string(src), "x, ok := v.Value.(time.Time)\n")
+}
+
+func TestGenerate_errors(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenFields modbus.Fields
+		givenType   string
+		expectError string
+	}{
+		{
+			name:        "nok, invalid type name",
+			givenFields: modbus.Fields{{Name: "a", Type: modbus.FieldTypeUint16}},
+			givenType:   "1Meter",
+			expectError: "invalid type name: '1Meter'",
+		},
+		{
+			name:        "nok, empty field name",
+			givenFields: modbus.Fields{{Type: modbus.FieldTypeUint16}},
+			givenType:   "Meter",
+			expectError: "field name can not be empty. index: 0",
+		},
+		{
+			name:        "nok, duplicate field name",
+			givenFields: modbus.Fields{{Name: "a", Type: modbus.FieldTypeUint16}, {Name: "a", Type: modbus.FieldTypeUint16}},
+			givenType:   "Meter",
+			expectError: "field name is not unique: 'a'",
+		},
+		{
+			name:        "nok, same Go name",
+			givenFields: modbus.Fields{{Name: "ac_power", Type: modbus.FieldTypeUint16}, {Name: "ac-power", Type: modbus.FieldTypeUint16}},
+			givenType:   "Meter",
+			expectError: "fields 'ac_power' and 'ac-power' result same Go field name: AcPower",
+		},
+		{
+			name:        "nok, unknown field type",
+			givenFields: modbus.Fields{{Name: "a"}},
+			givenType:   "Meter",
+			expectError: "field 'a': unknown field type",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := generate(tc.givenFields, "meter", tc.givenType)
+
+			assert.EqualError(t, err, tc.expectError)
+			assert.Nil(t, src)
+		})
+	}
+}
+
+func TestToGoName(t *testing.T) {
+	assert.Equal(t, "AcL1Voltage", toGoName("ac_l1_voltage"))
+	assert.Equal(t, "TotalEnergyKWh", toGoName("total energy kWh"))
+	assert.Equal(t, "F1stPhase", toGoName("1st-phase"))
+	assert.Equal(t, "F", toGoName("__"))
+}
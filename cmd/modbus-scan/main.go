@@ -0,0 +1,105 @@
+// Command modbus-scan probes register address range of Modbus server units and prints JSON map of addresses that
+// respond and addresses that result Illegal Data Address exception.
+//
+// Usage:
+//
+//	modbus-scan -address tcp://192.168.0.10:502 -units 1,2 -fc 3 -start 0 -end 999
+//
+// Use -rtu flag to scan with Modbus RTU framing over TCP (i.e. transparent Ethernet-serial gateways).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/aldas/go-modbus-client"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	address := flag.String("address", "", "server address (i.e. tcp://192.168.0.10:502)")
+	isRTU := flag.Bool("rtu", false, "use Modbus RTU framing over TCP")
+	units := flag.String("units", "1", "comma separated unit IDs to scan")
+	functionCode := flag.Uint("fc", 3, "read function code used to probe addresses (1, 2, 3 or 4)")
+	start := flag.Uint("start", 0, "first address to scan")
+	end := flag.Uint("end", 999, "last address to scan (inclusive)")
+	blockSize := flag.Uint("block", 16, "amount of addresses read with single probe request")
+	timeout := flag.Duration("timeout", time.Second, "read timeout of single probe request")
+	interval := flag.Duration("interval", 0, "minimal delay between probe requests")
+	flag.Parse()
+
+	if *address == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	unitIDs, err := parseUnitIDs(*units)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *functionCode > 255 || *start > 65535 || *end > 65535 || *blockSize > 65535 {
+		log.Fatal("function code, addresses or block size out of range")
+	}
+
+	conf := modbus.ClientConfig{ReadTimeout: *timeout}
+	client := modbus.NewTCPClientWithConfig(conf)
+	if *isRTU {
+		client = modbus.NewRTUClientWithConfig(conf)
+	}
+	ctx := context.Background()
+	if err := client.Connect(ctx, *address); err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	scanConf := modbus.RegisterScanConfig{
+		UnitIDs:       unitIDs,
+		FunctionCode:  uint8(*functionCode),
+		Start:         uint16(*start),
+		End:           uint16(*end),
+		BlockSize:     uint16(*blockSize),
+		ProbeInterval: *interval,
+	}
+	scan := modbus.ScanRegistersTCP
+	if *isRTU {
+		scan = modbus.ScanRegistersRTU
+	}
+	results, err := scan(ctx, client, scanConf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseUnitIDs parses comma separated unit IDs and unit ID ranges (i.e. `1,2,10-12`)
+func parseUnitIDs(value string) ([]uint8, error) {
+	var result []uint8
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+		first, err := strconv.ParseUint(from, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unit ID: '%v'", part)
+		}
+		last, err := strconv.ParseUint(to, 10, 8)
+		if err != nil || last < first {
+			return nil, fmt.Errorf("invalid unit ID range: '%v'", part)
+		}
+		for id := first; id <= last; id++ {
+			result = append(result, uint8(id))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseUnitIDs(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        string
+		expect      []uint8
+		expectError string
+	}{
+		{name: "ok, single", when: "1", expect: []uint8{1}},
+		{name: "ok, list and range", when: "1, 5-7,255", expect: []uint8{1, 5, 6, 7, 255}},
+		{name: "nok, not a number", when: "x", expectError: "invalid unit ID: 'x'"},
+		{name: "nok, out of range", when: "256", expectError: "invalid unit ID: '256'"},
+		{name: "nok, reversed range", when: "7-5", expectError: "invalid unit ID range: '7-5'"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseUnitIDs(tc.when)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"sort"
+)
+
+// maxCoilsInWriteRequest is maximum amount of coils that can be written with single FC15 request
+const maxCoilsInWriteRequest = uint16(1968)
+
+// CoilWritePlanner writes desired states of sparse set of coils with minimal amount of requests. Planner reads current
+// coil states (FC1), writes only coils which state differs from desired state (FC5 for single coil, FC15 for span of
+// coils), verifies written states by reading them back (FC1) and reports exactly which coils were changed.
+type CoilWritePlanner struct {
+	UnitID uint8
+	IsRTU  bool
+	// MaxGap is maximum amount of unchanged coils between changed coils that are written with their current state to
+	// merge writes into single FC15 request. Defaults to 0 meaning that only adjacent changed coils are merged.
+	// Same gap is used to merge reads of current and verified states of desired coils.
+	// NB: coils in gap are overwritten with state read at the start of the execution.
+	MaxGap uint16
+}
+
+// CoilChange is change of single coil state made by CoilWritePlanner
+type CoilChange struct {
+	Address uint16
+	From    bool
+	To      bool
+}
+
+// CoilWriteReport is result of CoilWritePlanner execution
+type CoilWriteReport struct {
+	// Changed contains coils which state was changed, ordered by address
+	Changed []CoilChange
+	// Requests contains write requests that were sent
+	Requests []packet.Request
+	// Mismatched contains addresses of coils that did not have desired state when verified after writing
+	Mismatched []uint16
+}
+
+// coilSpan is range of coils written with single request
+type coilSpan struct {
+	startAddress uint16
+	coils        []bool
+}
+
+// Execute writes given desired coil states (address to state) to the device and verifies them.
+func (p CoilWritePlanner) Execute(ctx context.Context, client Doer, desired map[uint16]bool) (CoilWriteReport, error) {
+	report := CoilWriteReport{}
+	if len(desired) == 0 {
+		return report, nil
+	}
+	addresses := make([]uint16, 0, len(desired))
+	for a := range desired {
+		addresses = append(addresses, a)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i] < addresses[j] })
+
+	readRanges := p.readRanges(addresses)
+	current, err := p.readStates(ctx, client, readRanges)
+	if err != nil {
+		return report, fmt.Errorf("coil write planner failed to read current states: %w", err)
+	}
+
+	for _, a := range addresses {
+		if from := current[a]; from != desired[a] {
+			report.Changed = append(report.Changed, CoilChange{Address: a, From: from, To: desired[a]})
+		}
+	}
+	if len(report.Changed) == 0 {
+		return report, nil
+	}
+
+	for _, span := range p.spans(report.Changed, current) {
+		req, err := p.writeRequest(span)
+		if err != nil {
+			return report, err
+		}
+		if _, err := client.Do(ctx, req); err != nil {
+			return report, fmt.Errorf("coil write planner failed to write coils at address %v: %w", span.startAddress, err)
+		}
+		report.Requests = append(report.Requests, req)
+	}
+
+	verified, err := p.readStates(ctx, client, readRanges)
+	if err != nil {
+		return report, fmt.Errorf("coil write planner failed to verify written states: %w", err)
+	}
+	for _, a := range addresses {
+		if verified[a] != desired[a] {
+			report.Mismatched = append(report.Mismatched, a)
+		}
+	}
+	if len(report.Mismatched) > 0 {
+		return report, fmt.Errorf("coil write planner verification failed for addresses: %v", report.Mismatched)
+	}
+	return report, nil
+}
+
+// spans groups changes into write spans. Changes closer than MaxGap coils are merged and coils between them are
+// written with their current state.
+func (p CoilWritePlanner) spans(changes []CoilChange, current map[uint16]bool) []coilSpan {
+	var result []coilSpan
+	for i := 0; i < len(changes); {
+		start := changes[i].Address
+		end := start
+		j := i + 1
+		for ; j < len(changes); j++ {
+			a := changes[j].Address
+			if uint32(a)-uint32(end)-1 > uint32(p.MaxGap) || uint32(a)-uint32(start)+1 > uint32(maxCoilsInWriteRequest) {
+				break
+			}
+			end = a
+		}
+		coils := make([]bool, end-start+1)
+		for k := range coils {
+			coils[k] = current[start+uint16(k)]
+		}
+		for _, c := range changes[i:j] {
+			coils[c.Address-start] = c.To
+		}
+		result = append(result, coilSpan{startAddress: start, coils: coils})
+		i = j
+	}
+	return result
+}
+
+func (p CoilWritePlanner) writeRequest(span coilSpan) (packet.Request, error) {
+	if len(span.coils) == 1 {
+		if p.IsRTU {
+			return packet.NewWriteSingleCoilRequestRTU(p.UnitID, span.startAddress, span.coils[0])
+		}
+		return packet.NewWriteSingleCoilRequestTCP(p.UnitID, span.startAddress, span.coils[0])
+	}
+	if p.IsRTU {
+		return packet.NewWriteMultipleCoilsRequestRTU(p.UnitID, span.startAddress, span.coils)
+	}
+	return packet.NewWriteMultipleCoilsRequestTCP(p.UnitID, span.startAddress, span.coils)
+}
+
+// readRanges groups sorted addresses into address ranges (first and last address, inclusive) to read. Addresses closer
+// than MaxGap coils are read with the same range. Coils between changes merged into a write span are always within
+// same read range as gap between changes is not larger than MaxGap.
+func (p CoilWritePlanner) readRanges(addresses []uint16) [][2]uint16 {
+	var result [][2]uint16
+	for _, a := range addresses {
+		if n := len(result); n > 0 && uint32(a)-uint32(result[n-1][1])-1 <= uint32(p.MaxGap) {
+			result[n-1][1] = a
+			continue
+		}
+		result = append(result, [2]uint16{a, a})
+	}
+	return result
+}
+
+// readStates reads coil states of given address ranges
+func (p CoilWritePlanner) readStates(ctx context.Context, client Doer, ranges [][2]uint16) (map[uint16]bool, error) {
+	result := map[uint16]bool{}
+	for _, r := range ranges {
+		coils, err := p.readCoils(ctx, client, r[0], r[1])
+		if err != nil {
+			return nil, err
+		}
+		for i, state := range coils {
+			result[r[0]+uint16(i)] = state
+		}
+	}
+	return result, nil
+}
+
+// readCoils reads coil states from first to last address (inclusive) with as many requests as needed
+func (p CoilWritePlanner) readCoils(ctx context.Context, client Doer, first uint16, last uint16) ([]bool, error) {
+	result := make([]bool, 0, int(last-first)+1)
+	for start := uint32(first); start <= uint32(last); start += uint32(packet.MaxCoilsInReadResponse) {
+		quantity := uint32(packet.MaxCoilsInReadResponse)
+		if rest := uint32(last) - start + 1; rest < quantity {
+			quantity = rest
+		}
+		read := readRequest{
+			isRTU:        p.IsRTU,
+			unitID:       p.UnitID,
+			functionCode: packet.FunctionReadCoils,
+			startAddress: uint16(start),
+			quantity:     uint16(quantity),
+		}
+		req, err := read.newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		payload := readResponsePayload(resp, p.IsRTU)
+		if len(payload)*8 < int(quantity) {
+			return nil, fmt.Errorf("received less coils than requested: %v", len(payload)*8)
+		}
+		for i := 0; i < int(quantity); i++ {
+			result = append(result, payload[i/8]&(1<<(i%8)) != 0)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fakeCoilsDevice emulates device coils for FC1, FC5 and FC15 TCP requests
+type fakeCoilsDevice struct {
+	coils    map[uint16]bool
+	stuck    map[uint16]bool // coils that ignore writes
+	requests []packet.Request
+}
+
+func (d *fakeCoilsDevice) set(address uint16, state bool) {
+	if !d.stuck[address] {
+		d.coils[address] = state
+	}
+}
+
+func (d *fakeCoilsDevice) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	d.requests = append(d.requests, req)
+	switch r := req.(type) {
+	case *packet.ReadCoilsRequestTCP:
+		coils := make([]bool, r.Quantity)
+		for i := range coils {
+			coils[i] = d.coils[r.StartAddress+uint16(i)]
+		}
+		data := packet.CoilsToBytes(coils)
+		return &packet.ReadCoilsResponseTCP{
+			MBAPHeader:        r.MBAPHeader,
+			ReadCoilsResponse: packet.ReadCoilsResponse{UnitID: r.UnitID, CoilsByteLength: uint8(len(data)), Data: data},
+		}, nil
+	case *packet.WriteSingleCoilRequestTCP:
+		d.set(r.Address, r.CoilState)
+		return &packet.WriteSingleCoilResponseTCP{
+			MBAPHeader:              r.MBAPHeader,
+			WriteSingleCoilResponse: packet.WriteSingleCoilResponse{UnitID: r.UnitID, StartAddress: r.Address, CoilState: r.CoilState},
+		}, nil
+	case *packet.WriteMultipleCoilsRequestTCP:
+		for i := uint16(0); i < r.CoilCount; i++ {
+			d.set(r.StartAddress+i, r.Data[i/8]&(1<<(i%8)) != 0)
+		}
+		return &packet.WriteMultipleCoilsResponseTCP{
+			MBAPHeader:                 r.MBAPHeader,
+			WriteMultipleCoilsResponse: packet.WriteMultipleCoilsResponse{UnitID: r.UnitID, StartAddress: r.StartAddress, CoilCount: r.CoilCount},
+		}, nil
+	}
+	return nil, errors.New("unsupported request")
+}
+
+func TestCoilWritePlanner_Execute(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenMaxGap uint16
+		expectReads [][2]uint16 // start address, coil count
+		expectSpans [][2]uint16 // start address, coil count
+	}{
+		{
+			name:        "ok, no gap merging",
+			expectReads: [][2]uint16{{10, 2}, {13, 1}, {15, 1}, {20, 1}},
+			expectSpans: [][2]uint16{{10, 2}, {13, 1}, {20, 1}},
+		},
+		{
+			name:        "ok, merge small gaps",
+			givenMaxGap: 1,
+			expectReads: [][2]uint16{{10, 6}, {20, 1}},
+			expectSpans: [][2]uint16{{10, 4}, {20, 1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := &fakeCoilsDevice{coils: map[uint16]bool{12: true, 15: true}}
+			planner := CoilWritePlanner{UnitID: 1, MaxGap: tc.givenMaxGap}
+
+			report, err := planner.Execute(context.Background(), device, map[uint16]bool{
+				10: true,
+				11: true,
+				13: true,
+				15: true, // already in desired state
+				20: true,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, []CoilChange{
+				{Address: 10, From: false, To: true},
+				{Address: 11, From: false, To: true},
+				{Address: 13, From: false, To: true},
+				{Address: 20, From: false, To: true},
+			}, report.Changed)
+			assert.Nil(t, report.Mismatched)
+
+			spans := make([][2]uint16, 0, len(report.Requests))
+			for _, r := range report.Requests {
+				switch w := r.(type) {
+				case *packet.WriteSingleCoilRequestTCP:
+					spans = append(spans, [2]uint16{w.Address, 1})
+				case *packet.WriteMultipleCoilsRequestTCP:
+					spans = append(spans, [2]uint16{w.StartAddress, w.CoilCount})
+				}
+			}
+			assert.Equal(t, tc.expectSpans, spans)
+			assert.True(t, device.coils[12]) // gap coil keeps its state
+
+			reads := make([][2]uint16, 0, 2*len(tc.expectReads))
+			for _, r := range device.requests {
+				if read, ok := r.(*packet.ReadCoilsRequestTCP); ok {
+					reads = append(reads, [2]uint16{read.StartAddress, read.Quantity})
+				}
+			}
+			// current states are read and written states are verified with the same reads
+			assert.Equal(t, append(tc.expectReads, tc.expectReads...), reads)
+			assert.Len(t, device.requests, 2*len(tc.expectReads)+len(tc.expectSpans))
+		})
+	}
+}
+
+func TestCoilWritePlanner_Execute_nothingToChange(t *testing.T) {
+	device := &fakeCoilsDevice{coils: map[uint16]bool{1: true}}
+
+	report, err := CoilWritePlanner{}.Execute(context.Background(), device, map[uint16]bool{1: true, 2: false})
+
+	assert.NoError(t, err)
+	assert.Equal(t, CoilWriteReport{}, report)
+	assert.Len(t, device.requests, 1)
+}
+
+func TestCoilWritePlanner_Execute_verificationFails(t *testing.T) {
+	device := &fakeCoilsDevice{coils: map[uint16]bool{}, stuck: map[uint16]bool{2: true}}
+
+	report, err := CoilWritePlanner{}.Execute(context.Background(), device, map[uint16]bool{1: true, 2: true})
+
+	assert.EqualError(t, err, "coil write planner verification failed for addresses: [2]")
+	assert.Equal(t, []uint16{2}, report.Mismatched)
+	assert.Len(t, report.Changed, 2)
+}
@@ -0,0 +1,248 @@
+package modbus
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CoverageKind describes how address range is read by requests
+type CoverageKind string
+
+const (
+	// CoverageField is address range read by single request and used by fields
+	CoverageField CoverageKind = "field"
+	// CoverageGap is address range read by request but not used by any field (wasted read)
+	CoverageGap CoverageKind = "gap"
+	// CoverageOverlap is address range read by more than one request
+	CoverageOverlap CoverageKind = "overlap"
+	// CoverageInvalid is address range read by request but known to be invalid for the device (i.e. device responds
+	// with Illegal Data Address exception when it is read)
+	CoverageInvalid CoverageKind = "invalid"
+)
+
+// AddressRange is range of addresses from Start to End (inclusive)
+type AddressRange struct {
+	Start uint16 `json:"start"`
+	End   uint16 `json:"end"`
+}
+
+// CoverageMap describes which addresses are read by which requests, which of them are gaps not used by any field,
+// which are read by multiple requests and which fall into invalid address ranges. It helps commissioning engineers to
+// spot overlaps and wasted reads.
+type CoverageMap struct {
+	Groups []CoverageGroup `json:"groups"`
+}
+
+// CoverageGroup is coverage of single address space (server address + unit ID + function code)
+type CoverageGroup struct {
+	ServerAddress string            `json:"server_address"`
+	UnitID        uint8             `json:"unit_id"`
+	FunctionCode  uint8             `json:"function_code"`
+	Segments      []CoverageSegment `json:"segments"`
+}
+
+// CoverageSegment is continuous address range with same coverage
+type CoverageSegment struct {
+	AddressRange
+	Kind CoverageKind `json:"kind"`
+	// Requests contains indexes of requests (in slice given to NewCoverageMap) that read this range
+	Requests []int `json:"requests"`
+	// Fields contains names of fields that use this range
+	Fields []string `json:"fields,omitempty"`
+}
+
+// CoverageInvalidRange is address range known to be invalid for the device
+type CoverageInvalidRange struct {
+	ServerAddress string
+	UnitID        uint8
+	FunctionCode  uint8
+	AddressRange
+}
+
+type coverageGroupKey struct {
+	serverAddress string
+	unitID        uint8
+	functionCode  uint8
+}
+
+type coverageAddress struct {
+	requests []int
+	fields   []string
+	invalid  bool
+}
+
+// NewCoverageMap creates coverage map of given read requests (FC1/FC2/FC3/FC4). Invalid ranges are optional and mark
+// read addresses that are known to be invalid for the device.
+func NewCoverageMap(requests []BuilderRequest, invalid []CoverageInvalidRange) CoverageMap {
+	groups := map[coverageGroupKey]map[uint16]*coverageAddress{}
+	for i, r := range requests {
+		req, ok := asReadRequest(r.Request)
+		if !ok || req.quantity == 0 {
+			continue
+		}
+		key := coverageGroupKey{serverAddress: r.ServerAddress, unitID: req.unitID, functionCode: req.functionCode}
+		addresses, ok := groups[key]
+		if !ok {
+			addresses = map[uint16]*coverageAddress{}
+			groups[key] = addresses
+		}
+		end := uint32(req.startAddress) + uint32(req.quantity)
+		for a := uint32(req.startAddress); a < end; a++ {
+			ca, ok := addresses[uint16(a)]
+			if !ok {
+				ca = &coverageAddress{}
+				addresses[uint16(a)] = ca
+			}
+			ca.requests = append(ca.requests, i)
+		}
+		for _, f := range r.Fields {
+			fieldEnd := uint32(f.Address) + uint32(f.registerSize())
+			for a := uint32(f.Address); a < fieldEnd; a++ {
+				if ca, ok := addresses[uint16(a)]; ok && !slices.Contains(ca.fields, f.Name) {
+					ca.fields = append(ca.fields, f.Name)
+				}
+			}
+		}
+	}
+	for _, ir := range invalid {
+		addresses := groups[coverageGroupKey{serverAddress: ir.ServerAddress, unitID: ir.UnitID, functionCode: ir.FunctionCode}]
+		for a := uint32(ir.Start); a <= uint32(ir.End); a++ {
+			if ca, ok := addresses[uint16(a)]; ok {
+				ca.invalid = true
+			}
+		}
+	}
+
+	result := CoverageMap{Groups: make([]CoverageGroup, 0, len(groups))}
+	for key, addresses := range groups {
+		result.Groups = append(result.Groups, CoverageGroup{
+			ServerAddress: key.serverAddress,
+			UnitID:        key.unitID,
+			FunctionCode:  key.functionCode,
+			Segments:      coverageSegments(addresses),
+		})
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		a, b := result.Groups[i], result.Groups[j]
+		if a.ServerAddress != b.ServerAddress {
+			return a.ServerAddress < b.ServerAddress
+		}
+		if a.UnitID != b.UnitID {
+			return a.UnitID < b.UnitID
+		}
+		return a.FunctionCode < b.FunctionCode
+	})
+	return result
+}
+
+func coverageSegments(addresses map[uint16]*coverageAddress) []CoverageSegment {
+	sorted := make([]uint16, 0, len(addresses))
+	for a := range addresses {
+		sorted = append(sorted, a)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var result []CoverageSegment
+	for _, a := range sorted {
+		ca := addresses[a]
+		kind := CoverageField
+		switch {
+		case ca.invalid:
+			kind = CoverageInvalid
+		case len(ca.requests) > 1:
+			kind = CoverageOverlap
+		case len(ca.fields) == 0:
+			kind = CoverageGap
+		}
+		if n := len(result); n > 0 {
+			last := &result[n-1]
+			if uint32(last.End)+1 == uint32(a) && last.Kind == kind &&
+				slices.Equal(last.Requests, ca.requests) && slices.Equal(last.Fields, ca.fields) {
+				last.End = a
+				continue
+			}
+		}
+		result = append(result, CoverageSegment{
+			AddressRange: AddressRange{Start: a, End: a},
+			Kind:         kind,
+			Requests:     ca.requests,
+			Fields:       ca.fields,
+		})
+	}
+	return result
+}
+
+// RenderText writes coverage map as human-readable text table
+func (m CoverageMap) RenderText(w io.Writer) error {
+	sb := strings.Builder{}
+	for _, g := range m.Groups {
+		fmt.Fprintf(&sb, "%v unit %v FC%v\n", g.ServerAddress, g.UnitID, g.FunctionCode)
+		for _, s := range g.Segments {
+			fmt.Fprintf(&sb, "  %5d-%-5d %-8s requests %v", s.Start, s.End, s.Kind, s.Requests)
+			if len(s.Fields) > 0 {
+				fmt.Fprintf(&sb, " fields %v", strings.Join(s.Fields, ","))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var coverageColors = map[CoverageKind]string{
+	CoverageField:   "#4caf50",
+	CoverageGap:     "#bdbdbd",
+	CoverageOverlap: "#ff9800",
+	CoverageInvalid: "#f44336",
+}
+
+// RenderSVG writes coverage map as simple SVG image. Each group is rendered as single row where address ranges are
+// coloured by coverage kind. Hovering over range shows its details.
+func (m CoverageMap) RenderSVG(w io.Writer) error {
+	const (
+		labelWidth = 240
+		rowHeight  = 24
+		maxWidth   = 1000.0
+	)
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="12">`+"\n",
+		labelWidth+int(maxWidth), rowHeight*(len(m.Groups)+1))
+	for i, g := range m.Groups {
+		y := i * rowHeight
+		fmt.Fprintf(&sb, `<text x="4" y="%d">%s</text>`+"\n",
+			y+16, html.EscapeString(fmt.Sprintf("%v unit %v FC%v", g.ServerAddress, g.UnitID, g.FunctionCode)))
+		if len(g.Segments) == 0 {
+			continue
+		}
+		first := float64(g.Segments[0].Start)
+		scale := maxWidth / (float64(g.Segments[len(g.Segments)-1].End) - first + 1)
+		for _, s := range g.Segments {
+			title := fmt.Sprintf("%v-%v %v requests %v", s.Start, s.End, s.Kind, s.Requests)
+			if len(s.Fields) > 0 {
+				title += " fields " + strings.Join(s.Fields, ",")
+			}
+			fmt.Fprintf(&sb, `<rect x="%.2f" y="%d" width="%.2f" height="%d" fill="%s"><title>%s</title></rect>`+"\n",
+				labelWidth+(float64(s.Start)-first)*scale,
+				y+2,
+				float64(s.End-s.Start+1)*scale,
+				rowHeight-4,
+				coverageColors[s.Kind],
+				html.EscapeString(title),
+			)
+		}
+	}
+	x := labelWidth
+	y := len(m.Groups)*rowHeight + 16
+	for _, kind := range []CoverageKind{CoverageField, CoverageGap, CoverageOverlap, CoverageInvalid} {
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/><text x="%d" y="%d">%s</text>`+"\n",
+			x, y-10, coverageColors[kind], x+16, y, kind)
+		x += 100
+	}
+	sb.WriteString("</svg>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,95 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func exampleCoverageRequests(t *testing.T) []BuilderRequest {
+	req1, err := packet.NewReadHoldingRegistersRequestTCP(1, 0, 6)
+	assert.NoError(t, err)
+	req2, err := packet.NewReadHoldingRegistersRequestTCP(1, 4, 4)
+	assert.NoError(t, err)
+	return []BuilderRequest{
+		{
+			Request:       req1,
+			ServerAddress: "localhost:502",
+			UnitID:        1,
+			StartAddress:  0,
+			Fields: Fields{
+				{Name: "a", Address: 0, Type: FieldTypeUint32},
+				{Name: "b", Address: 5, Type: FieldTypeUint16},
+			},
+		},
+		{
+			Request:       req2,
+			ServerAddress: "localhost:502",
+			UnitID:        1,
+			StartAddress:  4,
+			Fields: Fields{
+				{Name: "c", Address: 6, Type: FieldTypeUint16},
+			},
+		},
+	}
+}
+
+func TestNewCoverageMap(t *testing.T) {
+	result := NewCoverageMap(exampleCoverageRequests(t), []CoverageInvalidRange{
+		{ServerAddress: "localhost:502", UnitID: 1, FunctionCode: packet.FunctionReadHoldingRegisters, AddressRange: AddressRange{Start: 7, End: 10}},
+	})
+
+	expect := CoverageMap{Groups: []CoverageGroup{
+		{
+			ServerAddress: "localhost:502",
+			UnitID:        1,
+			FunctionCode:  packet.FunctionReadHoldingRegisters,
+			Segments: []CoverageSegment{
+				{AddressRange: AddressRange{Start: 0, End: 1}, Kind: CoverageField, Requests: []int{0}, Fields: []string{"a"}},
+				{AddressRange: AddressRange{Start: 2, End: 3}, Kind: CoverageGap, Requests: []int{0}},
+				{AddressRange: AddressRange{Start: 4, End: 4}, Kind: CoverageOverlap, Requests: []int{0, 1}},
+				{AddressRange: AddressRange{Start: 5, End: 5}, Kind: CoverageOverlap, Requests: []int{0, 1}, Fields: []string{"b"}},
+				{AddressRange: AddressRange{Start: 6, End: 6}, Kind: CoverageField, Requests: []int{1}, Fields: []string{"c"}},
+				{AddressRange: AddressRange{Start: 7, End: 7}, Kind: CoverageInvalid, Requests: []int{1}},
+			},
+		},
+	}}
+	assert.Equal(t, expect, result)
+
+	b, err := json.Marshal(result.Groups[0].Segments[0])
+	assert.NoError(t, err)
+	assert.Equal(t, `{"start":0,"end":1,"kind":"field","requests":[0],"fields":["a"]}`, string(b))
+}
+
+func TestCoverageMap_RenderText(t *testing.T) {
+	result := NewCoverageMap(exampleCoverageRequests(t), nil)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, result.RenderText(&buf))
+
+	expect := `localhost:502 unit 1 FC3
+      0-1     field    requests [0] fields a
+      2-3     gap      requests [0]
+      4-4     overlap  requests [0 1]
+      5-5     overlap  requests [0 1] fields b
+      6-6     field    requests [1] fields c
+      7-7     gap      requests [1]
+`
+	assert.Equal(t, expect, buf.String())
+}
+
+func TestCoverageMap_RenderSVG(t *testing.T) {
+	result := NewCoverageMap(exampleCoverageRequests(t), nil)
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, result.RenderSVG(&buf))
+
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.Contains(t, svg, `<text x="4" y="16">localhost:502 unit 1 FC3</text>`)
+	assert.Contains(t, svg, `<rect x="240.00" y="2" width="250.00" height="20" fill="#4caf50"><title>0-1 field requests [0] fields a</title></rect>`)
+	assert.Equal(t, 6+4, strings.Count(svg, "<rect"))
+}
@@ -0,0 +1,161 @@
+package modbus
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultCSVFileNameLayout = "2006-01-02.csv"
+
+var csvRecorderHeader = []string{"time", "server", "unit", "field", "address", "value", "error"}
+
+// CSVRecorderConfig is configuration for CSVRecorder
+type CSVRecorderConfig struct {
+	// Dir is directory where recording files are created. Directory must exist.
+	Dir string
+	// FileNameLayout is time layout (see time.Format) used to create file name from record time. When formatted name
+	// changes new file is started, so layout determines how files rotate. Defaults to `2006-01-02.csv` (daily files).
+	FileNameLayout string
+	// FlushInterval is how often buffered records are flushed to the file (measured by record times). Zero flushes
+	// after every Record call.
+	FlushInterval time.Duration
+}
+
+// CSVRecorder records extracted field values into rotating CSV files. Every value is written as separate row with
+// columns: time, server, unit, field, address, value, error. Header row is written to every new file. Existing files
+// are appended to. Useful for capturing data during commissioning.
+type CSVRecorder struct {
+	mu   sync.Mutex
+	conf CSVRecorderConfig
+
+	fileName  string
+	file      *os.File
+	writer    *csv.Writer
+	lastFlush time.Time
+}
+
+// NewCSVRecorder creates new CSVRecorder. Files are created lazily with first recorded values.
+func NewCSVRecorder(conf CSVRecorderConfig) (*CSVRecorder, error) {
+	if conf.Dir == "" {
+		return nil, errors.New("csv recorder directory can not be empty")
+	}
+	if conf.FlushInterval < 0 {
+		return nil, errors.New("csv recorder flush interval can not be negative")
+	}
+	if conf.FileNameLayout == "" {
+		conf.FileNameLayout = defaultCSVFileNameLayout
+	}
+	return &CSVRecorder{conf: conf}, nil
+}
+
+// Record writes given field values with record time to the file that record time belongs to
+func (r *CSVRecorder) Record(recordTime time.Time, values []FieldValue) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.rotate(recordTime); err != nil {
+		return err
+	}
+	timestamp := recordTime.Format(time.RFC3339Nano)
+	for _, v := range values {
+		value := ""
+		errText := ""
+		if v.Error != nil {
+			errText = v.Error.Error()
+		} else if v.Value != nil {
+			value = fmt.Sprint(v.Value)
+		}
+		row := []string{
+			timestamp,
+			v.Field.ServerAddress,
+			strconv.Itoa(int(v.Field.UnitID)),
+			v.Field.Name,
+			strconv.Itoa(int(v.Field.Address)),
+			value,
+			errText,
+		}
+		if err := r.writer.Write(row); err != nil {
+			return fmt.Errorf("csv recorder write failed: %w", err)
+		}
+	}
+	if r.conf.FlushInterval == 0 || recordTime.Sub(r.lastFlush) >= r.conf.FlushInterval {
+		r.lastFlush = recordTime
+		return r.flush()
+	}
+	return nil
+}
+
+// Flush writes buffered records to the current file
+func (r *CSVRecorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flush()
+}
+
+// Close flushes buffered records and closes the current file
+func (r *CSVRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeFile()
+}
+
+func (r *CSVRecorder) flush() error {
+	if r.writer == nil {
+		return nil
+	}
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		return fmt.Errorf("csv recorder flush failed: %w", err)
+	}
+	return nil
+}
+
+func (r *CSVRecorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	flushErr := r.flush()
+	closeErr := r.file.Close()
+	r.file = nil
+	r.writer = nil
+	r.fileName = ""
+	if flushErr != nil {
+		return flushErr
+	}
+	return closeErr
+}
+
+func (r *CSVRecorder) rotate(recordTime time.Time) error {
+	fileName := recordTime.Format(r.conf.FileNameLayout)
+	if r.file != nil && fileName == r.fileName {
+		return nil
+	}
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(r.conf.Dir, fileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("csv recorder failed to open file: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("csv recorder failed to open file: %w", err)
+	}
+	r.file = file
+	r.fileName = fileName
+	r.writer = csv.NewWriter(file)
+	r.lastFlush = recordTime
+	if stat.Size() == 0 {
+		if err := r.writer.Write(csvRecorderHeader); err != nil {
+			return fmt.Errorf("csv recorder write failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package modbus
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCSVRecorder_Record(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewCSVRecorder(CSVRecorderConfig{Dir: dir})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	day1 := time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC)
+	err = recorder.Record(day1, []FieldValue{
+		{Field: Field{Name: "temperature", ServerAddress: "localhost:502", UnitID: 1, Address: 10}, Value: 21.5},
+		{Field: Field{Name: "state", ServerAddress: "localhost:502", UnitID: 1, Address: 11}, Error: errors.New("failed")},
+	})
+	assert.NoError(t, err)
+	err = recorder.Record(day1.Add(2*time.Second), []FieldValue{
+		{Field: Field{Name: "alarm", UnitID: 2, Address: 1}, Value: true},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.Close())
+
+	first, err := os.ReadFile(filepath.Join(dir, "2024-03-01.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, `time,server,unit,field,address,value,error
+2024-03-01T23:59:59Z,localhost:502,1,temperature,10,21.5,
+2024-03-01T23:59:59Z,localhost:502,1,state,11,,failed
+`, string(first))
+
+	second, err := os.ReadFile(filepath.Join(dir, "2024-03-02.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, `time,server,unit,field,address,value,error
+2024-03-02T00:00:01Z,,2,alarm,1,true,
+`, string(second))
+
+	// existing file is appended to without repeating header
+	recorder, _ = NewCSVRecorder(CSVRecorderConfig{Dir: dir})
+	assert.NoError(t, recorder.Record(day1.Add(3*time.Second), []FieldValue{{Field: Field{Name: "x"}, Value: uint16(1)}}))
+	assert.NoError(t, recorder.Close())
+
+	second, err = os.ReadFile(filepath.Join(dir, "2024-03-02.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, `time,server,unit,field,address,value,error
+2024-03-02T00:00:01Z,,2,alarm,1,true,
+2024-03-02T00:00:02Z,,0,x,0,1,
+`, string(second))
+}
+
+func TestCSVRecorder_FlushInterval(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewCSVRecorder(CSVRecorderConfig{Dir: dir, FileNameLayout: "rec.csv", FlushInterval: 10 * time.Second})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer recorder.Close()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, recorder.Record(now, []FieldValue{{Field: Field{Name: "a"}, Value: 1}}))
+	content, _ := os.ReadFile(filepath.Join(dir, "rec.csv"))
+	assert.Equal(t, "", string(content))
+
+	assert.NoError(t, recorder.Record(now.Add(10*time.Second), []FieldValue{{Field: Field{Name: "b"}, Value: 2}}))
+	content, _ = os.ReadFile(filepath.Join(dir, "rec.csv"))
+	assert.Equal(t, `time,server,unit,field,address,value,error
+2024-03-01T12:00:00Z,,0,a,0,1,
+2024-03-01T12:00:10Z,,0,b,0,2,
+`, string(content))
+}
+
+func TestNewCSVRecorder_errors(t *testing.T) {
+	_, err := NewCSVRecorder(CSVRecorderConfig{})
+	assert.EqualError(t, err, "csv recorder directory can not be empty")
+
+	_, err = NewCSVRecorder(CSVRecorderConfig{Dir: ".", FlushInterval: -1})
+	assert.EqualError(t, err, "csv recorder flush interval can not be negative")
+}
@@ -0,0 +1,82 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// DeviceIdentification is device identification read with Read Device Identification (FC43/14) requests
+type DeviceIdentification struct {
+	VendorName         string
+	ProductCode        string
+	MajorMinorRevision string
+	// ConformityLevel is identification conformity level reported by the device
+	ConformityLevel uint8
+	// Objects contains values of all received objects by object ID
+	Objects map[uint8][]byte
+}
+
+// ReadDeviceIdentificationTCP reads basic device identification (vendor name, product code and revision) from the
+// device using Read Device Identification (FC43/14) Modbus TCP requests. See ReadDeviceIdentificationRTU for details.
+func ReadDeviceIdentificationTCP(ctx context.Context, client Doer, unitID uint8) (DeviceIdentification, error) {
+	return readDeviceIdentification(ctx, client, unitID, false)
+}
+
+// ReadDeviceIdentificationRTU reads basic device identification (vendor name, product code and revision) from the
+// device using Read Device Identification (FC43/14) Modbus RTU requests. When objects do not fit into single response
+// device sets "more follows" flag and objects are streamed with next requests starting from next object ID.
+func ReadDeviceIdentificationRTU(ctx context.Context, client Doer, unitID uint8) (DeviceIdentification, error) {
+	return readDeviceIdentification(ctx, client, unitID, true)
+}
+
+func readDeviceIdentification(ctx context.Context, client Doer, unitID uint8, isRTU bool) (DeviceIdentification, error) {
+	result := DeviceIdentification{Objects: map[uint8][]byte{}}
+
+	objectID := packet.DeviceIDObjectVendorName
+	for {
+		var req packet.Request
+		var err error
+		if isRTU {
+			req, err = packet.NewReadDeviceIdentificationRequestRTU(unitID, packet.ReadDeviceIDCodeBasic, objectID)
+		} else {
+			req, err = packet.NewReadDeviceIdentificationRequestTCP(unitID, packet.ReadDeviceIDCodeBasic, objectID)
+		}
+		if err != nil {
+			return DeviceIdentification{}, err
+		}
+		resp, err := client.Do(ctx, req)
+		if err != nil {
+			return DeviceIdentification{}, err
+		}
+		var r packet.ReadDeviceIdentificationResponse
+		switch tmp := resp.(type) {
+		case *packet.ReadDeviceIdentificationResponseTCP:
+			r = tmp.ReadDeviceIdentificationResponse
+		case *packet.ReadDeviceIdentificationResponseRTU:
+			r = tmp.ReadDeviceIdentificationResponse
+		case *packet.ReadDeviceIdentificationResponseASCII:
+			r = tmp.ReadDeviceIdentificationResponse
+		default:
+			return DeviceIdentification{}, fmt.Errorf("unexpected response type for read device identification request: %T", resp)
+		}
+
+		result.ConformityLevel = r.ConformityLevel
+		for _, o := range r.Objects {
+			result.Objects[o.ID] = o.Value
+		}
+		if !r.MoreFollows {
+			break
+		}
+		// next object ID must move forward, otherwise misbehaving device could make us loop forever
+		if r.NextObjectID <= objectID {
+			return DeviceIdentification{}, fmt.Errorf("read device identification next object ID does not advance: %v", r.NextObjectID)
+		}
+		objectID = r.NextObjectID
+	}
+
+	result.VendorName = string(result.Objects[packet.DeviceIDObjectVendorName])
+	result.ProductCode = string(result.Objects[packet.DeviceIDObjectProductCode])
+	result.MajorMinorRevision = string(result.Objects[packet.DeviceIDObjectMajorMinorRevision])
+	return result, nil
+}
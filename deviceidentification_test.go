@@ -0,0 +1,59 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadDeviceIdentificationTCP(t *testing.T) {
+	var objectIDs []uint8
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		r := req.(*packet.ReadDeviceIdentificationRequestTCP)
+		objectIDs = append(objectIDs, r.ObjectID)
+		resp := packet.ReadDeviceIdentificationResponse{
+			UnitID:           r.UnitID,
+			ReadDeviceIDCode: packet.ReadDeviceIDCodeBasic,
+			ConformityLevel:  0x81,
+		}
+		if r.ObjectID == 0 {
+			// first response fits only vendor name and product code
+			resp.MoreFollows = true
+			resp.NextObjectID = packet.DeviceIDObjectMajorMinorRevision
+			resp.Objects = []packet.DeviceIdentificationObject{
+				{ID: packet.DeviceIDObjectVendorName, Value: []byte("ACME")},
+				{ID: packet.DeviceIDObjectProductCode, Value: []byte("X-100")},
+			}
+		} else {
+			resp.Objects = []packet.DeviceIdentificationObject{
+				{ID: packet.DeviceIDObjectMajorMinorRevision, Value: []byte("V1.2")},
+			}
+		}
+		return &packet.ReadDeviceIdentificationResponseTCP{MBAPHeader: r.MBAPHeader, ReadDeviceIdentificationResponse: resp}, nil
+	})
+
+	result, err := ReadDeviceIdentificationTCP(context.Background(), client, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint8{0, 2}, objectIDs)
+	assert.Equal(t, "ACME", result.VendorName)
+	assert.Equal(t, "X-100", result.ProductCode)
+	assert.Equal(t, "V1.2", result.MajorMinorRevision)
+	assert.Equal(t, uint8(0x81), result.ConformityLevel)
+	assert.Len(t, result.Objects, 3)
+}
+
+func TestReadDeviceIdentificationRTU_nextObjectIDDoesNotAdvance(t *testing.T) {
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		return &packet.ReadDeviceIdentificationResponseRTU{ReadDeviceIdentificationResponse: packet.ReadDeviceIdentificationResponse{
+			UnitID:       1,
+			MoreFollows:  true,
+			NextObjectID: 0,
+		}}, nil
+	})
+
+	_, err := ReadDeviceIdentificationRTU(context.Background(), client, 1)
+
+	assert.EqualError(t, err, "read device identification next object ID does not advance: 0")
+}
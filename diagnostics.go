@@ -0,0 +1,159 @@
+package modbus
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ConnectErrorKind classifies reason why connecting to the server failed
+type ConnectErrorKind string
+
+const (
+	// ConnectErrorUnknown is used when reason of connection failure could not be determined
+	ConnectErrorUnknown ConnectErrorKind = "unknown"
+	// ConnectErrorDNS is used when server host name could not be resolved
+	ConnectErrorDNS ConnectErrorKind = "dns"
+	// ConnectErrorRefused is used when server host is reachable but nothing is listening on the port
+	ConnectErrorRefused ConnectErrorKind = "refused"
+	// ConnectErrorUnreachable is used when server host or network is unreachable
+	ConnectErrorUnreachable ConnectErrorKind = "unreachable"
+	// ConnectErrorTimeout is used when connection was not established within connect timeout
+	ConnectErrorTimeout ConnectErrorKind = "timeout"
+	// ConnectErrorTLSHandshake is used when TLS handshake with server failed
+	ConnectErrorTLSHandshake ConnectErrorKind = "tls_handshake"
+)
+
+// ConnectError is error returned by Client.Connect. It contains details about connection attempt and classification
+// of the failure to make troubleshooting connection problems easier.
+type ConnectError struct {
+	// Address is address given to Connect
+	Address string
+	// Network is network part of the address (tcp, tcp4, udp etc.)
+	Network string
+	// ResolvedAddress is resolved remote address when connection attempt got that far
+	ResolvedAddress string
+	// Timeout is connect timeout that was used
+	Timeout time.Duration
+	Kind    ConnectErrorKind
+	Err     error
+}
+
+func newConnectError(address string, timeout time.Duration, err error) *ConnectError {
+	network, _ := addressExtractor(address)
+	result := &ConnectError{
+		Address: address,
+		Network: network,
+		Timeout: timeout,
+		Kind:    classifyConnectError(err),
+		Err:     err,
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Addr != nil {
+		result.ResolvedAddress = opErr.Addr.String()
+	}
+	return result
+}
+
+// Error returns error message with connection attempt details
+func (e *ConnectError) Error() string {
+	resolved := ""
+	if e.ResolvedAddress != "" {
+		resolved = fmt.Sprintf(", resolved: %v", e.ResolvedAddress)
+	}
+	return fmt.Sprintf(
+		"failed to connect to '%v' (network: %v%v, timeout: %v, kind: %v): %v",
+		e.Address,
+		e.Network,
+		resolved,
+		e.Timeout,
+		e.Kind,
+		e.Err,
+	)
+}
+
+// Unwrap allows unwrapping errors with errors.Is and errors.As
+func (e *ConnectError) Unwrap() error { return e.Err }
+
+func classifyConnectError(err error) ConnectErrorKind {
+	var dnsErr *net.DNSError
+	var recordHeaderErr tls.RecordHeaderError
+	var certErr *tls.CertificateVerificationError
+	var alertErr tls.AlertError
+	switch {
+	case errors.As(err, &dnsErr):
+		return ConnectErrorDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ConnectErrorRefused
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return ConnectErrorUnreachable
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return ConnectErrorTimeout
+	case errors.As(err, &recordHeaderErr), errors.As(err, &certErr), errors.As(err, &alertErr):
+		return ConnectErrorTLSHandshake
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ConnectErrorTimeout
+	}
+	return ConnectErrorUnknown
+}
+
+// ProbeResult is result of ProbeAddress diagnostics
+type ProbeResult struct {
+	Address string
+	Network string
+	// ResolvedIPs contains IP addresses host name resolved to
+	ResolvedIPs []string
+	// ResolveDuration is time it took to resolve host name
+	ResolveDuration time.Duration
+	// Reachable is true when connection to the port could be opened
+	Reachable bool
+	// ConnectDuration is time it took to open (or fail to open) connection to the port
+	ConnectDuration time.Duration
+	// Err is error that occurred during probing. Value of type *ConnectError when connecting failed.
+	Err error
+}
+
+// ProbeAddress is diagnostics helper that resolves host name of given address and checks if port is open by opening
+// connection to it. Connection is closed immediately and no Modbus traffic is sent.
+func ProbeAddress(ctx context.Context, address string, timeout time.Duration) ProbeResult {
+	network, addr := addressExtractor(address)
+	result := ProbeResult{Address: address, Network: network}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if host != "" {
+		start := time.Now()
+		ips, err := net.DefaultResolver.LookupHost(ctx, host)
+		result.ResolveDuration = time.Since(start)
+		if err != nil {
+			result.Err = newConnectError(address, timeout, err)
+			return result
+		}
+		result.ResolvedIPs = ips
+	}
+
+	dialAddress := address
+	if network == "tls" { // only port is checked, TLS handshake is not done
+		dialAddress = "tcp://" + addr
+	}
+	start := time.Now()
+	conn, err := newDialContextFunc(timeout, defaultTCPKeepAlive)(ctx, dialAddress)
+	result.ConnectDuration = time.Since(start)
+	if err != nil {
+		result.Err = newConnectError(address, timeout, err)
+		return result
+	}
+	result.Reachable = true
+	_ = conn.Close()
+	return result
+}
@@ -0,0 +1,96 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClassifyConnectError(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   error
+		expect ConnectErrorKind
+	}{
+		{
+			name:   "dns",
+			when:   &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "nope.invalid"}},
+			expect: ConnectErrorDNS,
+		},
+		{
+			name:   "refused",
+			when:   &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			expect: ConnectErrorRefused,
+		},
+		{
+			name:   "unreachable",
+			when:   &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)},
+			expect: ConnectErrorUnreachable,
+		},
+		{
+			name:   "timeout",
+			when:   fmt.Errorf("dial: %w", os.ErrDeadlineExceeded),
+			expect: ConnectErrorTimeout,
+		},
+		{
+			name:   "unknown",
+			when:   errors.New("something"),
+			expect: ConnectErrorUnknown,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, classifyConnectError(tc.when))
+		})
+	}
+}
+
+func TestClient_Connect_refused(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := l.Addr().String()
+	assert.NoError(t, l.Close()) // nothing is listening on this port anymore
+
+	client := NewTCPClientWithConfig(ClientConfig{ConnectTimeout: 500 * time.Millisecond})
+	err = client.Connect(context.Background(), addr)
+
+	var connErr *ConnectError
+	assert.True(t, errors.As(err, &connErr))
+	assert.Equal(t, ConnectErrorRefused, connErr.Kind)
+	assert.Equal(t, addr, connErr.ResolvedAddress)
+	assert.Equal(t, "tcp", connErr.Network)
+	assert.Equal(t, 500*time.Millisecond, connErr.Timeout)
+}
+
+func TestProbeAddress(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	result := ProbeAddress(context.Background(), l.Addr().String(), time.Second)
+
+	assert.NoError(t, result.Err)
+	assert.True(t, result.Reachable)
+	assert.Equal(t, []string{"127.0.0.1"}, result.ResolvedIPs)
+	assert.Equal(t, "tcp", result.Network)
+}
+
+func TestProbeAddress_notReachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := l.Addr().String()
+	assert.NoError(t, l.Close())
+
+	result := ProbeAddress(context.Background(), "tcp://"+addr, time.Second)
+
+	assert.False(t, result.Reachable)
+	var connErr *ConnectError
+	assert.True(t, errors.As(result.Err, &connErr))
+	assert.Equal(t, ConnectErrorRefused, connErr.Kind)
+}
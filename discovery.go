@@ -0,0 +1,195 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DiscoveryProbe is type of request used to check if endpoint is Modbus device
+type DiscoveryProbe uint8
+
+const (
+	// DiscoveryProbeDeviceIdentification probes endpoints with Read Device Identification (FC43/14) request. Device
+	// responding with Modbus exception (i.e. does not support FC43) is still considered responsive.
+	DiscoveryProbeDeviceIdentification DiscoveryProbe = 0
+	// DiscoveryProbeHoldingRegister probes endpoints by reading known holding register (FC3)
+	DiscoveryProbeHoldingRegister DiscoveryProbe = 1
+)
+
+// maxDiscoveryHosts limits size of the subnet that can be probed
+const maxDiscoveryHosts = 65536
+
+// DiscoveryConfig is configuration for Discover
+type DiscoveryConfig struct {
+	// Subnet is subnet to probe in CIDR notation (i.e. `192.168.0.0/24`). Network and broadcast addresses of IPv4
+	// subnets larger than /31 are skipped. Maximum subnet size is 65536 addresses.
+	Subnet string
+	// Ports are ports probed on every host. Defaults to 502.
+	Ports []uint16
+	// Network is network used to connect to endpoints (`tcp` or `udp`). Defaults to `tcp`.
+	Network string
+	// UnitID is unit ID used in probe requests
+	UnitID uint8
+
+	// Probe is type of request sent to endpoints. Defaults to DiscoveryProbeDeviceIdentification.
+	Probe DiscoveryProbe
+	// RegisterAddress is address of holding register read with DiscoveryProbeHoldingRegister probe
+	RegisterAddress uint16
+
+	// Concurrency is maximum amount of endpoints probed at the same time. Defaults to 16.
+	Concurrency int
+	// ProbeInterval is minimal interval between starting probes (rate limit). 0 means no rate limiting.
+	ProbeInterval time.Duration
+
+	// ClientConfig is configuration for clients that probe endpoints. Set ConnectTimeout and ReadTimeout to short
+	// values as most of the endpoints in subnet usually do not respond.
+	ClientConfig ClientConfig
+}
+
+// DiscoveredDevice is endpoint that responded to discovery probe
+type DiscoveredDevice struct {
+	// Address is address of the endpoint (`network://host:port`) suitable for Client.Connect
+	Address string
+	UnitID  uint8
+	// Identification is basic identification of the device. Nil when DiscoveryProbeDeviceIdentification was not used or
+	// device does not support Read Device Identification (FC43/14).
+	Identification *DeviceIdentification
+	// Exception is Modbus exception device responded with to the probe request
+	Exception error
+}
+
+// Discover probes hosts in given subnet concurrently and returns endpoints that respond to Modbus probe request. This
+// is useful for bootstrapping configuration for new sites. Endpoints that can not be connected to or that do not
+// respond in time are not returned. Results are ordered by address.
+func Discover(ctx context.Context, conf DiscoveryConfig) ([]DiscoveredDevice, error) {
+	hosts, err := discoveryHosts(conf.Subnet)
+	if err != nil {
+		return nil, err
+	}
+	ports := conf.Ports
+	if len(ports) == 0 {
+		ports = []uint16{502}
+	}
+	network := conf.Network
+	if network == "" {
+		network = "tcp"
+	}
+	concurrency := conf.Concurrency
+	if concurrency <= 0 {
+		concurrency = 16
+	}
+
+	var rateLimit <-chan time.Time
+	if conf.ProbeInterval > 0 {
+		ticker := time.NewTicker(conf.ProbeInterval)
+		defer ticker.Stop()
+		rateLimit = ticker.C
+	}
+
+	addresses := make([]string, 0, len(hosts)*len(ports))
+	for _, h := range hosts {
+		for _, p := range ports {
+			addresses = append(addresses, network+"://"+net.JoinHostPort(h.String(), strconv.Itoa(int(p))))
+		}
+	}
+	results := make([]*DiscoveredDevice, len(addresses))
+
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, concurrency)
+	for i, address := range addresses {
+		if rateLimit != nil && i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-rateLimit:
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, address string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = probeEndpoint(ctx, conf, address)
+		}(i, address)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	devices := make([]DiscoveredDevice, 0)
+	for _, r := range results {
+		if r != nil {
+			devices = append(devices, *r)
+		}
+	}
+	return devices, nil
+}
+
+func probeEndpoint(ctx context.Context, conf DiscoveryConfig, address string) *DiscoveredDevice {
+	client := NewTCPClientWithConfig(conf.ClientConfig)
+	if err := client.Connect(ctx, address); err != nil {
+		return nil
+	}
+	defer client.Close()
+
+	result := &DiscoveredDevice{Address: address, UnitID: conf.UnitID}
+	var err error
+	switch conf.Probe {
+	case DiscoveryProbeHoldingRegister:
+		var req *packet.ReadHoldingRegistersRequestTCP
+		if req, err = packet.NewReadHoldingRegistersRequestTCP(conf.UnitID, conf.RegisterAddress, 1); err != nil {
+			return nil
+		}
+		_, err = client.Do(ctx, req)
+	default:
+		var ident DeviceIdentification
+		if ident, err = ReadDeviceIdentificationTCP(ctx, client, conf.UnitID); err == nil {
+			result.Identification = &ident
+		}
+	}
+	if err != nil {
+		var exception *packet.ErrorResponseTCP
+		if !errors.As(err, &exception) {
+			return nil
+		}
+		result.Exception = exception
+	}
+	return result
+}
+
+func discoveryHosts(subnet string) ([]netip.Addr, error) {
+	prefix, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid discovery subnet: %w", err)
+	}
+	prefix = prefix.Masked()
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	if hostBits > 16 {
+		return nil, fmt.Errorf("discovery subnet is too large, maximum is %v addresses", maxDiscoveryHosts)
+	}
+
+	hosts := make([]netip.Addr, 0, 1<<hostBits)
+	for a := prefix.Addr(); a.IsValid() && prefix.Contains(a); a = a.Next() {
+		hosts = append(hosts, a)
+	}
+	// skip network and broadcast addresses
+	if prefix.Addr().Is4() && hostBits > 1 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
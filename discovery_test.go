@@ -0,0 +1,104 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"github.com/aldas/go-modbus-client/modbustest"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func runDiscoveryTestServer(t *testing.T, ctx context.Context, response func(req []byte) []byte) uint16 {
+	addr, err := modbustest.RunServerOnRandomPort(ctx, func(received []byte, bytesRead int) ([]byte, bool) {
+		return response(received[:bytesRead]), false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(addr)
+	p, _ := strconv.Atoi(port)
+	return uint16(p)
+}
+
+func TestDiscover_deviceIdentification(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	port := runDiscoveryTestServer(t, ctx, func(req []byte) []byte {
+		resp := packet.ReadDeviceIdentificationResponseTCP{
+			MBAPHeader: packet.MBAPHeader{TransactionID: uint16(req[0])<<8 | uint16(req[1])},
+			ReadDeviceIdentificationResponse: packet.ReadDeviceIdentificationResponse{
+				UnitID:           req[6],
+				ReadDeviceIDCode: packet.ReadDeviceIDCodeBasic,
+				ConformityLevel:  0x01,
+				Objects: []packet.DeviceIdentificationObject{
+					{ID: packet.DeviceIDObjectVendorName, Value: []byte("ACME")},
+				},
+			},
+		}
+		return resp.Bytes()
+	})
+
+	devices, err := Discover(ctx, DiscoveryConfig{
+		Subnet:       "127.0.0.1/32",
+		Ports:        []uint16{port},
+		UnitID:       1,
+		ClientConfig: ClientConfig{ConnectTimeout: 200 * time.Millisecond, ReadTimeout: 200 * time.Millisecond},
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, devices, 1) {
+		assert.Equal(t, "tcp://127.0.0.1:"+strconv.Itoa(int(port)), devices[0].Address)
+		assert.Equal(t, "ACME", devices[0].Identification.VendorName)
+		assert.NoError(t, devices[0].Exception)
+	}
+}
+
+func TestDiscover_holdingRegisterException(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	port := runDiscoveryTestServer(t, ctx, func(req []byte) []byte {
+		return packet.ErrorResponseTCP{
+			TransactionID: uint16(req[0])<<8 | uint16(req[1]),
+			UnitID:        req[6],
+			Function:      req[7],
+			Code:          packet.ErrIllegalDataAddress,
+		}.Bytes()
+	})
+
+	devices, err := Discover(ctx, DiscoveryConfig{
+		Subnet:          "127.0.0.1/32",
+		Ports:           []uint16{port, 1}, // nothing listens on port 1
+		Probe:           DiscoveryProbeHoldingRegister,
+		RegisterAddress: 100,
+		ProbeInterval:   time.Millisecond,
+		ClientConfig:    ClientConfig{ConnectTimeout: 200 * time.Millisecond, ReadTimeout: 200 * time.Millisecond},
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, devices, 1) {
+		assert.Nil(t, devices[0].Identification)
+		assert.EqualError(t, devices[0].Exception, "Illegal data address")
+	}
+}
+
+func TestDiscoveryHosts(t *testing.T) {
+	hosts, err := discoveryHosts("192.168.0.5/30")
+	assert.NoError(t, err)
+	assert.Equal(t, "[192.168.0.5 192.168.0.6]", fmt.Sprint(hosts))
+
+	hosts, err = discoveryHosts("10.0.0.0/31")
+	assert.NoError(t, err)
+	assert.Equal(t, "[10.0.0.0 10.0.0.1]", fmt.Sprint(hosts))
+
+	_, err = discoveryHosts("10.0.0.0/8")
+	assert.EqualError(t, err, "discovery subnet is too large, maximum is 65536 addresses")
+
+	_, err = discoveryHosts("10.0.0.0")
+	assert.EqualError(t, err, `invalid discovery subnet: netip.ParsePrefix("10.0.0.0"): no '/'`)
+}
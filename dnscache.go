@@ -0,0 +1,134 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// DNSCache caches host name lookups of server addresses. It can be shared by multiple clients so host names are not
+// resolved for each connection attempt. When connecting to all cached IP addresses fails, host name is resolved again
+// so devices addressed by dynamic DNS names fail over correctly when their IP address changes.
+//
+// Go standard library resolver does not expose TTL of DNS records so entries expire after TTL given to NewDNSCache.
+// Use value that is not longer than TTL of DNS records of your devices.
+type DNSCache struct {
+	ttl        time.Duration
+	timeNow    func() time.Time
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	addresses []string
+	expiresAt time.Time
+}
+
+// NewDNSCache creates new instance of DNSCache with given TTL for cached entries
+func NewDNSCache(ttl time.Duration) *DNSCache {
+	return &DNSCache{
+		ttl:        ttl,
+		timeNow:    time.Now,
+		lookupHost: net.DefaultResolver.LookupHost,
+		entries:    map[string]dnsCacheEntry{},
+	}
+}
+
+// LookupHost returns IP addresses of given host from cache or resolves them when cache entry is missing or expired.
+func (c *DNSCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addresses, _, err := c.lookup(ctx, host)
+	return addresses, err
+}
+
+func (c *DNSCache) lookup(ctx context.Context, host string) ([]string, bool, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{host}, false, nil
+	}
+	now := c.timeNow()
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.addresses, true, nil
+	}
+
+	addresses, err := c.lookupHost(ctx, host)
+	if err != nil {
+		return nil, false, err
+	}
+	c.mu.Lock()
+	c.entries[host] = dnsCacheEntry{addresses: addresses, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return addresses, false, nil
+}
+
+// Invalidate removes cached entry of given host so next lookup resolves it again
+func (c *DNSCache) Invalidate(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, host)
+}
+
+// DialContextFunc returns function that can be used as ClientConfig.DialContextFunc. It connects to server using host
+// IP addresses from cache. When connecting to all cached addresses fails, host name is resolved again and connecting
+// is retried with newly resolved addresses.
+func (c *DNSCache) DialContextFunc(timeout time.Duration) func(ctx context.Context, address string) (net.Conn, error) {
+	return c.dialContextFunc(timeout, defaultTCPKeepAlive)
+}
+
+func (c *DNSCache) dialContextFunc(
+	timeout time.Duration,
+	keepAlive time.Duration,
+) func(ctx context.Context, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: keepAlive,
+	}
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		return c.dial(ctx, dialer.DialContext, address)
+	}
+}
+
+func (c *DNSCache) dial(
+	ctx context.Context,
+	dialFunc func(ctx context.Context, network string, address string) (net.Conn, error),
+	address string,
+) (net.Conn, error) {
+	network, addr := addressExtractor(address)
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	dialAll := func() (net.Conn, bool, error) {
+		ips, fromCache, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(ips) == 0 {
+			return nil, fromCache, errors.New("host name resolved to no addresses")
+		}
+		var errs []error
+		for _, ip := range ips {
+			conn, err := dialFunc(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, fromCache, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, fromCache, errors.Join(errs...)
+	}
+
+	conn, fromCache, err := dialAll()
+	if err == nil || !fromCache || ctx.Err() != nil {
+		return conn, err
+	}
+	// cached addresses could be stale. resolve host name again and retry
+	c.Invalidate(host)
+	conn, _, err = dialAll()
+	return conn, err
+}
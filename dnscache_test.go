@@ -0,0 +1,88 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDNSCache_LookupHost_cachesUntilTTL(t *testing.T) {
+	now := time.Unix(1615662935, 0)
+	lookups := 0
+	cache := NewDNSCache(time.Minute)
+	cache.timeNow = func() time.Time { return now }
+	cache.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"10.0.0.1"}, nil
+	}
+
+	_, err := cache.LookupHost(context.Background(), "plc.example.com")
+	assert.NoError(t, err)
+	ips, err := cache.LookupHost(context.Background(), "plc.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1"}, ips)
+	assert.Equal(t, 1, lookups)
+
+	now = now.Add(time.Minute)
+	_, err = cache.LookupHost(context.Background(), "plc.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, lookups)
+
+	ips, err = cache.LookupHost(context.Background(), "127.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1"}, ips)
+	assert.Equal(t, 2, lookups)
+}
+
+func TestDNSCache_dial_reResolvesWhenCachedAddressFails(t *testing.T) {
+	currentIP := "10.0.0.1"
+	cache := NewDNSCache(time.Hour)
+	cache.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		return []string{currentIP}, nil
+	}
+	var dialed []string
+	dialFunc := func(ctx context.Context, network string, address string) (net.Conn, error) {
+		dialed = append(dialed, network+"://"+address)
+		if address != net.JoinHostPort(currentIP, "502") {
+			return nil, errors.New("connection refused")
+		}
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+
+	conn, err := cache.dial(context.Background(), dialFunc, "plc.example.com:502")
+	assert.NoError(t, err)
+	assert.NotNil(t, conn)
+
+	currentIP = "10.0.0.2" // device got new IP address
+	conn, err = cache.dial(context.Background(), dialFunc, "tcp4://plc.example.com:502")
+	assert.NoError(t, err)
+	assert.NotNil(t, conn)
+
+	assert.Equal(t, []string{
+		"tcp://10.0.0.1:502",
+		"tcp4://10.0.0.1:502",
+		"tcp4://10.0.0.2:502",
+	}, dialed)
+}
+
+func TestDNSCache_dial_doesNotReResolveFreshLookup(t *testing.T) {
+	lookups := 0
+	cache := NewDNSCache(time.Hour)
+	cache.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"10.0.0.1"}, nil
+	}
+	dialFunc := func(ctx context.Context, network string, address string) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	conn, err := cache.dial(context.Background(), dialFunc, "plc.example.com:502")
+	assert.EqualError(t, err, "connection refused")
+	assert.Nil(t, conn)
+	assert.Equal(t, 1, lookups)
+}
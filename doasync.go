@@ -0,0 +1,121 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/aldas/go-modbus-client/packet"
+	"sync/atomic"
+)
+
+type asyncRequest struct {
+	ctx    context.Context
+	req    packet.Request
+	result chan PipelineResult
+}
+
+// DoAsync queues given Modbus request to be sent to modbus server and returns channel that receives result of the
+// request. Requests queued while previous requests are in flight are sent together with Client.DoPipelined so up to
+// ClientConfig.PipelineWindow requests from concurrent callers are in flight over single TCP connection at the same
+// time. Responses are correlated to requests by MBAP transaction ID. Requests with transaction ID equal to request
+// already in the same window are held back to the next window, so use unique transaction IDs (see
+// packet.NewSequentialTransactionIDGenerator) to get most out of pipelining.
+//
+// Returned channel receives exactly one result. When ctx is done before request is sent the result contains context
+// error and request is not sent. When ctx is done while request is in flight the result contains context error right
+// away and response to that request is discarded. Context of one caller does not affect other requests in the same
+// window.
+func (c *Client) DoAsync(ctx context.Context, req packet.Request) <-chan PipelineResult {
+	result := make(chan PipelineResult, 1)
+
+	c.asyncMu.Lock()
+	defer c.asyncMu.Unlock()
+
+	c.asyncQueue = append(c.asyncQueue, asyncRequest{ctx: ctx, req: req, result: result})
+	if !c.asyncRunning {
+		c.asyncRunning = true
+		go c.dispatchAsync()
+	}
+	return result
+}
+
+// dispatchAsync sends queued requests window by window until queue is empty
+func (c *Client) dispatchAsync() {
+	for {
+		batch := c.nextAsyncBatch()
+		if len(batch) == 0 {
+			return
+		}
+
+		requests := make([]packet.Request, len(batch))
+		answered := make([]atomic.Bool, len(batch))
+		stops := make([]func() bool, len(batch))
+		// pipeline is canceled only when contexts of all callers in the batch are done. Caller which context is done
+		// receives its context error immediately and its response is discarded when it arrives.
+		ctx, cancel := context.WithCancel(context.Background())
+		var remaining atomic.Int32
+		remaining.Store(int32(len(batch)))
+		for i, r := range batch {
+			requests[i] = r.req
+			stops[i] = context.AfterFunc(r.ctx, func() {
+				if answered[i].CompareAndSwap(false, true) {
+					r.result <- PipelineResult{Err: r.ctx.Err()}
+				}
+				if remaining.Add(-1) == 0 {
+					cancel()
+				}
+			})
+		}
+		results := c.DoPipelined(ctx, requests)
+		for i, r := range batch {
+			stops[i]()
+			if answered[i].CompareAndSwap(false, true) {
+				r.result <- results[i]
+			}
+		}
+		cancel()
+	}
+}
+
+// nextAsyncBatch takes up to pipeline window requests with unique transaction IDs from the queue. Requests which
+// context is already done are answered with context error and not sent.
+func (c *Client) nextAsyncBatch() []asyncRequest {
+	c.asyncMu.Lock()
+	defer c.asyncMu.Unlock()
+
+	window := c.pipelineWindow
+	if window < 1 {
+		window = 1
+	}
+	batch := make([]asyncRequest, 0, window)
+	transactionIDs := map[uint16]bool{}
+	rest := c.asyncQueue[:0]
+	for _, r := range c.asyncQueue {
+		if err := r.ctx.Err(); err != nil {
+			r.result <- PipelineResult{Err: err}
+			continue
+		}
+		if len(batch) == window {
+			rest = append(rest, r)
+			continue
+		}
+		if r.req != nil && !c.isRTU {
+			if data := r.req.Bytes(); len(data) >= 2 {
+				transactionID := binary.BigEndian.Uint16(data[0:2])
+				if transactionIDs[transactionID] {
+					rest = append(rest, r) // wait for next window to avoid ambiguous responses
+					continue
+				}
+				transactionIDs[transactionID] = true
+			}
+		}
+		batch = append(batch, r)
+	}
+	for i := len(rest); i < len(c.asyncQueue); i++ {
+		c.asyncQueue[i] = asyncRequest{} // release references for GC
+	}
+	c.asyncQueue = rest
+	if len(batch) == 0 {
+		c.asyncRunning = false
+	}
+	return batch
+}
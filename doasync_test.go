@@ -0,0 +1,140 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestClient_DoAsync(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	req1 := exampleFC1Request()
+	req2 := exampleFC1Request()
+	req2.(*packet.ReadCoilsRequestTCP).TransactionID = 0x1235
+	req3 := exampleFC1Request() // same transaction ID as req1 so it is held back to next window
+
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Times(3).Return(nil)
+	conn.On("Write", req1.Bytes()).Twice().Return(0, nil) // req1 and req3 have same bytes
+	conn.On("Write", req2.Bytes()).Once().Return(0, nil)
+
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(20, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{
+				0x12, 0x35, 0x0, 0x0, 0x0, 0x3, 0x1, 0x81, 0x2, // exception for second request comes first
+				0x12, 0x34, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1,
+			})
+		}).Once()
+	conn.On("Read", mock.Anything).
+		Return(11, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1})
+		}).Once()
+
+	client := NewTCPClientWithConfig(ClientConfig{PipelineWindow: 3})
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+	client.asyncRunning = true // queue all requests before dispatching starts
+
+	ctx := context.Background()
+	result1 := client.DoAsync(ctx, req1)
+	result2 := client.DoAsync(ctx, req2)
+	result3 := client.DoAsync(ctx, req3)
+	go client.dispatchAsync()
+
+	r1 := <-result1
+	assert.NoError(t, r1.Err)
+	assert.Equal(t, exampleFC1Response(), r1.Response)
+
+	r2 := <-result2
+	assert.EqualError(t, r2.Err, "Illegal data address")
+	assert.Nil(t, r2.Response)
+
+	r3 := <-result3
+	assert.NoError(t, r3.Err)
+	assert.Equal(t, exampleFC1Response(), r3.Response)
+
+	conn.AssertExpectations(t)
+}
+
+func TestClient_DoAsync_contextDone(t *testing.T) {
+	conn := new(netConnMock)
+	client := NewTCPClientWithConfig(ClientConfig{PipelineWindow: 2})
+	client.conn = conn
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := <-client.DoAsync(ctx, exampleFC1Request())
+
+	assert.Equal(t, PipelineResult{Err: context.Canceled}, result)
+	conn.AssertExpectations(t) // nothing is written
+}
+
+func TestClient_DoAsync_contextDoneWhileInFlight(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	req1 := exampleFC1Request()
+	req2 := exampleFC1Request()
+	req2.(*packet.ReadCoilsRequestTCP).TransactionID = 0x1235
+
+	written := make(chan struct{})
+	release := make(chan struct{})
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Twice().Return(nil)
+	conn.On("Write", req1.Bytes()).Once().Return(0, nil)
+	conn.On("Write", req2.Bytes()).Once().Return(0, nil).Run(func(args mock.Arguments) {
+		close(written)
+	})
+
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(22, nil).
+		Run(func(args mock.Arguments) {
+			<-release
+			b := args.Get(0).([]byte)
+			copy(b, []byte{
+				0x12, 0x34, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1, // response to canceled request is discarded
+				0x12, 0x35, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1,
+			})
+		}).Once()
+
+	client := NewTCPClientWithConfig(ClientConfig{PipelineWindow: 2})
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+	client.asyncRunning = true // queue all requests before dispatching starts
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel2()
+	result1 := client.DoAsync(ctx1, req1)
+	result2 := client.DoAsync(ctx2, req2)
+	go client.dispatchAsync()
+
+	<-written
+	cancel1()
+	r1 := <-result1 // canceled caller gets result before response arrives
+	assert.Equal(t, PipelineResult{Err: context.Canceled}, r1)
+	close(release)
+
+	r2 := <-result2
+	assert.NoError(t, r2.Err)
+	expect := exampleFC1Response()
+	expect.(*packet.ReadCoilsResponseTCP).TransactionID = 0x1235
+	assert.Equal(t, expect, r2.Response)
+
+	conn.AssertExpectations(t)
+}
@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"github.com/aldas/go-modbus-client/packet"
+	"time"
+)
+
+// ClientEvent is structured event emitted by Client to ClientConfig.OnEvent. Event is one of ConnectEvent,
+// DisconnectEvent, RequestStartEvent, RequestEndEvent or ExceptionEvent. Use type switch to handle events of interest.
+//
+// Unlike ClientHooks events carry decoded request information so tracing and debugging middleware can be written
+// without parsing raw bytes.
+type ClientEvent interface {
+	// EventTime returns time when event occurred
+	EventTime() time.Time
+}
+
+// ConnectEvent is emitted after connection attempt to the server. Err is set when connection failed.
+type ConnectEvent struct {
+	Time          time.Time
+	ServerAddress string
+	Err           error
+}
+
+// DisconnectEvent is emitted when client connection is closed with Client.Close
+type DisconnectEvent struct {
+	Time          time.Time
+	ServerAddress string
+	Err           error
+}
+
+// RequestStartEvent is emitted before request is sent to the server. Request is the request after RequestTransformer
+// has been applied to it. Do not modify the request.
+type RequestStartEvent struct {
+	Time          time.Time
+	ServerAddress string
+	UnitID        uint8
+	FunctionCode  uint8
+	Request       packet.Request
+}
+
+// RequestEndEvent is emitted when request is done. Err is set when request failed (including Modbus exceptions).
+type RequestEndEvent struct {
+	Time          time.Time
+	ServerAddress string
+	UnitID        uint8
+	FunctionCode  uint8
+	// RoundTrip is time from sending the request until response was received or request failed
+	RoundTrip time.Duration
+	Err       error
+}
+
+// ExceptionEvent is emitted after RequestEndEvent when server responded with Modbus exception
+type ExceptionEvent struct {
+	Time          time.Time
+	ServerAddress string
+	UnitID        uint8
+	FunctionCode  uint8
+	ExceptionCode packet.ErrCode
+}
+
+// EventTime returns time when event occurred
+func (e ConnectEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns time when event occurred
+func (e DisconnectEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns time when event occurred
+func (e RequestStartEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns time when event occurred
+func (e RequestEndEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns time when event occurred
+func (e ExceptionEvent) EventTime() time.Time { return e.Time }
+
+// requestStart emits RequestStartEvent for given request
+func (c *Client) requestStart(data []byte, req packet.Request) {
+	if c.onEvent == nil {
+		return
+	}
+	c.onEvent(RequestStartEvent{
+		Time:          c.timeNow(),
+		ServerAddress: c.address,
+		UnitID:        frameUnitID(data, c.isRTU),
+		FunctionCode:  req.FunctionCode(),
+		Request:       req,
+	})
+}
@@ -0,0 +1,66 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestClient_OnEvent(t *testing.T) {
+	var events []ClientEvent
+	client := NewTCPClientWithConfig(ClientConfig{
+		OnEvent: func(event ClientEvent) {
+			events = append(events, event)
+		},
+		DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+			clientConn, serverConn := net.Pipe()
+			go func() {
+				req := make([]byte, 12)
+				if _, err := io.ReadFull(serverConn, req); err != nil {
+					return
+				}
+				// respond with Illegal Data Address exception
+				_, _ = serverConn.Write([]byte{req[0], req[1], 0x0, 0x0, 0x0, 0x3, req[6], 0x83, 0x2})
+			}()
+			return clientConn, nil
+		},
+	})
+	err := client.Connect(context.Background(), ":502")
+	assert.NoError(t, err)
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(7, 100, 1)
+	_, err = client.Do(context.Background(), req)
+	assert.Error(t, err)
+	assert.NoError(t, client.Close())
+
+	if !assert.Len(t, events, 5) {
+		return
+	}
+	assert.Equal(t, ConnectEvent{Time: events[0].EventTime(), ServerAddress: ":502"}, events[0])
+
+	start := events[1].(RequestStartEvent)
+	assert.Equal(t, uint8(7), start.UnitID)
+	assert.Equal(t, packet.FunctionReadHoldingRegisters, start.FunctionCode)
+	assert.Same(t, req, start.Request)
+
+	end := events[2].(RequestEndEvent)
+	assert.Equal(t, ":502", end.ServerAddress)
+	assert.Equal(t, uint8(7), end.UnitID)
+	assert.True(t, end.RoundTrip <= end.Time.Sub(start.Time))
+	assert.Error(t, end.Err)
+
+	assert.Equal(t, ExceptionEvent{
+		Time:          end.Time,
+		ServerAddress: ":502",
+		UnitID:        7,
+		FunctionCode:  packet.FunctionReadHoldingRegisters,
+		ExceptionCode: packet.ErrIllegalDataAddress,
+	}, events[3])
+
+	disconnect := events[4].(DisconnectEvent)
+	assert.Equal(t, ":502", disconnect.ServerAddress)
+	assert.NoError(t, disconnect.Err)
+}
@@ -0,0 +1,76 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseFieldsJSON decodes fields from JSON array of field objects. Unknown keys in field objects are ignored. Use
+// ParseFieldsJSONStrict to report typos in keys (i.e. `adress`) instead of silently ignoring them.
+//
+// Field `read_timeout` can be given as duration string (i.e. "5s", "1500ms") or as integer nanoseconds.
+func ParseFieldsJSON(data []byte) (Fields, error) {
+	return parseFieldsJSON(data, false)
+}
+
+// ParseFieldsJSONStrict decodes fields from JSON array of field objects. Unlike ParseFieldsJSON unknown keys in field
+// objects result an error. Errors contain index of the field that could not be decoded. Use Fields.Validate to
+// report all problems of decoded fields at once.
+func ParseFieldsJSONStrict(data []byte) (Fields, error) {
+	return parseFieldsJSON(data, true)
+}
+
+func parseFieldsJSON(data []byte, strict bool) (Fields, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("fields JSON decoding failed: %w", err)
+	}
+	result := make(Fields, len(raw))
+	for i, r := range raw {
+		r, err := readTimeoutAsNanoseconds(r)
+		if err != nil {
+			return nil, fmt.Errorf("field JSON decoding failed. index: %v err: %w", i, err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(r))
+		if strict {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(&result[i]); err != nil {
+			return nil, fmt.Errorf("field JSON decoding failed. index: %v err: %w", i, err)
+		}
+	}
+	return result, nil
+}
+
+// readTimeoutAsNanoseconds replaces `read_timeout` duration string (i.e. "5s") in field object with integer
+// nanoseconds that time.Duration is decoded from.
+func readTimeoutAsNanoseconds(data json.RawMessage) (json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return data, nil // let field decoding report the error
+	}
+	changed := false
+	for key, value := range obj {
+		if !strings.EqualFold(key, "read_timeout") || len(value) == 0 || value[0] != '"' {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, err
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("read_timeout is invalid duration: %w", err)
+		}
+		obj[key] = json.RawMessage(strconv.FormatInt(int64(d), 10))
+		changed = true
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(obj)
+}
@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseFieldsJSON(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		given      string
+		whenStrict bool
+		expect     Fields
+		expectErr  string
+	}{
+		{
+			name:  "ok, lenient",
+			given: `[{"Name":"power","address":10,"type":5,"unit":"W"},{"Name":"x","adress":11,"type":5}]`,
+			expect: Fields{
+				{Name: "power", Address: 10, Type: FieldTypeUint16, Unit: "W"},
+				{Name: "x", Type: FieldTypeUint16},
+			},
+		},
+		{
+			name:       "ok, strict",
+			given:      `[{"Name":"power","address":10,"type":5,"unit":"W"}]`,
+			whenStrict: true,
+			expect: Fields{
+				{Name: "power", Address: 10, Type: FieldTypeUint16, Unit: "W"},
+			},
+		},
+		{
+			name:       "ok, read timeout as duration string",
+			given:      `[{"Name":"power","address":10,"type":5,"read_timeout":"1.5s"}]`,
+			whenStrict: true,
+			expect: Fields{
+				{Name: "power", Address: 10, Type: FieldTypeUint16, ReadTimeout: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "ok, read timeout as integer nanoseconds",
+			given: `[{"Name":"power","address":10,"type":5,"read_timeout":5000000000}]`,
+			expect: Fields{
+				{Name: "power", Address: 10, Type: FieldTypeUint16, ReadTimeout: 5 * time.Second},
+			},
+		},
+		{
+			name:      "nok, invalid read timeout duration string",
+			given:     `[{"Name":"power","address":10,"type":5,"read_timeout":"5 seconds"}]`,
+			expectErr: `field JSON decoding failed. index: 0 err: read_timeout is invalid duration: time: unknown unit " seconds" in duration "5 seconds"`,
+		},
+		{
+			name:       "nok, strict unknown key",
+			given:      `[{"Name":"power","address":10,"type":5},{"Name":"x","adress":11,"type":5}]`,
+			whenStrict: true,
+			expectErr:  `field JSON decoding failed. index: 1 err: json: unknown field "adress"`,
+		},
+		{
+			name:      "nok, invalid value type",
+			given:     `[{"Name":"power","address":"10","type":5}]`,
+			expectErr: "field JSON decoding failed. index: 0 err: json: cannot unmarshal string into Go struct field Field.address of type uint16",
+		},
+		{
+			name:      "nok, not an array",
+			given:     `{"Name":"power"}`,
+			expectErr: "fields JSON decoding failed: json: cannot unmarshal object into Go value of type",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fields Fields
+			var err error
+			if tc.whenStrict {
+				fields, err = ParseFieldsJSONStrict([]byte(tc.given))
+			} else {
+				fields, err = ParseFieldsJSON([]byte(tc.given))
+			}
+
+			assert.Equal(t, tc.expect, fields)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
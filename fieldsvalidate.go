@@ -0,0 +1,73 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Validate checks all fields and reports all found problems at once instead of stopping at first invalid field.
+// In addition to Field.Validate checks it reports non-unique field names and register fields that overlap with
+// different type (i.e. uint32 at address 10 and float32 at address 11 of the same server and unit). Fields occupying
+// only part of the register (bits and bytes) may overlap with other fields.
+//
+// Every problem is separate error (see errors.Join) containing index and name of the field.
+func (fs Fields) Validate() error {
+	var errs []error
+	firstByName := map[string]int{}
+	for i, f := range fs {
+		if err := f.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("field index: %v name: '%v': %w", i, f.Name, err))
+		}
+		if f.Name == "" {
+			continue
+		}
+		if first, ok := firstByName[f.Name]; ok {
+			errs = append(errs, fmt.Errorf("field index: %v name: '%v': field name is not unique, first defined at index: %v", i, f.Name, first))
+			continue
+		}
+		firstByName[f.Name] = i
+	}
+	return errors.Join(append(errs, fs.overlapErrors()...)...)
+}
+
+func (fs Fields) overlapErrors() []error {
+	indexes := make([]int, 0, len(fs))
+	for i, f := range fs {
+		switch f.Type {
+		case FieldTypeBit, FieldTypeByte, FieldTypeUint8, FieldTypeInt8, FieldTypeCoil:
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		fi, fj := fs[indexes[i]], fs[indexes[j]]
+		if fi.ServerAddress != fj.ServerAddress {
+			return fi.ServerAddress < fj.ServerAddress
+		}
+		if fi.UnitID != fj.UnitID {
+			return fi.UnitID < fj.UnitID
+		}
+		return fi.Address < fj.Address
+	})
+
+	var errs []error
+	for i, idx := range indexes {
+		f := fs[idx]
+		end := uint32(f.Address) + uint32(f.registerSize())
+		for _, otherIdx := range indexes[i+1:] {
+			other := fs[otherIdx]
+			if other.ServerAddress != f.ServerAddress || other.UnitID != f.UnitID || uint32(other.Address) >= end {
+				break
+			}
+			if other.Address == f.Address && other.Type == f.Type {
+				continue
+			}
+			errs = append(errs, fmt.Errorf(
+				"field index: %v name: '%v': field overlaps with field of different type at index: %v name: '%v'",
+				otherIdx, other.Name, idx, f.Name,
+			))
+		}
+	}
+	return errs
+}
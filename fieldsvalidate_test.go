@@ -0,0 +1,49 @@
+package modbus
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFields_Validate(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		given     Fields
+		expectErr string
+	}{
+		{
+			name: "ok",
+			given: Fields{
+				{Name: "status", ServerAddress: ":502", Address: 10, Type: FieldTypeUint16},
+				{Name: "status_bit", ServerAddress: ":502", Address: 10, Type: FieldTypeBit, Bit: 2},
+				{Name: "status_copy", ServerAddress: ":502", Address: 10, Type: FieldTypeUint16},
+				{Name: "power", ServerAddress: ":502", Address: 11, Type: FieldTypeUint32},
+				{Name: "other_unit", ServerAddress: ":502", UnitID: 2, Address: 11, Type: FieldTypeFloat32},
+			},
+		},
+		{
+			name: "nok, reports all problems",
+			given: Fields{
+				{Name: "power", ServerAddress: ":502", Address: 10, Type: FieldTypeUint32},
+				{Name: "no_type", ServerAddress: ":502", Address: 20},
+				{Name: "power", ServerAddress: ":502", Address: 30, Type: FieldTypeUint16},
+				{Name: "power_float", ServerAddress: ":502", Address: 11, Type: FieldTypeFloat32},
+				{Name: "energy", ServerAddress: ":502", Address: 12, Type: FieldTypeUint16},
+			},
+			expectErr: "field index: 1 name: 'no_type': field type must be set\n" +
+				"field index: 2 name: 'power': field name is not unique, first defined at index: 0\n" +
+				"field index: 3 name: 'power_float': field overlaps with field of different type at index: 0 name: 'power'\n" +
+				"field index: 4 name: 'energy': field overlaps with field of different type at index: 3 name: 'power_float'",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.given.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package modbus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var timeLocations sync.Map // cache of loaded time zones. key is zone name, value is *time.Location
+
+// loadTimeLocation returns time zone with given name. Empty name means UTC.
+func loadTimeLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if loc, ok := timeLocations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	timeLocations.Store(name, loc)
+	return loc, nil
+}
+
+// timeFromSeconds converts seconds since field epoch to time in field time zone
+func (f *Field) timeFromSeconds(seconds int64) (time.Time, error) {
+	loc, err := loadTimeLocation(f.TimeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("field time zone is invalid: %w", err)
+	}
+	t := time.Unix(f.TimeEpoch+seconds, 0).UTC()
+	if loc == time.UTC {
+		return t, nil
+	}
+	// device clock counts wall clock time of the time zone
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+}
+
+// secondsFromTime converts time to seconds since field epoch in field time zone. This is inverse of timeFromSeconds.
+func (f *Field) secondsFromTime(value interface{}) (int64, error) {
+	t, ok := value.(time.Time)
+	if !ok {
+		return 0, fmt.Errorf("time field value must be time.Time, got: %T", value)
+	}
+	loc, err := loadTimeLocation(f.TimeZone)
+	if err != nil {
+		return 0, fmt.Errorf("field time zone is invalid: %w", err)
+	}
+	t = t.In(loc)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return wall.Unix() - f.TimeEpoch, nil
+}
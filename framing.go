@@ -0,0 +1,114 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// Framing is Modbus frame format server (i.e. Ethernet-serial gateway) uses over network connection
+type Framing uint8
+
+const (
+	// FramingUnknown means that framing could not be detected
+	FramingUnknown Framing = 0
+	// FramingTCP is Modbus TCP framing (MBAP header)
+	FramingTCP Framing = 1
+	// FramingRTU is Modbus RTU framing (RTU over TCP)
+	FramingRTU Framing = 2
+)
+
+// String returns name of the framing
+func (f Framing) String() string {
+	switch f {
+	case FramingTCP:
+		return "tcp"
+	case FramingRTU:
+		return "rtu"
+	}
+	return "unknown"
+}
+
+// NewClient creates new Client for the framing with given configuration options
+func (f Framing) NewClient(conf ClientConfig) *Client {
+	if f == FramingRTU {
+		return NewRTUClientWithConfig(conf)
+	}
+	return NewTCPClientWithConfig(conf)
+}
+
+// FramingProbeConfig is configuration for DetectFraming
+type FramingProbeConfig struct {
+	// UnitID is unit ID used in probe requests
+	UnitID uint8
+	// Probe is type of request sent to the server. Defaults to DiscoveryProbeDeviceIdentification.
+	Probe DiscoveryProbe
+	// RegisterAddress is address of holding register read with DiscoveryProbeHoldingRegister probe
+	RegisterAddress uint16
+
+	// ClientConfig is configuration for clients that probe the server. ReadTimeout limits how long is waited for
+	// response to each probe.
+	ClientConfig ClientConfig
+}
+
+// DetectFraming detects which framing server at given address uses. Server is probed with Modbus TCP framing first
+// and with Modbus RTU framing (RTU over TCP) when it does not respond to TCP probe. Every probe uses new connection so
+// garbage left from the failed probe does not affect the next one. Server responding with Modbus exception is
+// considered as using that framing.
+//
+// This is useful for mixed fleets of Ethernet-serial gateways where some gateways convert Modbus TCP to RTU and some
+// pass RTU frames through as is.
+func DetectFraming(ctx context.Context, address string, conf FramingProbeConfig) (Framing, error) {
+	tcpErr := probeFraming(ctx, FramingTCP, address, conf)
+	if tcpErr == nil {
+		return FramingTCP, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return FramingUnknown, err
+	}
+	rtuErr := probeFraming(ctx, FramingRTU, address, conf)
+	if rtuErr == nil {
+		return FramingRTU, nil
+	}
+	return FramingUnknown, fmt.Errorf("could not detect framing, tcp probe: %w, rtu probe: %w", tcpErr, rtuErr)
+}
+
+func probeFraming(ctx context.Context, framing Framing, address string, conf FramingProbeConfig) error {
+	client := framing.NewClient(conf.ClientConfig)
+	if err := client.Connect(ctx, address); err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var err error
+	switch conf.Probe {
+	case DiscoveryProbeHoldingRegister:
+		var req packet.Request
+		if framing == FramingRTU {
+			req, err = packet.NewReadHoldingRegistersRequestRTU(conf.UnitID, conf.RegisterAddress, 1)
+		} else {
+			req, err = packet.NewReadHoldingRegistersRequestTCP(conf.UnitID, conf.RegisterAddress, 1)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = client.Do(ctx, req)
+	default:
+		if framing == FramingRTU {
+			_, err = ReadDeviceIdentificationRTU(ctx, client, conf.UnitID)
+		} else {
+			_, err = ReadDeviceIdentificationTCP(ctx, client, conf.UnitID)
+		}
+	}
+	if err == nil || isExceptionResponse(err) {
+		return nil
+	}
+	return err
+}
+
+// isExceptionResponse checks if error is Modbus exception response sent by the server
+func isExceptionResponse(err error) bool {
+	var code packet.ErrCode
+	return errors.As(err, &code)
+}
@@ -0,0 +1,92 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"github.com/aldas/go-modbus-client/modbustest"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDetectFraming(t *testing.T) {
+	tcpServer := func(req []byte) []byte {
+		if len(req) < 8 || req[2] != 0 || req[3] != 0 {
+			return nil
+		}
+		return packet.ErrorResponseTCP{
+			TransactionID: uint16(req[0])<<8 | uint16(req[1]),
+			UnitID:        req[6],
+			Function:      req[7],
+			Code:          packet.ErrIllegalFunction,
+		}.Bytes()
+	}
+	rtuGateway := func(req []byte) []byte {
+		if len(req) != 8 { // FC3 RTU request is 8 bytes
+			return nil
+		}
+		return packet.ErrorResponseRTU{UnitID: req[0], Function: req[1], Code: packet.ErrIllegalDataAddress}.Bytes()
+	}
+	silent := func(req []byte) []byte {
+		return nil
+	}
+
+	var testCases = []struct {
+		name        string
+		whenServer  func(req []byte) []byte
+		expect      Framing
+		expectError string
+	}{
+		{
+			name:       "ok, tcp",
+			whenServer: tcpServer,
+			expect:     FramingTCP,
+		},
+		{
+			name:       "ok, rtu over tcp",
+			whenServer: rtuGateway,
+			expect:     FramingRTU,
+		},
+		{
+			name:        "nok, no response",
+			whenServer:  silent,
+			expect:      FramingUnknown,
+			expectError: "could not detect framing, tcp probe: total read timeout exceeded, rtu probe: total read timeout exceeded",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			addr, err := modbustest.RunServerOnRandomPort(ctx, func(received []byte, bytesRead int) ([]byte, bool) {
+				return tc.whenServer(received[:bytesRead]), false
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			framing, err := DetectFraming(ctx, addr, FramingProbeConfig{
+				UnitID:          1,
+				Probe:           DiscoveryProbeHoldingRegister,
+				RegisterAddress: 100,
+				ClientConfig:    ClientConfig{ReadTimeout: 100 * time.Millisecond},
+			})
+
+			assert.Equal(t, tc.expect, framing)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFraming_String(t *testing.T) {
+	assert.Equal(t, "tcp", fmt.Sprint(FramingTCP))
+	assert.Equal(t, "rtu", FramingRTU.String())
+	assert.Equal(t, "unknown", FramingUnknown.String())
+}
@@ -0,0 +1,88 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"time"
+)
+
+// startIdleProbe starts goroutine probing connection when it has been idle for idle probe interval. Goroutine of
+// previous connection is stopped. Must be called with c.mu locked.
+func (c *Client) startIdleProbe(address string) {
+	c.stopIdleProbe()
+	if c.idleProbeInterval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	c.idleProbeStop = stop
+	go c.idleProbeLoop(stop, address)
+}
+
+// stopIdleProbe stops idle probe goroutine. Must be called with c.mu locked.
+func (c *Client) stopIdleProbe() {
+	if c.idleProbeStop != nil {
+		close(c.idleProbeStop)
+		c.idleProbeStop = nil
+	}
+}
+
+func (c *Client) idleProbeLoop(stop chan struct{}, address string) {
+	timer := time.NewTimer(c.idleProbeInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+		timer.Reset(c.probeIdle(stop, address))
+	}
+}
+
+// probeIdle sends probe request when connection has been idle for idle probe interval and reconnects when probe gets
+// no response. Returns duration after which connection should be checked again.
+func (c *Client) probeIdle(stop chan struct{}, address string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-stop: // connection was closed or replaced while waiting for the lock
+		return c.idleProbeInterval
+	default:
+	}
+	if c.conn != nil {
+		idle := c.timeNow().Sub(c.lastActivity)
+		if idle < c.idleProbeInterval {
+			return c.idleProbeInterval - idle
+		}
+		req, err := c.idleProbe()
+		if err == nil {
+			_, err = c.doRequest(context.Background(), req)
+		}
+		if err == nil || isExceptionResponse(err) {
+			return c.idleProbeInterval
+		}
+		_ = c.conn.Close()
+		if c.onEvent != nil {
+			c.onEvent(DisconnectEvent{Time: c.timeNow(), ServerAddress: c.address, Err: err})
+		}
+		c.conn = nil
+	}
+	// failed reconnect is retried after next interval. Meanwhile requests fail with ErrClientNotConnected
+	_ = c.connect(context.Background(), address)
+	return c.idleProbeInterval
+}
+
+// idleProbe returns request sent as idle probe
+func (c *Client) idleProbe() (packet.Request, error) {
+	if c.idleProbeRequest != nil {
+		return c.idleProbeRequest, nil
+	}
+	switch {
+	case c.packetMaxLen == asciiPacketMaxLen:
+		return packet.NewReadExceptionStatusRequestASCII(1)
+	case c.isRTU:
+		return packet.NewReadExceptionStatusRequestRTU(1)
+	}
+	return packet.NewReadExceptionStatusRequestTCP(1)
+}
@@ -0,0 +1,130 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/modbustest"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// eventCounter counts client events by type
+type eventCounter struct {
+	mu          sync.Mutex
+	connects    int
+	disconnects int
+}
+
+func (e *eventCounter) onEvent(event ClientEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch event.(type) {
+	case ConnectEvent:
+		e.connects++
+	case DisconnectEvent:
+		e.disconnects++
+	}
+}
+
+func (e *eventCounter) counts() (int, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.connects, e.disconnects
+}
+
+func TestClient_idleProbe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	probes := make(chan []byte, 10)
+	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
+		probes <- append([]byte(nil), received...)
+		// exception response proves that connection is alive
+		return []byte{received[0], received[1], 0x0, 0x0, 0x0, 0x3, 0x1, 0x87, 0x1}, false
+	}
+	addr, err := modbustest.RunServerOnRandomPort(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := &eventCounter{}
+	client := NewTCPClientWithConfig(ClientConfig{
+		IdleProbeInterval: 20 * time.Millisecond,
+		OnEvent:           events.onEvent,
+	})
+	err = client.Connect(ctx, addr)
+	assert.NoError(t, err)
+
+	select {
+	case probe := <-probes:
+		assert.Equal(t, []byte{0x0, 0x0, 0x0, 0x2, 0x1, 0x7}, probe[2:]) // trim transaction ID
+	case <-ctx.Done():
+		t.Fatal("probe was not sent")
+	}
+	assert.NoError(t, client.Close())
+
+	connects, disconnects := events.counts()
+	assert.Equal(t, 1, connects)
+	assert.Equal(t, 1, disconnects)
+}
+
+func TestClient_idleProbe_reconnectsWhenNoResponse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
+		if received[7] == packet.FunctionReadHoldingRegisters {
+			return []byte{received[0], received[1], 0x0, 0x0, 0x0, 0x5, 0x1, 0x3, 0x2, 0xca, 0xfe}, false
+		}
+		return nil, false // probe is not answered like over half-open connection
+	}
+	addr, err := modbustest.RunServerOnRandomPort(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probe, _ := packet.NewReadInputRegistersRequestTCP(1, 1, 1)
+	events := &eventCounter{}
+	client := NewTCPClientWithConfig(ClientConfig{
+		ReadTimeout:       20 * time.Millisecond,
+		IdleProbeInterval: 20 * time.Millisecond,
+		IdleProbeRequest:  probe,
+		OnEvent:           events.onEvent,
+	})
+	err = client.Connect(ctx, addr)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	assert.Eventually(t, func() bool {
+		connects, disconnects := events.counts()
+		return connects >= 2 && disconnects >= 1
+	}, time.Second, 5*time.Millisecond)
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	resp, err := client.Do(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xca, 0xfe}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+}
+
+func TestClient_idleProbe_notSentWhenActive(t *testing.T) {
+	client := NewTCPClientWithConfig(ClientConfig{IdleProbeInterval: time.Minute})
+	now := time.Now()
+	client.timeNow = func() time.Time { return now }
+	conn := &netConnMock{}
+	client.conn = conn
+	client.lastActivity = now.Add(-20 * time.Second)
+
+	next := client.probeIdle(make(chan struct{}), "localhost:502")
+
+	assert.Equal(t, 40*time.Second, next)
+	assert.Len(t, conn.Calls, 0)
+}
+
+func TestClient_TCPKeepAlive(t *testing.T) {
+	assert.Equal(t, defaultTCPKeepAlive, NewTCPClient().tcpKeepAlive)
+
+	client := NewTCPClientWithConfig(ClientConfig{TCPKeepAlive: -1})
+	assert.Equal(t, time.Duration(-1), client.tcpKeepAlive)
+}
@@ -0,0 +1,137 @@
+package modbus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// EchoTestTCP sends Diagnostics (FC08) Return Query Data request with given data using Modbus TCP and checks that
+// server echoes same data back. See EchoTestRTU for details.
+func EchoTestTCP(ctx context.Context, client Doer, unitID uint8, data []byte) error {
+	return echoTest(ctx, client, unitID, data, false)
+}
+
+// EchoTestRTU sends Diagnostics (FC08) Return Query Data request with given data using Modbus RTU and checks that
+// server echoes same data back. This is useful for checking communication link to the device without touching any
+// registers.
+func EchoTestRTU(ctx context.Context, client Doer, unitID uint8, data []byte) error {
+	return echoTest(ctx, client, unitID, data, true)
+}
+
+func echoTest(ctx context.Context, client Doer, unitID uint8, data []byte, isRTU bool) error {
+	resp, err := doDiagnostics(ctx, client, unitID, packet.DiagnosticsReturnQueryData, data, isRTU)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(resp.Data, data) {
+		return errors.New("diagnostics echo response data does not match request data")
+	}
+	return nil
+}
+
+// ReadDiagnosticsCounterTCP reads diagnostics counter (i.e. packet.DiagnosticsReturnBusMessageCount) or diagnostic
+// register from the server using Diagnostics (FC08) Modbus TCP request
+func ReadDiagnosticsCounterTCP(ctx context.Context, client Doer, unitID uint8, subFunction uint16) (uint16, error) {
+	return readDiagnosticsCounter(ctx, client, unitID, subFunction, false)
+}
+
+// ReadDiagnosticsCounterRTU reads diagnostics counter (i.e. packet.DiagnosticsReturnBusMessageCount) or diagnostic
+// register from the server using Diagnostics (FC08) Modbus RTU request
+func ReadDiagnosticsCounterRTU(ctx context.Context, client Doer, unitID uint8, subFunction uint16) (uint16, error) {
+	return readDiagnosticsCounter(ctx, client, unitID, subFunction, true)
+}
+
+func readDiagnosticsCounter(ctx context.Context, client Doer, unitID uint8, subFunction uint16, isRTU bool) (uint16, error) {
+	resp, err := doDiagnostics(ctx, client, unitID, subFunction, []byte{0x00, 0x00}, isRTU)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Counter()
+}
+
+// ClearDiagnosticsCountersTCP clears all counters and diagnostic register of the server using Diagnostics (FC08)
+// Modbus TCP request
+func ClearDiagnosticsCountersTCP(ctx context.Context, client Doer, unitID uint8) error {
+	_, err := doDiagnostics(ctx, client, unitID, packet.DiagnosticsClearCounters, []byte{0x00, 0x00}, false)
+	return err
+}
+
+// ClearDiagnosticsCountersRTU clears all counters and diagnostic register of the server using Diagnostics (FC08)
+// Modbus RTU request
+func ClearDiagnosticsCountersRTU(ctx context.Context, client Doer, unitID uint8) error {
+	_, err := doDiagnostics(ctx, client, unitID, packet.DiagnosticsClearCounters, []byte{0x00, 0x00}, true)
+	return err
+}
+
+func doDiagnostics(ctx context.Context, client Doer, unitID uint8, subFunction uint16, data []byte, isRTU bool) (packet.DiagnosticsResponse, error) {
+	var req packet.Request
+	var err error
+	if isRTU {
+		req, err = packet.NewDiagnosticsRequestRTU(unitID, subFunction, data)
+	} else {
+		req, err = packet.NewDiagnosticsRequestTCP(unitID, subFunction, data)
+	}
+	if err != nil {
+		return packet.DiagnosticsResponse{}, err
+	}
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return packet.DiagnosticsResponse{}, err
+	}
+	var result packet.DiagnosticsResponse
+	switch r := resp.(type) {
+	case *packet.DiagnosticsResponseTCP:
+		result = r.DiagnosticsResponse
+	case *packet.DiagnosticsResponseRTU:
+		result = r.DiagnosticsResponse
+	case *packet.DiagnosticsResponseASCII:
+		result = r.DiagnosticsResponse
+	default:
+		return packet.DiagnosticsResponse{}, fmt.Errorf("unexpected response type for diagnostics request: %T", resp)
+	}
+	if result.SubFunction != subFunction {
+		return packet.DiagnosticsResponse{}, fmt.Errorf("diagnostics response sub-function does not match request: %v", result.SubFunction)
+	}
+	return result, nil
+}
+
+// ReadExceptionStatusTCP reads 8 exception status outputs of the device using Read Exception Status (FC07) Modbus TCP
+// request. See ReadExceptionStatusRTU for details.
+func ReadExceptionStatusTCP(ctx context.Context, client Doer, unitID uint8) (uint8, error) {
+	return readExceptionStatus(ctx, client, unitID, false)
+}
+
+// ReadExceptionStatusRTU reads 8 exception status outputs of the device using Read Exception Status (FC07) Modbus RTU
+// request. Meaning of the status bits is device specific (usually alarm summary bits of legacy drives).
+func ReadExceptionStatusRTU(ctx context.Context, client Doer, unitID uint8) (uint8, error) {
+	return readExceptionStatus(ctx, client, unitID, true)
+}
+
+func readExceptionStatus(ctx context.Context, client Doer, unitID uint8, isRTU bool) (uint8, error) {
+	var req packet.Request
+	var err error
+	if isRTU {
+		req, err = packet.NewReadExceptionStatusRequestRTU(unitID)
+	} else {
+		req, err = packet.NewReadExceptionStatusRequestTCP(unitID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	switch r := resp.(type) {
+	case *packet.ReadExceptionStatusResponseTCP:
+		return r.Status, nil
+	case *packet.ReadExceptionStatusResponseRTU:
+		return r.Status, nil
+	case *packet.ReadExceptionStatusResponseASCII:
+		return r.Status, nil
+	}
+	return 0, fmt.Errorf("unexpected response type for read exception status request: %T", resp)
+}
@@ -0,0 +1,117 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// diagnosticsEchoDevice responds to diagnostics requests by echoing the request back or with given response data
+func diagnosticsEchoDevice(responseData []byte) doerFunc {
+	return func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		switch r := req.(type) {
+		case *packet.DiagnosticsRequestTCP:
+			resp := packet.DiagnosticsResponse{UnitID: r.UnitID, SubFunction: r.SubFunction, Data: r.Data}
+			if responseData != nil {
+				resp.Data = responseData
+			}
+			return &packet.DiagnosticsResponseTCP{MBAPHeader: r.MBAPHeader, DiagnosticsResponse: resp}, nil
+		case *packet.DiagnosticsRequestRTU:
+			resp := packet.DiagnosticsResponse{UnitID: r.UnitID, SubFunction: r.SubFunction, Data: r.Data}
+			if responseData != nil {
+				resp.Data = responseData
+			}
+			return &packet.DiagnosticsResponseRTU{DiagnosticsResponse: resp}, nil
+		}
+		return nil, errors.New("unexpected request")
+	}
+}
+
+func TestEchoTestTCP(t *testing.T) {
+	assert.NoError(t, EchoTestTCP(context.Background(), diagnosticsEchoDevice(nil), 1, []byte{0xa5, 0x37}))
+
+	err := EchoTestTCP(context.Background(), diagnosticsEchoDevice([]byte{0xa5, 0x38}), 1, []byte{0xa5, 0x37})
+	assert.EqualError(t, err, "diagnostics echo response data does not match request data")
+}
+
+func TestEchoTestRTU(t *testing.T) {
+	assert.NoError(t, EchoTestRTU(context.Background(), diagnosticsEchoDevice(nil), 1, []byte{0xa5, 0x37}))
+}
+
+func TestReadDiagnosticsCounterTCP(t *testing.T) {
+	counter, err := ReadDiagnosticsCounterTCP(
+		context.Background(),
+		diagnosticsEchoDevice([]byte{0x01, 0x02}),
+		1,
+		packet.DiagnosticsReturnBusMessageCount,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x0102), counter)
+}
+
+func TestReadDiagnosticsCounterRTU(t *testing.T) {
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		return &packet.DiagnosticsResponseRTU{DiagnosticsResponse: packet.DiagnosticsResponse{
+			UnitID:      1,
+			SubFunction: packet.DiagnosticsReturnServerBusyCount,
+			Data:        []byte{0x00, 0x01},
+		}}, nil
+	})
+
+	_, err := ReadDiagnosticsCounterRTU(context.Background(), client, 1, packet.DiagnosticsReturnBusMessageCount)
+
+	assert.EqualError(t, err, "diagnostics response sub-function does not match request: 17")
+}
+
+func TestClearDiagnosticsCounters(t *testing.T) {
+	var sent []packet.Request
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		sent = append(sent, req)
+		return diagnosticsEchoDevice(nil)(ctx, req)
+	})
+
+	assert.NoError(t, ClearDiagnosticsCountersTCP(context.Background(), client, 1))
+	assert.NoError(t, ClearDiagnosticsCountersRTU(context.Background(), client, 2))
+
+	assert.Len(t, sent, 2)
+	assert.Equal(t, packet.DiagnosticsClearCounters, sent[0].(*packet.DiagnosticsRequestTCP).SubFunction)
+	assert.Equal(t, []byte{0x02, 0x08, 0x00, 0x0a, 0x00, 0x00}, sent[1].(*packet.DiagnosticsRequestRTU).DiagnosticsRequest.Bytes())
+}
+
+func TestReadExceptionStatusTCP(t *testing.T) {
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		r, ok := req.(*packet.ReadExceptionStatusRequestTCP)
+		if !ok {
+			return nil, errors.New("unexpected request")
+		}
+		return &packet.ReadExceptionStatusResponseTCP{
+			MBAPHeader:                  r.MBAPHeader,
+			ReadExceptionStatusResponse: packet.ReadExceptionStatusResponse{UnitID: r.UnitID, Status: 0x6d},
+		}, nil
+	})
+
+	status, err := ReadExceptionStatusTCP(context.Background(), client, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x6d), status)
+}
+
+func TestReadExceptionStatusRTU(t *testing.T) {
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		return &packet.ReadExceptionStatusResponseRTU{
+			ReadExceptionStatusResponse: packet.ReadExceptionStatusResponse{UnitID: 1, Status: 0x01},
+		}, nil
+	})
+
+	status, err := ReadExceptionStatusRTU(context.Background(), client, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x01), status)
+
+	client = func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		return &packet.ReadServerIDResponseRTU{}, nil
+	}
+	_, err = ReadExceptionStatusRTU(context.Background(), client, 1)
+	assert.EqualError(t, err, "unexpected response type for read exception status request: *packet.ReadServerIDResponseRTU")
+}
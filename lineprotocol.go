@@ -0,0 +1,90 @@
+package modbus
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	lineProtocolMeasurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `)
+	lineProtocolTagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `)
+	lineProtocolStringEscaper      = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+)
+
+// AppendLineProtocol appends field values to dst as InfluxDB line protocol lines and returns the extended buffer.
+// Every value is written as separate line with `server`, `unit` and `field` tags and single `value` field, i.e.
+//
+//	modbus,field=temperature,server=localhost:502,unit=1 value=21.5 1700000000000000000
+//
+// Field name is used for `field` tag, fields without name are tagged with their address. Values with extraction error
+// are skipped. Integer values are written as integers (`i` suffix), unsigned 64bit values that do not fit int64 with
+// `u` suffix. Timestamp is omitted when it is zero, so database assigns its own time.
+func AppendLineProtocol(dst []byte, measurement string, values []FieldValue, timestamp time.Time) []byte {
+	for _, v := range values {
+		if v.Error != nil {
+			continue
+		}
+		fieldValue, ok := appendLineProtocolValue(nil, v.Value)
+		if !ok {
+			continue
+		}
+		dst = append(dst, lineProtocolMeasurementEscaper.Replace(measurement)...)
+
+		name := v.Field.Name
+		if name == "" {
+			name = strconv.Itoa(int(v.Field.Address))
+		}
+		dst = append(dst, ",field="...)
+		dst = append(dst, lineProtocolTagEscaper.Replace(name)...)
+		if v.Field.ServerAddress != "" {
+			dst = append(dst, ",server="...)
+			dst = append(dst, lineProtocolTagEscaper.Replace(v.Field.ServerAddress)...)
+		}
+		dst = append(dst, ",unit="...)
+		dst = strconv.AppendUint(dst, uint64(v.Field.UnitID), 10)
+
+		dst = append(dst, " value="...)
+		dst = append(dst, fieldValue...)
+		if !timestamp.IsZero() {
+			dst = append(dst, ' ')
+			dst = strconv.AppendInt(dst, timestamp.UnixNano(), 10)
+		}
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+func appendLineProtocolValue(dst []byte, value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case bool:
+		return strconv.AppendBool(dst, v), true
+	case string:
+		dst = append(dst, '"')
+		dst = append(dst, lineProtocolStringEscaper.Replace(v)...)
+		return append(dst, '"'), true
+	case uint64:
+		if v > math.MaxInt64 {
+			return append(strconv.AppendUint(dst, v, 10), 'u'), true
+		}
+		return append(strconv.AppendUint(dst, v, 10), 'i'), true
+	case int64:
+		return append(strconv.AppendInt(dst, v, 10), 'i'), true
+	case uint8, int8, uint16, int16, uint32, int32:
+		f, _ := toFloat64(v) // exact for integers up to 32bit
+		return append(strconv.AppendInt(dst, int64(f), 10), 'i'), true
+	case float32:
+		return appendLineProtocolFloat(dst, float64(v), 32)
+	case float64:
+		return appendLineProtocolFloat(dst, v, 64)
+	}
+	return dst, false
+}
+
+func appendLineProtocolFloat(dst []byte, v float64, bitSize int) ([]byte, bool) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return dst, false // line protocol has no representation for NaN and infinity
+	}
+	return strconv.AppendFloat(dst, v, 'f', -1, bitSize), true
+}
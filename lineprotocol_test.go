@@ -0,0 +1,41 @@
+package modbus
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAppendLineProtocol(t *testing.T) {
+	values := []FieldValue{
+		{Field: Field{Name: "temperature", ServerAddress: "localhost:502", UnitID: 1}, Value: float32(21.1)},
+		{Field: Field{Name: "energy total", ServerAddress: "localhost:502", UnitID: 1}, Value: int64(-9007199254740993)},
+		{Field: Field{Name: "counter", ServerAddress: "localhost:502", UnitID: 1}, Value: uint64(math.MaxUint64)},
+		{Field: Field{Name: "state", UnitID: 2}, Value: `say "hi"`},
+		{Field: Field{Address: 100, UnitID: 2}, Value: true},
+		{Field: Field{Name: "small", UnitID: 2}, Value: uint16(7)},
+		{Field: Field{Name: "failed", UnitID: 2}, Value: uint16(7), Error: errors.New("fail")},
+		{Field: Field{Name: "nan", UnitID: 2}, Value: math.NaN()},
+	}
+
+	result := AppendLineProtocol(nil, "modbus,site 1", values, time.Unix(1700000000, 5))
+
+	expect := `modbus\,site\ 1,field=temperature,server=localhost:502,unit=1 value=21.1 1700000000000000005
+modbus\,site\ 1,field=energy\ total,server=localhost:502,unit=1 value=-9007199254740993i 1700000000000000005
+modbus\,site\ 1,field=counter,server=localhost:502,unit=1 value=18446744073709551615u 1700000000000000005
+modbus\,site\ 1,field=state,unit=2 value="say \"hi\"" 1700000000000000005
+modbus\,site\ 1,field=100,unit=2 value=true 1700000000000000005
+modbus\,site\ 1,field=small,unit=2 value=7i 1700000000000000005
+`
+	assert.Equal(t, expect, string(result))
+}
+
+func TestAppendLineProtocol_withoutTimestamp(t *testing.T) {
+	values := []FieldValue{{Field: Field{Name: "x", UnitID: 1}, Value: 1.5}}
+
+	result := AppendLineProtocol([]byte("existing\n"), "m", values, time.Time{})
+
+	assert.Equal(t, "existing\nm,field=x,unit=1 value=1.5\n", string(result))
+}
@@ -0,0 +1,353 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"math"
+)
+
+// maxFloat16 is largest finite IEEE 754 half-precision float value
+const maxFloat16 = 65504
+
+const (
+	maxUint48 = 1<<48 - 1
+	minInt48  = -1 << 47
+	maxInt48  = 1<<47 - 1
+)
+
+// MarshalBytes converts given value to register data of the field. This is inverse of Field.ExtractFrom - bytes
+// returned can be sent to the device with write requests (FC6/FC16) and extracted back to same value with same field
+// definition. Returned slice length is field register size in bytes. For fields that occupy only part of the register
+// (bit, byte, uint8, int8) other bits of the register are left 0.
+//
+// Bit fields expect bool value, string fields expect string value, time fields expect time.Time value and numeric
+// fields expect any Go numeric type that fits into field type without overflow (floats with fractional part are not
+// accepted for integer types).
+func (f *Field) MarshalBytes(value interface{}) ([]byte, error) {
+	dst := make([]byte, f.registerSize()*2)
+	if err := f.marshalInto(dst, value); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// marshalInto writes field value into given field register data. For fields that occupy only part of the register
+// only bits/byte of that field are changed in dst.
+func (f *Field) marshalInto(dst []byte, value interface{}) error {
+	if len(dst) != int(f.registerSize())*2 {
+		return errors.New("marshal destination length does not match field register size")
+	}
+	byteOrder := f.ByteOrder
+	if byteOrder == 0 {
+		byteOrder = packet.BigEndianHighWordFirst
+	}
+
+	switch f.Type {
+	case FieldTypeBit:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("bit field value must be bool, got: %T", value)
+		}
+		if f.Bit > 15 {
+			return errors.New("field bit value must be in range (0-15)")
+		}
+		nThByte := 1 // low byte of register
+		bit := f.Bit
+		if bit > 7 {
+			bit -= 8
+			nThByte = 0 // high byte of register
+		}
+		if v {
+			dst[nThByte] |= 1 << bit
+		} else {
+			dst[nThByte] &^= 1 << bit
+		}
+	case FieldTypeByte, FieldTypeUint8:
+		v, err := toUint64(value, math.MaxUint8)
+		if err != nil {
+			return err
+		}
+		if f.FromHighByte {
+			dst[0] = uint8(v)
+		} else {
+			dst[1] = uint8(v)
+		}
+	case FieldTypeInt8:
+		v, err := toInt64(value, math.MinInt8, math.MaxInt8)
+		if err != nil {
+			return err
+		}
+		if f.FromHighByte {
+			dst[0] = uint8(int8(v))
+		} else {
+			dst[1] = uint8(int8(v))
+		}
+	case FieldTypeUint16:
+		v, err := toUint64(value, math.MaxUint16)
+		if err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint16(dst, uint16(v))
+	case FieldTypeInt16:
+		v, err := toInt64(value, math.MinInt16, math.MaxInt16)
+		if err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint16(dst, uint16(int16(v)))
+	case FieldTypeUint32:
+		v, err := toUint64(value, math.MaxUint32)
+		if err != nil {
+			return err
+		}
+		putUint32(dst, uint32(v), byteOrder)
+	case FieldTypeInt32:
+		v, err := toInt64(value, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		putUint32(dst, uint32(int32(v)), byteOrder)
+	case FieldTypeUint64:
+		v, err := toUint64(value, math.MaxUint64)
+		if err != nil {
+			return err
+		}
+		putUint64(dst, v, byteOrder)
+	case FieldTypeInt64:
+		v, err := toInt64(value, math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return err
+		}
+		putUint64(dst, uint64(v), byteOrder)
+	case FieldTypeUint48:
+		v, err := toUint64(value, maxUint48)
+		if err != nil {
+			return err
+		}
+		putUint48(dst, v, byteOrder)
+	case FieldTypeInt48:
+		v, err := toInt64(value, minInt48, maxInt48)
+		if err != nil {
+			return err
+		}
+		putUint48(dst, uint64(v), byteOrder)
+	case FieldTypeUnixTime32:
+		v, err := f.secondsFromTime(value)
+		if err != nil {
+			return err
+		}
+		if v < 0 || v > math.MaxUint32 {
+			return fmt.Errorf("time is out of range of 32 bit unix time field: %v", value)
+		}
+		putUint32(dst, uint32(v), byteOrder)
+	case FieldTypeUnixTime64:
+		v, err := f.secondsFromTime(value)
+		if err != nil {
+			return err
+		}
+		putUint64(dst, uint64(v), byteOrder)
+	case FieldTypeBCD:
+		v, err := toUint64(value, f.maxBCDValue())
+		if err != nil {
+			return err
+		}
+		bcd, err := packet.Uint32ToBCD(uint32(v))
+		if err != nil {
+			return err
+		}
+		if len(dst) == 2 {
+			binary.BigEndian.PutUint16(dst, uint16(bcd))
+		} else {
+			putUint32(dst, bcd, byteOrder)
+		}
+	case FieldTypeFloat16:
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value is not numeric, got: %T", value)
+		}
+		if !math.IsInf(v, 0) && !math.IsNaN(v) && math.Abs(v) > maxFloat16 {
+			return fmt.Errorf("value overflows float16: %v", v)
+		}
+		bits := packet.Float16bits(float32(v))
+		if byteOrder&packet.LittleEndian != 0 {
+			binary.LittleEndian.PutUint16(dst, bits)
+		} else {
+			binary.BigEndian.PutUint16(dst, bits)
+		}
+	case FieldTypeFloat32:
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value is not numeric, got: %T", value)
+		}
+		if !math.IsInf(v, 0) && !math.IsNaN(v) && math.Abs(v) > math.MaxFloat32 {
+			return fmt.Errorf("value overflows float32: %v", v)
+		}
+		putUint32(dst, math.Float32bits(float32(v)), byteOrder)
+	case FieldTypeFloat64:
+		v, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("value is not numeric, got: %T", value)
+		}
+		putUint64(dst, math.Float64bits(v), byteOrder)
+	case FieldTypeString:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("string field value must be string, got: %T", value)
+		}
+		maxLength := int(f.Length)
+		if f.StringNullTerminated {
+			maxLength-- // room for null terminator
+		}
+		if len(v) > maxLength {
+			return fmt.Errorf("string value length %v exceeds field max value length %v", len(v), maxLength)
+		}
+		for i := 0; i < len(v); i++ {
+			if v[i] > 127 {
+				return errors.New("string value can contain only ASCII characters")
+			}
+		}
+		padding := byte(0x0)
+		if f.StringPadding == StringPaddingSpace {
+			padding = ' '
+		}
+		for i := range dst {
+			if i < int(f.Length) {
+				dst[i] = padding
+			} else {
+				dst[i] = 0 // byte after odd length string is not part of the value
+			}
+		}
+		copy(dst, v)
+		if f.StringNullTerminated {
+			dst[len(v)] = 0
+		}
+		if byteOrder&packet.BigEndian != 0 {
+			// strings are extracted with bytes swapped in every register. swap them back
+			for i := 1; i < len(dst); i += 2 {
+				dst[i-1], dst[i] = dst[i], dst[i-1]
+			}
+		}
+	case FieldTypeCoil:
+		return errors.New("coil field can not be marshalled to register data")
+	default:
+		return errors.New("marshalling failure due unknown field type")
+	}
+	return nil
+}
+
+func putUint32(dst []byte, v uint32, byteOrder packet.ByteOrder) {
+	if byteOrder&packet.LittleEndian != 0 {
+		binary.LittleEndian.PutUint32(dst, v)
+	} else {
+		binary.BigEndian.PutUint32(dst, v)
+	}
+	if byteOrder&packet.LowWordFirst != 0 {
+		// reverse words/registers order (low word first)
+		dst[0], dst[1], dst[2], dst[3] = dst[2], dst[3], dst[0], dst[1]
+	}
+}
+
+func putUint48(dst []byte, v uint64, byteOrder packet.ByteOrder) {
+	for i := 0; i < 6; i++ {
+		b := byte(v >> (8 * i))
+		if byteOrder&packet.LittleEndian != 0 {
+			dst[i] = b
+		} else {
+			dst[5-i] = b
+		}
+	}
+	if byteOrder&packet.LowWordFirst != 0 {
+		// reverse words/registers order (low word first)
+		dst[0], dst[1], dst[4], dst[5] = dst[4], dst[5], dst[0], dst[1]
+	}
+}
+
+func putUint64(dst []byte, v uint64, byteOrder packet.ByteOrder) {
+	if byteOrder&packet.LittleEndian != 0 {
+		binary.LittleEndian.PutUint64(dst, v)
+	} else {
+		binary.BigEndian.PutUint64(dst, v)
+	}
+	if byteOrder&packet.LowWordFirst != 0 {
+		// reverse words/registers order (low word first)
+		dst[0], dst[1], dst[6], dst[7] = dst[6], dst[7], dst[0], dst[1]
+		dst[2], dst[3], dst[4], dst[5] = dst[4], dst[5], dst[2], dst[3]
+	}
+}
+
+func toUint64(value interface{}, max uint64) (uint64, error) {
+	var result uint64
+	switch v := value.(type) {
+	case uint8:
+		result = uint64(v)
+	case uint16:
+		result = uint64(v)
+	case uint32:
+		result = uint64(v)
+	case uint64:
+		result = v
+	case uint:
+		result = uint64(v)
+	case int8, int16, int32, int64, int:
+		i, err := toInt64(v, math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return 0, err
+		}
+		if i < 0 {
+			return 0, fmt.Errorf("value is out of range (0-%v): %v", max, i)
+		}
+		result = uint64(i)
+	case float32, float64:
+		f, _ := toFloat64(v)
+		// float64(math.MaxUint64) rounds up to 2^64 so upper bound of uint64 range has to be checked with `>=`
+		if f != math.Trunc(f) || f < 0 || f >= 1<<64 || f > float64(max) {
+			return 0, fmt.Errorf("value is not integer in range (0-%v): %v", max, f)
+		}
+		result = uint64(f)
+	default:
+		return 0, fmt.Errorf("value is not numeric, got: %T", value)
+	}
+	if result > max {
+		return 0, fmt.Errorf("value is out of range (0-%v): %v", max, result)
+	}
+	return result, nil
+}
+
+func toInt64(value interface{}, min int64, max int64) (int64, error) {
+	var result int64
+	switch v := value.(type) {
+	case int8:
+		result = int64(v)
+	case int16:
+		result = int64(v)
+	case int32:
+		result = int64(v)
+	case int64:
+		result = v
+	case int:
+		result = int64(v)
+	case uint8, uint16, uint32, uint64, uint:
+		u, err := toUint64(v, math.MaxUint64)
+		if err != nil {
+			return 0, err
+		}
+		if u > uint64(max) {
+			return 0, fmt.Errorf("value is out of range (%v-%v): %v", min, max, u)
+		}
+		result = int64(u)
+	case float32, float64:
+		f, _ := toFloat64(v)
+		// float64(math.MaxInt64) rounds up to 2^63 so upper bound of int64 range has to be checked with `>=`
+		if f != math.Trunc(f) || f < float64(min) || f >= 1<<63 || f > float64(max) {
+			return 0, fmt.Errorf("value is not integer in range (%v-%v): %v", min, max, f)
+		}
+		result = int64(f)
+	default:
+		return 0, fmt.Errorf("value is not numeric, got: %T", value)
+	}
+	if result < min || result > max {
+		return 0, fmt.Errorf("value is out of range (%v-%v): %v", min, max, result)
+	}
+	return result, nil
+}
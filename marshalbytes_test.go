@@ -0,0 +1,336 @@
+package modbus
+
+import (
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestField_MarshalBytes(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       Field
+		value       interface{}
+		expect      []byte
+		expectError string
+	}{
+		{
+			name:   "ok, bit in high byte",
+			given:  Field{Type: FieldTypeBit, Bit: 9},
+			value:  true,
+			expect: []byte{0x02, 0x00},
+		},
+		{
+			name:   "ok, bit in low byte",
+			given:  Field{Type: FieldTypeBit, Bit: 0},
+			value:  true,
+			expect: []byte{0x00, 0x01},
+		},
+		{
+			name:        "nok, bit with non bool value",
+			given:       Field{Type: FieldTypeBit, Bit: 0},
+			value:       1,
+			expectError: "bit field value must be bool, got: int",
+		},
+		{
+			name:   "ok, uint8 high byte",
+			given:  Field{Type: FieldTypeUint8, FromHighByte: true},
+			value:  200,
+			expect: []byte{0xc8, 0x00},
+		},
+		{
+			name:        "nok, uint8 overflow",
+			given:       Field{Type: FieldTypeUint8},
+			value:       256,
+			expectError: "value is out of range (0-255): 256",
+		},
+		{
+			name:   "ok, int8 low byte",
+			given:  Field{Type: FieldTypeInt8},
+			value:  int8(-1),
+			expect: []byte{0x00, 0xff},
+		},
+		{
+			name:   "ok, uint16",
+			given:  Field{Type: FieldTypeUint16},
+			value:  uint16(0xcafe),
+			expect: []byte{0xca, 0xfe},
+		},
+		{
+			name:   "ok, uint16 from whole float64",
+			given:  Field{Type: FieldTypeUint16},
+			value:  float64(300),
+			expect: []byte{0x01, 0x2c},
+		},
+		{
+			name:        "nok, uint16 from fractional float64",
+			given:       Field{Type: FieldTypeUint16},
+			value:       1.5,
+			expectError: "value is not integer in range (0-65535): 1.5",
+		},
+		{
+			name:        "nok, uint16 from negative int",
+			given:       Field{Type: FieldTypeUint16},
+			value:       -1,
+			expectError: "value is out of range (0-65535): -1",
+		},
+		{
+			name:   "ok, int16",
+			given:  Field{Type: FieldTypeInt16},
+			value:  -2,
+			expect: []byte{0xff, 0xfe},
+		},
+		{
+			name:        "nok, int16 overflow",
+			given:       Field{Type: FieldTypeInt16},
+			value:       uint32(40000),
+			expectError: "value is out of range (-32768-32767): 40000",
+		},
+		{
+			name:   "ok, uint32 default byte order",
+			given:  Field{Type: FieldTypeUint32},
+			value:  uint32(0x01020304),
+			expect: []byte{0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			name:   "ok, uint32 big endian low word first",
+			given:  Field{Type: FieldTypeUint32, ByteOrder: packet.BigEndianLowWordFirst},
+			value:  uint32(0x01020304),
+			expect: []byte{0x03, 0x04, 0x01, 0x02},
+		},
+		{
+			name:   "ok, int64 little endian high word first",
+			given:  Field{Type: FieldTypeInt64, ByteOrder: packet.LittleEndianHighWordFirst},
+			value:  int64(0x0102030405060708),
+			expect: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01},
+		},
+		{
+			name:   "ok, uint64 big endian low word first",
+			given:  Field{Type: FieldTypeUint64, ByteOrder: packet.BigEndianLowWordFirst},
+			value:  uint64(0x0102030405060708),
+			expect: []byte{0x07, 0x08, 0x05, 0x06, 0x03, 0x04, 0x01, 0x02},
+		},
+		{
+			name:   "ok, uint64 from float64 2^63",
+			given:  Field{Type: FieldTypeUint64},
+			value:  float64(1 << 63),
+			expect: []byte{0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:        "nok, uint64 from float64 2^64",
+			given:       Field{Type: FieldTypeUint64},
+			value:       float64(1 << 64),
+			expectError: "value is not integer in range (0-18446744073709551615): 1.8446744073709552e+19",
+		},
+		{
+			name:   "ok, int64 from float64 -2^63",
+			given:  Field{Type: FieldTypeInt64},
+			value:  float64(-1 << 63),
+			expect: []byte{0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:        "nok, int64 from float64 2^63",
+			given:       Field{Type: FieldTypeInt64},
+			value:       float64(1 << 63),
+			expectError: "value is not integer in range (-9223372036854775808-9223372036854775807): 9.223372036854776e+18",
+		},
+		{
+			name:   "ok, uint48",
+			given:  Field{Type: FieldTypeUint48},
+			value:  uint64(0x010203040506),
+			expect: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+		},
+		{
+			name:   "ok, uint48 little endian low word first",
+			given:  Field{Type: FieldTypeUint48, ByteOrder: packet.LittleEndianLowWordFirst},
+			value:  uint64(0x010203040506),
+			expect: []byte{0x02, 0x01, 0x04, 0x03, 0x06, 0x05},
+		},
+		{
+			name:        "nok, uint48 overflow",
+			given:       Field{Type: FieldTypeUint48},
+			value:       uint64(1 << 48),
+			expectError: "value is out of range (0-281474976710655): 281474976710656",
+		},
+		{
+			name:   "ok, int48 big endian low word first",
+			given:  Field{Type: FieldTypeInt48, ByteOrder: packet.BigEndianLowWordFirst},
+			value:  int64(-2),
+			expect: []byte{0xFF, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF},
+		},
+		{
+			name:        "nok, int48 overflow",
+			given:       Field{Type: FieldTypeInt48},
+			value:       int64(-1<<47 - 1),
+			expectError: "value is out of range (-140737488355328-140737488355327): -140737488355329",
+		},
+		{
+			name:   "ok, unix time 32",
+			given:  Field{Type: FieldTypeUnixTime32},
+			value:  time.Date(2024, 1, 1, 2, 0, 0, 0, time.FixedZone("EET", 2*3600)),
+			expect: []byte{0x65, 0x92, 0x00, 0x80},
+		},
+		{
+			name:        "nok, unix time 32 before epoch",
+			given:       Field{Type: FieldTypeUnixTime32},
+			value:       time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+			expectError: "time is out of range of 32 bit unix time field: 1969-12-31 23:59:59 +0000 UTC",
+		},
+		{
+			name:        "nok, unix time 64 from int",
+			given:       Field{Type: FieldTypeUnixTime64},
+			value:       1704067200,
+			expectError: "time field value must be time.Time, got: int",
+		},
+		{
+			name:   "ok, bcd default digits",
+			given:  Field{Type: FieldTypeBCD},
+			value:  1234,
+			expect: []byte{0x12, 0x34},
+		},
+		{
+			name:   "ok, bcd 6 digits low word first",
+			given:  Field{Type: FieldTypeBCD, Length: 6, ByteOrder: packet.BigEndianLowWordFirst},
+			value:  uint32(123456),
+			expect: []byte{0x34, 0x56, 0x00, 0x12},
+		},
+		{
+			name:        "nok, bcd value has too many digits",
+			given:       Field{Type: FieldTypeBCD, Length: 3},
+			value:       1000,
+			expectError: "value is out of range (0-999): 1000",
+		},
+		{
+			name:   "ok, float16",
+			given:  Field{Type: FieldTypeFloat16},
+			value:  float32(2.5),
+			expect: []byte{0x41, 0x00},
+		},
+		{
+			name:   "ok, float16 little endian",
+			given:  Field{Type: FieldTypeFloat16, ByteOrder: packet.LittleEndianHighWordFirst},
+			value:  1.5,
+			expect: []byte{0x00, 0x3e},
+		},
+		{
+			name:        "nok, float16 overflow",
+			given:       Field{Type: FieldTypeFloat16},
+			value:       float64(70000),
+			expectError: "value overflows float16: 70000",
+		},
+		{
+			name:   "ok, float32",
+			given:  Field{Type: FieldTypeFloat32},
+			value:  float32(2.5),
+			expect: []byte{0x40, 0x20, 0x00, 0x00},
+		},
+		{
+			name:   "ok, float64 from int",
+			given:  Field{Type: FieldTypeFloat64},
+			value:  int32(1),
+			expect: []byte{0x3f, 0xf0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:        "nok, float32 from string",
+			given:       Field{Type: FieldTypeFloat32},
+			value:       "1",
+			expectError: "value is not numeric, got: string",
+		},
+		{
+			name:   "ok, string default byte order",
+			given:  Field{Type: FieldTypeString, Length: 5},
+			value:  "abc",
+			expect: []byte{'b', 'a', 0x0, 'c', 0x0, 0x0},
+		},
+		{
+			name:   "ok, string little endian",
+			given:  Field{Type: FieldTypeString, Length: 3, ByteOrder: packet.LittleEndianHighWordFirst},
+			value:  "abc",
+			expect: []byte{'a', 'b', 'c', 0x0},
+		},
+		{
+			name:        "nok, string too long",
+			given:       Field{Type: FieldTypeString, Length: 2},
+			value:       "abc",
+			expectError: "string value length 3 exceeds field max value length 2",
+		},
+		{
+			name:   "ok, string padded with spaces",
+			given:  Field{Type: FieldTypeString, Length: 5, StringPadding: StringPaddingSpace},
+			value:  "ab",
+			expect: []byte{'b', 'a', ' ', ' ', 0x0, ' '},
+		},
+		{
+			name:   "ok, string padded with spaces and null terminated",
+			given:  Field{Type: FieldTypeString, Length: 4, StringPadding: StringPaddingSpace, StringNullTerminated: true},
+			value:  "a",
+			expect: []byte{0x0, 'a', ' ', ' '},
+		},
+		{
+			name:   "ok, string null terminated uses all but last byte",
+			given:  Field{Type: FieldTypeString, Length: 4, StringNullTerminated: true, ByteOrder: packet.LittleEndianHighWordFirst},
+			value:  "abc",
+			expect: []byte{'a', 'b', 'c', 0x0},
+		},
+		{
+			name:        "nok, string null terminated has no room for terminator",
+			given:       Field{Type: FieldTypeString, Length: 3, StringNullTerminated: true},
+			value:       "abc",
+			expectError: "string value length 3 exceeds field max value length 2",
+		},
+		{
+			name:        "nok, coil",
+			given:       Field{Type: FieldTypeCoil},
+			value:       true,
+			expectError: "coil field can not be marshalled to register data",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.given.MarshalBytes(tc.value)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestField_MarshalBytes_roundTrip(t *testing.T) {
+	tallinn, err := loadTimeLocation("Europe/Tallinn")
+	assert.NoError(t, err)
+
+	var testCases = []struct {
+		name  string
+		given Field
+		value interface{}
+	}{
+		{name: "uint32 LE LWF", given: Field{Type: FieldTypeUint32, ByteOrder: packet.LittleEndianLowWordFirst}, value: uint32(2923517522)},
+		{name: "int32 BE LWF", given: Field{Type: FieldTypeInt32, ByteOrder: packet.BigEndianLowWordFirst}, value: int32(-123456)},
+		{name: "float64 LE LWF", given: Field{Type: FieldTypeFloat64, ByteOrder: packet.LittleEndianLowWordFirst}, value: 1.0123},
+		{name: "string odd length", given: Field{Type: FieldTypeString, Length: 7}, value: "go rocks"[:7]},
+		{name: "string space padded", given: Field{Type: FieldTypeString, Length: 6, StringPadding: StringPaddingSpace}, value: "abc"},
+		{name: "unix time 32 LE LWF", given: Field{Type: FieldTypeUnixTime32, ByteOrder: packet.LittleEndianLowWordFirst}, value: time.Date(2024, 7, 1, 12, 30, 0, 0, time.UTC)},
+		{name: "unix time 64 epoch and zone", given: Field{Type: FieldTypeUnixTime64, TimeEpoch: 946684800, TimeZone: "Europe/Tallinn"}, value: time.Date(1999, 7, 1, 12, 30, 0, 0, tallinn)},
+		{name: "int8 high byte", given: Field{Type: FieldTypeInt8, FromHighByte: true}, value: int8(-100)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.given.Address = 10
+			b, err := tc.given.MarshalBytes(tc.value)
+			assert.NoError(t, err)
+
+			regs, err := packet.NewRegisters(b, 10)
+			assert.NoError(t, err)
+			result, err := tc.given.ExtractFrom(regs)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.value, result)
+		})
+	}
+}
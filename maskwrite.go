@@ -0,0 +1,73 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// WriteBitFieldTCP sets or clears single bit of holding register described by bit field (FieldTypeBit) using Mask Write
+// Register (FC22) Modbus TCP request. See WriteBitFieldRTU for details.
+func WriteBitFieldTCP(ctx context.Context, client Doer, field Field, value bool) error {
+	return writeBitField(ctx, client, field, value, false)
+}
+
+// WriteBitFieldRTU sets or clears single bit of holding register described by bit field (FieldTypeBit) using Mask Write
+// Register (FC22) Modbus RTU request. Server applies masks to current register value itself so other bits of the
+// register are not changed and there is no read-modify-write race with other clients writing the same register.
+func WriteBitFieldRTU(ctx context.Context, client Doer, field Field, value bool) error {
+	return writeBitField(ctx, client, field, value, true)
+}
+
+func writeBitField(ctx context.Context, client Doer, field Field, value bool, isRTU bool) error {
+	andMask, orMask, err := field.bitMasks(value)
+	if err != nil {
+		return err
+	}
+
+	var req packet.Request
+	if isRTU {
+		req, err = packet.NewMaskWriteRegisterRequestRTU(field.UnitID, field.Address, andMask, orMask)
+	} else {
+		req, err = packet.NewMaskWriteRegisterRequestTCP(field.UnitID, field.Address, andMask, orMask)
+	}
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("write bit field failed: %w", err)
+	}
+	var result packet.MaskWriteRegisterResponse
+	switch r := resp.(type) {
+	case *packet.MaskWriteRegisterResponseTCP:
+		result = r.MaskWriteRegisterResponse
+	case *packet.MaskWriteRegisterResponseRTU:
+		result = r.MaskWriteRegisterResponse
+	case *packet.MaskWriteRegisterResponseASCII:
+		result = r.MaskWriteRegisterResponse
+	default:
+		return fmt.Errorf("unexpected response type for mask write register request: %T", resp)
+	}
+	if result.Address != field.Address || result.AndMask != andMask || result.OrMask != orMask {
+		return errors.New("mask write register response does not match request")
+	}
+	return nil
+}
+
+// bitMasks returns Mask Write Register (FC22) AND and OR masks setting (or clearing) bit field to given value
+func (f *Field) bitMasks(value bool) (uint16, uint16, error) {
+	if f.Type != FieldTypeBit {
+		return 0, 0, errors.New("mask write is supported only for bit fields")
+	}
+	if f.Bit > 15 {
+		return 0, 0, errors.New("field bit value must be in range (0-15)")
+	}
+	bit := uint16(1) << f.Bit
+	orMask := uint16(0)
+	if value {
+		orMask = bit
+	}
+	return ^bit, orMask, nil
+}
@@ -0,0 +1,106 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// maskWriteDevice applies mask write requests to single register value like server would do
+func maskWriteDevice(register *uint16) doerFunc {
+	return func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		var r packet.MaskWriteRegisterRequest
+		switch tmp := req.(type) {
+		case *packet.MaskWriteRegisterRequestTCP:
+			r = tmp.MaskWriteRegisterRequest
+		case *packet.MaskWriteRegisterRequestRTU:
+			r = tmp.MaskWriteRegisterRequest
+		default:
+			return nil, errors.New("unexpected request")
+		}
+		*register = (*register & r.AndMask) | (r.OrMask & ^r.AndMask)
+
+		resp := packet.MaskWriteRegisterResponse{UnitID: r.UnitID, Address: r.Address, AndMask: r.AndMask, OrMask: r.OrMask}
+		if _, ok := req.(*packet.MaskWriteRegisterRequestRTU); ok {
+			return &packet.MaskWriteRegisterResponseRTU{MaskWriteRegisterResponse: resp}, nil
+		}
+		return &packet.MaskWriteRegisterResponseTCP{MaskWriteRegisterResponse: resp}, nil
+	}
+}
+
+func TestWriteBitFieldTCP(t *testing.T) {
+	register := uint16(0b1010_0000_0000_0001)
+	client := maskWriteDevice(&register)
+	field := Field{UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 3}
+
+	assert.NoError(t, WriteBitFieldTCP(context.Background(), client, field, true))
+	assert.Equal(t, uint16(0b1010_0000_0000_1001), register)
+
+	field.Bit = 15
+	assert.NoError(t, WriteBitFieldTCP(context.Background(), client, field, false))
+	assert.Equal(t, uint16(0b0010_0000_0000_1001), register)
+}
+
+func TestWriteBitFieldRTU(t *testing.T) {
+	register := uint16(0xffff)
+	client := maskWriteDevice(&register)
+	field := Field{UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 0}
+
+	assert.NoError(t, WriteBitFieldRTU(context.Background(), client, field, false))
+	assert.Equal(t, uint16(0xfffe), register)
+}
+
+func TestWriteBitField_errors(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenField Field
+		whenDoer  doerFunc
+		expectErr string
+	}{
+		{
+			name:      "nok, not a bit field",
+			whenField: Field{UnitID: 1, Address: 10, Type: FieldTypeUint16},
+			expectErr: "mask write is supported only for bit fields",
+		},
+		{
+			name:      "nok, bit out of range",
+			whenField: Field{UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 16},
+			expectErr: "field bit value must be in range (0-15)",
+		},
+		{
+			name:      "nok, request failed",
+			whenField: Field{UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 1},
+			whenDoer: func(ctx context.Context, req packet.Request) (packet.Response, error) {
+				return nil, errors.New("timeout")
+			},
+			expectErr: "write bit field failed: timeout",
+		},
+		{
+			name:      "nok, unexpected response type",
+			whenField: Field{UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 1},
+			whenDoer: func(ctx context.Context, req packet.Request) (packet.Response, error) {
+				return &packet.WriteSingleRegisterResponseTCP{}, nil
+			},
+			expectErr: "unexpected response type for mask write register request: *packet.WriteSingleRegisterResponseTCP",
+		},
+		{
+			name:      "nok, response does not match request",
+			whenField: Field{UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 1},
+			whenDoer: func(ctx context.Context, req packet.Request) (packet.Response, error) {
+				return &packet.MaskWriteRegisterResponseTCP{
+					MaskWriteRegisterResponse: packet.MaskWriteRegisterResponse{UnitID: 1, Address: 11},
+				}, nil
+			},
+			expectErr: "mask write register response does not match request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := WriteBitFieldTCP(context.Background(), tc.whenDoer, tc.whenField, true)
+			assert.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
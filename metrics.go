@@ -0,0 +1,271 @@
+package modbus
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// errReadTimeoutExceeded is returned (wrapped in ClientError) when response was not received within read timeout
+var errReadTimeoutExceeded = errors.New("total read timeout exceeded")
+
+// ClientMetrics receives measurements of connections and requests made by Client, SerialClient and ClientPool. Implement
+// it to export metrics to monitoring system of your choice (i.e. as prometheus.Collector). ClientStats is built-in
+// implementation that can be published with expvar.
+//
+// NB: methods are called synchronously from goroutine doing the request and can be called concurrently.
+// Implementations must be fast and safe for concurrent use.
+type ClientMetrics interface {
+	// ConnectDone is called after every connection attempt to the server. err is nil for successful connection.
+	ConnectDone(serverAddress string, err error)
+	// RequestDone is called after every request that was written to the server
+	RequestDone(m RequestMetric)
+}
+
+// RequestMetric is measurement of single request
+type RequestMetric struct {
+	// ServerAddress is address of the server request was sent to. Empty for SerialClient.
+	ServerAddress string
+	UnitID        uint8
+	FunctionCode  uint8
+	// RoundTrip is time from writing the request until response was received or request failed
+	RoundTrip time.Duration
+	// Err is error request failed with. Nil for successful requests.
+	Err error
+}
+
+// ExceptionCode returns Modbus exception code when request failed with Modbus exception
+func (m RequestMetric) ExceptionCode() (packet.ErrCode, bool) {
+	if m.Err == nil {
+		return 0, false
+	}
+	var tcp *packet.ErrorResponseTCP
+	if errors.As(m.Err, &tcp) {
+		return tcp.Code, true
+	}
+	var rtu *packet.ErrorResponseRTU
+	if errors.As(m.Err, &rtu) {
+		return rtu.Code, true
+	}
+	var ascii *packet.ErrorResponseASCII
+	if errors.As(m.Err, &ascii) {
+		return ascii.Code, true
+	}
+	return 0, false
+}
+
+// IsTimeout returns true when request failed because response was not received in time
+func (m RequestMetric) IsTimeout() bool {
+	if m.Err == nil {
+		return false
+	}
+	return errors.Is(m.Err, errReadTimeoutExceeded) ||
+		errors.Is(m.Err, context.DeadlineExceeded) ||
+		errors.Is(m.Err, os.ErrDeadlineExceeded)
+}
+
+// frameUnitID returns unit ID of given request frame
+func frameUnitID(data []byte, isRTU bool) uint8 {
+	if !isRTU {
+		if len(data) < 7 {
+			return 0
+		}
+		return data[6]
+	}
+	if len(data) > 2 && data[0] == ':' { // ASCII frame, unit ID is hex encoded
+		var unitID [1]byte
+		if _, err := hex.Decode(unitID[:], data[1:3]); err == nil {
+			return unitID[0]
+		}
+		return 0
+	}
+	if len(data) < 1 {
+		return 0
+	}
+	return data[0]
+}
+
+// DefaultLatencyBuckets are upper bounds of round trip latency histogram buckets used by ClientStats
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// ClientStats is ClientMetrics implementation that collects counters and round trip latency histograms per server and
+// unit ID in memory. ClientStats implements expvar.Var so it can be published with `expvar.Publish("modbus", stats)`.
+// ClientStats is safe for concurrent use and can be shared between multiple clients.
+type ClientStats struct {
+	buckets []time.Duration
+
+	mu      sync.Mutex
+	servers map[string]*ServerStats
+	units   map[unitStatsKey]*UnitStats
+}
+
+type unitStatsKey struct {
+	serverAddress string
+	unitID        uint8
+}
+
+// ServerStats are connection counters of single server
+type ServerStats struct {
+	ServerAddress string `json:"server_address"`
+	// Connects is count of successful connection attempts. Every connect after the first one is reconnect.
+	Connects uint64 `json:"connects"`
+	// ConnectErrors is count of failed connection attempts
+	ConnectErrors uint64 `json:"connect_errors"`
+}
+
+// UnitStats are request counters and round trip latency histogram of single server and unit ID
+type UnitStats struct {
+	ServerAddress string `json:"server_address"`
+	UnitID        uint8  `json:"unit_id"`
+
+	// Requests is count of requests sent
+	Requests uint64 `json:"requests"`
+	// Responses is count of successful responses received
+	Responses uint64 `json:"responses"`
+	// Errors is count of failed requests (including exceptions and timeouts)
+	Errors uint64 `json:"errors"`
+	// Timeouts is count of requests that did not receive response in time
+	Timeouts uint64 `json:"timeouts"`
+	// Exceptions is count of Modbus exceptions received by exception code
+	Exceptions map[packet.ErrCode]uint64 `json:"exceptions"`
+
+	// LatencyBuckets are upper bounds of latency histogram buckets
+	LatencyBuckets []time.Duration `json:"latency_buckets"`
+	// LatencyCounts is count of round trips for each bucket. Last element counts round trips exceeding last bucket.
+	LatencyCounts []uint64 `json:"latency_counts"`
+	// LatencySum is sum of all round trip latencies
+	LatencySum time.Duration `json:"latency_sum"`
+}
+
+// NewClientStats creates new instance of ClientStats. When no latency buckets are given DefaultLatencyBuckets are used.
+func NewClientStats(latencyBuckets ...time.Duration) *ClientStats {
+	if len(latencyBuckets) == 0 {
+		latencyBuckets = DefaultLatencyBuckets
+	}
+	buckets := make([]time.Duration, len(latencyBuckets))
+	copy(buckets, latencyBuckets)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+
+	return &ClientStats{
+		buckets: buckets,
+		servers: map[string]*ServerStats{},
+		units:   map[unitStatsKey]*UnitStats{},
+	}
+}
+
+// ConnectDone counts connection attempt to the server
+func (s *ClientStats) ConnectDone(serverAddress string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	server, ok := s.servers[serverAddress]
+	if !ok {
+		server = &ServerStats{ServerAddress: serverAddress}
+		s.servers[serverAddress] = server
+	}
+	if err != nil {
+		server.ConnectErrors++
+		return
+	}
+	server.Connects++
+}
+
+// RequestDone counts request and its outcome and adds round trip to latency histogram
+func (s *ClientStats) RequestDone(m RequestMetric) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := unitStatsKey{serverAddress: m.ServerAddress, unitID: m.UnitID}
+	unit, ok := s.units[key]
+	if !ok {
+		unit = &UnitStats{
+			ServerAddress:  m.ServerAddress,
+			UnitID:         m.UnitID,
+			Exceptions:     map[packet.ErrCode]uint64{},
+			LatencyBuckets: s.buckets,
+			LatencyCounts:  make([]uint64, len(s.buckets)+1),
+		}
+		s.units[key] = unit
+	}
+	unit.Requests++
+	if m.Err == nil {
+		unit.Responses++
+	} else {
+		unit.Errors++
+		if m.IsTimeout() {
+			unit.Timeouts++
+		}
+		if code, ok := m.ExceptionCode(); ok {
+			unit.Exceptions[code]++
+		}
+	}
+	i := sort.Search(len(s.buckets), func(i int) bool { return m.RoundTrip <= s.buckets[i] })
+	unit.LatencyCounts[i]++
+	unit.LatencySum += m.RoundTrip
+}
+
+// ClientStatsSnapshot is point in time copy of ClientStats
+type ClientStatsSnapshot struct {
+	Servers []ServerStats `json:"servers"`
+	Units   []UnitStats   `json:"units"`
+}
+
+// Snapshot returns copy of collected stats ordered by server address and unit ID
+func (s *ClientStats) Snapshot() ClientStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := ClientStatsSnapshot{
+		Servers: make([]ServerStats, 0, len(s.servers)),
+		Units:   make([]UnitStats, 0, len(s.units)),
+	}
+	for _, server := range s.servers {
+		result.Servers = append(result.Servers, *server)
+	}
+	for _, unit := range s.units {
+		u := *unit
+		u.Exceptions = make(map[packet.ErrCode]uint64, len(unit.Exceptions))
+		for code, count := range unit.Exceptions {
+			u.Exceptions[code] = count
+		}
+		u.LatencyCounts = make([]uint64, len(unit.LatencyCounts))
+		copy(u.LatencyCounts, unit.LatencyCounts)
+		result.Units = append(result.Units, u)
+	}
+	sort.Slice(result.Servers, func(i, j int) bool {
+		return result.Servers[i].ServerAddress < result.Servers[j].ServerAddress
+	})
+	sort.Slice(result.Units, func(i, j int) bool {
+		if result.Units[i].ServerAddress != result.Units[j].ServerAddress {
+			return result.Units[i].ServerAddress < result.Units[j].ServerAddress
+		}
+		return result.Units[i].UnitID < result.Units[j].UnitID
+	})
+	return result
+}
+
+// String returns stats snapshot as JSON. Implements expvar.Var interface.
+func (s *ClientStats) String() string {
+	b, err := json.Marshal(s.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
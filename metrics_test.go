@@ -0,0 +1,185 @@
+package modbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRequestMetric_ExceptionCode(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		when       error
+		expect     packet.ErrCode
+		expectIsOk bool
+	}{
+		{
+			name:       "ok, tcp exception",
+			when:       newExceptionError(&packet.ErrorResponseTCP{Function: 3, Code: packet.ErrIllegalDataAddress}),
+			expect:     packet.ErrIllegalDataAddress,
+			expectIsOk: true,
+		},
+		{
+			name:       "ok, rtu exception",
+			when:       newExceptionError(&packet.ErrorResponseRTU{Function: 3, Code: packet.ErrServerFailure}),
+			expect:     packet.ErrServerFailure,
+			expectIsOk: true,
+		},
+		{
+			name:       "ok, gateway exception",
+			when:       newExceptionError(&packet.ErrorResponseTCP{Function: 3, Code: packet.ErrGatewayPathUnavailable}),
+			expect:     packet.ErrGatewayPathUnavailable,
+			expectIsOk: true,
+		},
+		{
+			name:       "nok, other error",
+			when:       errors.New("other"),
+			expectIsOk: false,
+		},
+		{
+			name:       "nok, no error",
+			when:       nil,
+			expectIsOk: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, ok := RequestMetric{Err: tc.when}.ExceptionCode()
+			assert.Equal(t, tc.expect, code)
+			assert.Equal(t, tc.expectIsOk, ok)
+		})
+	}
+}
+
+func TestRequestMetric_IsTimeout(t *testing.T) {
+	assert.True(t, RequestMetric{Err: &ClientError{Err: errReadTimeoutExceeded}}.IsTimeout())
+	assert.True(t, RequestMetric{Err: context.DeadlineExceeded}.IsTimeout())
+	assert.False(t, RequestMetric{Err: context.Canceled}.IsTimeout())
+	assert.False(t, RequestMetric{}.IsTimeout())
+}
+
+func TestFrameUnitID(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   []byte
+		isRTU  bool
+		expect uint8
+	}{
+		{
+			name:   "tcp",
+			when:   []byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x6, 0x11, 0x3, 0x0, 0xc8, 0x0, 0x9},
+			expect: 0x11,
+		},
+		{
+			name:   "rtu",
+			when:   []byte{0x11, 0x3, 0x0, 0xc8, 0x0, 0x9, 0x0, 0x0},
+			isRTU:  true,
+			expect: 0x11,
+		},
+		{
+			name:   "ascii",
+			when:   []byte(":1103006B0003\r\n"),
+			isRTU:  true,
+			expect: 0x11,
+		},
+		{
+			name:   "too short",
+			when:   []byte{0x12, 0x34},
+			expect: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, frameUnitID(tc.when, tc.isRTU))
+		})
+	}
+}
+
+func TestClientStats(t *testing.T) {
+	stats := NewClientStats(10*time.Millisecond, 100*time.Millisecond)
+
+	stats.ConnectDone(":502", nil)
+	stats.ConnectDone(":502", errors.New("refused"))
+	stats.ConnectDone(":502", nil)
+
+	stats.RequestDone(RequestMetric{ServerAddress: ":502", UnitID: 1, FunctionCode: 3, RoundTrip: 5 * time.Millisecond})
+	stats.RequestDone(RequestMetric{
+		ServerAddress: ":502", UnitID: 1, FunctionCode: 3, RoundTrip: 50 * time.Millisecond,
+		Err: newExceptionError(&packet.ErrorResponseTCP{Function: 3, Code: packet.ErrIllegalDataAddress}),
+	})
+	stats.RequestDone(RequestMetric{
+		ServerAddress: ":502", UnitID: 2, FunctionCode: 3, RoundTrip: 2 * time.Second,
+		Err: &ClientError{Err: errReadTimeoutExceeded},
+	})
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, []ServerStats{{ServerAddress: ":502", Connects: 2, ConnectErrors: 1}}, snapshot.Servers)
+	assert.Equal(t, []UnitStats{
+		{
+			ServerAddress:  ":502",
+			UnitID:         1,
+			Requests:       2,
+			Responses:      1,
+			Errors:         1,
+			Exceptions:     map[packet.ErrCode]uint64{packet.ErrIllegalDataAddress: 1},
+			LatencyBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+			LatencyCounts:  []uint64{1, 1, 0},
+			LatencySum:     55 * time.Millisecond,
+		},
+		{
+			ServerAddress:  ":502",
+			UnitID:         2,
+			Requests:       1,
+			Errors:         1,
+			Timeouts:       1,
+			Exceptions:     map[packet.ErrCode]uint64{},
+			LatencyBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+			LatencyCounts:  []uint64{0, 0, 1},
+			LatencySum:     2 * time.Second,
+		},
+	}, snapshot.Units)
+
+	var fromJSON ClientStatsSnapshot
+	assert.NoError(t, json.Unmarshal([]byte(stats.String()), &fromJSON))
+	assert.Equal(t, snapshot, fromJSON)
+}
+
+func TestClient_Do_reportsMetrics(t *testing.T) {
+	stats := NewClientStats()
+	client := NewTCPClientWithConfig(ClientConfig{
+		Metrics: stats,
+		DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+			clientConn, serverConn := net.Pipe()
+			go func() {
+				req := make([]byte, 12)
+				if _, err := io.ReadFull(serverConn, req); err != nil {
+					return
+				}
+				// respond with Illegal Data Address exception
+				_, _ = serverConn.Write([]byte{req[0], req[1], 0x0, 0x0, 0x0, 0x3, req[6], 0x83, 0x2})
+			}()
+			return clientConn, nil
+		},
+	})
+	err := client.Connect(context.Background(), ":502")
+	assert.NoError(t, err)
+	defer client.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(7, 100, 1)
+	_, err = client.Do(context.Background(), req)
+	assert.Error(t, err)
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, []ServerStats{{ServerAddress: ":502", Connects: 1}}, snapshot.Servers)
+	if assert.Len(t, snapshot.Units, 1) {
+		assert.Equal(t, uint8(7), snapshot.Units[0].UnitID)
+		assert.Equal(t, uint64(1), snapshot.Units[0].Requests)
+		assert.Equal(t, map[packet.ErrCode]uint64{packet.ErrIllegalDataAddress: 1}, snapshot.Units[0].Exceptions)
+	}
+}
@@ -0,0 +1,74 @@
+package modbustest
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// ResponseTiming controls how TimedConn sends responses to the client
+type ResponseTiming struct {
+	// Delay is time waited after request is received before first response byte is sent. To test client read timeout
+	// handling deterministically set it to client read timeout plus/minus margin.
+	Delay time.Duration
+	// ChunkSize is amount of bytes sent at once. Defaults to whole response at once. Set to 1 to dribble response byte
+	// by byte.
+	ChunkSize int
+	// ChunkGap is time waited between sending response chunks
+	ChunkGap time.Duration
+}
+
+// NewTimedConn creates in-memory connection that responds to every request written to it with response returned by
+// handler and sends that response with given timing. Handler returning nil response sends nothing. Connection
+// supports deadlines and can be used with modbus.Client (see DialContextFunc) to test timeout and partial read handling
+// without real network.
+func NewTimedConn(handler func(request []byte) []byte, timing ResponseTiming) net.Conn {
+	client, server := net.Pipe()
+	go serveTimed(server, handler, timing)
+	return client
+}
+
+// DialContextFunc returns dial function suitable for modbus.ClientConfig.DialContextFunc that creates new TimedConn
+// for every connection
+func DialContextFunc(
+	handler func(request []byte) []byte,
+	timing ResponseTiming,
+) func(ctx context.Context, address string) (net.Conn, error) {
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		return NewTimedConn(handler, timing), nil
+	}
+}
+
+func serveTimed(conn net.Conn, handler func(request []byte) []byte, timing ResponseTiming) {
+	defer conn.Close()
+
+	received := make([]byte, 1024)
+	for {
+		n, err := conn.Read(received)
+		if err != nil {
+			return
+		}
+		response := handler(received[:n])
+		if len(response) == 0 {
+			continue
+		}
+		time.Sleep(timing.Delay)
+
+		chunkSize := timing.ChunkSize
+		if chunkSize <= 0 {
+			chunkSize = len(response)
+		}
+		for i := 0; i < len(response); i += chunkSize {
+			if i > 0 {
+				time.Sleep(timing.ChunkGap)
+			}
+			end := i + chunkSize
+			if end > len(response) {
+				end = len(response)
+			}
+			if _, err := conn.Write(response[i:end]); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package packet
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidLRC is error returned when packet data does not match its LRC value
+var ErrInvalidLRC = errors.New("packet longitudinal redundancy check does not match Modbus ASCII packet bytes")
+
+// LRC calculates longitudinal redundancy check (LRC) for given bytes. LRC is two's complement of 8 bit sum of bytes.
+func LRC(data []byte) uint8 {
+	sum := uint8(0)
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
+
+// RTUToASCII converts Modbus RTU packet (with CRC) to Modbus ASCII packet. CRC is replaced with LRC and
+// packet is encoded as uppercase hexadecimal characters started with ':' and ended with CRLF.
+//
+// Example: RTU packet 0x01 0x03 0x00 0x6B 0x00 0x03 0x74 0x17 is ":0103006B00038E\r\n" as ASCII packet
+func RTUToASCII(rtu []byte) []byte {
+	if len(rtu) < 2 {
+		return nil
+	}
+	payload := rtu[:len(rtu)-2]
+	raw := make([]byte, len(payload)+1)
+	copy(raw, payload)
+	raw[len(payload)] = LRC(payload)
+
+	result := make([]byte, 0, asciiLength(len(rtu)))
+	result = append(result, ':')
+	for _, b := range raw {
+		result = append(result, upperHex[b>>4], upperHex[b&0x0f])
+	}
+	return append(result, '\r', '\n')
+}
+
+const upperHex = "0123456789ABCDEF"
+
+// ASCIIToRTU converts Modbus ASCII packet to Modbus RTU packet (with CRC). Packet start, end and LRC are checked.
+func ASCIIToRTU(data []byte) ([]byte, error) {
+	dataLen := len(data)
+	// ':' + unit id (2) + function code (2) + LRC (2) + CRLF
+	if dataLen < 9 {
+		return nil, errors.New("data is too short to be a Modbus ASCII packet")
+	}
+	if data[0] != ':' {
+		return nil, errors.New("Modbus ASCII packet does not start with ':'")
+	}
+	if data[dataLen-2] != '\r' || data[dataLen-1] != '\n' {
+		return nil, errors.New("Modbus ASCII packet does not end with CRLF")
+	}
+	hexData := data[1 : dataLen-2]
+	if len(hexData)%2 != 0 {
+		return nil, errors.New("Modbus ASCII packet has odd number of hexadecimal characters")
+	}
+	raw := make([]byte, len(hexData)/2, len(hexData)/2+1)
+	if _, err := hex.Decode(raw, hexData); err != nil {
+		return nil, fmt.Errorf("Modbus ASCII packet has invalid hexadecimal characters: %w", err)
+	}
+	payload := raw[:len(raw)-1]
+	if LRC(payload) != raw[len(raw)-1] {
+		return nil, ErrInvalidLRC
+	}
+	return binary.LittleEndian.AppendUint16(payload, CRC16(payload)), nil
+}
+
+// asciiLength returns length of ASCII packet for RTU packet of given length. 2 CRC bytes are replaced by 1 LRC byte,
+// each byte is encoded as 2 characters and ':' + CRLF are added.
+func asciiLength(rtuLength int) int {
+	return 2*(rtuLength-1) + 3
+}
+
+// ParseASCIIRequest checks packet LRC and parses given bytes into modbus ASCII request packet or returns error.
+// Options are passed to request parse functions that support them.
+func ParseASCIIRequest(data []byte, opts ...ParseOption) (Request, error) {
+	rtu, err := ASCIIToRTU(data)
+	if err != nil {
+		return nil, err
+	}
+	req, err := ParseRTURequest(rtu, opts...)
+	if err != nil {
+		return nil, err
+	}
+	switch r := req.(type) {
+	case *ReadCoilsRequestRTU:
+		return &ReadCoilsRequestASCII{ReadCoilsRequestRTU: *r}, nil
+	case *ReadDiscreteInputsRequestRTU:
+		return &ReadDiscreteInputsRequestASCII{ReadDiscreteInputsRequestRTU: *r}, nil
+	case *ReadHoldingRegistersRequestRTU:
+		return &ReadHoldingRegistersRequestASCII{ReadHoldingRegistersRequestRTU: *r}, nil
+	case *ReadInputRegistersRequestRTU:
+		return &ReadInputRegistersRequestASCII{ReadInputRegistersRequestRTU: *r}, nil
+	case *WriteSingleCoilRequestRTU:
+		return &WriteSingleCoilRequestASCII{WriteSingleCoilRequestRTU: *r}, nil
+	case *WriteSingleRegisterRequestRTU:
+		return &WriteSingleRegisterRequestASCII{WriteSingleRegisterRequestRTU: *r}, nil
+	case *ReadExceptionStatusRequestRTU:
+		return &ReadExceptionStatusRequestASCII{ReadExceptionStatusRequestRTU: *r}, nil
+	case *DiagnosticsRequestRTU:
+		return &DiagnosticsRequestASCII{DiagnosticsRequestRTU: *r}, nil
+	case *WriteMultipleCoilsRequestRTU:
+		return &WriteMultipleCoilsRequestASCII{WriteMultipleCoilsRequestRTU: *r}, nil
+	case *WriteMultipleRegistersRequestRTU:
+		return &WriteMultipleRegistersRequestASCII{WriteMultipleRegistersRequestRTU: *r}, nil
+	case *ReadServerIDRequestRTU:
+		return &ReadServerIDRequestASCII{ReadServerIDRequestRTU: *r}, nil
+	case *MaskWriteRegisterRequestRTU:
+		return &MaskWriteRegisterRequestASCII{MaskWriteRegisterRequestRTU: *r}, nil
+	case *ReadWriteMultipleRegistersRequestRTU:
+		return &ReadWriteMultipleRegistersRequestASCII{ReadWriteMultipleRegistersRequestRTU: *r}, nil
+	case *ReadFIFOQueueRequestRTU:
+		return &ReadFIFOQueueRequestASCII{ReadFIFOQueueRequestRTU: *r}, nil
+	case *CANopenGeneralReferenceRequestRTU:
+		return &CANopenGeneralReferenceRequestASCII{CANopenGeneralReferenceRequestRTU: *r}, nil
+	case *ReadDeviceIdentificationRequestRTU:
+		return &ReadDeviceIdentificationRequestASCII{ReadDeviceIdentificationRequestRTU: *r}, nil
+	default:
+		return nil, fmt.Errorf("unknown request type parsed: %T", req)
+	}
+}
+
+// ParseASCIIResponse checks packet LRC and parses given bytes into modbus ASCII response packet or into
+// ErrorResponseASCII or returns error
+func ParseASCIIResponse(data []byte) (Response, error) {
+	if err := AsASCIIErrorPacket(data); err != nil {
+		return nil, err
+	}
+	rtu, err := ASCIIToRTU(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ParseRTUResponse(rtu)
+	if err != nil {
+		return nil, err
+	}
+	switch r := resp.(type) {
+	case *ReadCoilsResponseRTU:
+		return &ReadCoilsResponseASCII{ReadCoilsResponseRTU: *r}, nil
+	case *ReadDiscreteInputsResponseRTU:
+		return &ReadDiscreteInputsResponseASCII{ReadDiscreteInputsResponseRTU: *r}, nil
+	case *ReadHoldingRegistersResponseRTU:
+		return &ReadHoldingRegistersResponseASCII{ReadHoldingRegistersResponseRTU: *r}, nil
+	case *ReadInputRegistersResponseRTU:
+		return &ReadInputRegistersResponseASCII{ReadInputRegistersResponseRTU: *r}, nil
+	case *WriteSingleCoilResponseRTU:
+		return &WriteSingleCoilResponseASCII{WriteSingleCoilResponseRTU: *r}, nil
+	case *WriteSingleRegisterResponseRTU:
+		return &WriteSingleRegisterResponseASCII{WriteSingleRegisterResponseRTU: *r}, nil
+	case *ReadExceptionStatusResponseRTU:
+		return &ReadExceptionStatusResponseASCII{ReadExceptionStatusResponseRTU: *r}, nil
+	case *DiagnosticsResponseRTU:
+		return &DiagnosticsResponseASCII{DiagnosticsResponseRTU: *r}, nil
+	case *WriteMultipleCoilsResponseRTU:
+		return &WriteMultipleCoilsResponseASCII{WriteMultipleCoilsResponseRTU: *r}, nil
+	case *WriteMultipleRegistersResponseRTU:
+		return &WriteMultipleRegistersResponseASCII{WriteMultipleRegistersResponseRTU: *r}, nil
+	case *ReadServerIDResponseRTU:
+		return &ReadServerIDResponseASCII{ReadServerIDResponseRTU: *r}, nil
+	case *MaskWriteRegisterResponseRTU:
+		return &MaskWriteRegisterResponseASCII{MaskWriteRegisterResponseRTU: *r}, nil
+	case *ReadWriteMultipleRegistersResponseRTU:
+		return &ReadWriteMultipleRegistersResponseASCII{ReadWriteMultipleRegistersResponseRTU: *r}, nil
+	case *ReadFIFOQueueResponseRTU:
+		return &ReadFIFOQueueResponseASCII{ReadFIFOQueueResponseRTU: *r}, nil
+	case *CANopenGeneralReferenceResponseRTU:
+		return &CANopenGeneralReferenceResponseASCII{CANopenGeneralReferenceResponseRTU: *r}, nil
+	case *ReadDeviceIdentificationResponseRTU:
+		return &ReadDeviceIdentificationResponseASCII{ReadDeviceIdentificationResponseRTU: *r}, nil
+	default:
+		return nil, fmt.Errorf("unknown response type parsed: %T", resp)
+	}
+}
+
+// ErrorResponseASCII is ASCII error response send by server to client
+type ErrorResponseASCII struct {
+	UnitID   uint8
+	Function uint8
+	Code     ErrCode
+}
+
+// Error translates error code to error message.
+func (re ErrorResponseASCII) Error() string {
+	return re.Code.String()
+}
+
+// Is allows matching error response by exception code with errors.Is (i.e. `errors.Is(err, packet.ErrServerBusy)`)
+func (re ErrorResponseASCII) Is(target error) bool {
+	return matchErrCode(re.Code, target)
+}
+
+// As allows extracting exception code from error response with errors.As into ErrCode variable
+func (re ErrorResponseASCII) As(target interface{}) bool {
+	return asErrCode(re.Code, target)
+}
+
+// Bytes returns ErrorResponseASCII packet as bytes form
+func (re ErrorResponseASCII) Bytes() []byte {
+	return RTUToASCII(ErrorResponseRTU(re).Bytes())
+}
+
+// FunctionCode returns function code to which error response originates from / was responded to
+func (re ErrorResponseASCII) FunctionCode() uint8 {
+	return re.Function
+}
+
+// AsASCIIErrorPacket converts raw packet bytes to Modbus ASCII error response if possible
+//
+// Example packet: ":0A810273\r\n"
+// 0x0a - unit id
+// 0x81 - function code + 128 (error bitmask)
+// 0x02 - error code
+// 0x73 - LRC
+func AsASCIIErrorPacket(data []byte) error {
+	// ':' + 3 bytes as hex + LRC as hex + CRLF
+	if len(data) != 11 {
+		return nil
+	}
+	rtu, err := ASCIIToRTU(data)
+	if err != nil {
+		return nil
+	}
+	if err := AsRTUErrorPacket(rtu); err != nil {
+		rtuErr := err.(*ErrorResponseRTU)
+		return &ErrorResponseASCII{
+			UnitID:   rtuErr.UnitID,
+			Function: rtuErr.Function,
+			Code:     rtuErr.Code,
+		}
+	}
+	return nil // probably start of valid packet
+}
@@ -0,0 +1,221 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLRC(t *testing.T) {
+	assert.Equal(t, uint8(0x8e), LRC([]byte{0x01, 0x03, 0x00, 0x6b, 0x00, 0x03}))
+	assert.Equal(t, uint8(0x00), LRC([]byte{}))
+	assert.Equal(t, uint8(0x01), LRC([]byte{0xff}))
+}
+
+func TestRTUToASCII(t *testing.T) {
+	rtu := []byte{0x01, 0x03, 0x00, 0x6b, 0x00, 0x03, 0x74, 0x17}
+
+	assert.Equal(t, []byte(":0103006B00038E\r\n"), RTUToASCII(rtu))
+	assert.Nil(t, RTUToASCII([]byte{0x01}))
+}
+
+func TestASCIIToRTU(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        []byte
+		expect      []byte
+		expectError string
+	}{
+		{
+			name:   "ok",
+			when:   []byte(":0103006B00038E\r\n"),
+			expect: []byte{0x01, 0x03, 0x00, 0x6b, 0x00, 0x03, 0x74, 0x17},
+		},
+		{
+			name:   "ok, lowercase hex",
+			when:   []byte(":0103006b00038e\r\n"),
+			expect: []byte{0x01, 0x03, 0x00, 0x6b, 0x00, 0x03, 0x74, 0x17},
+		},
+		{
+			name:        "nok, too short",
+			when:        []byte(":0103\r\n"),
+			expectError: "data is too short to be a Modbus ASCII packet",
+		},
+		{
+			name:        "nok, missing start",
+			when:        []byte("0103006B00038E\r\n"),
+			expectError: "Modbus ASCII packet does not start with ':'",
+		},
+		{
+			name:        "nok, missing CRLF",
+			when:        []byte(":0103006B00038E\n"),
+			expectError: "Modbus ASCII packet does not end with CRLF",
+		},
+		{
+			name:        "nok, odd number of characters",
+			when:        []byte(":0103006B00038\r\n"),
+			expectError: "Modbus ASCII packet has odd number of hexadecimal characters",
+		},
+		{
+			name:        "nok, invalid hex",
+			when:        []byte(":0103006X00038E\r\n"),
+			expectError: "Modbus ASCII packet has invalid hexadecimal characters: encoding/hex: invalid byte: U+0058 'X'",
+		},
+		{
+			name:        "nok, invalid LRC",
+			when:        []byte(":0103006B00038F\r\n"),
+			expectError: "packet longitudinal redundancy check does not match Modbus ASCII packet bytes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rtu, err := ASCIIToRTU(tc.when)
+
+			assert.Equal(t, tc.expect, rtu)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseASCIIRequest(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        []byte
+		expect      Request
+		expectError string
+	}{
+		{
+			name: "ok, ReadHoldingRegistersRequestASCII (fc03)",
+			when: []byte(":0103006B00038E\r\n"),
+			expect: &ReadHoldingRegistersRequestASCII{
+				ReadHoldingRegistersRequestRTU: ReadHoldingRegistersRequestRTU{
+					ReadHoldingRegistersRequest: ReadHoldingRegistersRequest{
+						UnitID:       1,
+						StartAddress: 107,
+						Quantity:     3,
+					},
+				},
+			},
+		},
+		{
+			name: "ok, WriteSingleCoilRequestASCII (fc05)",
+			when: []byte(":010500ACFF004F\r\n"),
+			expect: &WriteSingleCoilRequestASCII{
+				WriteSingleCoilRequestRTU: WriteSingleCoilRequestRTU{
+					WriteSingleCoilRequest: WriteSingleCoilRequest{
+						UnitID:    1,
+						Address:   172,
+						CoilState: true,
+					},
+				},
+			},
+		},
+		{
+			name:        "nok, invalid LRC",
+			when:        []byte(":0103006B00038F\r\n"),
+			expectError: "packet longitudinal redundancy check does not match Modbus ASCII packet bytes",
+		},
+		{
+			name:        "nok, unknown function code",
+			when:        []byte(":0163006B00032E\r\n"),
+			expectError: "unknown function code parsed: 99",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseASCIIRequest(tc.when)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.when, req.Bytes())
+			}
+		})
+	}
+}
+
+func TestParseASCIIResponse(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        []byte
+		expect      Response
+		expectError string
+	}{
+		{
+			name: "ok, ReadHoldingRegistersResponseASCII (fc03)",
+			when: []byte(":010302CAFE32\r\n"),
+			expect: &ReadHoldingRegistersResponseASCII{
+				ReadHoldingRegistersResponseRTU: ReadHoldingRegistersResponseRTU{
+					ReadHoldingRegistersResponse: ReadHoldingRegistersResponse{
+						UnitID:          1,
+						RegisterByteLen: 2,
+						Data:            []byte{0xca, 0xfe},
+					},
+				},
+			},
+		},
+		{
+			name: "ok, ReadCoilsResponseASCII (fc01)",
+			when: []byte(":01010105F8\r\n"),
+			expect: &ReadCoilsResponseASCII{
+				ReadCoilsResponseRTU: ReadCoilsResponseRTU{
+					ReadCoilsResponse: ReadCoilsResponse{
+						UnitID:          1,
+						CoilsByteLength: 1,
+						Data:            []byte{0x05},
+					},
+				},
+			},
+		},
+		{
+			name:        "nok, error response",
+			when:        []byte(":0A810273\r\n"),
+			expectError: "Illegal data address",
+		},
+		{
+			name:        "nok, invalid LRC",
+			when:        []byte(":010302CAFE33\r\n"),
+			expectError: "packet longitudinal redundancy check does not match Modbus ASCII packet bytes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseASCIIResponse(tc.when)
+
+			assert.Equal(t, tc.expect, resp)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.when, resp.Bytes())
+			}
+		})
+	}
+}
+
+func TestAsASCIIErrorPacket(t *testing.T) {
+	err := AsASCIIErrorPacket([]byte(":0A810273\r\n"))
+
+	assert.Equal(t, &ErrorResponseASCII{UnitID: 10, Function: 1, Code: 2}, err)
+	assert.Equal(t, []byte(":0A810273\r\n"), err.(*ErrorResponseASCII).Bytes())
+
+	assert.NoError(t, AsASCIIErrorPacket([]byte(":010302CAFE32\r\n")))
+	assert.NoError(t, AsASCIIErrorPacket([]byte(":0A810274\r\n"))) // invalid LRC
+}
+
+func TestReadHoldingRegistersRequestASCII(t *testing.T) {
+	req, err := NewReadHoldingRegistersRequestASCII(1, 107, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(":0103006B00038E\r\n"), req.Bytes())
+	assert.Equal(t, 1+2*(1+1+1+6+1)+2, req.ExpectedResponseLength())
+	assert.Equal(t, FunctionReadHoldingRegisters, req.FunctionCode())
+}
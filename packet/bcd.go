@@ -0,0 +1,33 @@
+package packet
+
+import "fmt"
+
+// BCDToUint32 decodes binary-coded decimal value (every 4 bits/nibble is one decimal digit 0-9) to its decimal
+// value. For example 0x1234 decodes to 1234. Nibble with value over 9 results an error.
+func BCDToUint32(bcd uint32) (uint32, error) {
+	result := uint32(0)
+	multiplier := uint32(1)
+	for i := 0; i < 8; i++ {
+		digit := (bcd >> (4 * i)) & 0xf
+		if digit > 9 {
+			return 0, fmt.Errorf("invalid BCD digit: 0x%x", digit)
+		}
+		result += digit * multiplier
+		multiplier *= 10
+	}
+	return result, nil
+}
+
+// Uint32ToBCD encodes value to binary-coded decimal (every 4 bits/nibble is one decimal digit 0-9). For example 1234
+// encodes to 0x1234. Values over 99999999 do not fit into 32 bits and result an error.
+func Uint32ToBCD(value uint32) (uint32, error) {
+	if value > 99999999 {
+		return 0, fmt.Errorf("value does not fit into 8 BCD digits: %v", value)
+	}
+	result := uint32(0)
+	for i := 0; value > 0; i++ {
+		result |= (value % 10) << (4 * i)
+		value /= 10
+	}
+	return result, nil
+}
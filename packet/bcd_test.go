@@ -0,0 +1,58 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBCDToUint32(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        uint32
+		expect      uint32
+		expectError string
+	}{
+		{name: "ok, zero", when: 0x0, expect: 0},
+		{name: "ok, 1234", when: 0x1234, expect: 1234},
+		{name: "ok, max", when: 0x99999999, expect: 99999999},
+		{name: "nok, invalid digit", when: 0x12a4, expectError: "invalid BCD digit: 0xa"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := BCDToUint32(tc.when)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUint32ToBCD(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        uint32
+		expect      uint32
+		expectError string
+	}{
+		{name: "ok, zero", when: 0, expect: 0x0},
+		{name: "ok, 1234", when: 1234, expect: 0x1234},
+		{name: "ok, max", when: 99999999, expect: 0x99999999},
+		{name: "nok, too large", when: 100000000, expectError: "value does not fit into 8 BCD digits: 100000000"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Uint32ToBCD(tc.when)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
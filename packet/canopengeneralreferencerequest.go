@@ -0,0 +1,246 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// MEICANopenGeneralReference is MEI (Modbus Encapsulated Interface) type for CANopen General Reference (FC43/13)
+const MEICANopenGeneralReference = uint8(0x0D)
+
+// maxCANopenDataLen is maximum length of MEI type specific data (253 byte PDU - 1 function code - 1 MEI type)
+const maxCANopenDataLen = 251
+
+// CANopenGeneralReferenceRequestTCP is TCP Request for CANopen General Reference (FC=43, MEI type=13). MEI type
+// specific data is passed through to the device as is.
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x06 0x10 0x2B 0x0D 0x10 0x18 0x01
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x06 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x10 - unit id (6)
+// 0x2B - function code (7)
+// 0x0D - MEI type (8)
+// 0x10 0x18 0x01 - MEI type specific data (9, ... N), here object index 0x1018 and subindex 0x01
+type CANopenGeneralReferenceRequestTCP struct {
+	MBAPHeader
+	CANopenGeneralReferenceRequest
+}
+
+// CANopenGeneralReferenceRequestRTU is RTU Request for CANopen General Reference (FC=43, MEI type=13). MEI type
+// specific data is passed through to the device as is.
+//
+// Example packet: 0x10 0x2B 0x0D 0x10 0x18 0x01 0xEE 0x24
+// 0x10 - unit id (0)
+// 0x2B - function code (1)
+// 0x0D - MEI type (2)
+// 0x10 0x18 0x01 - MEI type specific data (3, ... N), here object index 0x1018 and subindex 0x01
+// 0xEE 0x24 - CRC16 (n-2,n-1)
+type CANopenGeneralReferenceRequestRTU struct {
+	CANopenGeneralReferenceRequest
+}
+
+// CANopenGeneralReferenceRequest is Request for CANopen General Reference (FC=43, MEI type=13)
+type CANopenGeneralReferenceRequest struct {
+	UnitID uint8
+	// Data is MEI type specific data. Contents are device/gateway specific (see CiA 309-2).
+	Data []byte
+}
+
+// NewCANopenGeneralReferenceRequestTCP creates new instance of CANopen General Reference TCP request
+func NewCANopenGeneralReferenceRequestTCP(unitID uint8, data []byte) (*CANopenGeneralReferenceRequestTCP, error) {
+	if err := checkCANopenData(data); err != nil {
+		return nil, err
+	}
+	return &CANopenGeneralReferenceRequestTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: RandomTransactionID(),
+			ProtocolID:    0,
+		},
+		CANopenGeneralReferenceRequest: CANopenGeneralReferenceRequest{
+			UnitID: unitID,
+			Data:   copyCANopenData(data),
+		},
+	}, nil
+}
+
+// Bytes returns CANopenGeneralReferenceRequestTCP packet as bytes form
+func (r CANopenGeneralReferenceRequestTCP) Bytes() []byte {
+	length := r.len()
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.CANopenGeneralReferenceRequest.bytes(result[6:])
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r CANopenGeneralReferenceRequestTCP) ExpectedResponseLength() int {
+	// response = 6 header len + 1 unitID + 1 fc + 1 MEI type + N MEI type specific data
+	// response length is variable, so this is minimal valid response length
+	return 6 + 3
+}
+
+// ParseCANopenGeneralReferenceRequestTCP parses given bytes into CANopenGeneralReferenceRequestTCP
+func ParseCANopenGeneralReferenceRequestTCP(data []byte) (*CANopenGeneralReferenceRequestTCP, error) {
+	header, err := ParseMBAPHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 9 || len(data)-9 > maxCANopenDataLen {
+		return nil, NewErrorParseTCP(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[6]
+	if data[7] != FunctionEncapsulatedInterface || data[8] != MEICANopenGeneralReference {
+		tmpErr := NewErrorParseTCP(ErrIllegalFunction, "received function code in packet is not 0x2B/0x0D")
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionEncapsulatedInterface
+		return nil, tmpErr
+	}
+	return &CANopenGeneralReferenceRequestTCP{
+		MBAPHeader: header,
+		CANopenGeneralReferenceRequest: CANopenGeneralReferenceRequest{
+			UnitID: unitID,
+			Data:   copyCANopenData(data[9:]),
+		},
+	}, nil
+}
+
+// NewCANopenGeneralReferenceRequestRTU creates new instance of CANopen General Reference RTU request
+func NewCANopenGeneralReferenceRequestRTU(unitID uint8, data []byte) (*CANopenGeneralReferenceRequestRTU, error) {
+	if err := checkCANopenData(data); err != nil {
+		return nil, err
+	}
+	return &CANopenGeneralReferenceRequestRTU{
+		CANopenGeneralReferenceRequest: CANopenGeneralReferenceRequest{
+			UnitID: unitID,
+			Data:   copyCANopenData(data),
+		},
+	}, nil
+}
+
+// Bytes returns CANopenGeneralReferenceRequestRTU packet as bytes form
+func (r CANopenGeneralReferenceRequestRTU) Bytes() []byte {
+	length := r.len()
+	result := make([]byte, length+2)
+	bytes := r.CANopenGeneralReferenceRequest.bytes(result)
+	crc := CRC16(bytes[:length])
+	result[length] = uint8(crc)
+	result[length+1] = uint8(crc >> 8)
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r CANopenGeneralReferenceRequestRTU) ExpectedResponseLength() int {
+	// response = 1 UnitID + 1 functionCode + 1 MEI type + N MEI type specific data + 2 CRC
+	// response length is variable, so this is minimal valid response length
+	return 3 + 2
+}
+
+// ParseCANopenGeneralReferenceRequestRTU parses given bytes into CANopenGeneralReferenceRequestRTU. Packet length is
+// variable so given bytes must include CRC bytes.
+// Does not check CRC
+func ParseCANopenGeneralReferenceRequestRTU(data []byte) (*CANopenGeneralReferenceRequestRTU, error) {
+	dLen := len(data)
+	if dLen < 5 || dLen-5 > maxCANopenDataLen {
+		return nil, NewErrorParseRTU(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[0]
+	if data[1] != FunctionEncapsulatedInterface || data[2] != MEICANopenGeneralReference {
+		tmpErr := NewErrorParseRTU(ErrIllegalFunction, "received function code in packet is not 0x2B/0x0D")
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionEncapsulatedInterface
+		return nil, tmpErr
+	}
+	return &CANopenGeneralReferenceRequestRTU{
+		CANopenGeneralReferenceRequest: CANopenGeneralReferenceRequest{
+			UnitID: unitID,
+			Data:   copyCANopenData(data[3 : dLen-2]),
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r CANopenGeneralReferenceRequest) FunctionCode() uint8 {
+	return FunctionEncapsulatedInterface
+}
+
+// Bytes returns CANopenGeneralReferenceRequest packet as bytes form
+func (r CANopenGeneralReferenceRequest) Bytes() []byte {
+	return r.bytes(make([]byte, r.len()))
+}
+
+// Index returns CANopen object index from first 2 bytes (big endian) of MEI type specific data
+func (r CANopenGeneralReferenceRequest) Index() (uint16, error) {
+	return canopenIndex(r.Data)
+}
+
+// SubIndex returns CANopen object subindex from third byte of MEI type specific data
+func (r CANopenGeneralReferenceRequest) SubIndex() (uint8, error) {
+	return canopenSubIndex(r.Data)
+}
+
+func (r CANopenGeneralReferenceRequest) len() uint16 {
+	return 3 + uint16(len(r.Data))
+}
+
+func (r CANopenGeneralReferenceRequest) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionEncapsulatedInterface
+	bytes[2] = MEICANopenGeneralReference
+	copy(bytes[3:], r.Data)
+	return bytes
+}
+
+func checkCANopenData(data []byte) error {
+	if len(data) > maxCANopenDataLen {
+		return fmt.Errorf("canopen general reference data length is out of range (0-251): %v", len(data))
+	}
+	return nil
+}
+
+func copyCANopenData(data []byte) []byte {
+	result := make([]byte, len(data))
+	copy(result, data)
+	return result
+}
+
+func canopenIndex(data []byte) (uint16, error) {
+	if len(data) < 2 {
+		return 0, errors.New("canopen general reference data is too short to contain object index")
+	}
+	return binary.BigEndian.Uint16(data[0:2]), nil
+}
+
+func canopenSubIndex(data []byte) (uint8, error) {
+	if len(data) < 3 {
+		return 0, errors.New("canopen general reference data is too short to contain object subindex")
+	}
+	return data[2], nil
+}
+
+// CANopenGeneralReferenceRequestASCII is ASCII Request for CANopen General Reference (FC=43, MEI type=13). Packet is
+// RTU packet without CRC encoded as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type CANopenGeneralReferenceRequestASCII struct {
+	CANopenGeneralReferenceRequestRTU
+}
+
+// NewCANopenGeneralReferenceRequestASCII creates new instance of CANopen General Reference ASCII request
+func NewCANopenGeneralReferenceRequestASCII(unitID uint8, data []byte) (*CANopenGeneralReferenceRequestASCII, error) {
+	r, err := NewCANopenGeneralReferenceRequestRTU(unitID, data)
+	if err != nil {
+		return nil, err
+	}
+	return &CANopenGeneralReferenceRequestASCII{CANopenGeneralReferenceRequestRTU: *r}, nil
+}
+
+// Bytes returns CANopenGeneralReferenceRequestASCII packet as bytes form
+func (r CANopenGeneralReferenceRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.CANopenGeneralReferenceRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r CANopenGeneralReferenceRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.CANopenGeneralReferenceRequestRTU.ExpectedResponseLength())
+}
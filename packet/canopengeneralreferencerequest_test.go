@@ -0,0 +1,109 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewCANopenGeneralReferenceRequestTCP(t *testing.T) {
+	req, err := NewCANopenGeneralReferenceRequestTCP(0x10, []byte{0x10, 0x18, 0x01})
+	assert.NoError(t, err)
+	req.TransactionID = 0x8180
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x6, 0x10, 0x2b, 0xd, 0x10, 0x18, 0x1}, req.Bytes())
+	assert.Equal(t, 9, req.ExpectedResponseLength())
+	assert.Equal(t, FunctionEncapsulatedInterface, req.FunctionCode())
+
+	index, err := req.Index()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x1018), index)
+
+	subIndex, err := req.SubIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x01), subIndex)
+
+	_, err = NewCANopenGeneralReferenceRequestTCP(0x10, make([]byte, 252))
+	assert.EqualError(t, err, "canopen general reference data length is out of range (0-251): 252")
+}
+
+func TestParseCANopenGeneralReferenceRequestTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *CANopenGeneralReferenceRequestTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x6, 0x10, 0x2b, 0xd, 0x10, 0x18, 0x1},
+			expect: &CANopenGeneralReferenceRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				CANopenGeneralReferenceRequest: CANopenGeneralReferenceRequest{
+					UnitID: 0x10,
+					Data:   []byte{0x10, 0x18, 0x1},
+				},
+			},
+		},
+		{
+			name:        "nok, invalid MEI type",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x6, 0x10, 0x2b, 0xe, 0x10, 0x18, 0x1},
+			expectError: "received function code in packet is not 0x2B/0x0D",
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x2, 0x10, 0x2b},
+			expectError: "invalid data length to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseCANopenGeneralReferenceRequestTCP(tc.given)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCANopenGeneralReferenceRequestRTU(t *testing.T) {
+	req, err := NewCANopenGeneralReferenceRequestRTU(0x10, []byte{0x10, 0x18, 0x01})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte{0x10, 0x2b, 0xd, 0x10, 0x18, 0x1, 0xee, 0x24}, req.Bytes())
+	assert.Equal(t, 5, req.ExpectedResponseLength())
+
+	parsed, err := ParseCANopenGeneralReferenceRequestRTU(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+
+	parsedAny, err := ParseRTURequest(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsedAny)
+
+	_, err = ParseCANopenGeneralReferenceRequestRTU([]byte{0x10, 0x2b, 0xd, 0x0})
+	assert.EqualError(t, err, "invalid data length to be valid packet")
+}
+
+func TestCANopenGeneralReferenceRequest_accessorsTooShort(t *testing.T) {
+	req := CANopenGeneralReferenceRequest{Data: []byte{0x10}}
+
+	_, err := req.Index()
+	assert.EqualError(t, err, "canopen general reference data is too short to contain object index")
+
+	_, err = req.SubIndex()
+	assert.EqualError(t, err, "canopen general reference data is too short to contain object subindex")
+}
+
+func TestCANopenGeneralReferenceRequestASCII(t *testing.T) {
+	req, err := NewCANopenGeneralReferenceRequestASCII(0x10, []byte{0x10, 0x18, 0x01})
+	assert.NoError(t, err)
+
+	parsed, err := ParseASCIIRequest(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+}
@@ -0,0 +1,149 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// CANopenGeneralReferenceResponseTCP is TCP Response for CANopen General Reference (FC=43, MEI type=13)
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x08 0x10 0x2B 0x0D 0x10 0x18 0x01 0x12 0x34
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x08 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x10 - unit id (6)
+// 0x2B - function code (7)
+// 0x0D - MEI type (8)
+// 0x10 0x18 0x01 0x12 0x34 - MEI type specific data (9, ... N)
+type CANopenGeneralReferenceResponseTCP struct {
+	MBAPHeader
+	CANopenGeneralReferenceResponse
+}
+
+// CANopenGeneralReferenceResponseRTU is RTU Response for CANopen General Reference (FC=43, MEI type=13)
+//
+// Example packet: 0x10 0x2B 0x0D 0x10 0x18 0x01 0x12 0x34 0x40 0xCC
+// 0x10 - unit id (0)
+// 0x2B - function code (1)
+// 0x0D - MEI type (2)
+// 0x10 0x18 0x01 0x12 0x34 - MEI type specific data (3, ... N)
+// 0x40 0xCC - CRC16 (n-2,n-1)
+type CANopenGeneralReferenceResponseRTU struct {
+	CANopenGeneralReferenceResponse
+}
+
+// CANopenGeneralReferenceResponse is Response for CANopen General Reference (FC=43, MEI type=13)
+type CANopenGeneralReferenceResponse struct {
+	UnitID uint8
+	// Data is MEI type specific data. Contents are device/gateway specific (see CiA 309-2).
+	Data []byte
+}
+
+// Bytes returns CANopenGeneralReferenceResponseTCP packet as bytes form
+func (r CANopenGeneralReferenceResponseTCP) Bytes() []byte {
+	length := r.len()
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.CANopenGeneralReferenceResponse.bytes(result[6:])
+	return result
+}
+
+// ParseCANopenGeneralReferenceResponseTCP parses given bytes into CANopenGeneralReferenceResponseTCP
+func ParseCANopenGeneralReferenceResponseTCP(data []byte) (*CANopenGeneralReferenceResponseTCP, error) {
+	dLen := len(data)
+	if dLen < 9 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	pduLen := binary.BigEndian.Uint16(data[4:6])
+	if dLen != 6+int(pduLen) {
+		return nil, errors.New("received data length does not match PDU len in packet")
+	}
+	if data[8] != MEICANopenGeneralReference {
+		return nil, errors.New("received MEI type in packet is not 0x0D")
+	}
+	return &CANopenGeneralReferenceResponseTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: binary.BigEndian.Uint16(data[0:2]),
+			ProtocolID:    0,
+		},
+		CANopenGeneralReferenceResponse: CANopenGeneralReferenceResponse{
+			UnitID: data[6],
+			// data[7] function code
+			// data[8] MEI type
+			Data: copyCANopenData(data[9:]),
+		},
+	}, nil
+}
+
+// Bytes returns CANopenGeneralReferenceResponseRTU packet as bytes form
+func (r CANopenGeneralReferenceResponseRTU) Bytes() []byte {
+	length := r.len()
+	result := make([]byte, length+2)
+	bytes := r.CANopenGeneralReferenceResponse.bytes(result)
+	crc := CRC16(bytes[:length])
+	result[length] = uint8(crc)
+	result[length+1] = uint8(crc >> 8)
+	return result
+}
+
+// ParseCANopenGeneralReferenceResponseRTU parses given bytes into CANopenGeneralReferenceResponseRTU
+func ParseCANopenGeneralReferenceResponseRTU(data []byte) (*CANopenGeneralReferenceResponseRTU, error) {
+	dLen := len(data)
+	if dLen < 5 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	if data[2] != MEICANopenGeneralReference {
+		return nil, errors.New("received MEI type in packet is not 0x0D")
+	}
+	return &CANopenGeneralReferenceResponseRTU{
+		CANopenGeneralReferenceResponse: CANopenGeneralReferenceResponse{
+			UnitID: data[0],
+			// data[1] function code
+			// data[2] MEI type
+			Data: copyCANopenData(data[3 : dLen-2]),
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r CANopenGeneralReferenceResponse) FunctionCode() uint8 {
+	return FunctionEncapsulatedInterface
+}
+
+// Bytes returns CANopenGeneralReferenceResponse packet as bytes form
+func (r CANopenGeneralReferenceResponse) Bytes() []byte {
+	return r.bytes(make([]byte, r.len()))
+}
+
+// Index returns CANopen object index from first 2 bytes (big endian) of MEI type specific data
+func (r CANopenGeneralReferenceResponse) Index() (uint16, error) {
+	return canopenIndex(r.Data)
+}
+
+// SubIndex returns CANopen object subindex from third byte of MEI type specific data
+func (r CANopenGeneralReferenceResponse) SubIndex() (uint8, error) {
+	return canopenSubIndex(r.Data)
+}
+
+func (r CANopenGeneralReferenceResponse) len() uint16 {
+	return 3 + uint16(len(r.Data))
+}
+
+func (r CANopenGeneralReferenceResponse) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionEncapsulatedInterface
+	bytes[2] = MEICANopenGeneralReference
+	copy(bytes[3:], r.Data)
+	return bytes
+}
+
+// CANopenGeneralReferenceResponseASCII is ASCII Response for CANopen General Reference (FC=43, MEI type=13). Packet
+// is RTU packet without CRC encoded as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type CANopenGeneralReferenceResponseASCII struct {
+	CANopenGeneralReferenceResponseRTU
+}
+
+// Bytes returns CANopenGeneralReferenceResponseASCII packet as bytes form
+func (r CANopenGeneralReferenceResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.CANopenGeneralReferenceResponseRTU.Bytes())
+}
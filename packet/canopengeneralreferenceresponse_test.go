@@ -0,0 +1,102 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCANopenGeneralReferenceResponseTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *CANopenGeneralReferenceResponseTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x10, 0x2b, 0xd, 0x10, 0x18, 0x1, 0x12, 0x34},
+			expect: &CANopenGeneralReferenceResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				CANopenGeneralReferenceResponse: CANopenGeneralReferenceResponse{
+					UnitID: 0x10,
+					Data:   []byte{0x10, 0x18, 0x1, 0x12, 0x34},
+				},
+			},
+		},
+		{
+			name:        "nok, invalid MEI type",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x4, 0x10, 0x2b, 0xe, 0x10},
+			expectError: "received MEI type in packet is not 0x0D",
+		},
+		{
+			name:        "nok, pdu length does not match",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x5, 0x10, 0x2b, 0xd, 0x10},
+			expectError: "received data length does not match PDU len in packet",
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x2, 0x10, 0x2b},
+			expectError: "received data length too short to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseCANopenGeneralReferenceResponseTCP(tc.given)
+
+			assert.Equal(t, tc.expect, resp)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCANopenGeneralReferenceResponseTCP_Bytes(t *testing.T) {
+	resp := CANopenGeneralReferenceResponseTCP{
+		MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+		CANopenGeneralReferenceResponse: CANopenGeneralReferenceResponse{
+			UnitID: 0x10,
+			Data:   []byte{0x10, 0x18, 0x1, 0x12, 0x34},
+		},
+	}
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x10, 0x2b, 0xd, 0x10, 0x18, 0x1, 0x12, 0x34}, resp.Bytes())
+
+	parsed, err := ParseTCPResponse(resp.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, &resp, parsed)
+}
+
+func TestCANopenGeneralReferenceResponseRTU(t *testing.T) {
+	given := []byte{0x10, 0x2b, 0xd, 0x10, 0x18, 0x1, 0x12, 0x34, 0x40, 0xcc}
+
+	resp, err := ParseCANopenGeneralReferenceResponseRTU(given)
+	assert.NoError(t, err)
+	assert.Equal(t, given, resp.Bytes())
+
+	index, err := resp.Index()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x1018), index)
+
+	subIndex, err := resp.SubIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0x01), subIndex)
+
+	_, err = ParseCANopenGeneralReferenceResponseRTU([]byte{0x10, 0x2b, 0xe, 0x0, 0x0})
+	assert.EqualError(t, err, "received MEI type in packet is not 0x0D")
+}
+
+func TestCANopenGeneralReferenceResponseASCII(t *testing.T) {
+	resp := CANopenGeneralReferenceResponseASCII{
+		CANopenGeneralReferenceResponseRTU: CANopenGeneralReferenceResponseRTU{
+			CANopenGeneralReferenceResponse: CANopenGeneralReferenceResponse{UnitID: 0x10, Data: []byte{0x10, 0x18, 0x1}},
+		},
+	}
+
+	parsed, err := ParseASCIIResponse(resp.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, &resp, parsed)
+}
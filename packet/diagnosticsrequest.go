@@ -0,0 +1,266 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Diagnostics (FC=08) sub-function codes
+const (
+	// DiagnosticsReturnQueryData is sub-function for echoing request data back (loopback test)
+	DiagnosticsReturnQueryData = uint16(0x00)
+	// DiagnosticsRestartCommunications is sub-function for restarting serial line port of the server. Data 0xFF00
+	// clears communications event log.
+	DiagnosticsRestartCommunications = uint16(0x01)
+	// DiagnosticsReturnDiagnosticRegister is sub-function for reading 16-bit diagnostic register of the server
+	DiagnosticsReturnDiagnosticRegister = uint16(0x02)
+	// DiagnosticsChangeASCIIInputDelimiter is sub-function for changing ASCII end of message delimiter character
+	DiagnosticsChangeASCIIInputDelimiter = uint16(0x03)
+	// DiagnosticsForceListenOnlyMode is sub-function for forcing server into Listen Only Mode. Server does not respond
+	// to this request.
+	DiagnosticsForceListenOnlyMode = uint16(0x04)
+	// DiagnosticsClearCounters is sub-function for clearing all counters and diagnostic register
+	DiagnosticsClearCounters = uint16(0x0A)
+	// DiagnosticsReturnBusMessageCount is sub-function for reading quantity of messages the server has detected on the bus
+	DiagnosticsReturnBusMessageCount = uint16(0x0B)
+	// DiagnosticsReturnBusCommunicationErrorCount is sub-function for reading quantity of CRC errors the server has encountered
+	DiagnosticsReturnBusCommunicationErrorCount = uint16(0x0C)
+	// DiagnosticsReturnBusExceptionErrorCount is sub-function for reading quantity of exception responses returned by the server
+	DiagnosticsReturnBusExceptionErrorCount = uint16(0x0D)
+	// DiagnosticsReturnServerMessageCount is sub-function for reading quantity of messages addressed to the server
+	DiagnosticsReturnServerMessageCount = uint16(0x0E)
+	// DiagnosticsReturnServerNoResponseCount is sub-function for reading quantity of messages server did not respond to
+	DiagnosticsReturnServerNoResponseCount = uint16(0x0F)
+	// DiagnosticsReturnServerNAKCount is sub-function for reading quantity of Negative Acknowledge exception responses
+	DiagnosticsReturnServerNAKCount = uint16(0x10)
+	// DiagnosticsReturnServerBusyCount is sub-function for reading quantity of Server Device Busy exception responses
+	DiagnosticsReturnServerBusyCount = uint16(0x11)
+	// DiagnosticsReturnBusCharacterOverrunCount is sub-function for reading quantity of messages that server could not
+	// handle due to character overrun condition
+	DiagnosticsReturnBusCharacterOverrunCount = uint16(0x12)
+	// DiagnosticsClearOverrunCounter is sub-function for clearing overrun error counter and flag
+	DiagnosticsClearOverrunCounter = uint16(0x14)
+)
+
+// maxDiagnosticsDataLen is maximum length of diagnostics data. 253 bytes PDU - 1 function code - 2 sub-function.
+const maxDiagnosticsDataLen = 250
+
+// DiagnosticsRequestTCP is TCP Request for Diagnostics (FC=08)
+//
+// Example packet: 0x00 0x01 0x00 0x00 0x00 0x06 0x11 0x08 0x00 0x00 0xA5 0x37
+// 0x00 0x01 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x06 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x11 - unit id (6)
+// 0x08 - function code (7)
+// 0x00 0x00 - sub-function (8,9)
+// 0xA5 0x37 - data (10,11, variable length)
+type DiagnosticsRequestTCP struct {
+	MBAPHeader
+	DiagnosticsRequest
+}
+
+// DiagnosticsRequestRTU is RTU Request for Diagnostics (FC=08)
+//
+// Example packet: 0x11 0x08 0x00 0x00 0xA5 0x37 0xd8 0x1d
+// 0x11 - unit id (0)
+// 0x08 - function code (1)
+// 0x00 0x00 - sub-function (2,3)
+// 0xA5 0x37 - data (4,5, variable length)
+// 0xd8 0x1d - CRC16 (n-2,n-1)
+type DiagnosticsRequestRTU struct {
+	DiagnosticsRequest
+}
+
+// DiagnosticsRequest is Request for Diagnostics (FC=08)
+type DiagnosticsRequest struct {
+	UnitID      uint8
+	SubFunction uint16
+	// Data is sub-function specific data. Counter sub-functions send 0x0000 as data.
+	Data []byte
+}
+
+// NewDiagnosticsRequestTCP creates new instance of Diagnostics TCP request
+func NewDiagnosticsRequestTCP(unitID uint8, subFunction uint16, data []byte) (*DiagnosticsRequestTCP, error) {
+	if err := checkDiagnosticsData(data); err != nil {
+		return nil, err
+	}
+	return &DiagnosticsRequestTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: RandomTransactionID(),
+			ProtocolID:    0,
+		},
+		DiagnosticsRequest: DiagnosticsRequest{
+			UnitID: unitID,
+			// function code is added by Bytes()
+			SubFunction: subFunction,
+			Data:        data,
+		},
+	}, nil
+}
+
+// Bytes returns DiagnosticsRequestTCP packet as bytes form
+func (r DiagnosticsRequestTCP) Bytes() []byte {
+	length := r.DiagnosticsRequest.len()
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.DiagnosticsRequest.bytes(result[6:])
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r DiagnosticsRequestTCP) ExpectedResponseLength() int {
+	// response = 6 header len + 1 unitID + 1 fc + 2 sub-function + N data (server echoes request)
+	return 6 + int(r.DiagnosticsRequest.len())
+}
+
+// ParseDiagnosticsRequestTCP parses given bytes into DiagnosticsRequestTCP
+func ParseDiagnosticsRequestTCP(data []byte) (*DiagnosticsRequestTCP, error) {
+	header, err := ParseMBAPHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	unitID := data[6]
+	if len(data) < 10 {
+		tmpErr := NewErrorParseTCP(ErrIllegalDataValue, "received data length too short to be valid packet")
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionDiagnostics
+		return nil, tmpErr
+	}
+	if data[7] != FunctionDiagnostics {
+		tmpErr := NewErrorParseTCP(ErrIllegalFunction, "received function code in packet is not 0x08")
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionDiagnostics
+		return nil, tmpErr
+	}
+	return &DiagnosticsRequestTCP{
+		MBAPHeader: header,
+		DiagnosticsRequest: DiagnosticsRequest{
+			UnitID: unitID,
+			// function code = data[7]
+			SubFunction: binary.BigEndian.Uint16(data[8:10]),
+			Data:        copyDiagnosticsData(data[10:]),
+		},
+	}, nil
+}
+
+// NewDiagnosticsRequestRTU creates new instance of Diagnostics RTU request
+func NewDiagnosticsRequestRTU(unitID uint8, subFunction uint16, data []byte) (*DiagnosticsRequestRTU, error) {
+	if err := checkDiagnosticsData(data); err != nil {
+		return nil, err
+	}
+	return &DiagnosticsRequestRTU{
+		DiagnosticsRequest: DiagnosticsRequest{
+			UnitID: unitID,
+			// function code is added by Bytes()
+			SubFunction: subFunction,
+			Data:        data,
+		},
+	}, nil
+}
+
+// Bytes returns DiagnosticsRequestRTU packet as bytes form
+func (r DiagnosticsRequestRTU) Bytes() []byte {
+	length := r.DiagnosticsRequest.len()
+	result := make([]byte, length+2)
+	bytes := r.DiagnosticsRequest.bytes(result)
+	crc := CRC16(bytes[:length])
+	result[length] = uint8(crc)
+	result[length+1] = uint8(crc >> 8)
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r DiagnosticsRequestRTU) ExpectedResponseLength() int {
+	// response = 1 UnitID + 1 functionCode + 2 sub-function + N data + 2 CRC (server echoes request)
+	return int(r.DiagnosticsRequest.len()) + 2
+}
+
+// ParseDiagnosticsRequestRTU parses given bytes into DiagnosticsRequestRTU. As packet has variable length data must
+// include CRC.
+func ParseDiagnosticsRequestRTU(data []byte) (*DiagnosticsRequestRTU, error) {
+	dLen := len(data)
+	if dLen < 6 {
+		return nil, NewErrorParseRTU(ErrServerFailure, "received data length too short to be valid packet")
+	}
+	unitID := data[0]
+	if data[1] != FunctionDiagnostics {
+		tmpErr := NewErrorParseRTU(ErrIllegalFunction, "received function code in packet is not 0x08")
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionDiagnostics
+		return nil, tmpErr
+	}
+	return &DiagnosticsRequestRTU{
+		DiagnosticsRequest: DiagnosticsRequest{
+			UnitID: unitID,
+			// function code = data[1]
+			SubFunction: binary.BigEndian.Uint16(data[2:4]),
+			Data:        copyDiagnosticsData(data[4 : dLen-2]),
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r DiagnosticsRequest) FunctionCode() uint8 {
+	return FunctionDiagnostics
+}
+
+// Bytes returns DiagnosticsRequest packet as bytes form
+func (r DiagnosticsRequest) Bytes() []byte {
+	return r.bytes(make([]byte, r.len()))
+}
+
+func (r DiagnosticsRequest) len() uint16 {
+	return 4 + uint16(len(r.Data))
+}
+
+func (r DiagnosticsRequest) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionDiagnostics
+	binary.BigEndian.PutUint16(bytes[2:4], r.SubFunction)
+	copy(bytes[4:], r.Data)
+	return bytes
+}
+
+func checkDiagnosticsData(data []byte) error {
+	if len(data) > maxDiagnosticsDataLen {
+		return errors.New("diagnostics data length is too long to fit into packet")
+	}
+	return nil
+}
+
+func copyDiagnosticsData(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	result := make([]byte, len(data))
+	copy(result, data)
+	return result
+}
+
+// DiagnosticsRequestASCII is ASCII Request for Diagnostics (FC=08). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type DiagnosticsRequestASCII struct {
+	DiagnosticsRequestRTU
+}
+
+// NewDiagnosticsRequestASCII creates new instance of Diagnostics ASCII request
+func NewDiagnosticsRequestASCII(unitID uint8, subFunction uint16, data []byte) (*DiagnosticsRequestASCII, error) {
+	r, err := NewDiagnosticsRequestRTU(unitID, subFunction, data)
+	if err != nil {
+		return nil, err
+	}
+	return &DiagnosticsRequestASCII{DiagnosticsRequestRTU: *r}, nil
+}
+
+// Bytes returns DiagnosticsRequestASCII packet as bytes form
+func (r DiagnosticsRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.DiagnosticsRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r DiagnosticsRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.DiagnosticsRequestRTU.ExpectedResponseLength())
+}
@@ -0,0 +1,182 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewDiagnosticsRequestTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenData    []byte
+		expect      *DiagnosticsRequestTCP
+		expectError string
+	}{
+		{
+			name:     "ok",
+			whenData: []byte{0xa5, 0x37},
+			expect: &DiagnosticsRequestTCP{
+				MBAPHeader: MBAPHeader{ProtocolID: 0},
+				DiagnosticsRequest: DiagnosticsRequest{
+					UnitID:      0x11,
+					SubFunction: DiagnosticsReturnQueryData,
+					Data:        []byte{0xa5, 0x37},
+				},
+			},
+		},
+		{
+			name:        "nok, data too long",
+			whenData:    make([]byte, 251),
+			expectError: "diagnostics data length is too long to fit into packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := NewDiagnosticsRequestTCP(0x11, DiagnosticsReturnQueryData, tc.whenData)
+
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.Nil(t, req)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEqual(t, uint16(0), req.TransactionID)
+			req.TransactionID = 0
+			assert.Equal(t, tc.expect, req)
+		})
+	}
+}
+
+func TestDiagnosticsRequestTCP_Bytes(t *testing.T) {
+	example := DiagnosticsRequestTCP{
+		MBAPHeader: MBAPHeader{TransactionID: 1},
+		DiagnosticsRequest: DiagnosticsRequest{
+			UnitID:      0x11,
+			SubFunction: DiagnosticsReturnQueryData,
+			Data:        []byte{0xa5, 0x37},
+		},
+	}
+
+	assert.Equal(t, []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x6, 0x11, 0x8, 0x0, 0x0, 0xa5, 0x37}, example.Bytes())
+	assert.Equal(t, 12, example.ExpectedResponseLength())
+	assert.Equal(t, FunctionDiagnostics, example.FunctionCode())
+}
+
+func TestParseDiagnosticsRequestTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *DiagnosticsRequestTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x6, 0x11, 0x8, 0x0, 0xb, 0x0, 0x0},
+			expect: &DiagnosticsRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 1},
+				DiagnosticsRequest: DiagnosticsRequest{
+					UnitID:      0x11,
+					SubFunction: DiagnosticsReturnBusMessageCount,
+					Data:        []byte{0x0, 0x0},
+				},
+			},
+		},
+		{
+			name:  "ok, without data",
+			given: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x4, 0x11, 0x8, 0x0, 0x4},
+			expect: &DiagnosticsRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 1},
+				DiagnosticsRequest: DiagnosticsRequest{
+					UnitID:      0x11,
+					SubFunction: DiagnosticsForceListenOnlyMode,
+				},
+			},
+		},
+		{
+			name:        "nok, invalid function code",
+			given:       []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x6, 0x11, 0x6, 0x0, 0xb, 0x0, 0x0},
+			expectError: "received function code in packet is not 0x08",
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x3, 0x11, 0x8, 0x0},
+			expectError: "received data length too short to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseDiagnosticsRequestTCP(tc.given)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsRequestRTU_Bytes(t *testing.T) {
+	req, err := NewDiagnosticsRequestRTU(0x11, DiagnosticsReturnQueryData, []byte{0xa5, 0x37})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x11, 0x8, 0x0, 0x0, 0xa5, 0x37, 0xd8, 0x1d}, req.Bytes())
+	assert.Equal(t, 8, req.ExpectedResponseLength())
+}
+
+func TestParseDiagnosticsRequestRTU(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *DiagnosticsRequestRTU
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x11, 0x8, 0x0, 0x0, 0xa5, 0x37, 0xd8, 0x1d},
+			expect: &DiagnosticsRequestRTU{
+				DiagnosticsRequest: DiagnosticsRequest{
+					UnitID:      0x11,
+					SubFunction: DiagnosticsReturnQueryData,
+					Data:        []byte{0xa5, 0x37},
+				},
+			},
+		},
+		{
+			name:        "nok, invalid function code",
+			given:       []byte{0x11, 0x6, 0x0, 0x0, 0xa5, 0x37, 0xd8, 0x1d},
+			expectError: "received function code in packet is not 0x08",
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x11, 0x8, 0x0, 0x0, 0xa5},
+			expectError: "received data length too short to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseDiagnosticsRequestRTU(tc.given)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsRequestASCII(t *testing.T) {
+	req, err := NewDiagnosticsRequestASCII(0x11, DiagnosticsReturnQueryData, []byte{0xa5, 0x37})
+	assert.NoError(t, err)
+
+	parsed, err := ParseASCIIRequest(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+	assert.Equal(t, asciiLength(8), req.ExpectedResponseLength())
+}
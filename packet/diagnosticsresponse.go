@@ -0,0 +1,143 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// DiagnosticsResponseTCP is TCP Response for Diagnostics (FC=08)
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x06 0x11 0x08 0x00 0x0B 0x01 0x02
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x06 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x11 - unit id (6)
+// 0x08 - function code (7)
+// 0x00 0x0B - sub-function (8,9)
+// 0x01 0x02 - data (10,11, variable length)
+type DiagnosticsResponseTCP struct {
+	MBAPHeader
+	DiagnosticsResponse
+}
+
+// DiagnosticsResponseRTU is RTU Response for Diagnostics (FC=08)
+//
+// Example packet: 0x11 0x08 0x00 0x0B 0x01 0x02 0x13 0x08
+// 0x11 - unit id (0)
+// 0x08 - function code (1)
+// 0x00 0x0B - sub-function (2,3)
+// 0x01 0x02 - data (4,5, variable length)
+// 0x13 0x08 - CRC16 (n-2,n-1)
+type DiagnosticsResponseRTU struct {
+	DiagnosticsResponse
+}
+
+// DiagnosticsResponse is Response for Diagnostics (FC=08)
+type DiagnosticsResponse struct {
+	UnitID      uint8
+	SubFunction uint16
+	// Data is sub-function specific data. For Return Query Data (0x00) it is echo of request data and for counter
+	// sub-functions it is 2 byte counter value.
+	Data []byte
+}
+
+// Bytes returns DiagnosticsResponseTCP packet as bytes form
+func (r DiagnosticsResponseTCP) Bytes() []byte {
+	length := r.DiagnosticsResponse.len()
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.DiagnosticsResponse.bytes(result[6:])
+	return result
+}
+
+// ParseDiagnosticsResponseTCP parses given bytes into DiagnosticsResponseTCP
+func ParseDiagnosticsResponseTCP(data []byte) (*DiagnosticsResponseTCP, error) {
+	dLen := len(data)
+	if dLen < 10 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	pduLen := binary.BigEndian.Uint16(data[4:6])
+	if dLen != 6+int(pduLen) {
+		return nil, errors.New("received data length does not match PDU len in packet")
+	}
+
+	return &DiagnosticsResponseTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: binary.BigEndian.Uint16(data[0:2]),
+			ProtocolID:    0,
+		},
+		DiagnosticsResponse: DiagnosticsResponse{
+			UnitID:      data[6],
+			SubFunction: binary.BigEndian.Uint16(data[8:10]),
+			Data:        copyDiagnosticsData(data[10:]),
+		},
+	}, nil
+}
+
+// Bytes returns DiagnosticsResponseRTU packet as bytes form
+func (r DiagnosticsResponseRTU) Bytes() []byte {
+	length := r.DiagnosticsResponse.len()
+	result := make([]byte, length+2)
+	bytes := r.DiagnosticsResponse.bytes(result)
+	crc := CRC16(bytes[:length])
+	result[length] = uint8(crc)
+	result[length+1] = uint8(crc >> 8)
+	return result
+}
+
+// ParseDiagnosticsResponseRTU parses given bytes into DiagnosticsResponseRTU
+func ParseDiagnosticsResponseRTU(data []byte) (*DiagnosticsResponseRTU, error) {
+	dLen := len(data)
+	if dLen < 6 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	return &DiagnosticsResponseRTU{
+		DiagnosticsResponse: DiagnosticsResponse{
+			UnitID: data[0],
+			// data[1] function code
+			SubFunction: binary.BigEndian.Uint16(data[2:4]),
+			Data:        copyDiagnosticsData(data[4 : dLen-2]),
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r DiagnosticsResponse) FunctionCode() uint8 {
+	return FunctionDiagnostics
+}
+
+// Bytes returns DiagnosticsResponse packet as bytes form
+func (r DiagnosticsResponse) Bytes() []byte {
+	return r.bytes(make([]byte, r.len()))
+}
+
+func (r DiagnosticsResponse) len() uint16 {
+	return 4 + uint16(len(r.Data))
+}
+
+func (r DiagnosticsResponse) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionDiagnostics
+	binary.BigEndian.PutUint16(bytes[2:4], r.SubFunction)
+	copy(bytes[4:], r.Data)
+	return bytes
+}
+
+// Counter returns response data as 16-bit counter (or diagnostic register) value
+func (r DiagnosticsResponse) Counter() (uint16, error) {
+	if len(r.Data) != 2 {
+		return 0, errors.New("diagnostics response data is not 2 bytes long")
+	}
+	return binary.BigEndian.Uint16(r.Data), nil
+}
+
+// DiagnosticsResponseASCII is ASCII Response for Diagnostics (FC=08). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type DiagnosticsResponseASCII struct {
+	DiagnosticsResponseRTU
+}
+
+// Bytes returns DiagnosticsResponseASCII packet as bytes form
+func (r DiagnosticsResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.DiagnosticsResponseRTU.Bytes())
+}
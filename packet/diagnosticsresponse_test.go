@@ -0,0 +1,109 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiagnosticsResponseTCP_Bytes(t *testing.T) {
+	example := DiagnosticsResponseTCP{
+		MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+		DiagnosticsResponse: DiagnosticsResponse{
+			UnitID:      0x11,
+			SubFunction: DiagnosticsReturnBusMessageCount,
+			Data:        []byte{0x1, 0x2},
+		},
+	}
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x6, 0x11, 0x8, 0x0, 0xb, 0x1, 0x2}, example.Bytes())
+	assert.Equal(t, FunctionDiagnostics, example.FunctionCode())
+}
+
+func TestParseDiagnosticsResponseTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *DiagnosticsResponseTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x6, 0x11, 0x8, 0x0, 0xb, 0x1, 0x2},
+			expect: &DiagnosticsResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				DiagnosticsResponse: DiagnosticsResponse{
+					UnitID:      0x11,
+					SubFunction: DiagnosticsReturnBusMessageCount,
+					Data:        []byte{0x1, 0x2},
+				},
+			},
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x3, 0x11, 0x8, 0x0},
+			expectError: "received data length too short to be valid packet",
+		},
+		{
+			name:        "nok, PDU len does not match",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x7, 0x11, 0x8, 0x0, 0xb, 0x1, 0x2},
+			expectError: "received data length does not match PDU len in packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseDiagnosticsResponseTCP(tc.given)
+
+			assert.Equal(t, tc.expect, resp)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseDiagnosticsResponseRTU(t *testing.T) {
+	given := []byte{0x11, 0x8, 0x0, 0xb, 0x1, 0x2, 0x13, 0x8}
+
+	resp, err := ParseDiagnosticsResponseRTU(given)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &DiagnosticsResponseRTU{
+		DiagnosticsResponse: DiagnosticsResponse{
+			UnitID:      0x11,
+			SubFunction: DiagnosticsReturnBusMessageCount,
+			Data:        []byte{0x1, 0x2},
+		},
+	}, resp)
+	assert.Equal(t, given, resp.Bytes())
+
+	_, err = ParseDiagnosticsResponseRTU([]byte{0x11, 0x8, 0x0, 0xb, 0x1})
+	assert.EqualError(t, err, "received data length too short to be valid packet")
+}
+
+func TestDiagnosticsResponse_Counter(t *testing.T) {
+	counter, err := DiagnosticsResponse{Data: []byte{0x1, 0x2}}.Counter()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x0102), counter)
+
+	_, err = DiagnosticsResponse{Data: []byte{0x1, 0x2, 0x3}}.Counter()
+	assert.EqualError(t, err, "diagnostics response data is not 2 bytes long")
+}
+
+func TestDiagnosticsResponseASCII(t *testing.T) {
+	resp := &DiagnosticsResponseASCII{
+		DiagnosticsResponseRTU: DiagnosticsResponseRTU{
+			DiagnosticsResponse: DiagnosticsResponse{
+				UnitID:      0x11,
+				SubFunction: DiagnosticsReturnBusMessageCount,
+				Data:        []byte{0x1, 0x2},
+			},
+		},
+	}
+
+	parsed, err := ParseASCIIResponse(resp.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, resp, parsed)
+}
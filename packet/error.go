@@ -5,18 +5,20 @@ import (
 	"fmt"
 )
 
-// ErrCode is enumeration for response error codes
+// ErrCode is enumeration for response error (exception) codes. ErrCode implements error so error responses can be
+// matched with errors.Is (i.e. `errors.Is(err, packet.ErrIllegalDataAddress)`) and exception code extracted from error
+// with errors.As.
 type ErrCode uint8
 
 const (
 	// ErrUnknown is catchall error code
-	ErrUnknown = 0
+	ErrUnknown ErrCode = 0
 	// ErrIllegalFunction is The function code received in the query is not an allowable action for the server.
 	// This may be because the function code is only applicable to newer devices, and was not implemented in the
 	// unit selected. It could also indicate that the server is in the wrong state to process a request of this
 	// type, for example because it is unconfigured and is being asked to return register values.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 48
-	ErrIllegalFunction = 1
+	ErrIllegalFunction ErrCode = 1
 	// ErrIllegalDataAddress is The data address received in the query is not an allowable address for the server.
 	// More specifically, the combination of reference number and transfer length is invalid. For a controller with 100
 	// registers, the PDU addresses the first register as 0, and the last one as 99. If a request is submitted with a
@@ -26,46 +28,47 @@ const (
 	// Code 0x02 “Illegal Data Address” since it attempts to operate on registers 96, 97, 98, 99 and 100, and
 	// there is no register with address 100.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 48
-	ErrIllegalDataAddress = 2
+	ErrIllegalDataAddress ErrCode = 2
 	// ErrIllegalDataValue is A value contained in the query data field is not an allowable value for server.
 	// This indicates a fault in the structure of the remainder of a complex request, such as that the implied length
 	// is incorrect. It specifically does NOT mean that a data item submitted for storage in a register has a value
 	// outside the expectation of the application program, since the MODBUS protocol is unaware of the significance of
 	// any particular value of any particular register.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 48
-	ErrIllegalDataValue = 3
+	ErrIllegalDataValue ErrCode = 3
 	// ErrServerFailure is An unrecoverable error occurred while the server was attempting to perform the requested action.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 48
-	ErrServerFailure = 4
+	ErrServerFailure ErrCode = 4
 	// ErrAcknowledge is Specialized use in conjunction with programming commands. The server has accepted the request
 	// and is processing it, but a long duration of time will be required to do so. This response is returned to prevent
 	// a timeout error from occurring in the client. The client can next issue a Poll Program Complete message to
 	// determine if processing is completed.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 48
-	ErrAcknowledge = 5
+	ErrAcknowledge ErrCode = 5
 	// ErrServerBusy is Specialized use in conjunction with programming commands. The server is engaged in processing a
 	// long duration program command. The client should retransmit the message later when the server is free.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 48
-	ErrServerBusy = 6
+	ErrServerBusy ErrCode = 6
 	// ErrMemoryParityError is Specialized use in conjunction with function codes 20 and 21 and reference type 6, to
 	// indicate that the extended file area failed to pass a consistency check.
 	// The server attempted to read record file, but detected a parity error in the memory. The client can retry
 	// the request, but service may be required on the server device.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 48
-	ErrMemoryParityError = 8
+	ErrMemoryParityError ErrCode = 8
 	// ErrGatewayPathUnavailable is Specialized use in conjunction with gateways, indicates that the gateway was unable
 	// to allocate an internal communication path from the input port to the output port for processing the request.
 	// Usually means that the gateway is misconfigured or overloaded.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 49
-	ErrGatewayPathUnavailable = 10
+	ErrGatewayPathUnavailable ErrCode = 10
 	// ErrGatewayTargetedDeviceResponse is Specialized use in conjunction with gateways, indicates that no response was
 	// obtained from the target device. Usually means that the device is not present on the network.
 	// Quote from: `MODBUS Application Protocol Specification V1.1b3`, page 49
-	ErrGatewayTargetedDeviceResponse = 11
+	ErrGatewayTargetedDeviceResponse ErrCode = 11
 )
 
-func errorText(code uint8) string {
-	switch code {
+// String returns human readable description of the exception code
+func (c ErrCode) String() string {
+	switch c {
 	case ErrIllegalFunction:
 		return "Illegal function"
 	case ErrIllegalDataAddress:
@@ -87,12 +90,62 @@ func errorText(code uint8) string {
 	case ErrUnknown:
 		fallthrough
 	default:
-		return fmt.Sprintf("Unknown error code: %v", code)
+		return fmt.Sprintf("Unknown error code: %v", uint8(c))
 	}
 }
 
+// Error returns human readable description of the exception code
+func (c ErrCode) Error() string {
+	return c.String()
+}
+
+// IsRetryable returns true when request responded with the exception code can be retried later without changes.
+// These are Acknowledge (0x05), Server busy (0x06) and gateway exceptions (0x0A, 0x0B).
+func (c ErrCode) IsRetryable() bool {
+	switch c {
+	case ErrAcknowledge, ErrServerBusy, ErrGatewayPathUnavailable, ErrGatewayTargetedDeviceResponse:
+		return true
+	}
+	return false
+}
+
+// IsIllegalFunction returns true for Illegal function (0x01) exception code
+func (c ErrCode) IsIllegalFunction() bool { return c == ErrIllegalFunction }
+
+// IsIllegalAddress returns true for Illegal data address (0x02) exception code
+func (c ErrCode) IsIllegalAddress() bool { return c == ErrIllegalDataAddress }
+
+// IsIllegalDataValue returns true for Illegal data value (0x03) exception code
+func (c ErrCode) IsIllegalDataValue() bool { return c == ErrIllegalDataValue }
+
+// IsServerFailure returns true for Server failure (0x04) exception code
+func (c ErrCode) IsServerFailure() bool { return c == ErrServerFailure }
+
+// IsServerBusy returns true for Server busy (0x06) exception code
+func (c ErrCode) IsServerBusy() bool { return c == ErrServerBusy }
+
+// IsGatewayError returns true for gateway exception codes (0x0A, 0x0B)
+func (c ErrCode) IsGatewayError() bool {
+	return c == ErrGatewayPathUnavailable || c == ErrGatewayTargetedDeviceResponse
+}
+
+// matchErrCode implements errors.Is matching of error response against ErrCode target
+func matchErrCode(code ErrCode, target error) bool {
+	t, ok := target.(ErrCode)
+	return ok && t == code
+}
+
+// asErrCode implements errors.As extraction of ErrCode from error response
+func asErrCode(code ErrCode, target interface{}) bool {
+	t, ok := target.(*ErrCode)
+	if ok {
+		*t = code
+	}
+	return ok
+}
+
 // NewErrorParseTCP creates new instance of parsing error that can be sent to the client
-func NewErrorParseTCP(code uint8, message string) *ErrorParseTCP {
+func NewErrorParseTCP(code ErrCode, message string) *ErrorParseTCP {
 	return &ErrorParseTCP{
 		Message: message,
 		Packet: ErrorResponseTCP{
@@ -125,12 +178,22 @@ type ErrorResponseTCP struct {
 	TransactionID uint16
 	UnitID        uint8
 	Function      uint8
-	Code          uint8
+	Code          ErrCode
 }
 
 // Error translates error code to error message.
 func (re ErrorResponseTCP) Error() string {
-	return errorText(re.Code)
+	return re.Code.String()
+}
+
+// Is allows matching error response by exception code with errors.Is (i.e. `errors.Is(err, packet.ErrServerBusy)`)
+func (re ErrorResponseTCP) Is(target error) bool {
+	return matchErrCode(re.Code, target)
+}
+
+// As allows extracting exception code from error response with errors.As into ErrCode variable
+func (re ErrorResponseTCP) As(target interface{}) bool {
+	return asErrCode(re.Code, target)
 }
 
 // Bytes returns ErrorResponseTCP packet as bytes form
@@ -142,7 +205,7 @@ func (re ErrorResponseTCP) Bytes() []byte {
 	binary.BigEndian.PutUint16(result[4:6], 3)
 	result[6] = re.UnitID
 	result[7] = re.Function + functionCodeErrorBitmask
-	result[8] = re.Code
+	result[8] = uint8(re.Code)
 
 	return result
 }
@@ -153,7 +216,7 @@ func (re ErrorResponseTCP) FunctionCode() uint8 {
 }
 
 // NewErrorParseRTU creates new instance of parsing error that can be sent to the client
-func NewErrorParseRTU(code uint8, message string) *ErrorParseRTU {
+func NewErrorParseRTU(code ErrCode, message string) *ErrorParseRTU {
 	return &ErrorParseRTU{
 		Message: message,
 		Packet: ErrorResponseRTU{
@@ -184,12 +247,22 @@ func (e ErrorParseRTU) Bytes() []byte {
 type ErrorResponseRTU struct {
 	UnitID   uint8
 	Function uint8
-	Code     uint8
+	Code     ErrCode
 }
 
 // Error translates error code to error message.
 func (re ErrorResponseRTU) Error() string {
-	return errorText(re.Code)
+	return re.Code.String()
+}
+
+// Is allows matching error response by exception code with errors.Is (i.e. `errors.Is(err, packet.ErrServerBusy)`)
+func (re ErrorResponseRTU) Is(target error) bool {
+	return matchErrCode(re.Code, target)
+}
+
+// As allows extracting exception code from error response with errors.As into ErrCode variable
+func (re ErrorResponseRTU) As(target interface{}) bool {
+	return asErrCode(re.Code, target)
 }
 
 // Bytes returns ErrorResponseRTU packet as bytes form
@@ -198,7 +271,7 @@ func (re ErrorResponseRTU) Bytes() []byte {
 
 	result[0] = re.UnitID
 	result[1] = re.Function + functionCodeErrorBitmask
-	result[2] = re.Code
+	result[2] = uint8(re.Code)
 	crc := CRC16(result[0:3])
 	result[3] = uint8(crc)
 	result[4] = uint8(crc >> 8)
@@ -233,7 +306,7 @@ func AsTCPErrorPacket(data []byte) error {
 			TransactionID: binary.BigEndian.Uint16(data[0:2]),
 			UnitID:        data[6],
 			Function:      data[7] - functionCodeErrorBitmask,
-			Code:          data[8],
+			Code:          ErrCode(data[8]),
 		}
 	}
 	return nil // probably start of valid packet
@@ -258,7 +331,7 @@ func AsRTUErrorPacket(data []byte) error {
 		return &ErrorResponseRTU{
 			UnitID:   data[0],
 			Function: data[1] - functionCodeErrorBitmask,
-			Code:     data[2],
+			Code:     ErrCode(data[2]),
 		}
 	}
 	return nil // probably start of valid packet
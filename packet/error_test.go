@@ -1,6 +1,8 @@
 package packet
 
 import (
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -256,3 +258,56 @@ func TestAsRTUErrorPacket(t *testing.T) {
 		})
 	}
 }
+
+func TestErrCode_predicates(t *testing.T) {
+	var testCases = []struct {
+		given           ErrCode
+		expectString    string
+		expectRetryable bool
+		expectGateway   bool
+	}{
+		{given: ErrIllegalFunction, expectString: "Illegal function"},
+		{given: ErrIllegalDataAddress, expectString: "Illegal data address"},
+		{given: ErrServerFailure, expectString: "Server failure"},
+		{given: ErrAcknowledge, expectString: "Acknowledge", expectRetryable: true},
+		{given: ErrServerBusy, expectString: "Server busy", expectRetryable: true},
+		{given: ErrGatewayPathUnavailable, expectString: "Gateway path unavailable", expectRetryable: true, expectGateway: true},
+		{given: ErrGatewayTargetedDeviceResponse, expectString: "Gateway targeted device failed to respond", expectRetryable: true, expectGateway: true},
+		{given: ErrCode(99), expectString: "Unknown error code: 99"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectString, func(t *testing.T) {
+			assert.Equal(t, tc.expectString, tc.given.String())
+			assert.Equal(t, tc.expectString, tc.given.Error())
+			assert.Equal(t, tc.expectRetryable, tc.given.IsRetryable())
+			assert.Equal(t, tc.expectGateway, tc.given.IsGatewayError())
+			assert.Equal(t, tc.given == ErrIllegalFunction, tc.given.IsIllegalFunction())
+			assert.Equal(t, tc.given == ErrIllegalDataAddress, tc.given.IsIllegalAddress())
+			assert.Equal(t, tc.given == ErrServerFailure, tc.given.IsServerFailure())
+			assert.Equal(t, tc.given == ErrServerBusy, tc.given.IsServerBusy())
+		})
+	}
+}
+
+func TestErrorResponse_errorsIsAndAs(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		given error
+	}{
+		{name: "TCP", given: &ErrorResponseTCP{TransactionID: 1, UnitID: 1, Function: 3, Code: ErrIllegalDataAddress}},
+		{name: "RTU", given: &ErrorResponseRTU{UnitID: 1, Function: 3, Code: ErrIllegalDataAddress}},
+		{name: "ASCII", given: &ErrorResponseASCII{UnitID: 1, Function: 3, Code: ErrIllegalDataAddress}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("request failed: %w", tc.given)
+
+			assert.True(t, errors.Is(wrapped, ErrIllegalDataAddress))
+			assert.False(t, errors.Is(wrapped, ErrIllegalDataValue))
+
+			var code ErrCode
+			assert.True(t, errors.As(wrapped, &code))
+			assert.Equal(t, ErrIllegalDataAddress, code)
+		})
+	}
+}
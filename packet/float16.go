@@ -0,0 +1,66 @@
+package packet
+
+import "math"
+
+// Float16frombits returns float32 value of IEEE 754 half-precision (binary16) floating point number given as bits.
+// Every half-precision value is exactly representable as float32.
+func Float16frombits(b uint16) float32 {
+	sign := uint32(b&0x8000) << 16
+	exponent := uint32(b>>10) & 0x1f
+	mantissa := uint32(b & 0x3ff)
+
+	switch exponent {
+	case 0:
+		// zero or subnormal number: mantissa * 2^-24
+		v := float32(mantissa) / (1 << 24)
+		if sign != 0 {
+			return -v
+		}
+		return v
+	case 0x1f:
+		// infinity or NaN
+		return math.Float32frombits(sign | 0x7f800000 | mantissa<<13)
+	}
+	return math.Float32frombits(sign | (exponent-15+127)<<23 | mantissa<<13)
+}
+
+// Float16bits returns IEEE 754 half-precision (binary16) representation of given float32 value. Value is rounded to
+// the nearest half-precision value (ties to even). Values too large for half-precision are converted to infinity.
+func Float16bits(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exponent := int((b >> 23) & 0xff)
+	mantissa := b & 0x7fffff
+
+	if exponent == 0xff { // infinity or NaN
+		if mantissa == 0 {
+			return sign | 0x7c00
+		}
+		return sign | 0x7e00 | uint16(mantissa>>13)
+	}
+	e := exponent - 127 + 15
+	if e >= 0x1f {
+		return sign | 0x7c00 // overflow to infinity
+	}
+	if e <= 0 {
+		if e < -10 {
+			return sign // underflow to zero
+		}
+		// subnormal half-precision number
+		mantissa |= 0x800000
+		shift := uint32(14 - e)
+		return sign | uint16(roundShift(mantissa, shift))
+	}
+	return sign | uint16(uint32(e)<<10+roundShift(mantissa, 13))
+}
+
+// roundShift shifts value right by given amount of bits rounding to nearest (ties to even)
+func roundShift(v uint32, shift uint32) uint32 {
+	result := v >> shift
+	remainder := v & (1<<shift - 1)
+	halfway := uint32(1) << (shift - 1)
+	if remainder > halfway || (remainder == halfway && result&1 == 1) {
+		result++
+	}
+	return result
+}
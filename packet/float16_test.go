@@ -0,0 +1,58 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestFloat16frombits(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   uint16
+		expect float32
+	}{
+		{name: "zero", when: 0x0000, expect: 0},
+		{name: "one", when: 0x3c00, expect: 1},
+		{name: "minus two", when: 0xc000, expect: -2},
+		{name: "max", when: 0x7bff, expect: 65504},
+		{name: "smallest normal", when: 0x0400, expect: 6.1035156e-05},
+		{name: "smallest subnormal", when: 0x0001, expect: 5.9604645e-08},
+		{name: "one third", when: 0x3555, expect: 0.33325195},
+		{name: "infinity", when: 0x7c00, expect: float32(math.Inf(1))},
+		{name: "negative infinity", when: 0xfc00, expect: float32(math.Inf(-1))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, Float16frombits(tc.when))
+		})
+	}
+	assert.True(t, math.IsNaN(float64(Float16frombits(0x7e00))))
+}
+
+func TestFloat16bits(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   float32
+		expect uint16
+	}{
+		{name: "zero", when: 0, expect: 0x0000},
+		{name: "negative zero", when: float32(math.Copysign(0, -1)), expect: 0x8000},
+		{name: "one", when: 1, expect: 0x3c00},
+		{name: "minus two", when: -2, expect: 0xc000},
+		{name: "max", when: 65504, expect: 0x7bff},
+		{name: "overflow", when: 65536, expect: 0x7c00},
+		{name: "smallest subnormal", when: 5.9604645e-08, expect: 0x0001},
+		{name: "underflow", when: 1e-10, expect: 0x0000},
+		{name: "one third is rounded", when: 1.0 / 3, expect: 0x3555},
+		{name: "ties to even", when: 1 + 1.0/2048, expect: 0x3c00},
+		{name: "ties to even, rounds up", when: 1 + 3.0/2048, expect: 0x3c02},
+		{name: "infinity", when: float32(math.Inf(1)), expect: 0x7c00},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, Float16bits(tc.when))
+		})
+	}
+	assert.Equal(t, uint16(0x7e00), Float16bits(float32(math.NaN()))&0x7e00)
+}
@@ -0,0 +1,203 @@
+package packet
+
+import (
+	"encoding/binary"
+)
+
+// MaskWriteRegisterRequestTCP is TCP Request for Mask Write Register (FC=22). Server sets register value to
+// (current AND AndMask) OR (OrMask AND (NOT AndMask)).
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x08 0x11 0x16 0x00 0x04 0x00 0xF2 0x00 0x25
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x08 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x11 - unit id (6)
+// 0x16 - function code (7)
+// 0x00 0x04 - register address (8,9)
+// 0x00 0xF2 - AND mask (10,11)
+// 0x00 0x25 - OR mask (12,13)
+type MaskWriteRegisterRequestTCP struct {
+	MBAPHeader
+	MaskWriteRegisterRequest
+}
+
+// MaskWriteRegisterRequestRTU is RTU Request for Mask Write Register (FC=22). Server sets register value to
+// (current AND AndMask) OR (OrMask AND (NOT AndMask)).
+//
+// Example packet: 0x11 0x16 0x00 0x04 0x00 0xF2 0x00 0x25 0x66 0xE2
+// 0x11 - unit id (0)
+// 0x16 - function code (1)
+// 0x00 0x04 - register address (2,3)
+// 0x00 0xF2 - AND mask (4,5)
+// 0x00 0x25 - OR mask (6,7)
+// 0x66 0xE2 - CRC16 (8,9)
+type MaskWriteRegisterRequestRTU struct {
+	MaskWriteRegisterRequest
+}
+
+// MaskWriteRegisterRequest is Request for Mask Write Register (FC=22)
+type MaskWriteRegisterRequest struct {
+	UnitID  uint8
+	Address uint16
+	AndMask uint16
+	OrMask  uint16
+}
+
+// NewMaskWriteRegisterRequestTCP creates new instance of Mask Write Register TCP request
+func NewMaskWriteRegisterRequestTCP(unitID uint8, address uint16, andMask uint16, orMask uint16) (*MaskWriteRegisterRequestTCP, error) {
+	return &MaskWriteRegisterRequestTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: RandomTransactionID(),
+			ProtocolID:    0,
+		},
+		MaskWriteRegisterRequest: MaskWriteRegisterRequest{
+			UnitID: unitID,
+			// function code is added by Bytes()
+			Address: address,
+			AndMask: andMask,
+			OrMask:  orMask,
+		},
+	}, nil
+}
+
+// Bytes returns MaskWriteRegisterRequestTCP packet as bytes form
+func (r MaskWriteRegisterRequestTCP) Bytes() []byte {
+	length := uint16(8)
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.MaskWriteRegisterRequest.bytes(result[6 : 6+length])
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r MaskWriteRegisterRequestTCP) ExpectedResponseLength() int {
+	// response = 6 header len + 1 unitID + 1 fc + 2 address + 2 AND mask + 2 OR mask
+	return 6 + 8
+}
+
+// ParseMaskWriteRegisterRequestTCP parses given bytes into MaskWriteRegisterRequestTCP
+func ParseMaskWriteRegisterRequestTCP(data []byte) (*MaskWriteRegisterRequestTCP, error) {
+	header, err := ParseMBAPHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 14 {
+		return nil, NewErrorParseTCP(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[6]
+	if data[7] != FunctionMaskWriteRegister {
+		tmpErr := NewErrorParseTCP(ErrIllegalFunction, "received function code in packet is not 0x16")
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionMaskWriteRegister
+		return nil, tmpErr
+	}
+	return &MaskWriteRegisterRequestTCP{
+		MBAPHeader: header,
+		MaskWriteRegisterRequest: MaskWriteRegisterRequest{
+			UnitID: unitID,
+			// function code = data[7]
+			Address: binary.BigEndian.Uint16(data[8:10]),
+			AndMask: binary.BigEndian.Uint16(data[10:12]),
+			OrMask:  binary.BigEndian.Uint16(data[12:14]),
+		},
+	}, nil
+}
+
+// NewMaskWriteRegisterRequestRTU creates new instance of Mask Write Register RTU request
+func NewMaskWriteRegisterRequestRTU(unitID uint8, address uint16, andMask uint16, orMask uint16) (*MaskWriteRegisterRequestRTU, error) {
+	return &MaskWriteRegisterRequestRTU{
+		MaskWriteRegisterRequest: MaskWriteRegisterRequest{
+			UnitID: unitID,
+			// function code is added by Bytes()
+			Address: address,
+			AndMask: andMask,
+			OrMask:  orMask,
+		},
+	}, nil
+}
+
+// Bytes returns MaskWriteRegisterRequestRTU packet as bytes form
+func (r MaskWriteRegisterRequestRTU) Bytes() []byte {
+	result := make([]byte, 8+2)
+	bytes := r.MaskWriteRegisterRequest.bytes(result)
+	crc := CRC16(bytes[:8])
+	result[8] = uint8(crc)
+	result[9] = uint8(crc >> 8)
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r MaskWriteRegisterRequestRTU) ExpectedResponseLength() int {
+	// response = 1 UnitID + 1 functionCode + 2 address + 2 AND mask + 2 OR mask + 2 CRC
+	return 8 + 2
+}
+
+// ParseMaskWriteRegisterRequestRTU parses given bytes into MaskWriteRegisterRequestRTU
+// Does not check CRC
+func ParseMaskWriteRegisterRequestRTU(data []byte) (*MaskWriteRegisterRequestRTU, error) {
+	dLen := len(data)
+	if dLen != 10 && dLen != 8 { // with or without CRC bytes
+		return nil, NewErrorParseRTU(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[0]
+	if data[1] != FunctionMaskWriteRegister {
+		tmpErr := NewErrorParseRTU(ErrIllegalFunction, "received function code in packet is not 0x16")
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionMaskWriteRegister
+		return nil, tmpErr
+	}
+	return &MaskWriteRegisterRequestRTU{
+		MaskWriteRegisterRequest: MaskWriteRegisterRequest{
+			UnitID: unitID,
+			// function code = data[1]
+			Address: binary.BigEndian.Uint16(data[2:4]),
+			AndMask: binary.BigEndian.Uint16(data[4:6]),
+			OrMask:  binary.BigEndian.Uint16(data[6:8]),
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r MaskWriteRegisterRequest) FunctionCode() uint8 {
+	return FunctionMaskWriteRegister
+}
+
+// Bytes returns MaskWriteRegisterRequest packet as bytes form
+func (r MaskWriteRegisterRequest) Bytes() []byte {
+	return r.bytes(make([]byte, 8))
+}
+
+func (r MaskWriteRegisterRequest) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionMaskWriteRegister
+	binary.BigEndian.PutUint16(bytes[2:4], r.Address)
+	binary.BigEndian.PutUint16(bytes[4:6], r.AndMask)
+	binary.BigEndian.PutUint16(bytes[6:8], r.OrMask)
+	return bytes
+}
+
+// MaskWriteRegisterRequestASCII is ASCII Request for Mask Write Register (FC=22). Packet is RTU packet without CRC
+// encoded as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type MaskWriteRegisterRequestASCII struct {
+	MaskWriteRegisterRequestRTU
+}
+
+// NewMaskWriteRegisterRequestASCII creates new instance of Mask Write Register ASCII request
+func NewMaskWriteRegisterRequestASCII(unitID uint8, address uint16, andMask uint16, orMask uint16) (*MaskWriteRegisterRequestASCII, error) {
+	r, err := NewMaskWriteRegisterRequestRTU(unitID, address, andMask, orMask)
+	if err != nil {
+		return nil, err
+	}
+	return &MaskWriteRegisterRequestASCII{MaskWriteRegisterRequestRTU: *r}, nil
+}
+
+// Bytes returns MaskWriteRegisterRequestASCII packet as bytes form
+func (r MaskWriteRegisterRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.MaskWriteRegisterRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r MaskWriteRegisterRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.MaskWriteRegisterRequestRTU.ExpectedResponseLength())
+}
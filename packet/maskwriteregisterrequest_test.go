@@ -0,0 +1,90 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewMaskWriteRegisterRequestTCP(t *testing.T) {
+	req, err := NewMaskWriteRegisterRequestTCP(0x11, 0x0004, 0x00F2, 0x0025)
+	assert.NoError(t, err)
+	req.TransactionID = 0x8180
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25}, req.Bytes())
+	assert.Equal(t, 14, req.ExpectedResponseLength())
+	assert.Equal(t, FunctionMaskWriteRegister, req.FunctionCode())
+}
+
+func TestParseMaskWriteRegisterRequestTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *MaskWriteRegisterRequestTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25},
+			expect: &MaskWriteRegisterRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				MaskWriteRegisterRequest: MaskWriteRegisterRequest{
+					UnitID:  0x11,
+					Address: 0x0004,
+					AndMask: 0x00F2,
+					OrMask:  0x0025,
+				},
+			},
+		},
+		{
+			name:        "nok, invalid function code",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x11, 0x17, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25},
+			expectError: "received function code in packet is not 0x16",
+		},
+		{
+			name:        "nok, invalid length",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x7, 0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0},
+			expectError: "invalid data length to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseMaskWriteRegisterRequestTCP(tc.given)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaskWriteRegisterRequestRTU(t *testing.T) {
+	req, err := NewMaskWriteRegisterRequestRTU(0x11, 0x0004, 0x00F2, 0x0025)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte{0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25, 0x66, 0xe2}, req.Bytes())
+	assert.Equal(t, 10, req.ExpectedResponseLength())
+
+	parsed, err := ParseMaskWriteRegisterRequestRTU(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+
+	_, err = ParseMaskWriteRegisterRequestRTU([]byte{0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0})
+	assert.EqualError(t, err, "invalid data length to be valid packet")
+
+	_, err = ParseMaskWriteRegisterRequestRTU([]byte{0x11, 0x17, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25})
+	assert.EqualError(t, err, "received function code in packet is not 0x16")
+}
+
+func TestMaskWriteRegisterRequestASCII(t *testing.T) {
+	req, err := NewMaskWriteRegisterRequestASCII(0x11, 0x0004, 0x00F2, 0x0025)
+	assert.NoError(t, err)
+	assert.Equal(t, asciiLength(10), req.ExpectedResponseLength())
+
+	parsed, err := ParseASCIIRequest(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+}
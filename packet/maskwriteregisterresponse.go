@@ -0,0 +1,138 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// MaskWriteRegisterResponseTCP is TCP Response for Mask Write Register (FC=22). Response is echo of the request.
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x08 0x11 0x16 0x00 0x04 0x00 0xF2 0x00 0x25
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x08 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x11 - unit id (6)
+// 0x16 - function code (7)
+// 0x00 0x04 - register address (8,9)
+// 0x00 0xF2 - AND mask (10,11)
+// 0x00 0x25 - OR mask (12,13)
+type MaskWriteRegisterResponseTCP struct {
+	MBAPHeader
+	MaskWriteRegisterResponse
+}
+
+// MaskWriteRegisterResponseRTU is RTU Response for Mask Write Register (FC=22). Response is echo of the request.
+//
+// Example packet: 0x11 0x16 0x00 0x04 0x00 0xF2 0x00 0x25 0x66 0xE2
+// 0x11 - unit id (0)
+// 0x16 - function code (1)
+// 0x00 0x04 - register address (2,3)
+// 0x00 0xF2 - AND mask (4,5)
+// 0x00 0x25 - OR mask (6,7)
+// 0x66 0xE2 - CRC16 (8,9)
+type MaskWriteRegisterResponseRTU struct {
+	MaskWriteRegisterResponse
+}
+
+// MaskWriteRegisterResponse is Response for Mask Write Register (FC=22)
+type MaskWriteRegisterResponse struct {
+	UnitID  uint8
+	Address uint16
+	AndMask uint16
+	OrMask  uint16
+}
+
+// Bytes returns MaskWriteRegisterResponseTCP packet as bytes form
+func (r MaskWriteRegisterResponseTCP) Bytes() []byte {
+	length := uint16(8)
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.MaskWriteRegisterResponse.bytes(result[6 : 6+length])
+	return result
+}
+
+// ParseMaskWriteRegisterResponseTCP parses given bytes into MaskWriteRegisterResponseTCP
+func ParseMaskWriteRegisterResponseTCP(data []byte) (*MaskWriteRegisterResponseTCP, error) {
+	dLen := len(data)
+	if dLen < 14 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	pduLen := binary.BigEndian.Uint16(data[4:6])
+	if dLen != 6+int(pduLen) {
+		return nil, errors.New("received data length does not match PDU len in packet")
+	}
+
+	return &MaskWriteRegisterResponseTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: binary.BigEndian.Uint16(data[0:2]),
+			ProtocolID:    0,
+		},
+		MaskWriteRegisterResponse: MaskWriteRegisterResponse{
+			UnitID: data[6],
+			// data[7] function code
+			Address: binary.BigEndian.Uint16(data[8:10]),
+			AndMask: binary.BigEndian.Uint16(data[10:12]),
+			OrMask:  binary.BigEndian.Uint16(data[12:14]),
+		},
+	}, nil
+}
+
+// Bytes returns MaskWriteRegisterResponseRTU packet as bytes form
+func (r MaskWriteRegisterResponseRTU) Bytes() []byte {
+	result := make([]byte, 8+2)
+	bytes := r.MaskWriteRegisterResponse.bytes(result)
+	crc := CRC16(bytes[:8])
+	result[8] = uint8(crc)
+	result[9] = uint8(crc >> 8)
+	return result
+}
+
+// ParseMaskWriteRegisterResponseRTU parses given bytes into MaskWriteRegisterResponseRTU
+func ParseMaskWriteRegisterResponseRTU(data []byte) (*MaskWriteRegisterResponseRTU, error) {
+	dLen := len(data)
+	if dLen < 10 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	if dLen > 10 {
+		return nil, errors.New("received data length too long to be valid packet")
+	}
+	return &MaskWriteRegisterResponseRTU{
+		MaskWriteRegisterResponse: MaskWriteRegisterResponse{
+			UnitID: data[0],
+			// data[1] function code
+			Address: binary.BigEndian.Uint16(data[2:4]),
+			AndMask: binary.BigEndian.Uint16(data[4:6]),
+			OrMask:  binary.BigEndian.Uint16(data[6:8]),
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r MaskWriteRegisterResponse) FunctionCode() uint8 {
+	return FunctionMaskWriteRegister
+}
+
+// Bytes returns MaskWriteRegisterResponse packet as bytes form
+func (r MaskWriteRegisterResponse) Bytes() []byte {
+	return r.bytes(make([]byte, 8))
+}
+
+func (r MaskWriteRegisterResponse) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionMaskWriteRegister
+	binary.BigEndian.PutUint16(bytes[2:4], r.Address)
+	binary.BigEndian.PutUint16(bytes[4:6], r.AndMask)
+	binary.BigEndian.PutUint16(bytes[6:8], r.OrMask)
+	return bytes
+}
+
+// MaskWriteRegisterResponseASCII is ASCII Response for Mask Write Register (FC=22). Packet is RTU packet without CRC
+// encoded as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type MaskWriteRegisterResponseASCII struct {
+	MaskWriteRegisterResponseRTU
+}
+
+// Bytes returns MaskWriteRegisterResponseASCII packet as bytes form
+func (r MaskWriteRegisterResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.MaskWriteRegisterResponseRTU.Bytes())
+}
@@ -0,0 +1,101 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMaskWriteRegisterResponseTCP_Bytes(t *testing.T) {
+	given := MaskWriteRegisterResponseTCP{
+		MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+		MaskWriteRegisterResponse: MaskWriteRegisterResponse{
+			UnitID:  0x11,
+			Address: 0x0004,
+			AndMask: 0x00F2,
+			OrMask:  0x0025,
+		},
+	}
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25}, given.Bytes())
+	assert.Equal(t, FunctionMaskWriteRegister, given.FunctionCode())
+}
+
+func TestParseMaskWriteRegisterResponseTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *MaskWriteRegisterResponseTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25},
+			expect: &MaskWriteRegisterResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				MaskWriteRegisterResponse: MaskWriteRegisterResponse{
+					UnitID:  0x11,
+					Address: 0x0004,
+					AndMask: 0x00F2,
+					OrMask:  0x0025,
+				},
+			},
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x7, 0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0},
+			expectError: "received data length too short to be valid packet",
+		},
+		{
+			name:        "nok, pdu length does not match",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x9, 0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25},
+			expectError: "received data length does not match PDU len in packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseMaskWriteRegisterResponseTCP(tc.given)
+
+			assert.Equal(t, tc.expect, resp)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaskWriteRegisterResponseRTU(t *testing.T) {
+	given := []byte{0x11, 0x16, 0x0, 0x4, 0x0, 0xf2, 0x0, 0x25, 0x66, 0xe2}
+
+	resp, err := ParseMaskWriteRegisterResponseRTU(given)
+	assert.NoError(t, err)
+	assert.Equal(t, &MaskWriteRegisterResponseRTU{
+		MaskWriteRegisterResponse: MaskWriteRegisterResponse{
+			UnitID:  0x11,
+			Address: 0x0004,
+			AndMask: 0x00F2,
+			OrMask:  0x0025,
+		},
+	}, resp)
+	assert.Equal(t, given, resp.Bytes())
+
+	_, err = ParseMaskWriteRegisterResponseRTU(given[:9])
+	assert.EqualError(t, err, "received data length too short to be valid packet")
+
+	_, err = ParseMaskWriteRegisterResponseRTU(append(given, 0x0))
+	assert.EqualError(t, err, "received data length too long to be valid packet")
+}
+
+func TestMaskWriteRegisterResponseASCII(t *testing.T) {
+	resp := MaskWriteRegisterResponseASCII{
+		MaskWriteRegisterResponseRTU: MaskWriteRegisterResponseRTU{
+			MaskWriteRegisterResponse: MaskWriteRegisterResponse{UnitID: 0x11, Address: 0x0004, AndMask: 0x00F2, OrMask: 0x0025},
+		},
+	}
+
+	parsed, err := ParseASCIIResponse(resp.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, &resp, parsed)
+}
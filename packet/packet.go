@@ -13,6 +13,8 @@ const (
 	MaxRegistersInReadResponse = uint16(125)
 	// MaxCoilsInReadResponse is maximum quantity of discretes/coils that can be returned by read request (fc01/fc02)
 	MaxCoilsInReadResponse = uint16(2000) // 2000/8=250 bytes
+	// MaxRegistersInFIFOQueue is maximum quantity of registers that can be returned by Read FIFO Queue request (fc24)
+	MaxRegistersInFIFOQueue = uint16(31)
 )
 
 const (
@@ -28,27 +30,43 @@ const (
 	FunctionWriteSingleCoil = uint8(5) // 0x05
 	// FunctionWriteSingleRegister is function code for Write Single Register (FC06)
 	FunctionWriteSingleRegister = uint8(6) // 0x06
+	// FunctionReadExceptionStatus is function code for Read Exception Status (FC07)
+	FunctionReadExceptionStatus = uint8(7) // 0x07
+	// FunctionDiagnostics is function code for Diagnostics (FC08)
+	FunctionDiagnostics = uint8(8) // 0x08
 	// FunctionWriteMultipleCoils is function code for Write Multiple Coils (FC15)
 	FunctionWriteMultipleCoils = uint8(15) // 0x0f
 	// FunctionWriteMultipleRegisters is function code for Write Multiple Registers (FC16)
 	FunctionWriteMultipleRegisters = uint8(16) // 0x10
 	// FunctionReadServerID is function code for Read Server ID (FC16)
 	FunctionReadServerID = uint8(17) // 0x11
+	// FunctionMaskWriteRegister is function code for Mask Write Register (FC22)
+	FunctionMaskWriteRegister = uint8(22) // 0x16
 	// FunctionReadWriteMultipleRegisters is function code for Read / Write Multiple Registers (FC23)
 	FunctionReadWriteMultipleRegisters = uint8(23) // 0x17
+	// FunctionReadFIFOQueue is function code for Read FIFO Queue (FC24)
+	FunctionReadFIFOQueue = uint8(24) // 0x18
+	// FunctionEncapsulatedInterface is function code for Encapsulated Interface Transport (FC43). Read Device
+	// Identification (MEI type 14) and CANopen General Reference (MEI type 13) are supported.
+	FunctionEncapsulatedInterface = uint8(43) // 0x2b
 )
 
-var supportedFunctionCodes = [10]byte{
+var supportedFunctionCodes = [15]byte{
 	FunctionReadCoils,
 	FunctionReadDiscreteInputs,
 	FunctionReadHoldingRegisters,
 	FunctionReadInputRegisters,
 	FunctionWriteSingleCoil,
 	FunctionWriteSingleRegister,
+	FunctionReadExceptionStatus,
+	FunctionDiagnostics,
 	FunctionWriteMultipleCoils,
 	FunctionWriteMultipleRegisters,
 	FunctionReadServerID,
+	FunctionMaskWriteRegister,
 	FunctionReadWriteMultipleRegisters,
+	FunctionReadFIFOQueue,
+	FunctionEncapsulatedInterface,
 }
 
 // MBAPHeader (Modbus Application Header) is header part of modbus TCP packet. NB: this library does pack unitID into header
@@ -169,6 +187,29 @@ func putReadRequestBytes(dst []byte, unitID uint8, functionCode uint8, startAddr
 	binary.BigEndian.PutUint16(dst[4:6], quantity)
 }
 
+// ParseOption is option for packet parse functions
+type ParseOption func(o *parseOptions)
+
+type parseOptions struct {
+	lenientCoilsPadding bool
+}
+
+func newParseOptions(opts []ParseOption) parseOptions {
+	o := parseOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLenientCoilsPadding is parse option to allow non-zero padding bits in the last byte of coils data. Some devices
+// do not clear unused bits when sending coils.
+func WithLenientCoilsPadding() ParseOption {
+	return func(o *parseOptions) {
+		o.lenientCoilsPadding = true
+	}
+}
+
 // ErrInvalidCRC is error returned when packet data does not match its CRC value
 var ErrInvalidCRC = errors.New("packet cyclic redundancy check does not match Modbus RTU packet bytes")
 
@@ -180,6 +221,26 @@ var ErrInvalidCRC = errors.New("packet cyclic redundancy check does not match Mo
 // Example of frame in hexadecimal: 01 04 02 FF FF B8 80 (CRC-16-ANSI calculation from 01 to FF gives 80B8, which is transmitted least significant byte first).
 func CRC16(data []byte) uint16 {
 	crc := uint16(0xffff)
+	for _, b := range data {
+		crc = (crc >> 8) ^ crc16Table[byte(crc)^b]
+	}
+	return crc
+}
+
+// crc16Table is lookup table for CRC16 calculation. Table driven calculation processes byte at the time instead of
+// bit at the time and is considerably faster for high packet rates.
+var crc16Table = makeCRC16Table()
+
+func makeCRC16Table() [256]uint16 {
+	table := [256]uint16{}
+	for i := range table {
+		table[i] = crc16Bitwise([]byte{byte(i)}, 0)
+	}
+	return table
+}
+
+// crc16Bitwise calculates CRC16 bit by bit starting from given initial value
+func crc16Bitwise(data []byte, crc uint16) uint16 {
 	for _, b := range data {
 		crc ^= uint16(b)
 		for i := 0; i < 8; i++ {
@@ -190,6 +190,23 @@ func TestCRC16(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			result := CRC16(tc.when)
 			assert.Equal(t, tc.expect, result)
+
+			assert.Equal(t, tc.expect, crc16Bitwise(tc.when, 0xffff))
 		})
 	}
 }
+
+var benchmarkCRC16Frame = []byte{0x01, 0x03, 0x02, 0x00, 0x01, 0x00, 0x7d, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+func BenchmarkCRC16(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CRC16(benchmarkCRC16Frame)
+	}
+}
+
+func BenchmarkCRC16_bitwise(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		crc16Bitwise(benchmarkCRC16Frame, 0xffff)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
-	"math/rand"
 )
 
 // ReadCoilsRequestTCP is TCP Request for Read Coils function (FC=01)
@@ -50,7 +49,7 @@ func NewReadCoilsRequestTCP(unitID uint8, startAddress uint16, quantity uint16)
 
 	return &ReadCoilsRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		ReadCoilsRequest: ReadCoilsRequest{
@@ -139,8 +138,8 @@ func (r ReadCoilsRequestRTU) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadCoilsRequestRTU) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode + 2 coils byte count + N coils data
-	return 4 + r.coilByteLength()
+	// response = 1 UnitID + 1 functionCode + 1 coils byte count + N coils data + 2 CRC
+	return 5 + r.coilByteLength()
 }
 
 // ParseReadCoilsRequestRTU parses given bytes into ReadCoilsRequestRTU
@@ -192,3 +191,28 @@ func (r ReadCoilsRequest) bytes(bytes []byte) []byte {
 	putReadRequestBytes(bytes, r.UnitID, FunctionReadCoils, r.StartAddress, r.Quantity)
 	return bytes
 }
+
+// ReadCoilsRequestASCII is ASCII Request for Read Coils (FC=01). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadCoilsRequestASCII struct {
+	ReadCoilsRequestRTU
+}
+
+// NewReadCoilsRequestASCII creates new instance of Read Coils ASCII request
+func NewReadCoilsRequestASCII(unitID uint8, startAddress uint16, quantity uint16) (*ReadCoilsRequestASCII, error) {
+	r, err := NewReadCoilsRequestRTU(unitID, startAddress, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadCoilsRequestASCII{ReadCoilsRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadCoilsRequestASCII packet as bytes form
+func (r ReadCoilsRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadCoilsRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadCoilsRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadCoilsRequestRTU.ExpectedResponseLength())
+}
@@ -256,22 +256,22 @@ func TestReadCoilsRequestRTU_ExpectedResponseLength(t *testing.T) {
 		{
 			name:         "ok, 1 byte",
 			whenQuantity: 8,
-			expect:       4 + 1,
+			expect:       5 + 1,
 		},
 		{
 			name:         "ok, 2 bytes",
 			whenQuantity: 9,
-			expect:       4 + 2,
+			expect:       5 + 2,
 		},
 		{
 			name:         "ok, 11 bytes",
 			whenQuantity: 8*10 + 7,
-			expect:       4 + 11,
+			expect:       5 + 11,
 		},
 		{
 			name:         "ok, 253 bytes",
 			whenQuantity: 8 * 253,
-			expect:       4 + 253,
+			expect:       5 + 253,
 		},
 	}
 
@@ -131,3 +131,14 @@ func (r ReadCoilsResponse) bytes(data []byte) []byte {
 func (r ReadCoilsResponse) IsCoilSet(startAddress uint16, coilAddress uint16) (bool, error) {
 	return isBitSet(r.Data, startAddress, coilAddress)
 }
+
+// ReadCoilsResponseASCII is ASCII Response for Read Coils (FC=01). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadCoilsResponseASCII struct {
+	ReadCoilsResponseRTU
+}
+
+// Bytes returns ReadCoilsResponseASCII packet as bytes form
+func (r ReadCoilsResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadCoilsResponseRTU.Bytes())
+}
@@ -0,0 +1,256 @@
+package packet
+
+import "errors"
+
+// MEIReadDeviceIdentification is MEI (Modbus Encapsulated Interface) type for Read Device Identification (FC43/14)
+const MEIReadDeviceIdentification = uint8(0x0E)
+
+// Read Device ID codes for Read Device Identification (FC43/14) request
+const (
+	// ReadDeviceIDCodeBasic is request to stream basic device identification objects (0x00-0x02)
+	ReadDeviceIDCodeBasic = uint8(0x01)
+	// ReadDeviceIDCodeRegular is request to stream regular device identification objects (0x00-0x06)
+	ReadDeviceIDCodeRegular = uint8(0x02)
+	// ReadDeviceIDCodeExtended is request to stream extended device identification objects (0x00-0xFF)
+	ReadDeviceIDCodeExtended = uint8(0x03)
+	// ReadDeviceIDCodeSpecific is request to get one specific identification object (individual access)
+	ReadDeviceIDCodeSpecific = uint8(0x04)
+)
+
+// Device identification object IDs
+const (
+	// DeviceIDObjectVendorName is object ID of vendor name (basic category)
+	DeviceIDObjectVendorName = uint8(0x00)
+	// DeviceIDObjectProductCode is object ID of product code (basic category)
+	DeviceIDObjectProductCode = uint8(0x01)
+	// DeviceIDObjectMajorMinorRevision is object ID of major/minor revision (basic category)
+	DeviceIDObjectMajorMinorRevision = uint8(0x02)
+	// DeviceIDObjectVendorURL is object ID of vendor URL (regular category)
+	DeviceIDObjectVendorURL = uint8(0x03)
+	// DeviceIDObjectProductName is object ID of product name (regular category)
+	DeviceIDObjectProductName = uint8(0x04)
+	// DeviceIDObjectModelName is object ID of model name (regular category)
+	DeviceIDObjectModelName = uint8(0x05)
+	// DeviceIDObjectUserApplicationName is object ID of user application name (regular category)
+	DeviceIDObjectUserApplicationName = uint8(0x06)
+)
+
+// ReadDeviceIdentificationRequestTCP is TCP Request for Read Device Identification (FC=43, MEI type=14)
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x05 0x10 0x2B 0x0E 0x01 0x00
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x05 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x10 - unit id (6)
+// 0x2B - function code (7)
+// 0x0E - MEI type (8)
+// 0x01 - read device id code (9)
+// 0x00 - object id (10)
+type ReadDeviceIdentificationRequestTCP struct {
+	MBAPHeader
+	ReadDeviceIdentificationRequest
+}
+
+// ReadDeviceIdentificationRequestRTU is RTU Request for Read Device Identification (FC=43, MEI type=14)
+//
+// Example packet: 0x10 0x2B 0x0E 0x01 0x00 0x8c 0x74
+// 0x10 - unit id (0)
+// 0x2B - function code (1)
+// 0x0E - MEI type (2)
+// 0x01 - read device id code (3)
+// 0x00 - object id (4)
+// 0x8c 0x74 - CRC16 (5,6)
+type ReadDeviceIdentificationRequestRTU struct {
+	ReadDeviceIdentificationRequest
+}
+
+// ReadDeviceIdentificationRequest is Request for Read Device Identification (FC=43, MEI type=14)
+type ReadDeviceIdentificationRequest struct {
+	UnitID uint8
+	// ReadDeviceIDCode is access type (ReadDeviceIDCodeBasic, ReadDeviceIDCodeRegular, ReadDeviceIDCodeExtended or
+	// ReadDeviceIDCodeSpecific)
+	ReadDeviceIDCode uint8
+	// ObjectID is object to start streaming from or object to read with individual access
+	ObjectID uint8
+}
+
+// NewReadDeviceIdentificationRequestTCP creates new instance of Read Device Identification TCP request
+func NewReadDeviceIdentificationRequestTCP(unitID uint8, readDeviceIDCode uint8, objectID uint8) (*ReadDeviceIdentificationRequestTCP, error) {
+	if err := checkReadDeviceIDCode(readDeviceIDCode); err != nil {
+		return nil, err
+	}
+	return &ReadDeviceIdentificationRequestTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: RandomTransactionID(),
+			ProtocolID:    0,
+		},
+		ReadDeviceIdentificationRequest: ReadDeviceIdentificationRequest{
+			UnitID:           unitID,
+			ReadDeviceIDCode: readDeviceIDCode,
+			ObjectID:         objectID,
+		},
+	}, nil
+}
+
+// Bytes returns ReadDeviceIdentificationRequestTCP packet as bytes form
+func (r ReadDeviceIdentificationRequestTCP) Bytes() []byte {
+	length := uint16(5)
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.ReadDeviceIdentificationRequest.bytes(result[6 : 6+length])
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadDeviceIdentificationRequestTCP) ExpectedResponseLength() int {
+	// response = 6 header len + 1 unitID + 1 fc + 1 MEI type + 1 read device id code + 1 conformity level +
+	// 1 more follows + 1 next object id + 1 number of objects + N objects
+	// response length is variable, so this is minimal valid response length
+	return 6 + 8
+}
+
+// ParseReadDeviceIdentificationRequestTCP parses given bytes into ReadDeviceIdentificationRequestTCP
+func ParseReadDeviceIdentificationRequestTCP(data []byte) (*ReadDeviceIdentificationRequestTCP, error) {
+	header, err := ParseMBAPHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 11 {
+		return nil, NewErrorParseTCP(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[6]
+	if data[7] != FunctionEncapsulatedInterface || data[8] != MEIReadDeviceIdentification {
+		tmpErr := NewErrorParseTCP(ErrIllegalFunction, "received function code in packet is not 0x2B/0x0E")
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionEncapsulatedInterface
+		return nil, tmpErr
+	}
+	if err := checkReadDeviceIDCode(data[9]); err != nil {
+		tmpErr := NewErrorParseTCP(ErrIllegalDataValue, err.Error())
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionEncapsulatedInterface
+		return nil, tmpErr
+	}
+	return &ReadDeviceIdentificationRequestTCP{
+		MBAPHeader: header,
+		ReadDeviceIdentificationRequest: ReadDeviceIdentificationRequest{
+			UnitID:           unitID,
+			ReadDeviceIDCode: data[9],
+			ObjectID:         data[10],
+		},
+	}, nil
+}
+
+// NewReadDeviceIdentificationRequestRTU creates new instance of Read Device Identification RTU request
+func NewReadDeviceIdentificationRequestRTU(unitID uint8, readDeviceIDCode uint8, objectID uint8) (*ReadDeviceIdentificationRequestRTU, error) {
+	if err := checkReadDeviceIDCode(readDeviceIDCode); err != nil {
+		return nil, err
+	}
+	return &ReadDeviceIdentificationRequestRTU{
+		ReadDeviceIdentificationRequest: ReadDeviceIdentificationRequest{
+			UnitID:           unitID,
+			ReadDeviceIDCode: readDeviceIDCode,
+			ObjectID:         objectID,
+		},
+	}, nil
+}
+
+// Bytes returns ReadDeviceIdentificationRequestRTU packet as bytes form
+func (r ReadDeviceIdentificationRequestRTU) Bytes() []byte {
+	result := make([]byte, 5+2)
+	bytes := r.ReadDeviceIdentificationRequest.bytes(result)
+	crc := CRC16(bytes[:5])
+	result[5] = uint8(crc)
+	result[6] = uint8(crc >> 8)
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadDeviceIdentificationRequestRTU) ExpectedResponseLength() int {
+	// response = 1 UnitID + 1 functionCode + 1 MEI type + 1 read device id code + 1 conformity level +
+	// 1 more follows + 1 next object id + 1 number of objects + N objects + 2 CRC
+	// response length is variable, so this is minimal valid response length
+	return 8 + 2
+}
+
+// ParseReadDeviceIdentificationRequestRTU parses given bytes into ReadDeviceIdentificationRequestRTU
+// Does not check CRC
+func ParseReadDeviceIdentificationRequestRTU(data []byte) (*ReadDeviceIdentificationRequestRTU, error) {
+	dLen := len(data)
+	if dLen != 7 && dLen != 5 { // with or without CRC bytes
+		return nil, NewErrorParseRTU(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[0]
+	if data[1] != FunctionEncapsulatedInterface || data[2] != MEIReadDeviceIdentification {
+		tmpErr := NewErrorParseRTU(ErrIllegalFunction, "received function code in packet is not 0x2B/0x0E")
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionEncapsulatedInterface
+		return nil, tmpErr
+	}
+	if err := checkReadDeviceIDCode(data[3]); err != nil {
+		tmpErr := NewErrorParseRTU(ErrIllegalDataValue, err.Error())
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionEncapsulatedInterface
+		return nil, tmpErr
+	}
+	return &ReadDeviceIdentificationRequestRTU{
+		ReadDeviceIdentificationRequest: ReadDeviceIdentificationRequest{
+			UnitID:           unitID,
+			ReadDeviceIDCode: data[3],
+			ObjectID:         data[4],
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r ReadDeviceIdentificationRequest) FunctionCode() uint8 {
+	return FunctionEncapsulatedInterface
+}
+
+// Bytes returns ReadDeviceIdentificationRequest packet as bytes form
+func (r ReadDeviceIdentificationRequest) Bytes() []byte {
+	return r.bytes(make([]byte, 5))
+}
+
+func (r ReadDeviceIdentificationRequest) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionEncapsulatedInterface
+	bytes[2] = MEIReadDeviceIdentification
+	bytes[3] = r.ReadDeviceIDCode
+	bytes[4] = r.ObjectID
+	return bytes
+}
+
+func checkReadDeviceIDCode(code uint8) error {
+	if code < ReadDeviceIDCodeBasic || code > ReadDeviceIDCodeSpecific {
+		return errors.New("read device id code is out of range (1-4)")
+	}
+	return nil
+}
+
+// ReadDeviceIdentificationRequestASCII is ASCII Request for Read Device Identification (FC=43, MEI type=14). Packet is
+// RTU packet without CRC encoded as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadDeviceIdentificationRequestASCII struct {
+	ReadDeviceIdentificationRequestRTU
+}
+
+// NewReadDeviceIdentificationRequestASCII creates new instance of Read Device Identification ASCII request
+func NewReadDeviceIdentificationRequestASCII(unitID uint8, readDeviceIDCode uint8, objectID uint8) (*ReadDeviceIdentificationRequestASCII, error) {
+	r, err := NewReadDeviceIdentificationRequestRTU(unitID, readDeviceIDCode, objectID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadDeviceIdentificationRequestASCII{ReadDeviceIdentificationRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadDeviceIdentificationRequestASCII packet as bytes form
+func (r ReadDeviceIdentificationRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadDeviceIdentificationRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadDeviceIdentificationRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadDeviceIdentificationRequestRTU.ExpectedResponseLength())
+}
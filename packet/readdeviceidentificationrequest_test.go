@@ -0,0 +1,93 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewReadDeviceIdentificationRequestTCP(t *testing.T) {
+	req, err := NewReadDeviceIdentificationRequestTCP(0x10, ReadDeviceIDCodeBasic, DeviceIDObjectVendorName)
+	assert.NoError(t, err)
+	req.TransactionID = 0x8180
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x5, 0x10, 0x2b, 0xe, 0x1, 0x0}, req.Bytes())
+	assert.Equal(t, 14, req.ExpectedResponseLength())
+	assert.Equal(t, FunctionEncapsulatedInterface, req.FunctionCode())
+
+	_, err = NewReadDeviceIdentificationRequestTCP(0x10, 5, 0)
+	assert.EqualError(t, err, "read device id code is out of range (1-4)")
+}
+
+func TestParseReadDeviceIdentificationRequestTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *ReadDeviceIdentificationRequestTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x5, 0x10, 0x2b, 0xe, 0x2, 0x3},
+			expect: &ReadDeviceIdentificationRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				ReadDeviceIdentificationRequest: ReadDeviceIdentificationRequest{
+					UnitID:           0x10,
+					ReadDeviceIDCode: ReadDeviceIDCodeRegular,
+					ObjectID:         DeviceIDObjectVendorURL,
+				},
+			},
+		},
+		{
+			name:        "nok, invalid MEI type",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x5, 0x10, 0x2b, 0xd, 0x2, 0x3},
+			expectError: "received function code in packet is not 0x2B/0x0E",
+		},
+		{
+			name:        "nok, invalid read device id code",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x5, 0x10, 0x2b, 0xe, 0x0, 0x3},
+			expectError: "read device id code is out of range (1-4)",
+		},
+		{
+			name:        "nok, invalid length",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x4, 0x10, 0x2b, 0xe, 0x2},
+			expectError: "invalid data length to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseReadDeviceIdentificationRequestTCP(tc.given)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadDeviceIdentificationRequestRTU(t *testing.T) {
+	req, err := NewReadDeviceIdentificationRequestRTU(0x10, ReadDeviceIDCodeBasic, DeviceIDObjectVendorName)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte{0x10, 0x2b, 0xe, 0x1, 0x0, 0x8c, 0x74}, req.Bytes())
+	assert.Equal(t, 10, req.ExpectedResponseLength())
+
+	parsed, err := ParseReadDeviceIdentificationRequestRTU(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+
+	_, err = ParseReadDeviceIdentificationRequestRTU([]byte{0x10, 0x2b, 0xe, 0x1})
+	assert.EqualError(t, err, "invalid data length to be valid packet")
+}
+
+func TestReadDeviceIdentificationRequestASCII(t *testing.T) {
+	req, err := NewReadDeviceIdentificationRequestASCII(0x10, ReadDeviceIDCodeBasic, DeviceIDObjectVendorName)
+	assert.NoError(t, err)
+
+	parsed, err := ParseASCIIRequest(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+}
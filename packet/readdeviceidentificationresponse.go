@@ -0,0 +1,209 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ReadDeviceIdentificationResponseTCP is TCP Response for Read Device Identification (FC=43, MEI type=14)
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x0D 0x10 0x2B 0x0E 0x01 0x01 0x00 0x00 0x01 0x00 0x03 0x41 0x42 0x43
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x0D - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x10 - unit id (6)
+// 0x2B - function code (7)
+// 0x0E - MEI type (8)
+// 0x01 - read device id code (9)
+// 0x01 - conformity level (10)
+// 0x00 - more follows (11), 0xFF when there are more objects to request
+// 0x00 - next object id (12)
+// 0x01 - number of objects (13)
+// 0x00 - object id (14)
+// 0x03 - object length (15)
+// 0x41 0x42 0x43 - object value (16,17,18)
+type ReadDeviceIdentificationResponseTCP struct {
+	MBAPHeader
+	ReadDeviceIdentificationResponse
+}
+
+// ReadDeviceIdentificationResponseRTU is RTU Response for Read Device Identification (FC=43, MEI type=14)
+//
+// Example packet: 0x10 0x2B 0x0E 0x01 0x01 0x00 0x00 0x01 0x00 0x03 0x41 0x42 0x43 0x11 0x72
+// 0x10 - unit id (0)
+// 0x2B - function code (1)
+// 0x0E - MEI type (2)
+// 0x01 - read device id code (3)
+// 0x01 - conformity level (4)
+// 0x00 - more follows (5), 0xFF when there are more objects to request
+// 0x00 - next object id (6)
+// 0x01 - number of objects (7)
+// 0x00 - object id (8)
+// 0x03 - object length (9)
+// 0x41 0x42 0x43 - object value (10,11,12)
+// 0x11 0x72 - CRC16 (n-2,n-1)
+type ReadDeviceIdentificationResponseRTU struct {
+	ReadDeviceIdentificationResponse
+}
+
+// ReadDeviceIdentificationResponse is Response for Read Device Identification (FC=43, MEI type=14)
+type ReadDeviceIdentificationResponse struct {
+	UnitID           uint8
+	ReadDeviceIDCode uint8
+	// ConformityLevel is identification conformity level of the device (0x01 basic, 0x02 regular, 0x03 extended).
+	// Values 0x81, 0x82 and 0x83 mean that device supports also individual access to objects.
+	ConformityLevel uint8
+	// MoreFollows is true when objects did not fit into single response and next request should start from NextObjectID
+	MoreFollows  bool
+	NextObjectID uint8
+	Objects      []DeviceIdentificationObject
+}
+
+// DeviceIdentificationObject is single device identification object
+type DeviceIdentificationObject struct {
+	ID    uint8
+	Value []byte
+}
+
+// Bytes returns ReadDeviceIdentificationResponseTCP packet as bytes form
+func (r ReadDeviceIdentificationResponseTCP) Bytes() []byte {
+	length := r.ReadDeviceIdentificationResponse.len()
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.ReadDeviceIdentificationResponse.bytes(result[6:])
+	return result
+}
+
+// ParseReadDeviceIdentificationResponseTCP parses given bytes into ReadDeviceIdentificationResponseTCP
+func ParseReadDeviceIdentificationResponseTCP(data []byte) (*ReadDeviceIdentificationResponseTCP, error) {
+	dLen := len(data)
+	if dLen < 14 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	pduLen := binary.BigEndian.Uint16(data[4:6])
+	if dLen != 6+int(pduLen) {
+		return nil, errors.New("received data length does not match PDU len in packet")
+	}
+	resp, err := parseReadDeviceIdentificationResponse(data[6:])
+	if err != nil {
+		return nil, err
+	}
+	return &ReadDeviceIdentificationResponseTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: binary.BigEndian.Uint16(data[0:2]),
+			ProtocolID:    0,
+		},
+		ReadDeviceIdentificationResponse: resp,
+	}, nil
+}
+
+// Bytes returns ReadDeviceIdentificationResponseRTU packet as bytes form
+func (r ReadDeviceIdentificationResponseRTU) Bytes() []byte {
+	length := r.ReadDeviceIdentificationResponse.len()
+	result := make([]byte, length+2)
+	bytes := r.ReadDeviceIdentificationResponse.bytes(result)
+	crc := CRC16(bytes[:length])
+	result[length] = uint8(crc)
+	result[length+1] = uint8(crc >> 8)
+	return result
+}
+
+// ParseReadDeviceIdentificationResponseRTU parses given bytes into ReadDeviceIdentificationResponseRTU
+func ParseReadDeviceIdentificationResponseRTU(data []byte) (*ReadDeviceIdentificationResponseRTU, error) {
+	dLen := len(data)
+	if dLen < 10 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	resp, err := parseReadDeviceIdentificationResponse(data[:dLen-2])
+	if err != nil {
+		return nil, err
+	}
+	return &ReadDeviceIdentificationResponseRTU{ReadDeviceIdentificationResponse: resp}, nil
+}
+
+// parseReadDeviceIdentificationResponse parses response PDU starting from unit ID
+func parseReadDeviceIdentificationResponse(data []byte) (ReadDeviceIdentificationResponse, error) {
+	if data[2] != MEIReadDeviceIdentification {
+		return ReadDeviceIdentificationResponse{}, errors.New("received MEI type in packet is not 0x0E")
+	}
+	result := ReadDeviceIdentificationResponse{
+		UnitID: data[0],
+		// data[1] function code
+		// data[2] MEI type
+		ReadDeviceIDCode: data[3],
+		ConformityLevel:  data[4],
+		MoreFollows:      data[5] == 0xFF,
+		NextObjectID:     data[6],
+	}
+	objectCount := int(data[7])
+	result.Objects = make([]DeviceIdentificationObject, 0, objectCount)
+	i := 8
+	for n := 0; n < objectCount; n++ {
+		if i+2 > len(data) {
+			return ReadDeviceIdentificationResponse{}, errors.New("received data length too short to contain all objects")
+		}
+		objectLen := int(data[i+1])
+		if i+2+objectLen > len(data) {
+			return ReadDeviceIdentificationResponse{}, errors.New("received data length too short to contain all objects")
+		}
+		value := make([]byte, objectLen)
+		copy(value, data[i+2:i+2+objectLen])
+		result.Objects = append(result.Objects, DeviceIdentificationObject{ID: data[i], Value: value})
+		i += 2 + objectLen
+	}
+	if i != len(data) {
+		return ReadDeviceIdentificationResponse{}, errors.New("received data length too long to be valid packet")
+	}
+	return result, nil
+}
+
+// FunctionCode returns function code of this request
+func (r ReadDeviceIdentificationResponse) FunctionCode() uint8 {
+	return FunctionEncapsulatedInterface
+}
+
+// Bytes returns ReadDeviceIdentificationResponse packet as bytes form
+func (r ReadDeviceIdentificationResponse) Bytes() []byte {
+	return r.bytes(make([]byte, r.len()))
+}
+
+func (r ReadDeviceIdentificationResponse) len() uint16 {
+	length := uint16(8)
+	for _, o := range r.Objects {
+		length += 2 + uint16(len(o.Value))
+	}
+	return length
+}
+
+func (r ReadDeviceIdentificationResponse) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionEncapsulatedInterface
+	bytes[2] = MEIReadDeviceIdentification
+	bytes[3] = r.ReadDeviceIDCode
+	bytes[4] = r.ConformityLevel
+	bytes[5] = 0x00
+	if r.MoreFollows {
+		bytes[5] = 0xFF
+	}
+	bytes[6] = r.NextObjectID
+	bytes[7] = uint8(len(r.Objects))
+	i := 8
+	for _, o := range r.Objects {
+		bytes[i] = o.ID
+		bytes[i+1] = uint8(len(o.Value))
+		copy(bytes[i+2:], o.Value)
+		i += 2 + len(o.Value)
+	}
+	return bytes
+}
+
+// ReadDeviceIdentificationResponseASCII is ASCII Response for Read Device Identification (FC=43, MEI type=14). Packet
+// is RTU packet without CRC encoded as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadDeviceIdentificationResponseASCII struct {
+	ReadDeviceIdentificationResponseRTU
+}
+
+// Bytes returns ReadDeviceIdentificationResponseASCII packet as bytes form
+func (r ReadDeviceIdentificationResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadDeviceIdentificationResponseRTU.Bytes())
+}
@@ -0,0 +1,100 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseReadDeviceIdentificationResponseTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *ReadDeviceIdentificationResponseTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xd, 0x10, 0x2b, 0xe, 0x1, 0x1, 0x0, 0x0, 0x1, 0x0, 0x3, 0x41, 0x42, 0x43},
+			expect: &ReadDeviceIdentificationResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				ReadDeviceIdentificationResponse: ReadDeviceIdentificationResponse{
+					UnitID:           0x10,
+					ReadDeviceIDCode: ReadDeviceIDCodeBasic,
+					ConformityLevel:  0x1,
+					Objects:          []DeviceIdentificationObject{{ID: 0, Value: []byte("ABC")}},
+				},
+			},
+		},
+		{
+			name:  "ok, more follows",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xd, 0x10, 0x2b, 0xe, 0x1, 0x81, 0xff, 0x1, 0x1, 0x0, 0x3, 0x41, 0x42, 0x43},
+			expect: &ReadDeviceIdentificationResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				ReadDeviceIdentificationResponse: ReadDeviceIdentificationResponse{
+					UnitID:           0x10,
+					ReadDeviceIDCode: ReadDeviceIDCodeBasic,
+					ConformityLevel:  0x81,
+					MoreFollows:      true,
+					NextObjectID:     1,
+					Objects:          []DeviceIdentificationObject{{ID: 0, Value: []byte("ABC")}},
+				},
+			},
+		},
+		{
+			name:        "nok, object does not fit",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xd, 0x10, 0x2b, 0xe, 0x1, 0x1, 0x0, 0x0, 0x1, 0x0, 0x4, 0x41, 0x42, 0x43},
+			expectError: "received data length too short to contain all objects",
+		},
+		{
+			name:        "nok, extra bytes",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xd, 0x10, 0x2b, 0xe, 0x1, 0x1, 0x0, 0x0, 0x1, 0x0, 0x2, 0x41, 0x42, 0x43},
+			expectError: "received data length too long to be valid packet",
+		},
+		{
+			name:        "nok, invalid MEI type",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x8, 0x10, 0x2b, 0xd, 0x1, 0x1, 0x0, 0x0, 0x0},
+			expectError: "received MEI type in packet is not 0x0E",
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x7, 0x10, 0x2b, 0xe, 0x1, 0x1, 0x0, 0x0},
+			expectError: "received data length too short to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseReadDeviceIdentificationResponseTCP(tc.given)
+
+			assert.Equal(t, tc.expect, resp)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.given, resp.Bytes())
+			}
+		})
+	}
+}
+
+func TestParseReadDeviceIdentificationResponseRTU(t *testing.T) {
+	given := []byte{0x10, 0x2b, 0xe, 0x1, 0x1, 0x0, 0x0, 0x1, 0x0, 0x3, 0x41, 0x42, 0x43, 0x11, 0x72}
+
+	resp, err := ParseReadDeviceIdentificationResponseRTU(given)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ReadDeviceIdentificationResponseRTU{
+		ReadDeviceIdentificationResponse: ReadDeviceIdentificationResponse{
+			UnitID:           0x10,
+			ReadDeviceIDCode: ReadDeviceIDCodeBasic,
+			ConformityLevel:  0x1,
+			Objects:          []DeviceIdentificationObject{{ID: 0, Value: []byte("ABC")}},
+		},
+	}, resp)
+	assert.Equal(t, given, resp.Bytes())
+	assert.Equal(t, FunctionEncapsulatedInterface, resp.FunctionCode())
+
+	parsed, err := ParseRTUResponseWithCRC(given)
+	assert.NoError(t, err)
+	assert.Equal(t, resp, parsed)
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
-	"math/rand"
 )
 
 // ReadDiscreteInputsRequestTCP is TCP Request for Read Discrete Inputs (FC=02)
@@ -50,7 +49,7 @@ func NewReadDiscreteInputsRequestTCP(unitID uint8, startAddress uint16, quantity
 
 	return &ReadDiscreteInputsRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		ReadDiscreteInputsRequest: ReadDiscreteInputsRequest{
@@ -139,8 +138,8 @@ func (r ReadDiscreteInputsRequestRTU) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadDiscreteInputsRequestRTU) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode + 2 coils byte count + N coils data
-	return 4 + r.coilByteLength()
+	// response = 1 UnitID + 1 functionCode + 1 coils byte count + N coils data + 2 CRC
+	return 5 + r.coilByteLength()
 }
 
 // ParseReadDiscreteInputsRequestRTU parses given bytes into ReadDiscreteInputsRequestRTU
@@ -191,3 +190,28 @@ func (r ReadDiscreteInputsRequest) bytes(bytes []byte) []byte {
 	putReadRequestBytes(bytes, r.UnitID, FunctionReadDiscreteInputs, r.StartAddress, r.Quantity)
 	return bytes
 }
+
+// ReadDiscreteInputsRequestASCII is ASCII Request for Read Discrete Inputs (FC=02). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadDiscreteInputsRequestASCII struct {
+	ReadDiscreteInputsRequestRTU
+}
+
+// NewReadDiscreteInputsRequestASCII creates new instance of Read Discrete Inputs ASCII request
+func NewReadDiscreteInputsRequestASCII(unitID uint8, startAddress uint16, quantity uint16) (*ReadDiscreteInputsRequestASCII, error) {
+	r, err := NewReadDiscreteInputsRequestRTU(unitID, startAddress, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadDiscreteInputsRequestASCII{ReadDiscreteInputsRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadDiscreteInputsRequestASCII packet as bytes form
+func (r ReadDiscreteInputsRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadDiscreteInputsRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadDiscreteInputsRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadDiscreteInputsRequestRTU.ExpectedResponseLength())
+}
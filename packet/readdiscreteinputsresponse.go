@@ -136,3 +136,14 @@ func (r ReadDiscreteInputsResponse) IsInputSet(startAddress uint16, inputAddress
 func (r ReadDiscreteInputsResponse) IsCoilSet(startAddress uint16, inputAddress uint16) (bool, error) {
 	return r.IsInputSet(startAddress, inputAddress)
 }
+
+// ReadDiscreteInputsResponseASCII is ASCII Response for Read Discrete Inputs (FC=02). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadDiscreteInputsResponseASCII struct {
+	ReadDiscreteInputsResponseRTU
+}
+
+// Bytes returns ReadDiscreteInputsResponseASCII packet as bytes form
+func (r ReadDiscreteInputsResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadDiscreteInputsResponseRTU.Bytes())
+}
@@ -0,0 +1,77 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewReadExceptionStatusRequestTCP(t *testing.T) {
+	req, err := NewReadExceptionStatusRequestTCP(0x11)
+	assert.NoError(t, err)
+	req.TransactionID = 0x8180
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x2, 0x11, 0x7}, req.Bytes())
+	assert.Equal(t, 9, req.ExpectedResponseLength())
+	assert.Equal(t, FunctionReadExceptionStatus, req.FunctionCode())
+}
+
+func TestParseReadExceptionStatusRequestTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *ReadExceptionStatusRequestTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x2, 0x11, 0x7},
+			expect: &ReadExceptionStatusRequestTCP{
+				MBAPHeader:                 MBAPHeader{TransactionID: 0x8180},
+				ReadExceptionStatusRequest: ReadExceptionStatusRequest{UnitID: 0x11},
+			},
+		},
+		{
+			name:        "nok, invalid function code",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x2, 0x11, 0x8},
+			expectError: "received function code in packet is not 0x07",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseReadExceptionStatusRequestTCP(tc.given)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadExceptionStatusRequestRTU(t *testing.T) {
+	req, err := NewReadExceptionStatusRequestRTU(0x11)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte{0x11, 0x7, 0x4c, 0x22}, req.Bytes())
+	assert.Equal(t, 5, req.ExpectedResponseLength())
+
+	parsed, err := ParseReadExceptionStatusRequestRTU(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+
+	_, err = ParseReadExceptionStatusRequestRTU([]byte{0x11, 0x7, 0x4c})
+	assert.EqualError(t, err, "invalid data length to be valid packet")
+}
+
+func TestReadExceptionStatusRequestASCII(t *testing.T) {
+	req, err := NewReadExceptionStatusRequestASCII(0x11)
+	assert.NoError(t, err)
+	assert.Equal(t, asciiLength(5), req.ExpectedResponseLength())
+
+	parsed, err := ParseASCIIRequest(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+}
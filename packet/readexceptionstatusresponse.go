@@ -0,0 +1,131 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ReadExceptionStatusResponseTCP is TCP Response for Read Exception Status (FC=07)
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x03 0x11 0x07 0x6D
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x03 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x11 - unit id (6)
+// 0x07 - function code (7)
+// 0x6D - output data, status of 8 exception status outputs (8)
+type ReadExceptionStatusResponseTCP struct {
+	MBAPHeader
+	ReadExceptionStatusResponse
+}
+
+// ReadExceptionStatusResponseRTU is RTU Response for Read Exception Status (FC=07)
+//
+// Example packet: 0x11 0x07 0x6D 0xE2 0x18
+// 0x11 - unit id (0)
+// 0x07 - function code (1)
+// 0x6D - output data, status of 8 exception status outputs (2)
+// 0xE2 0x18 - CRC16 (3,4)
+type ReadExceptionStatusResponseRTU struct {
+	ReadExceptionStatusResponse
+}
+
+// ReadExceptionStatusResponse is Response for Read Exception Status (FC=07)
+type ReadExceptionStatusResponse struct {
+	UnitID uint8
+	// Status contains 8 exception status outputs. Meaning of the bits is device specific.
+	Status uint8
+}
+
+// Bytes returns ReadExceptionStatusResponseTCP packet as bytes form
+func (r ReadExceptionStatusResponseTCP) Bytes() []byte {
+	length := uint16(3)
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.ReadExceptionStatusResponse.bytes(result[6 : 6+length])
+	return result
+}
+
+// ParseReadExceptionStatusResponseTCP parses given bytes into ReadExceptionStatusResponseTCP
+func ParseReadExceptionStatusResponseTCP(data []byte) (*ReadExceptionStatusResponseTCP, error) {
+	dLen := len(data)
+	if dLen < 9 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	pduLen := binary.BigEndian.Uint16(data[4:6])
+	if dLen != 6+int(pduLen) {
+		return nil, errors.New("received data length does not match PDU len in packet")
+	}
+	return &ReadExceptionStatusResponseTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: binary.BigEndian.Uint16(data[0:2]),
+			ProtocolID:    0,
+		},
+		ReadExceptionStatusResponse: ReadExceptionStatusResponse{
+			UnitID: data[6],
+			// data[7] function code
+			Status: data[8],
+		},
+	}, nil
+}
+
+// Bytes returns ReadExceptionStatusResponseRTU packet as bytes form
+func (r ReadExceptionStatusResponseRTU) Bytes() []byte {
+	result := make([]byte, 3+2)
+	bytes := r.ReadExceptionStatusResponse.bytes(result)
+	crc := CRC16(bytes[:3])
+	result[3] = uint8(crc)
+	result[4] = uint8(crc >> 8)
+	return result
+}
+
+// ParseReadExceptionStatusResponseRTU parses given bytes into ReadExceptionStatusResponseRTU
+func ParseReadExceptionStatusResponseRTU(data []byte) (*ReadExceptionStatusResponseRTU, error) {
+	dLen := len(data)
+	if dLen < 5 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	if dLen > 5 {
+		return nil, errors.New("received data length too long to be valid packet")
+	}
+	return &ReadExceptionStatusResponseRTU{
+		ReadExceptionStatusResponse: ReadExceptionStatusResponse{
+			UnitID: data[0],
+			// data[1] function code
+			Status: data[2],
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r ReadExceptionStatusResponse) FunctionCode() uint8 {
+	return FunctionReadExceptionStatus
+}
+
+// Bytes returns ReadExceptionStatusResponse packet as bytes form
+func (r ReadExceptionStatusResponse) Bytes() []byte {
+	return r.bytes(make([]byte, 3))
+}
+
+func (r ReadExceptionStatusResponse) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionReadExceptionStatus
+	bytes[2] = r.Status
+	return bytes
+}
+
+// IsSet returns true when exception status output (0-7) is set
+func (r ReadExceptionStatusResponse) IsSet(output uint8) bool {
+	return output < 8 && r.Status&(1<<output) != 0
+}
+
+// ReadExceptionStatusResponseASCII is ASCII Response for Read Exception Status (FC=07). Packet is RTU packet without
+// CRC encoded as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadExceptionStatusResponseASCII struct {
+	ReadExceptionStatusResponseRTU
+}
+
+// Bytes returns ReadExceptionStatusResponseASCII packet as bytes form
+func (r ReadExceptionStatusResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadExceptionStatusResponseRTU.Bytes())
+}
@@ -0,0 +1,96 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseReadExceptionStatusResponseTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *ReadExceptionStatusResponseTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x3, 0x11, 0x7, 0x6d},
+			expect: &ReadExceptionStatusResponseTCP{
+				MBAPHeader:                  MBAPHeader{TransactionID: 0x8180},
+				ReadExceptionStatusResponse: ReadExceptionStatusResponse{UnitID: 0x11, Status: 0x6d},
+			},
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x2, 0x11, 0x7},
+			expectError: "received data length too short to be valid packet",
+		},
+		{
+			name:        "nok, pdu length does not match",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x4, 0x11, 0x7, 0x6d},
+			expectError: "received data length does not match PDU len in packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseReadExceptionStatusResponseTCP(tc.given)
+
+			assert.Equal(t, tc.expect, resp)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadExceptionStatusResponseTCP_Bytes(t *testing.T) {
+	given := ReadExceptionStatusResponseTCP{
+		MBAPHeader:                  MBAPHeader{TransactionID: 0x8180},
+		ReadExceptionStatusResponse: ReadExceptionStatusResponse{UnitID: 0x11, Status: 0x6d},
+	}
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x3, 0x11, 0x7, 0x6d}, given.Bytes())
+	assert.Equal(t, FunctionReadExceptionStatus, given.FunctionCode())
+}
+
+func TestReadExceptionStatusResponseRTU(t *testing.T) {
+	given := []byte{0x11, 0x7, 0x6d, 0xe2, 0x18}
+
+	resp, err := ParseReadExceptionStatusResponseRTU(given)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReadExceptionStatusResponseRTU{
+		ReadExceptionStatusResponse: ReadExceptionStatusResponse{UnitID: 0x11, Status: 0x6d},
+	}, resp)
+	assert.Equal(t, given, resp.Bytes())
+
+	_, err = ParseReadExceptionStatusResponseRTU(given[:4])
+	assert.EqualError(t, err, "received data length too short to be valid packet")
+
+	_, err = ParseReadExceptionStatusResponseRTU(append(given, 0x0))
+	assert.EqualError(t, err, "received data length too long to be valid packet")
+}
+
+func TestReadExceptionStatusResponse_IsSet(t *testing.T) {
+	resp := ReadExceptionStatusResponse{Status: 0b0110_1101}
+
+	assert.True(t, resp.IsSet(0))
+	assert.False(t, resp.IsSet(1))
+	assert.True(t, resp.IsSet(6))
+	assert.False(t, resp.IsSet(7))
+	assert.False(t, resp.IsSet(8))
+}
+
+func TestReadExceptionStatusResponseASCII(t *testing.T) {
+	resp := ReadExceptionStatusResponseASCII{
+		ReadExceptionStatusResponseRTU: ReadExceptionStatusResponseRTU{
+			ReadExceptionStatusResponse: ReadExceptionStatusResponse{UnitID: 0x11, Status: 0x6d},
+		},
+	}
+
+	parsed, err := ParseASCIIResponse(resp.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, &resp, parsed)
+}
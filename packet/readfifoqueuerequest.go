@@ -0,0 +1,188 @@
+package packet
+
+import (
+	"encoding/binary"
+)
+
+// ReadFIFOQueueRequestTCP is TCP Request for Read FIFO Queue (FC=24)
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x04 0x01 0x18 0x04 0xDE
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x04 - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x01 - unit id (6)
+// 0x18 - function code (7)
+// 0x04 0xDE - FIFO pointer address (8,9)
+type ReadFIFOQueueRequestTCP struct {
+	MBAPHeader
+	ReadFIFOQueueRequest
+}
+
+// ReadFIFOQueueRequestRTU is RTU Request for Read FIFO Queue (FC=24)
+//
+// Example packet: 0x01 0x18 0x04 0xDE 0x03 0x47
+// 0x01 - unit id (0)
+// 0x18 - function code (1)
+// 0x04 0xDE - FIFO pointer address (2,3)
+// 0x03 0x47 - CRC16 (4,5)
+type ReadFIFOQueueRequestRTU struct {
+	ReadFIFOQueueRequest
+}
+
+// ReadFIFOQueueRequest is Request for Read FIFO Queue (FC=24)
+type ReadFIFOQueueRequest struct {
+	UnitID uint8
+	// FIFOPointerAddress is address of FIFO pointer register (register containing count of registers in queue)
+	FIFOPointerAddress uint16
+}
+
+// NewReadFIFOQueueRequestTCP creates new instance of Read FIFO Queue TCP request
+func NewReadFIFOQueueRequestTCP(unitID uint8, fifoPointerAddress uint16) (*ReadFIFOQueueRequestTCP, error) {
+	return &ReadFIFOQueueRequestTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: RandomTransactionID(),
+			ProtocolID:    0,
+		},
+		ReadFIFOQueueRequest: ReadFIFOQueueRequest{
+			UnitID: unitID,
+			// function code is added by Bytes()
+			FIFOPointerAddress: fifoPointerAddress,
+		},
+	}, nil
+}
+
+// Bytes returns ReadFIFOQueueRequestTCP packet as bytes form
+func (r ReadFIFOQueueRequestTCP) Bytes() []byte {
+	length := uint16(4)
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.ReadFIFOQueueRequest.bytes(result[6:10])
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadFIFOQueueRequestTCP) ExpectedResponseLength() int {
+	// response = 6 header len + 1 unitID + 1 fc + 2 byte count + 2 FIFO count + N*2 FIFO registers (0-31)
+	// response length is variable, so this is minimal valid response length
+	return 6 + 6
+}
+
+// ParseReadFIFOQueueRequestTCP parses given bytes into ReadFIFOQueueRequestTCP
+func ParseReadFIFOQueueRequestTCP(data []byte) (*ReadFIFOQueueRequestTCP, error) {
+	header, err := ParseMBAPHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 10 {
+		return nil, NewErrorParseTCP(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[6]
+	if data[7] != FunctionReadFIFOQueue {
+		tmpErr := NewErrorParseTCP(ErrIllegalFunction, "received function code in packet is not 0x18")
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionReadFIFOQueue
+		return nil, tmpErr
+	}
+	return &ReadFIFOQueueRequestTCP{
+		MBAPHeader: header,
+		ReadFIFOQueueRequest: ReadFIFOQueueRequest{
+			UnitID: unitID,
+			// function code = data[7]
+			FIFOPointerAddress: binary.BigEndian.Uint16(data[8:10]),
+		},
+	}, nil
+}
+
+// NewReadFIFOQueueRequestRTU creates new instance of Read FIFO Queue RTU request
+func NewReadFIFOQueueRequestRTU(unitID uint8, fifoPointerAddress uint16) (*ReadFIFOQueueRequestRTU, error) {
+	return &ReadFIFOQueueRequestRTU{
+		ReadFIFOQueueRequest: ReadFIFOQueueRequest{
+			UnitID: unitID,
+			// function code is added by Bytes()
+			FIFOPointerAddress: fifoPointerAddress,
+		},
+	}, nil
+}
+
+// Bytes returns ReadFIFOQueueRequestRTU packet as bytes form
+func (r ReadFIFOQueueRequestRTU) Bytes() []byte {
+	result := make([]byte, 4+2)
+	bytes := r.ReadFIFOQueueRequest.bytes(result)
+	crc := CRC16(bytes[:4])
+	result[4] = uint8(crc)
+	result[5] = uint8(crc >> 8)
+	return result
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadFIFOQueueRequestRTU) ExpectedResponseLength() int {
+	// response = 1 UnitID + 1 functionCode + 2 byte count + 2 FIFO count + N*2 FIFO registers (0-31) + 2 CRC
+	// response length is variable, so this is minimal valid response length
+	return 6 + 2
+}
+
+// ParseReadFIFOQueueRequestRTU parses given bytes into ReadFIFOQueueRequestRTU
+// Does not check CRC
+func ParseReadFIFOQueueRequestRTU(data []byte) (*ReadFIFOQueueRequestRTU, error) {
+	dLen := len(data)
+	if dLen != 6 && dLen != 4 { // with or without CRC bytes
+		return nil, NewErrorParseRTU(ErrServerFailure, "invalid data length to be valid packet")
+	}
+	unitID := data[0]
+	if data[1] != FunctionReadFIFOQueue {
+		tmpErr := NewErrorParseRTU(ErrIllegalFunction, "received function code in packet is not 0x18")
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionReadFIFOQueue
+		return nil, tmpErr
+	}
+	return &ReadFIFOQueueRequestRTU{
+		ReadFIFOQueueRequest: ReadFIFOQueueRequest{
+			UnitID: unitID,
+			// function code = data[1]
+			FIFOPointerAddress: binary.BigEndian.Uint16(data[2:4]),
+		},
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r ReadFIFOQueueRequest) FunctionCode() uint8 {
+	return FunctionReadFIFOQueue
+}
+
+// Bytes returns ReadFIFOQueueRequest packet as bytes form
+func (r ReadFIFOQueueRequest) Bytes() []byte {
+	return r.bytes(make([]byte, 4))
+}
+
+func (r ReadFIFOQueueRequest) bytes(bytes []byte) []byte {
+	bytes[0] = r.UnitID
+	bytes[1] = FunctionReadFIFOQueue
+	binary.BigEndian.PutUint16(bytes[2:4], r.FIFOPointerAddress)
+	return bytes
+}
+
+// ReadFIFOQueueRequestASCII is ASCII Request for Read FIFO Queue (FC=24). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadFIFOQueueRequestASCII struct {
+	ReadFIFOQueueRequestRTU
+}
+
+// NewReadFIFOQueueRequestASCII creates new instance of Read FIFO Queue ASCII request
+func NewReadFIFOQueueRequestASCII(unitID uint8, fifoPointerAddress uint16) (*ReadFIFOQueueRequestASCII, error) {
+	r, err := NewReadFIFOQueueRequestRTU(unitID, fifoPointerAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadFIFOQueueRequestASCII{ReadFIFOQueueRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadFIFOQueueRequestASCII packet as bytes form
+func (r ReadFIFOQueueRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadFIFOQueueRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadFIFOQueueRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadFIFOQueueRequestRTU.ExpectedResponseLength())
+}
@@ -0,0 +1,88 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewReadFIFOQueueRequestTCP(t *testing.T) {
+	req, err := NewReadFIFOQueueRequestTCP(0x01, 0x04DE)
+	assert.NoError(t, err)
+	req.TransactionID = 0x8180
+
+	assert.Equal(t, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x4, 0x1, 0x18, 0x4, 0xde}, req.Bytes())
+	assert.Equal(t, 12, req.ExpectedResponseLength())
+	assert.Equal(t, FunctionReadFIFOQueue, req.FunctionCode())
+}
+
+func TestParseReadFIFOQueueRequestTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *ReadFIFOQueueRequestTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x4, 0x1, 0x18, 0x4, 0xde},
+			expect: &ReadFIFOQueueRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				ReadFIFOQueueRequest: ReadFIFOQueueRequest{
+					UnitID:             0x01,
+					FIFOPointerAddress: 0x04DE,
+				},
+			},
+		},
+		{
+			name:        "nok, invalid function code",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x4, 0x1, 0x17, 0x4, 0xde},
+			expectError: "received function code in packet is not 0x18",
+		},
+		{
+			name:        "nok, invalid length",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x3, 0x1, 0x18, 0x4},
+			expectError: "invalid data length to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := ParseReadFIFOQueueRequestTCP(tc.given)
+
+			assert.Equal(t, tc.expect, req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadFIFOQueueRequestRTU(t *testing.T) {
+	req, err := NewReadFIFOQueueRequestRTU(0x01, 0x04DE)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte{0x1, 0x18, 0x4, 0xde, 0x3, 0x47}, req.Bytes())
+	assert.Equal(t, 8, req.ExpectedResponseLength())
+
+	parsed, err := ParseReadFIFOQueueRequestRTU(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+
+	_, err = ParseReadFIFOQueueRequestRTU([]byte{0x1, 0x18, 0x4})
+	assert.EqualError(t, err, "invalid data length to be valid packet")
+
+	_, err = ParseReadFIFOQueueRequestRTU([]byte{0x1, 0x17, 0x4, 0xde})
+	assert.EqualError(t, err, "received function code in packet is not 0x18")
+}
+
+func TestReadFIFOQueueRequestASCII(t *testing.T) {
+	req, err := NewReadFIFOQueueRequestASCII(0x01, 0x04DE)
+	assert.NoError(t, err)
+	assert.Equal(t, asciiLength(8), req.ExpectedResponseLength())
+
+	parsed, err := ParseASCIIRequest(req.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, req, parsed)
+}
@@ -0,0 +1,162 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ReadFIFOQueueResponseTCP is TCP Response for Read FIFO Queue (FC=24)
+//
+// Example packet: 0x81 0x80 0x00 0x00 0x00 0x0A 0x01 0x18 0x00 0x06 0x00 0x02 0x01 0xB8 0x12 0x84
+// 0x81 0x80 - transaction id (0,1)
+// 0x00 0x00 - protocol id (2,3)
+// 0x00 0x0A - number of bytes in the message (PDU = ProtocolDataUnit) to follow (4,5)
+// 0x01 - unit id (6)
+// 0x18 - function code (7)
+// 0x00 0x06 - byte count (8,9), FIFO count bytes + FIFO registers bytes
+// 0x00 0x02 - FIFO count (10,11), quantity of registers in queue (0-31)
+// 0x01 0xB8 0x12 0x84 - FIFO registers data (2 registers) (12,13, ... 2 bytes for each register)
+type ReadFIFOQueueResponseTCP struct {
+	MBAPHeader
+	ReadFIFOQueueResponse
+}
+
+// ReadFIFOQueueResponseRTU is RTU Response for Read FIFO Queue (FC=24)
+//
+// Example packet: 0x01 0x18 0x00 0x06 0x00 0x02 0x01 0xB8 0x12 0x84 0x19 0x18
+// 0x01 - unit id (0)
+// 0x18 - function code (1)
+// 0x00 0x06 - byte count (2,3), FIFO count bytes + FIFO registers bytes
+// 0x00 0x02 - FIFO count (4,5), quantity of registers in queue (0-31)
+// 0x01 0xB8 0x12 0x84 - FIFO registers data (2 registers) (6,7, ... 2 bytes for each register)
+// 0x19 0x18 - CRC16 (n-2,n-1)
+type ReadFIFOQueueResponseRTU struct {
+	ReadFIFOQueueResponse
+}
+
+// ReadFIFOQueueResponse is Response for Read FIFO Queue (FC=24)
+type ReadFIFOQueueResponse struct {
+	UnitID uint8
+	// FIFOCount is quantity of registers in queue (0-31)
+	FIFOCount uint16
+	Data      []byte
+}
+
+// Bytes returns ReadFIFOQueueResponseTCP packet as bytes form
+func (r ReadFIFOQueueResponseTCP) Bytes() []byte {
+	length := r.len()
+	result := make([]byte, tcpMBAPHeaderLen+length)
+	r.MBAPHeader.bytes(result[0:6], length)
+	r.ReadFIFOQueueResponse.bytes(result[6 : 6+length])
+	return result
+}
+
+// ParseReadFIFOQueueResponseTCP parses given bytes into ReadFIFOQueueResponseTCP
+func ParseReadFIFOQueueResponseTCP(data []byte) (*ReadFIFOQueueResponseTCP, error) {
+	dLen := len(data)
+	if dLen < 12 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	pduLen := binary.BigEndian.Uint16(data[4:6])
+	if dLen != 6+int(pduLen) {
+		return nil, errors.New("received data length does not match PDU len in packet")
+	}
+	resp, err := parseReadFIFOQueueResponse(data[6:])
+	if err != nil {
+		return nil, err
+	}
+	return &ReadFIFOQueueResponseTCP{
+		MBAPHeader: MBAPHeader{
+			TransactionID: binary.BigEndian.Uint16(data[0:2]),
+			ProtocolID:    0,
+		},
+		ReadFIFOQueueResponse: resp,
+	}, nil
+}
+
+// Bytes returns ReadFIFOQueueResponseRTU packet as bytes form
+func (r ReadFIFOQueueResponseRTU) Bytes() []byte {
+	length := r.len()
+	result := make([]byte, length+2)
+	bytes := r.ReadFIFOQueueResponse.bytes(result)
+	crc := CRC16(bytes[:length])
+	result[length] = uint8(crc)
+	result[length+1] = uint8(crc >> 8)
+	return result
+}
+
+// ParseReadFIFOQueueResponseRTU parses given bytes into ReadFIFOQueueResponseRTU
+func ParseReadFIFOQueueResponseRTU(data []byte) (*ReadFIFOQueueResponseRTU, error) {
+	dLen := len(data)
+	if dLen < 8 {
+		return nil, errors.New("received data length too short to be valid packet")
+	}
+	resp, err := parseReadFIFOQueueResponse(data[:dLen-2])
+	if err != nil {
+		return nil, err
+	}
+	return &ReadFIFOQueueResponseRTU{ReadFIFOQueueResponse: resp}, nil
+}
+
+// parseReadFIFOQueueResponse parses response PDU starting from unit ID
+func parseReadFIFOQueueResponse(data []byte) (ReadFIFOQueueResponse, error) {
+	byteCount := int(binary.BigEndian.Uint16(data[2:4]))
+	if len(data) != 4+byteCount {
+		return ReadFIFOQueueResponse{}, errors.New("received data length does not match byte count in packet")
+	}
+	fifoCount := binary.BigEndian.Uint16(data[4:6])
+	if fifoCount > MaxRegistersInFIFOQueue {
+		return ReadFIFOQueueResponse{}, fmt.Errorf("received FIFO count is out of range (0-31): %v", fifoCount)
+	}
+	if byteCount != 2+2*int(fifoCount) {
+		return ReadFIFOQueueResponse{}, errors.New("received byte count does not match FIFO count in packet")
+	}
+	return ReadFIFOQueueResponse{
+		UnitID: data[0],
+		// function code = data[1]
+		FIFOCount: fifoCount,
+		Data:      data[6 : 6+2*int(fifoCount)],
+	}, nil
+}
+
+// FunctionCode returns function code of this request
+func (r ReadFIFOQueueResponse) FunctionCode() uint8 {
+	return FunctionReadFIFOQueue
+}
+
+func (r ReadFIFOQueueResponse) len() uint16 {
+	return 6 + uint16(len(r.Data))
+}
+
+// Bytes returns ReadFIFOQueueResponse packet as bytes form
+func (r ReadFIFOQueueResponse) Bytes() []byte {
+	return r.bytes(make([]byte, r.len()))
+}
+
+func (r ReadFIFOQueueResponse) bytes(data []byte) []byte {
+	data[0] = r.UnitID
+	data[1] = FunctionReadFIFOQueue
+	binary.BigEndian.PutUint16(data[2:4], 2+uint16(len(r.Data)))
+	binary.BigEndian.PutUint16(data[4:6], r.FIFOCount)
+	copy(data[6:], r.Data)
+
+	return data
+}
+
+// AsRegisters returns FIFO queue data as Registers to more convenient access. FIFO registers do not have addresses of
+// their own so first register in queue is addressed with given startAddress, second with startAddress+1 and so on.
+func (r ReadFIFOQueueResponse) AsRegisters(startAddress uint16) (*Registers, error) {
+	return NewRegisters(r.Data, startAddress)
+}
+
+// ReadFIFOQueueResponseASCII is ASCII Response for Read FIFO Queue (FC=24). Packet is RTU packet without CRC encoded
+// as hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadFIFOQueueResponseASCII struct {
+	ReadFIFOQueueResponseRTU
+}
+
+// Bytes returns ReadFIFOQueueResponseASCII packet as bytes form
+func (r ReadFIFOQueueResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadFIFOQueueResponseRTU.Bytes())
+}
@@ -0,0 +1,163 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadFIFOQueueResponseTCP_Bytes(t *testing.T) {
+	given := ReadFIFOQueueResponseTCP{
+		MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+		ReadFIFOQueueResponse: ReadFIFOQueueResponse{
+			UnitID:    0x01,
+			FIFOCount: 2,
+			Data:      []byte{0x01, 0xB8, 0x12, 0x84},
+		},
+	}
+
+	expect := []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xa, 0x1, 0x18, 0x0, 0x6, 0x0, 0x2, 0x1, 0xb8, 0x12, 0x84}
+	assert.Equal(t, expect, given.Bytes())
+	assert.Equal(t, FunctionReadFIFOQueue, given.FunctionCode())
+}
+
+func TestParseReadFIFOQueueResponseTCP(t *testing.T) {
+	max31registers := make([]byte, 6+6+62)
+	copy(max31registers, []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x44, 0x1, 0x18, 0x0, 0x40, 0x0, 0x1f})
+
+	var testCases = []struct {
+		name        string
+		given       []byte
+		expect      *ReadFIFOQueueResponseTCP
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xa, 0x1, 0x18, 0x0, 0x6, 0x0, 0x2, 0x1, 0xb8, 0x12, 0x84},
+			expect: &ReadFIFOQueueResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				ReadFIFOQueueResponse: ReadFIFOQueueResponse{
+					UnitID:    0x01,
+					FIFOCount: 2,
+					Data:      []byte{0x01, 0xB8, 0x12, 0x84},
+				},
+			},
+		},
+		{
+			name:  "ok, empty queue",
+			given: []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x6, 0x1, 0x18, 0x0, 0x2, 0x0, 0x0},
+			expect: &ReadFIFOQueueResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				ReadFIFOQueueResponse: ReadFIFOQueueResponse{
+					UnitID:    0x01,
+					FIFOCount: 0,
+					Data:      []byte{},
+				},
+			},
+		},
+		{
+			name:  "ok, max 31 registers",
+			given: max31registers,
+			expect: &ReadFIFOQueueResponseTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x8180},
+				ReadFIFOQueueResponse: ReadFIFOQueueResponse{
+					UnitID:    0x01,
+					FIFOCount: 31,
+					Data:      make([]byte, 62),
+				},
+			},
+		},
+		{
+			name:        "nok, FIFO count over 31",
+			given:       append([]byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x46, 0x1, 0x18, 0x0, 0x42, 0x0, 0x20}, make([]byte, 64)...),
+			expectError: "received FIFO count is out of range (0-31): 32",
+		},
+		{
+			name:        "nok, byte count does not match FIFO count",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xa, 0x1, 0x18, 0x0, 0x6, 0x0, 0x1, 0x1, 0xb8, 0x12, 0x84},
+			expectError: "received byte count does not match FIFO count in packet",
+		},
+		{
+			name:        "nok, byte count does not match data length",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xa, 0x1, 0x18, 0x0, 0x8, 0x0, 0x2, 0x1, 0xb8, 0x12, 0x84},
+			expectError: "received data length does not match byte count in packet",
+		},
+		{
+			name:        "nok, pdu length does not match",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0xb, 0x1, 0x18, 0x0, 0x6, 0x0, 0x2, 0x1, 0xb8, 0x12, 0x84},
+			expectError: "received data length does not match PDU len in packet",
+		},
+		{
+			name:        "nok, too short",
+			given:       []byte{0x81, 0x80, 0x0, 0x0, 0x0, 0x5, 0x1, 0x18, 0x0, 0x2, 0x0},
+			expectError: "received data length too short to be valid packet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseReadFIFOQueueResponseTCP(tc.given)
+
+			assert.Equal(t, tc.expect, resp)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadFIFOQueueResponseRTU(t *testing.T) {
+	given := []byte{0x1, 0x18, 0x0, 0x6, 0x0, 0x2, 0x1, 0xb8, 0x12, 0x84, 0x19, 0x18}
+
+	resp, err := ParseReadFIFOQueueResponseRTU(given)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReadFIFOQueueResponseRTU{
+		ReadFIFOQueueResponse: ReadFIFOQueueResponse{
+			UnitID:    0x01,
+			FIFOCount: 2,
+			Data:      []byte{0x01, 0xB8, 0x12, 0x84},
+		},
+	}, resp)
+	assert.Equal(t, given, resp.Bytes())
+
+	_, err = ParseReadFIFOQueueResponseRTU([]byte{0x1, 0x18, 0x0, 0x2, 0x0, 0x0, 0x0})
+	assert.EqualError(t, err, "received data length too short to be valid packet")
+}
+
+func TestReadFIFOQueueResponseASCII(t *testing.T) {
+	rtu := ReadFIFOQueueResponseRTU{
+		ReadFIFOQueueResponse: ReadFIFOQueueResponse{
+			UnitID:    0x01,
+			FIFOCount: 2,
+			Data:      []byte{0x01, 0xB8, 0x12, 0x84},
+		},
+	}
+	resp := ReadFIFOQueueResponseASCII{ReadFIFOQueueResponseRTU: rtu}
+
+	parsed, err := ParseASCIIResponse(resp.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, &resp, parsed)
+}
+
+func TestReadFIFOQueueResponse_AsRegisters(t *testing.T) {
+	resp := ReadFIFOQueueResponse{
+		UnitID:    0x01,
+		FIFOCount: 2,
+		Data:      []byte{0x01, 0xB8, 0x12, 0x84},
+	}
+
+	registers, err := resp.AsRegisters(100)
+	assert.NoError(t, err)
+
+	v, err := registers.Uint16(100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x01B8), v)
+
+	v, err = registers.Uint16(101)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x1284), v)
+
+	_, err = ReadFIFOQueueResponse{}.AsRegisters(0)
+	assert.EqualError(t, err, "data length at least 2 bytes as 1 register is 2 bytes")
+}
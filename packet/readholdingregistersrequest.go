@@ -3,7 +3,6 @@ package packet
 import (
 	"encoding/binary"
 	"fmt"
-	"math/rand"
 )
 
 // ReadHoldingRegistersRequestTCP is TCP Request for Read Holding Registers (FC=03)
@@ -48,7 +47,7 @@ func NewReadHoldingRegistersRequestTCP(unitID uint8, startAddress uint16, quanti
 
 	return &ReadHoldingRegistersRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		ReadHoldingRegistersRequest: ReadHoldingRegistersRequest{
@@ -166,8 +165,8 @@ func ParseReadHoldingRegistersRequestRTU(data []byte) (*ReadHoldingRegistersRequ
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadHoldingRegistersRequest) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode + 2 register byte count + N register data
-	return 4 + 2*int(r.Quantity)
+	// response = 1 UnitID + 1 functionCode + 1 register byte count + N register data + 2 CRC
+	return 5 + 2*int(r.Quantity)
 }
 
 // FunctionCode returns function code of this request
@@ -184,3 +183,28 @@ func (r ReadHoldingRegistersRequest) bytes(bytes []byte) []byte {
 	putReadRequestBytes(bytes, r.UnitID, FunctionReadHoldingRegisters, r.StartAddress, r.Quantity)
 	return bytes
 }
+
+// ReadHoldingRegistersRequestASCII is ASCII Request for Read Holding Registers (FC=03). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadHoldingRegistersRequestASCII struct {
+	ReadHoldingRegistersRequestRTU
+}
+
+// NewReadHoldingRegistersRequestASCII creates new instance of Read Holding Registers ASCII request
+func NewReadHoldingRegistersRequestASCII(unitID uint8, startAddress uint16, quantity uint16) (*ReadHoldingRegistersRequestASCII, error) {
+	r, err := NewReadHoldingRegistersRequestRTU(unitID, startAddress, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadHoldingRegistersRequestASCII{ReadHoldingRegistersRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadHoldingRegistersRequestASCII packet as bytes form
+func (r ReadHoldingRegistersRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadHoldingRegistersRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadHoldingRegistersRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadHoldingRegistersRequestRTU.ExpectedResponseLength())
+}
@@ -130,3 +130,14 @@ func (r ReadHoldingRegistersResponse) bytes(data []byte) []byte {
 func (r ReadHoldingRegistersResponse) AsRegisters(requestStartAddress uint16) (*Registers, error) {
 	return NewRegisters(r.Data, requestStartAddress)
 }
+
+// ReadHoldingRegistersResponseASCII is ASCII Response for Read Holding Registers (FC=03). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadHoldingRegistersResponseASCII struct {
+	ReadHoldingRegistersResponseRTU
+}
+
+// Bytes returns ReadHoldingRegistersResponseASCII packet as bytes form
+func (r ReadHoldingRegistersResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadHoldingRegistersResponseRTU.Bytes())
+}
@@ -3,7 +3,6 @@ package packet
 import (
 	"encoding/binary"
 	"fmt"
-	"math/rand"
 )
 
 // ReadInputRegistersRequestTCP is TCP Request for Read Input Registers (FC=04)
@@ -48,7 +47,7 @@ func NewReadInputRegistersRequestTCP(unitID uint8, startAddress uint16, quantity
 
 	return &ReadInputRegistersRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		ReadInputRegistersRequest: ReadInputRegistersRequest{
@@ -166,8 +165,8 @@ func ParseReadInputRegistersRequestRTU(data []byte) (*ReadInputRegistersRequestR
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadInputRegistersRequest) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode + 2 register byte count + N register data
-	return 4 + 2*int(r.Quantity)
+	// response = 1 UnitID + 1 functionCode + 1 register byte count + N register data + 2 CRC
+	return 5 + 2*int(r.Quantity)
 }
 
 // FunctionCode returns function code of this request
@@ -184,3 +183,28 @@ func (r ReadInputRegistersRequest) bytes(bytes []byte) []byte {
 	putReadRequestBytes(bytes, r.UnitID, FunctionReadInputRegisters, r.StartAddress, r.Quantity)
 	return bytes
 }
+
+// ReadInputRegistersRequestASCII is ASCII Request for Read Input Registers (FC=04). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadInputRegistersRequestASCII struct {
+	ReadInputRegistersRequestRTU
+}
+
+// NewReadInputRegistersRequestASCII creates new instance of Read Input Registers ASCII request
+func NewReadInputRegistersRequestASCII(unitID uint8, startAddress uint16, quantity uint16) (*ReadInputRegistersRequestASCII, error) {
+	r, err := NewReadInputRegistersRequestRTU(unitID, startAddress, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadInputRegistersRequestASCII{ReadInputRegistersRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadInputRegistersRequestASCII packet as bytes form
+func (r ReadInputRegistersRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadInputRegistersRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadInputRegistersRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadInputRegistersRequestRTU.ExpectedResponseLength())
+}
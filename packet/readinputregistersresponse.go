@@ -130,3 +130,14 @@ func (r ReadInputRegistersResponse) bytes(data []byte) []byte {
 func (r ReadInputRegistersResponse) AsRegisters(requestStartAddress uint16) (*Registers, error) {
 	return NewRegisters(r.Data, requestStartAddress)
 }
+
+// ReadInputRegistersResponseASCII is ASCII Response for Read Input Registers (FC=04). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadInputRegistersResponseASCII struct {
+	ReadInputRegistersResponseRTU
+}
+
+// Bytes returns ReadInputRegistersResponseASCII packet as bytes form
+func (r ReadInputRegistersResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadInputRegistersResponseRTU.Bytes())
+}
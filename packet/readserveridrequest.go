@@ -1,9 +1,5 @@
 package packet
 
-import (
-	"math/rand"
-)
-
 // ReadServerIDRequestTCP is TCP Request for Read Server ID function (FC=17, 0x11)
 //
 // Example packet:  0x81 0x80 0x00 0x00 0x00 0x02 0x10 0x11
@@ -36,7 +32,7 @@ type ReadServerIDRequest struct {
 func NewReadServerIDRequestTCP(unitID uint8) (*ReadServerIDRequestTCP, error) {
 	return &ReadServerIDRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		ReadServerIDRequest: ReadServerIDRequest{
@@ -56,8 +52,9 @@ func (r ReadServerIDRequestTCP) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadServerIDRequestTCP) ExpectedResponseLength() int {
-	// response = 6 header len + 1 unitID + 1 fc
-	return 6 + 2
+	// response = 6 header len + 1 unitID + 1 fc + 1 byte count + N server id data (at least 1 byte)
+	// response length is variable, so this is minimal valid response length
+	return 6 + 4
 }
 
 // ParseReadServerIDRequestTCP parses given bytes into ReadServerIDRequestTCP
@@ -103,8 +100,9 @@ func (r ReadServerIDRequestRTU) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadServerIDRequestRTU) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode
-	return 2
+	// response = 1 UnitID + 1 functionCode + 1 byte count + N server id data (at least 1 byte) + 2 CRC
+	// response length is variable, so this is minimal valid response length
+	return 4 + 2
 }
 
 // ParseReadServerIDRequestRTU parses given bytes into ReadServerIDRequestRTU
@@ -143,3 +141,28 @@ func (r ReadServerIDRequest) bytes(bytes []byte) []byte {
 	bytes[1] = FunctionReadServerID
 	return bytes
 }
+
+// ReadServerIDRequestASCII is ASCII Request for Read Server ID (FC=17, 0x11). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadServerIDRequestASCII struct {
+	ReadServerIDRequestRTU
+}
+
+// NewReadServerIDRequestASCII creates new instance of Read Server ID ASCII request
+func NewReadServerIDRequestASCII(unitID uint8) (*ReadServerIDRequestASCII, error) {
+	r, err := NewReadServerIDRequestRTU(unitID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadServerIDRequestASCII{ReadServerIDRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadServerIDRequestASCII packet as bytes form
+func (r ReadServerIDRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadServerIDRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadServerIDRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadServerIDRequestRTU.ExpectedResponseLength())
+}
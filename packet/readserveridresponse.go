@@ -171,3 +171,14 @@ func (r ReadServerIDResponse) bytes(data []byte) []byte {
 
 	return data
 }
+
+// ReadServerIDResponseASCII is ASCII Response for Read Server ID (FC=17, 0x11). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadServerIDResponseASCII struct {
+	ReadServerIDResponseRTU
+}
+
+// Bytes returns ReadServerIDResponseASCII packet as bytes form
+func (r ReadServerIDResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadServerIDResponseRTU.Bytes())
+}
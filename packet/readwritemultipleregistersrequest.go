@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"math/rand"
 )
 
 // ReadWriteMultipleRegistersRequestTCP is TCP Request for Read / Write Multiple Registers (FC=23)
@@ -78,7 +77,7 @@ func NewReadWriteMultipleRegistersRequestTCP(
 
 	return &ReadWriteMultipleRegistersRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		ReadWriteMultipleRegistersRequest: ReadWriteMultipleRegistersRequest{
@@ -105,7 +104,8 @@ func (r ReadWriteMultipleRegistersRequestTCP) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadWriteMultipleRegistersRequestTCP) ExpectedResponseLength() int {
-	return 6 + 11 + int(r.ReadQuantity)*2
+	// response = 6 header len + 1 UnitID + 1 functionCode + 1 registers byte count + N registers data
+	return 6 + 3 + int(r.ReadQuantity)*2
 }
 
 // ParseReadWriteMultipleRegistersRequestTCP parses given bytes into ReadWriteMultipleRegistersRequestTCP
@@ -213,8 +213,8 @@ func (r ReadWriteMultipleRegistersRequestRTU) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r ReadWriteMultipleRegistersRequestRTU) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode + 2 registers bytes count + N registers data + 2 CRC
-	return 4 + 2*int(r.ReadQuantity) + 2
+	// response = 1 UnitID + 1 functionCode + 1 registers byte count + N registers data + 2 CRC
+	return 3 + 2*int(r.ReadQuantity) + 2
 }
 
 // ParseReadWriteMultipleRegistersRequestRTU parses given bytes into ReadWriteMultipleRegistersRequestRTU
@@ -295,3 +295,35 @@ func (r ReadWriteMultipleRegistersRequest) bytes(bytes []byte) []byte {
 	copy(bytes[11:], r.WriteData)
 	return bytes
 }
+
+// ReadWriteMultipleRegistersRequestASCII is ASCII Request for Read / Write Multiple Registers (FC=23, 0x17). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadWriteMultipleRegistersRequestASCII struct {
+	ReadWriteMultipleRegistersRequestRTU
+}
+
+// NewReadWriteMultipleRegistersRequestASCII creates new instance of Read / Write Multiple Registers ASCII request
+// NB: bytes for `data` must be in BigEndian byte order for server to interpret them correctly
+func NewReadWriteMultipleRegistersRequestASCII(
+	unitID uint8,
+	readStartAddress uint16,
+	readQuantity uint16,
+	writeStartAddress uint16,
+	writeData []byte,
+) (*ReadWriteMultipleRegistersRequestASCII, error) {
+	r, err := NewReadWriteMultipleRegistersRequestRTU(unitID, readStartAddress, readQuantity, writeStartAddress, writeData)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadWriteMultipleRegistersRequestASCII{ReadWriteMultipleRegistersRequestRTU: *r}, nil
+}
+
+// Bytes returns ReadWriteMultipleRegistersRequestASCII packet as bytes form
+func (r ReadWriteMultipleRegistersRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadWriteMultipleRegistersRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r ReadWriteMultipleRegistersRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.ReadWriteMultipleRegistersRequestRTU.ExpectedResponseLength())
+}
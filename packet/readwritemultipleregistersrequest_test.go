@@ -179,7 +179,7 @@ func TestReadWriteMultipleRegistersRequestTCP_ExpectedResponseLength(t *testing.
 		{
 			name:          "ok",
 			whenCoilState: true,
-			expect:        17 + 3*2,
+			expect:        9 + 3*2,
 		},
 	}
 
@@ -361,7 +361,7 @@ func TestReadWriteMultipleRegistersRequestRTU_ExpectedResponseLength(t *testing.
 		{
 			name:          "ok",
 			whenCoilState: 8,
-			expect:        6 + 2*2,
+			expect:        5 + 2*2,
 		},
 	}
 
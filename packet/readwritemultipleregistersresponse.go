@@ -130,3 +130,14 @@ func (r ReadWriteMultipleRegistersResponse) bytes(data []byte) []byte {
 func (r ReadWriteMultipleRegistersResponse) AsRegisters(requestStartAddress uint16) (*Registers, error) {
 	return NewRegisters(r.Data, requestStartAddress)
 }
+
+// ReadWriteMultipleRegistersResponseASCII is ASCII Response for Read / Write Multiple Registers (FC=23, 0x17). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type ReadWriteMultipleRegistersResponseASCII struct {
+	ReadWriteMultipleRegistersResponseRTU
+}
+
+// Bytes returns ReadWriteMultipleRegistersResponseASCII packet as bytes form
+func (r ReadWriteMultipleRegistersResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.ReadWriteMultipleRegistersResponseRTU.Bytes())
+}
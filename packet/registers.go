@@ -112,6 +112,11 @@ func (r *Registers) WithByteOrder(byteOrder ByteOrder) *Registers {
 	return r
 }
 
+// AddressRange returns first and last register address contained in Registers
+func (r Registers) AddressRange() (uint16, uint16) {
+	return r.startAddress, r.endAddress - 1
+}
+
 // Register returns single register data (16bit) from given address
 func (r Registers) Register(address uint16) ([]byte, error) {
 	b, err := r.register(address)
@@ -162,6 +167,39 @@ func (r Registers) doubleRegister(address uint16, byteOrder ByteOrder) ([]byte,
 	return r.data[startIndex : startIndex+4], nil
 }
 
+// TripleRegister returns three registers data (48bit) from starting from given address using word/register order
+func (r Registers) TripleRegister(address uint16, byteOrder ByteOrder) ([]byte, error) {
+	b, err := r.tripleRegister(address, byteOrder)
+	if err != nil {
+		return nil, err
+	}
+	return []byte{b[0], b[1], b[2], b[3], b[4], b[5]}, nil
+}
+
+func (r Registers) tripleRegister(address uint16, byteOrder ByteOrder) ([]byte, error) {
+	if address < r.startAddress {
+		return nil, errors.New("address under startAddress bounds")
+	}
+	if uint32(address)+3 > uint32(r.endAddress) {
+		return nil, errors.New("address over startAddress+quantity bounds")
+	}
+	startIndex := (address - r.startAddress) * 2
+	if byteOrder&LowWordFirst != 0 {
+		// reverse words/registers order (low word first)
+		return []byte{
+			r.data[startIndex+4],
+			r.data[startIndex+5],
+
+			r.data[startIndex+2],
+			r.data[startIndex+3],
+
+			r.data[startIndex],
+			r.data[startIndex+1],
+		}, nil
+	}
+	return r.data[startIndex : startIndex+6], nil
+}
+
 // QuadRegister returns four registers data (64bit) from starting from given address using word/register order
 func (r Registers) QuadRegister(address uint16, byteOrder ByteOrder) ([]byte, error) {
 	b, err := r.quadRegister(address, byteOrder)
@@ -257,6 +295,17 @@ func (r Registers) Uint16(address uint16) (uint16, error) {
 	return binary.BigEndian.Uint16(b), nil
 }
 
+// BCD16 returns register data decoded as binary-coded decimal (4 digits) from given address. For example register
+// data 0x1234 is returned as 1234. NB: BCD16 size is 1 register (16bits, 2 bytes).
+func (r Registers) BCD16(address uint16) (uint16, error) {
+	v, err := r.Uint16(address)
+	if err != nil {
+		return 0, err
+	}
+	result, err := BCDToUint32(uint32(v))
+	return uint16(result), err
+}
+
 // Int16 returns register data as int16 from given address. NB: Int16 size is 1 register (16bits, 2 bytes).
 func (r Registers) Int16(address uint16) (int16, error) {
 	b, err := r.register(address)
@@ -296,6 +345,21 @@ func (r Registers) Uint32WithByteOrder(address uint16, byteOrder ByteOrder) (uin
 	return binary.BigEndian.Uint32(b), nil
 }
 
+// BCD32 returns register data decoded as binary-coded decimal (8 digits) from given address. NB: BCD32 size is 2 registers (32bits, 4 bytes).
+func (r Registers) BCD32(address uint16) (uint32, error) {
+	return r.BCD32WithByteOrder(address, r.defaultByteOrder)
+}
+
+// BCD32WithByteOrder returns register data decoded as binary-coded decimal (8 digits) from given address with given
+// byte order. NB: BCD32 size is 2 registers (32bits, 4 bytes).
+func (r Registers) BCD32WithByteOrder(address uint16, byteOrder ByteOrder) (uint32, error) {
+	v, err := r.Uint32WithByteOrder(address, byteOrder)
+	if err != nil {
+		return 0, err
+	}
+	return BCDToUint32(v)
+}
+
 // Int32 returns register data as int32 from given address. NB: Int32 size is 2 registers (32bits, 4 bytes).
 func (r Registers) Int32(address uint16) (int32, error) {
 	b, err := r.doubleRegister(address, r.defaultByteOrder)
@@ -323,6 +387,40 @@ func (r Registers) Int32WithByteOrder(address uint16, byteOrder ByteOrder) (int3
 	return int32(binary.BigEndian.Uint32(b)), nil
 }
 
+// Uint48 returns register data as uint64 from given address. NB: Uint48 size is 3 registers (48bits, 6 bytes).
+func (r Registers) Uint48(address uint16) (uint64, error) {
+	return r.Uint48WithByteOrder(address, r.defaultByteOrder)
+}
+
+// Uint48WithByteOrder returns register data as uint64 from given address with given byte order. NB: uint48 size is 3 registers (48bits, 6 bytes).
+func (r Registers) Uint48WithByteOrder(address uint16, byteOrder ByteOrder) (uint64, error) {
+	if byteOrder == useDefaultByteOrder {
+		byteOrder = r.defaultByteOrder
+	}
+	b, err := r.tripleRegister(address, byteOrder)
+	if err != nil {
+		return 0, err
+	}
+	if byteOrder&LittleEndian != 0 {
+		return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 | uint64(b[4])<<32 | uint64(b[5])<<40, nil
+	}
+	return uint64(b[5]) | uint64(b[4])<<8 | uint64(b[3])<<16 | uint64(b[2])<<24 | uint64(b[1])<<32 | uint64(b[0])<<40, nil
+}
+
+// Int48 returns register data as int64 from given address. NB: Int48 size is 3 registers (48bits, 6 bytes).
+func (r Registers) Int48(address uint16) (int64, error) {
+	return r.Int48WithByteOrder(address, r.defaultByteOrder)
+}
+
+// Int48WithByteOrder returns register data as int64 from given address with given byte order. NB: int48 size is 3 registers (48bits, 6 bytes).
+func (r Registers) Int48WithByteOrder(address uint16, byteOrder ByteOrder) (int64, error) {
+	u, err := r.Uint48WithByteOrder(address, byteOrder)
+	if err != nil {
+		return 0, err
+	}
+	return int64(u<<16) >> 16, nil // sign extend 48th bit
+}
+
 // Uint64 returns register data as uint64 from given address. NB: Uint64 size is 4 registers (64bits, 8 bytes).
 func (r Registers) Uint64(address uint16) (uint64, error) {
 	b, err := r.quadRegister(address, r.defaultByteOrder)
@@ -377,6 +475,28 @@ func (r Registers) Int64WithByteOrder(address uint16, byteOrder ByteOrder) (int6
 	return int64(binary.BigEndian.Uint64(b)), nil
 }
 
+// Float16 returns register data as IEEE 754 half-precision float from given address. Value is returned as float32 as
+// Go does not have float16 type. NB: Float16 size is 1 register (16bits, 2 bytes).
+func (r Registers) Float16(address uint16) (float32, error) {
+	return r.Float16WithByteOrder(address, useDefaultByteOrder)
+}
+
+// Float16WithByteOrder returns register data as IEEE 754 half-precision float from given address with given byte
+// order. Only byte order (big/little endian) is relevant for single register. NB: Float16 size is 1 register (16bits, 2 bytes).
+func (r Registers) Float16WithByteOrder(address uint16, byteOrder ByteOrder) (float32, error) {
+	if byteOrder == useDefaultByteOrder {
+		byteOrder = r.defaultByteOrder
+	}
+	b, err := r.register(address)
+	if err != nil {
+		return 0, err
+	}
+	if byteOrder&LittleEndian != 0 {
+		return Float16frombits(binary.LittleEndian.Uint16(b)), nil
+	}
+	return Float16frombits(binary.BigEndian.Uint16(b)), nil
+}
+
 // Float32 returns register data as float32 from given address. NB: Float32 size is 2 registers (32bits, 4 bytes).
 func (r Registers) Float32(address uint16) (float32, error) {
 	b, err := r.doubleRegister(address, r.defaultByteOrder)
@@ -471,7 +591,9 @@ func (r Registers) StringWithByteOrder(address uint16, length uint8, byteOrder B
 
 	// TODO: clean these loops up to single for loop
 
-	rawBytes := r.data[startIndex:endIndex]
+	// copy data as swapping bytes in place would corrupt registers data for following extractions
+	rawBytes := make([]byte, endIndex-startIndex)
+	copy(rawBytes, r.data[startIndex:endIndex])
 	if byteOrder&BigEndian != 0 {
 		for i := 1; i < len(rawBytes); i++ {
 			// data is in BIG ENDIAN format in register (register is 2 bytes). so every 2 bytes needs to have their bytes swapped
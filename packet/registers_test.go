@@ -66,6 +66,15 @@ func TestRegisters_WithByteOrder(t *testing.T) {
 	assert.Equal(t, LittleEndian, r.defaultByteOrder)
 }
 
+func TestRegisters_AddressRange(t *testing.T) {
+	regs, err := NewRegisters([]byte{0x0, 0x1, 0x0, 0x2, 0x0, 0x3}, 100)
+	assert.NoError(t, err)
+
+	first, last := regs.AddressRange()
+	assert.Equal(t, uint16(100), first)
+	assert.Equal(t, uint16(102), last)
+}
+
 func TestRegisters_Register(t *testing.T) {
 	var testCases = []struct {
 		name        string
@@ -477,6 +486,100 @@ func TestRegisters_Uint16(t *testing.T) {
 	}
 }
 
+func TestRegisters_BCD16(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenBytes  []byte
+		whenAddress uint16
+		expect      uint16
+		expectError string
+	}{
+		{
+			name:       "ok, 1234",
+			givenBytes: []byte{0x12, 0x34},
+			expect:     1234,
+		},
+		{
+			name:        "ok, from address 1",
+			givenBytes:  []byte{0x12, 0x34, 0x99, 0x01},
+			whenAddress: 1,
+			expect:      9901,
+		},
+		{
+			name:        "nok, invalid digit",
+			givenBytes:  []byte{0x12, 0xf4},
+			expectError: "invalid BCD digit: 0xf",
+		},
+		{
+			name:        "nok, address over end",
+			givenBytes:  []byte{0x12, 0x34},
+			whenAddress: 1,
+			expectError: "address over startAddress+quantity bounds",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRegisters(tc.givenBytes, 0)
+			assert.NoError(t, err)
+
+			result, err := r.BCD16(tc.whenAddress)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegisters_BCD32WithByteOrder(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenBytes    []byte
+		whenByteOrder ByteOrder
+		expect        uint32
+		expectError   string
+	}{
+		{
+			name:          "ok, useDefaultByteOrder = BE high word",
+			givenBytes:    []byte{0x12, 0x34, 0x56, 0x78},
+			whenByteOrder: useDefaultByteOrder,
+			expect:        12345678,
+		},
+		{
+			name:          "ok, BE low word",
+			givenBytes:    []byte{0x56, 0x78, 0x12, 0x34},
+			whenByteOrder: BigEndianLowWordFirst,
+			expect:        12345678,
+		},
+		{
+			name:          "nok, invalid digit",
+			givenBytes:    []byte{0x00, 0x00, 0x00, 0x0b},
+			whenByteOrder: BigEndianHighWordFirst,
+			expectError:   "invalid BCD digit: 0xb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRegisters(tc.givenBytes, 0)
+			assert.NoError(t, err)
+
+			result, err := r.BCD32WithByteOrder(0, tc.whenByteOrder)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestRegisters_Int16(t *testing.T) {
 	var testCases = []struct {
 		name                 string
@@ -881,6 +984,136 @@ func TestRegisters_Int32WithByteOrder(t *testing.T) {
 	}
 }
 
+func TestRegisters_Uint48WithByteOrder(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenBytes    []byte
+		whenAddress   uint16
+		whenByteOrder ByteOrder
+		expect        uint64
+		expectError   string
+	}{
+		{
+			name:          "ok, useDefaultByteOrder = BE high word = 1",
+			givenBytes:    []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			whenByteOrder: useDefaultByteOrder,
+			expect:        1,
+		},
+		{
+			name:          "ok, BE high word 0x010203040506",
+			givenBytes:    []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+			whenByteOrder: BigEndianHighWordFirst,
+			expect:        0x010203040506,
+		},
+		{
+			name:          "ok, BE low word 0x010203040506",
+			givenBytes:    []byte{0x05, 0x06, 0x03, 0x04, 0x01, 0x02},
+			whenByteOrder: BigEndianLowWordFirst,
+			expect:        0x010203040506,
+		},
+		{
+			name:          "ok, LE high word 0x010203040506",
+			givenBytes:    []byte{0x06, 0x05, 0x04, 0x03, 0x02, 0x01},
+			whenByteOrder: LittleEndianHighWordFirst,
+			expect:        0x010203040506,
+		},
+		{
+			name:          "ok, LE low word 0x010203040506",
+			givenBytes:    []byte{0x02, 0x01, 0x04, 0x03, 0x06, 0x05},
+			whenByteOrder: LittleEndianLowWordFirst,
+			expect:        0x010203040506,
+		},
+		{
+			name:          "ok, from address 1",
+			givenBytes:    []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			whenAddress:   1,
+			whenByteOrder: BigEndianHighWordFirst,
+			expect:        0xFFFFFFFFFFFF,
+		},
+		{
+			name:        "nok, address over end",
+			givenBytes:  []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			whenAddress: 1,
+			expect:      0,
+			expectError: "address over startAddress+quantity bounds",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRegisters(tc.givenBytes, 0)
+			assert.NoError(t, err)
+
+			result, err := r.Uint48WithByteOrder(tc.whenAddress, tc.whenByteOrder)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegisters_Int48WithByteOrder(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenBytes    []byte
+		whenByteOrder ByteOrder
+		expect        int64
+		expectError   string
+	}{
+		{
+			name:          "ok, BE high word -1",
+			givenBytes:    []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			whenByteOrder: BigEndianHighWordFirst,
+			expect:        -1,
+		},
+		{
+			name:          "ok, BE high word max",
+			givenBytes:    []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			whenByteOrder: BigEndianHighWordFirst,
+			expect:        140737488355327,
+		},
+		{
+			name:          "ok, BE low word min",
+			givenBytes:    []byte{0x00, 0x00, 0x00, 0x00, 0x80, 0x00},
+			whenByteOrder: BigEndianLowWordFirst,
+			expect:        -140737488355328,
+		},
+		{
+			name:          "ok, LE high word -2",
+			givenBytes:    []byte{0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			whenByteOrder: LittleEndianHighWordFirst,
+			expect:        -2,
+		},
+		{
+			name:          "nok, address over end",
+			givenBytes:    []byte{0x00, 0x00},
+			whenByteOrder: BigEndianHighWordFirst,
+			expect:        0,
+			expectError:   "address over startAddress+quantity bounds",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRegisters(tc.givenBytes, 0)
+			assert.NoError(t, err)
+
+			result, err := r.Int48WithByteOrder(0, tc.whenByteOrder)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestRegisters_Uint64(t *testing.T) {
 	var testCases = []struct {
 		name                 string
@@ -1217,6 +1450,63 @@ func TestRegisters_Int64WithByteOrder(t *testing.T) {
 	}
 }
 
+func TestRegisters_Float16WithByteOrder(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenBytes    []byte
+		whenAddress   uint16
+		whenByteOrder ByteOrder
+		expect        float32
+		expectError   string
+	}{
+		{
+			name:          "ok, useDefaultByteOrder = BE = 1.5",
+			givenBytes:    []byte{0x3e, 0x00, 0xc5, 0x00},
+			whenByteOrder: useDefaultByteOrder,
+			expect:        1.5,
+		},
+		{
+			name:          "ok, BE low word first is same as BE for single register",
+			givenBytes:    []byte{0x3e, 0x00, 0xc5, 0x00},
+			whenAddress:   1,
+			whenByteOrder: BigEndianLowWordFirst,
+			expect:        -5,
+		},
+		{
+			name:          "ok, LE = 1.5",
+			givenBytes:    []byte{0x00, 0x3e, 0x00, 0xc5},
+			whenByteOrder: LittleEndian,
+			expect:        1.5,
+		},
+		{
+			name:        "nok, address over end",
+			givenBytes:  []byte{0x3e, 0x00, 0xc5, 0x00},
+			whenAddress: 2,
+			expect:      0,
+			expectError: "address over startAddress+quantity bounds",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Registers{
+				defaultByteOrder: BigEndianHighWordFirst,
+				startAddress:     0,
+				endAddress:       2,
+				data:             tc.givenBytes,
+			}
+			result, err := r.Float16WithByteOrder(tc.whenAddress, tc.whenByteOrder)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestRegisters_Float32(t *testing.T) {
 	var testCases = []struct {
 		name                 string
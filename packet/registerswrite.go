@@ -0,0 +1,237 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// NewRegistersForWrite creates new instance of Registers with given quantity of zeroed registers. Use SetX methods to
+// fill register data and Bytes to get data for write requests (i.e. WriteMultipleRegistersRequest).
+func NewRegistersForWrite(startAddress uint16, quantity uint16) (*Registers, error) {
+	if quantity == 0 {
+		return nil, errors.New("quantity must be at least 1")
+	}
+	if uint32(startAddress)+uint32(quantity) > math.MaxUint16 {
+		return nil, errors.New("startAddress+quantity is over register address range")
+	}
+	return NewRegisters(make([]byte, int(quantity)*2), startAddress)
+}
+
+// Bytes returns copy of registers data
+func (r Registers) Bytes() []byte {
+	result := make([]byte, len(r.data))
+	copy(result, r.data)
+	return result
+}
+
+// SetRegister sets single register data (16bit) at given address
+func (r *Registers) SetRegister(address uint16, data []byte) error {
+	if len(data) != 2 {
+		return errors.New("register data must be 2 bytes")
+	}
+	b, err := r.register(address)
+	if err != nil {
+		return err
+	}
+	copy(b, data)
+	return nil
+}
+
+// SetBit sets or clears N-th bit in register. NB: Bits are counted from 0 and right to left.
+func (r *Registers) SetBit(address uint16, bit uint8, value bool) error {
+	if bit > 15 {
+		return errors.New("bit value more than register (16bit) contains")
+	}
+	register, err := r.register(address)
+	if err != nil {
+		return err
+	}
+	nThByte := 1 // low byte of register
+	if bit > 7 {
+		bit -= 8
+		nThByte = 0 // high byte of register
+	}
+	if value {
+		register[nThByte] |= 1 << bit
+	} else {
+		register[nThByte] &^= 1 << bit
+	}
+	return nil
+}
+
+// SetUint8 sets uint8 value to given address high/low byte. Other byte of the register is left unchanged.
+func (r *Registers) SetUint8(address uint16, fromHighByte bool, value uint8) error {
+	register, err := r.register(address)
+	if err != nil {
+		return err
+	}
+	if fromHighByte {
+		register[0] = value
+	} else {
+		register[1] = value
+	}
+	return nil
+}
+
+// SetInt8 sets int8 value to given address high/low byte. Other byte of the register is left unchanged.
+func (r *Registers) SetInt8(address uint16, fromHighByte bool, value int8) error {
+	return r.SetUint8(address, fromHighByte, uint8(value))
+}
+
+// SetUint16 sets uint16 value to given address. NB: Uint16 size is 1 register (16bits, 2 bytes).
+func (r *Registers) SetUint16(address uint16, value uint16) error {
+	register, err := r.register(address)
+	if err != nil {
+		return err
+	}
+	if r.defaultByteOrder&LittleEndian != 0 {
+		binary.LittleEndian.PutUint16(register, value)
+	} else {
+		binary.BigEndian.PutUint16(register, value)
+	}
+	return nil
+}
+
+// SetInt16 sets int16 value to given address. NB: Int16 size is 1 register (16bits, 2 bytes).
+func (r *Registers) SetInt16(address uint16, value int16) error {
+	return r.SetUint16(address, uint16(value))
+}
+
+// SetUint32 sets uint32 value to given address. NB: Uint32 size is 2 registers (32bits, 4 bytes).
+func (r *Registers) SetUint32(address uint16, value uint32) error {
+	return r.SetUint32WithByteOrder(address, value, useDefaultByteOrder)
+}
+
+// SetUint32WithByteOrder sets uint32 value to given address with given byte order. NB: Uint32 size is 2 registers (32bits, 4 bytes).
+func (r *Registers) SetUint32WithByteOrder(address uint16, value uint32, byteOrder ByteOrder) error {
+	if byteOrder == useDefaultByteOrder {
+		byteOrder = r.defaultByteOrder
+	}
+	if _, err := r.doubleRegister(address, byteOrder); err != nil {
+		return err
+	}
+	b := make([]byte, 4)
+	if byteOrder&LittleEndian != 0 {
+		binary.LittleEndian.PutUint32(b, value)
+	} else {
+		binary.BigEndian.PutUint32(b, value)
+	}
+	r.setWords(address, b, byteOrder)
+	return nil
+}
+
+// SetInt32 sets int32 value to given address. NB: Int32 size is 2 registers (32bits, 4 bytes).
+func (r *Registers) SetInt32(address uint16, value int32) error {
+	return r.SetUint32WithByteOrder(address, uint32(value), useDefaultByteOrder)
+}
+
+// SetInt32WithByteOrder sets int32 value to given address with given byte order. NB: Int32 size is 2 registers (32bits, 4 bytes).
+func (r *Registers) SetInt32WithByteOrder(address uint16, value int32, byteOrder ByteOrder) error {
+	return r.SetUint32WithByteOrder(address, uint32(value), byteOrder)
+}
+
+// SetUint64 sets uint64 value to given address. NB: Uint64 size is 4 registers (64bits, 8 bytes).
+func (r *Registers) SetUint64(address uint16, value uint64) error {
+	return r.SetUint64WithByteOrder(address, value, useDefaultByteOrder)
+}
+
+// SetUint64WithByteOrder sets uint64 value to given address with given byte order. NB: Uint64 size is 4 registers (64bits, 8 bytes).
+func (r *Registers) SetUint64WithByteOrder(address uint16, value uint64, byteOrder ByteOrder) error {
+	if byteOrder == useDefaultByteOrder {
+		byteOrder = r.defaultByteOrder
+	}
+	if _, err := r.quadRegister(address, byteOrder); err != nil {
+		return err
+	}
+	b := make([]byte, 8)
+	if byteOrder&LittleEndian != 0 {
+		binary.LittleEndian.PutUint64(b, value)
+	} else {
+		binary.BigEndian.PutUint64(b, value)
+	}
+	r.setWords(address, b, byteOrder)
+	return nil
+}
+
+// SetInt64 sets int64 value to given address. NB: Int64 size is 4 registers (64bits, 8 bytes).
+func (r *Registers) SetInt64(address uint16, value int64) error {
+	return r.SetUint64WithByteOrder(address, uint64(value), useDefaultByteOrder)
+}
+
+// SetInt64WithByteOrder sets int64 value to given address with given byte order. NB: Int64 size is 4 registers (64bits, 8 bytes).
+func (r *Registers) SetInt64WithByteOrder(address uint16, value int64, byteOrder ByteOrder) error {
+	return r.SetUint64WithByteOrder(address, uint64(value), byteOrder)
+}
+
+// SetFloat32 sets float32 value to given address. NB: Float32 size is 2 registers (32bits, 4 bytes).
+func (r *Registers) SetFloat32(address uint16, value float32) error {
+	return r.SetUint32WithByteOrder(address, math.Float32bits(value), useDefaultByteOrder)
+}
+
+// SetFloat32WithByteOrder sets float32 value to given address with given byte order. NB: Float32 size is 2 registers (32bits, 4 bytes).
+func (r *Registers) SetFloat32WithByteOrder(address uint16, value float32, byteOrder ByteOrder) error {
+	return r.SetUint32WithByteOrder(address, math.Float32bits(value), byteOrder)
+}
+
+// SetFloat64 sets float64 value to given address. NB: Float64 size is 4 registers (64bits, 8 bytes).
+func (r *Registers) SetFloat64(address uint16, value float64) error {
+	return r.SetUint64WithByteOrder(address, math.Float64bits(value), useDefaultByteOrder)
+}
+
+// SetFloat64WithByteOrder sets float64 value to given address with given byte order. NB: Float64 size is 4 registers (64bits, 8 bytes).
+func (r *Registers) SetFloat64WithByteOrder(address uint16, value float64, byteOrder ByteOrder) error {
+	return r.SetUint64WithByteOrder(address, math.Float64bits(value), byteOrder)
+}
+
+// SetString sets string value to given address. Value shorter than length is padded with null (0x0) bytes.
+func (r *Registers) SetString(address uint16, length uint8, value string) error {
+	return r.SetStringWithByteOrder(address, length, value, useDefaultByteOrder)
+}
+
+// SetStringWithByteOrder sets string value to given address with given byte order. Value shorter than length is
+// padded with null (0x0) bytes. This is inverse of StringWithByteOrder.
+func (r *Registers) SetStringWithByteOrder(address uint16, length uint8, value string, byteOrder ByteOrder) error {
+	if byteOrder == useDefaultByteOrder {
+		byteOrder = r.defaultByteOrder
+	}
+	if len(value) > int(length) {
+		return errors.New("string value is longer than length")
+	}
+	if address < r.startAddress {
+		return errors.New("address under startAddress bounds")
+	}
+	startIndex := int(address-r.startAddress) * 2
+	endIndex := startIndex + int(length)
+	if length%2 != 0 {
+		endIndex++ // last character of odd length string occupies whole register
+	}
+	if endIndex > len(r.data) {
+		return errors.New("address over data bounds")
+	}
+
+	rawBytes := make([]byte, endIndex-startIndex)
+	copy(rawBytes, value)
+	if byteOrder&BigEndian != 0 {
+		for i := 1; i < len(rawBytes); i += 2 {
+			rawBytes[i-1], rawBytes[i] = rawBytes[i], rawBytes[i-1]
+		}
+	}
+	copy(r.data[startIndex:endIndex], rawBytes)
+	return nil
+}
+
+// setWords copies value bytes (in high word first order) to registers starting from given address. When byte order
+// has LowWordFirst flag the order of words is reversed.
+func (r *Registers) setWords(address uint16, value []byte, byteOrder ByteOrder) {
+	startIndex := int(address-r.startAddress) * 2
+	words := len(value) / 2
+	for i := 0; i < words; i++ {
+		w := i
+		if byteOrder&LowWordFirst != 0 {
+			w = words - 1 - i
+		}
+		r.data[startIndex+w*2] = value[i*2]
+		r.data[startIndex+w*2+1] = value[i*2+1]
+	}
+}
@@ -0,0 +1,220 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewRegistersForWrite(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		whenStartAddress uint16
+		whenQuantity     uint16
+		expectData       []byte
+		expectStart      uint16
+		expectEnd        uint16
+		expectError      string
+	}{
+		{
+			name:             "ok",
+			whenStartAddress: 10,
+			whenQuantity:     2,
+			expectData:       []byte{0x0, 0x0, 0x0, 0x0},
+			expectStart:      10,
+			expectEnd:        11,
+		},
+		{
+			name:         "nok, zero quantity",
+			whenQuantity: 0,
+			expectError:  "quantity must be at least 1",
+		},
+		{
+			name:             "nok, over address range",
+			whenStartAddress: 65535,
+			whenQuantity:     1,
+			expectError:      "startAddress+quantity is over register address range",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRegistersForWrite(tc.whenStartAddress, tc.whenQuantity)
+
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.Nil(t, r)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectData, r.Bytes())
+			start, end := r.AddressRange()
+			assert.Equal(t, tc.expectStart, start)
+			assert.Equal(t, tc.expectEnd, end)
+		})
+	}
+}
+
+func TestRegisters_SetX(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        func(r *Registers) error
+		expect      []byte
+		expectError string
+	}{
+		{
+			name:   "register",
+			when:   func(r *Registers) error { return r.SetRegister(11, []byte{0xCA, 0xFE}) },
+			expect: []byte{0x0, 0x0, 0xCA, 0xFE, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "bit 8 and bit 0",
+			when:   func(r *Registers) error { _ = r.SetBit(10, 8, true); return r.SetBit(10, 0, true) },
+			expect: []byte{0x01, 0x01, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "uint8 high byte",
+			when:   func(r *Registers) error { return r.SetUint8(10, true, 0xFF) },
+			expect: []byte{0xFF, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "int8 low byte",
+			when:   func(r *Registers) error { return r.SetInt8(10, false, -1) },
+			expect: []byte{0x0, 0xFF, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "uint16",
+			when:   func(r *Registers) error { return r.SetUint16(14, 0x0102) },
+			expect: []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x01, 0x02},
+		},
+		{
+			name:   "int16",
+			when:   func(r *Registers) error { return r.SetInt16(10, -2) },
+			expect: []byte{0xFF, 0xFE, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "uint32 default byte order",
+			when:   func(r *Registers) error { return r.SetUint32(10, 0x01020304) },
+			expect: []byte{0x01, 0x02, 0x03, 0x04, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "uint32 big endian low word first",
+			when:   func(r *Registers) error { return r.SetUint32WithByteOrder(10, 0x01020304, BigEndianLowWordFirst) },
+			expect: []byte{0x03, 0x04, 0x01, 0x02, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "int32 little endian low word first",
+			when:   func(r *Registers) error { return r.SetInt32WithByteOrder(10, 0x01020304, LittleEndianLowWordFirst) },
+			expect: []byte{0x02, 0x01, 0x04, 0x03, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name: "uint64 big endian low word first",
+			when: func(r *Registers) error {
+				return r.SetUint64WithByteOrder(11, 0x0102030405060708, BigEndianLowWordFirst)
+			},
+			expect: []byte{0x0, 0x0, 0x07, 0x08, 0x05, 0x06, 0x03, 0x04, 0x01, 0x02},
+		},
+		{
+			name:   "int64",
+			when:   func(r *Registers) error { return r.SetInt64(10, -1) },
+			expect: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x0, 0x0},
+		},
+		{
+			name:   "float32",
+			when:   func(r *Registers) error { return r.SetFloat32(10, 2.5) },
+			expect: []byte{0x40, 0x20, 0x00, 0x00, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "float64 little endian high word first",
+			when:   func(r *Registers) error { return r.SetFloat64WithByteOrder(10, 1, LittleEndianHighWordFirst) },
+			expect: []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0xf0, 0x3f, 0x0, 0x0},
+		},
+		{
+			name:   "string odd length",
+			when:   func(r *Registers) error { return r.SetString(10, 5, "abc") },
+			expect: []byte{'b', 'a', 0x0, 'c', 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:   "string little endian",
+			when:   func(r *Registers) error { return r.SetStringWithByteOrder(12, 4, "abcd", LittleEndian) },
+			expect: []byte{0x0, 0x0, 0x0, 0x0, 'a', 'b', 'c', 'd', 0x0, 0x0},
+		},
+		{
+			name:        "nok, string longer than length",
+			when:        func(r *Registers) error { return r.SetString(10, 2, "abc") },
+			expect:      make([]byte, 10),
+			expectError: "string value is longer than length",
+		},
+		{
+			name:        "nok, string over data bounds",
+			when:        func(r *Registers) error { return r.SetString(14, 3, "abc") },
+			expect:      make([]byte, 10),
+			expectError: "address over data bounds",
+		},
+		{
+			name:        "nok, register data length",
+			when:        func(r *Registers) error { return r.SetRegister(10, []byte{0x1}) },
+			expect:      make([]byte, 10),
+			expectError: "register data must be 2 bytes",
+		},
+		{
+			name:        "nok, bit out of range",
+			when:        func(r *Registers) error { return r.SetBit(10, 16, true) },
+			expect:      make([]byte, 10),
+			expectError: "bit value more than register (16bit) contains",
+		},
+		{
+			name:        "nok, uint32 over end",
+			when:        func(r *Registers) error { return r.SetUint32(14, 1) },
+			expect:      make([]byte, 10),
+			expectError: "address over startAddress+quantity bounds",
+		},
+		{
+			name:        "nok, uint64 under start",
+			when:        func(r *Registers) error { return r.SetUint64(9, 1) },
+			expect:      make([]byte, 10),
+			expectError: "address under startAddress bounds",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRegistersForWrite(10, 5)
+			assert.NoError(t, err)
+
+			err = tc.when(r)
+
+			assert.Equal(t, tc.expect, r.Bytes())
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegisters_SetX_roundTrip(t *testing.T) {
+	r, err := NewRegistersForWrite(0, 16)
+	assert.NoError(t, err)
+	r.WithByteOrder(LittleEndianLowWordFirst)
+
+	assert.NoError(t, r.SetInt16(0, -1234))
+	assert.NoError(t, r.SetUint32(1, 2923517522))
+	assert.NoError(t, r.SetInt64(3, -72623859790382856))
+	assert.NoError(t, r.SetFloat32(7, 1.85))
+	assert.NoError(t, r.SetFloat64(9, -1.0123))
+	assert.NoError(t, r.SetString(13, 5, "hello"))
+
+	i16, _ := r.Int16(0)
+	assert.Equal(t, int16(-1234), i16)
+	u32, _ := r.Uint32(1)
+	assert.Equal(t, uint32(2923517522), u32)
+	i64, _ := r.Int64(3)
+	assert.Equal(t, int64(-72623859790382856), i64)
+	f32, _ := r.Float32(7)
+	assert.Equal(t, float32(1.85), f32)
+	f64, _ := r.Float64(9)
+	assert.Equal(t, -1.0123, f64)
+	s, _ := r.String(13, 5)
+	assert.Equal(t, "hello", s)
+}
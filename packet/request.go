@@ -16,8 +16,9 @@ type Request interface {
 	ExpectedResponseLength() int
 }
 
-// ParseTCPRequest parses given bytes into modbus TCP request packet or returns error
-func ParseTCPRequest(data []byte) (Request, error) {
+// ParseTCPRequest parses given bytes into modbus TCP request packet or returns error. Options are passed to request
+// parse functions that support them (i.e. WithLenientCoilsPadding for Write Multiple Coils).
+func ParseTCPRequest(data []byte, opts ...ParseOption) (Request, error) {
 	if len(data) < 8 {
 		return nil, ErrTCPDataTooShort
 	}
@@ -35,21 +36,34 @@ func ParseTCPRequest(data []byte) (Request, error) {
 		return ParseWriteSingleCoilRequestTCP(data)
 	case FunctionWriteSingleRegister: // 0x06
 		return ParseWriteSingleRegisterRequestTCP(data)
+	case FunctionReadExceptionStatus: // 0x07
+		return ParseReadExceptionStatusRequestTCP(data)
+	case FunctionDiagnostics: // 0x08
+		return ParseDiagnosticsRequestTCP(data)
 	case FunctionWriteMultipleCoils: // 0x0f
-		return ParseWriteMultipleCoilsRequestTCP(data)
+		return ParseWriteMultipleCoilsRequestTCP(data, opts...)
 	case FunctionWriteMultipleRegisters: // 0x10
 		return ParseWriteMultipleRegistersRequestTCP(data)
 	case FunctionReadServerID: // 0x11
 		return ParseReadServerIDRequestTCP(data)
+	case FunctionMaskWriteRegister: // 0x16
+		return ParseMaskWriteRegisterRequestTCP(data)
 	case FunctionReadWriteMultipleRegisters: // 0x17
 		return ParseReadWriteMultipleRegistersRequestTCP(data)
+	case FunctionReadFIFOQueue: // 0x18
+		return ParseReadFIFOQueueRequestTCP(data)
+	case FunctionEncapsulatedInterface: // 0x2b
+		if len(data) > 8 && data[8] == MEICANopenGeneralReference {
+			return ParseCANopenGeneralReferenceRequestTCP(data)
+		}
+		return ParseReadDeviceIdentificationRequestTCP(data)
 	default:
 		return nil, NewErrorParseTCP(ErrIllegalFunction, fmt.Sprintf("unknown function code parsed: %v", functionCode))
 	}
 }
 
 // ParseRTURequestWithCRC checks packet CRC and parses given bytes into modbus RTU request packet or returns error
-func ParseRTURequestWithCRC(data []byte) (Response, error) {
+func ParseRTURequestWithCRC(data []byte, opts ...ParseOption) (Response, error) {
 	dataLen := len(data)
 	if dataLen < 4 {
 		return nil, errors.New("data is too short to be a Modbus RTU packet")
@@ -59,12 +73,12 @@ func ParseRTURequestWithCRC(data []byte) (Response, error) {
 	if packetCRC != actualCRC {
 		return nil, ErrInvalidCRC
 	}
-	return ParseRTURequest(data)
+	return ParseRTURequest(data, opts...)
 }
 
 // ParseRTURequest parses given bytes into modbus RTU request packet or returns error
-// Does not check CRC.
-func ParseRTURequest(data []byte) (Request, error) {
+// Does not check CRC. Options are passed to request parse functions that support them.
+func ParseRTURequest(data []byte, opts ...ParseOption) (Request, error) {
 	if len(data) < 4 {
 		return nil, errors.New("data is too short to be a Modbus RTU packet")
 	}
@@ -82,14 +96,27 @@ func ParseRTURequest(data []byte) (Request, error) {
 		return ParseWriteSingleCoilRequestRTU(data)
 	case FunctionWriteSingleRegister: // 0x06
 		return ParseWriteSingleRegisterRequestRTU(data)
+	case FunctionReadExceptionStatus: // 0x07
+		return ParseReadExceptionStatusRequestRTU(data)
+	case FunctionDiagnostics: // 0x08
+		return ParseDiagnosticsRequestRTU(data)
 	case FunctionWriteMultipleCoils: // 0x0f
-		return ParseWriteMultipleCoilsRequestRTU(data)
+		return ParseWriteMultipleCoilsRequestRTU(data, opts...)
 	case FunctionWriteMultipleRegisters: // 0x10
 		return ParseWriteMultipleRegistersRequestRTU(data)
 	case FunctionReadServerID: // 0x11
 		return ParseReadServerIDRequestRTU(data)
+	case FunctionMaskWriteRegister: // 0x16
+		return ParseMaskWriteRegisterRequestRTU(data)
 	case FunctionReadWriteMultipleRegisters: // 0x17
 		return ParseReadWriteMultipleRegistersRequestRTU(data)
+	case FunctionReadFIFOQueue: // 0x18
+		return ParseReadFIFOQueueRequestRTU(data)
+	case FunctionEncapsulatedInterface: // 0x2b
+		if len(data) > 2 && data[2] == MEICANopenGeneralReference {
+			return ParseCANopenGeneralReferenceRequestRTU(data)
+		}
+		return ParseReadDeviceIdentificationRequestRTU(data)
 	default:
 		return nil, fmt.Errorf("unknown function code parsed: %v", functionCode)
 	}
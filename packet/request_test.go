@@ -9,6 +9,7 @@ func TestParseTCPRequest(t *testing.T) {
 	var testCases = []struct {
 		name        string
 		when        []byte
+		whenOpts    []ParseOption
 		expect      interface{}
 		expectError string
 	}{
@@ -118,6 +119,26 @@ func TestParseTCPRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "nok, FunctionWriteMultipleCoils with non-zero padding bits",
+			when:        []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x08, 0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d},
+			expect:      (*WriteMultipleCoilsRequestTCP)(nil),
+			expectError: "coils data has non-zero padding bits after coil count 3",
+		},
+		{
+			name:     "ok, FunctionWriteMultipleCoils with non-zero padding bits in lenient mode",
+			when:     []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x08, 0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d},
+			whenOpts: []ParseOption{WithLenientCoilsPadding()},
+			expect: &WriteMultipleCoilsRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x0138},
+				WriteMultipleCoilsRequest: WriteMultipleCoilsRequest{
+					UnitID:       0x11,
+					StartAddress: 0x0410,
+					CoilCount:    0x03,
+					Data:         []byte{0x0d},
+				},
+			},
+		},
 		{
 			name: "ok, FunctionWriteMultipleRegisters",
 			when: []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x0d, 0x11, 0x10, 0x04, 0x10, 0x00, 0x03, 0x06, 0x00, 0xC8, 0x00, 0x82, 0x87, 0x01},
@@ -200,7 +221,7 @@ func TestParseTCPRequest(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := ParseTCPRequest(tc.when)
+			result, err := ParseTCPRequest(tc.when, tc.whenOpts...)
 
 			assert.Equal(t, tc.expect, result)
 			if tc.expectError != "" {
@@ -370,6 +391,7 @@ func TestParseRTURequest(t *testing.T) {
 	var testCases = []struct {
 		name        string
 		when        []byte
+		whenOpts    []ParseOption
 		expect      interface{}
 		expectError string
 	}{
@@ -384,6 +406,25 @@ func TestParseRTURequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "nok, WriteMultipleCoilsRequestRTU with non-zero padding bits",
+			when:        []byte{0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d, 0x00, 0x00},
+			expect:      (*WriteMultipleCoilsRequestRTU)(nil),
+			expectError: "coils data has non-zero padding bits after coil count 3",
+		},
+		{
+			name:     "ok, WriteMultipleCoilsRequestRTU with non-zero padding bits in lenient mode",
+			when:     []byte{0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d, 0x00, 0x00},
+			whenOpts: []ParseOption{WithLenientCoilsPadding()},
+			expect: &WriteMultipleCoilsRequestRTU{
+				WriteMultipleCoilsRequest: WriteMultipleCoilsRequest{
+					UnitID:       0x11,
+					StartAddress: 0x0410,
+					CoilCount:    0x03,
+					Data:         []byte{0x0d},
+				},
+			},
+		},
 		{
 			name:        "nok, too short",
 			when:        []byte{0x10, 0x01, 0x00},
@@ -400,7 +441,7 @@ func TestParseRTURequest(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := ParseRTURequest(tc.when)
+			result, err := ParseRTURequest(tc.when, tc.whenOpts...)
 
 			assert.Equal(t, tc.expect, result)
 			if tc.expectError != "" {
@@ -37,12 +37,25 @@ func ParseTCPResponse(data []byte) (Response, error) {
 		return ParseWriteSingleCoilResponseTCP(data)
 	case FunctionWriteSingleRegister: // 0x06
 		return ParseWriteSingleRegisterResponseTCP(data)
+	case FunctionReadExceptionStatus: // 0x07
+		return ParseReadExceptionStatusResponseTCP(data)
+	case FunctionDiagnostics: // 0x08
+		return ParseDiagnosticsResponseTCP(data)
 	case FunctionWriteMultipleCoils: // 0x0f
 		return ParseWriteMultipleCoilsResponseTCP(data)
 	case FunctionWriteMultipleRegisters: // 0x10
 		return ParseWriteMultipleRegistersResponseTCP(data)
+	case FunctionMaskWriteRegister: // 0x16
+		return ParseMaskWriteRegisterResponseTCP(data)
 	case FunctionReadWriteMultipleRegisters: // 0x17
 		return ParseReadWriteMultipleRegistersResponseTCP(data)
+	case FunctionReadFIFOQueue: // 0x18
+		return ParseReadFIFOQueueResponseTCP(data)
+	case FunctionEncapsulatedInterface: // 0x2b
+		if len(data) > 8 && data[8] == MEICANopenGeneralReference {
+			return ParseCANopenGeneralReferenceResponseTCP(data)
+		}
+		return ParseReadDeviceIdentificationResponseTCP(data)
 	case FunctionReadServerID: // 0x11
 		return ParseReadServerIDResponseTCP(data)
 	default:
@@ -87,12 +100,25 @@ func ParseRTUResponse(data []byte) (Response, error) {
 		return ParseWriteSingleCoilResponseRTU(data)
 	case FunctionWriteSingleRegister: // 0x06
 		return ParseWriteSingleRegisterResponseRTU(data)
+	case FunctionReadExceptionStatus: // 0x07
+		return ParseReadExceptionStatusResponseRTU(data)
+	case FunctionDiagnostics: // 0x08
+		return ParseDiagnosticsResponseRTU(data)
 	case FunctionWriteMultipleCoils: // 0x0f
 		return ParseWriteMultipleCoilsResponseRTU(data)
 	case FunctionWriteMultipleRegisters: // 0x10
 		return ParseWriteMultipleRegistersResponseRTU(data)
+	case FunctionMaskWriteRegister: // 0x16
+		return ParseMaskWriteRegisterResponseRTU(data)
 	case FunctionReadWriteMultipleRegisters: // 0x17
 		return ParseReadWriteMultipleRegistersResponseRTU(data)
+	case FunctionReadFIFOQueue: // 0x18
+		return ParseReadFIFOQueueResponseRTU(data)
+	case FunctionEncapsulatedInterface: // 0x2b
+		if len(data) > 2 && data[2] == MEICANopenGeneralReference {
+			return ParseCANopenGeneralReferenceResponseRTU(data)
+		}
+		return ParseReadDeviceIdentificationResponseRTU(data)
 	case FunctionReadServerID: // 0x11
 		return ParseReadServerIDResponseRTU(data)
 	default:
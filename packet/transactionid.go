@@ -0,0 +1,33 @@
+package packet
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// TransactionIDGenerator returns MBAP header transaction ID for new Modbus TCP request. Generator is given to
+// modbus.Builder (Builder.TransactionIDGenerator), modbus.RequestDefaults or modbus.ClientConfig to control transaction
+// IDs of requests they create or send.
+type TransactionIDGenerator func() uint16
+
+// RandomTransactionID returns random transaction ID in range 1-65535. NewXXXRequestTCP constructors use it for
+// transaction IDs.
+func RandomTransactionID() uint16 {
+	return uint16(1 + rand.Intn(65534))
+}
+
+// NewSequentialTransactionIDGenerator creates generator that returns transaction IDs sequentially starting from given
+// value. Value 0 is skipped when counter wraps around. Generator is safe for concurrent use. Using separate generator
+// for each connection results collision-free transaction IDs for up to 65535 requests in flight per connection.
+func NewSequentialTransactionIDGenerator(start uint16) TransactionIDGenerator {
+	counter := atomic.Uint32{}
+	counter.Store(uint32(start))
+	return func() uint16 {
+		for {
+			id := uint16(counter.Add(1) - 1)
+			if id != 0 {
+				return id
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package packet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewSequentialTransactionIDGenerator(t *testing.T) {
+	g := NewSequentialTransactionIDGenerator(65534)
+
+	assert.Equal(t, uint16(65534), g())
+	assert.Equal(t, uint16(65535), g())
+	assert.Equal(t, uint16(1), g()) // 0 is skipped
+	assert.Equal(t, uint16(2), g())
+}
@@ -3,7 +3,6 @@ package packet
 import (
 	"encoding/binary"
 	"fmt"
-	"math/rand"
 )
 
 // WriteMultipleCoilsRequestTCP is TCP Request for Write Multiple Coils (FC=15)
@@ -56,7 +55,7 @@ func NewWriteMultipleCoilsRequestTCP(unitID uint8, startAddress uint16, coils []
 	coilsBytes := CoilsToBytes(coils)
 	return &WriteMultipleCoilsRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		WriteMultipleCoilsRequest: WriteMultipleCoilsRequest{
@@ -84,8 +83,9 @@ func (r WriteMultipleCoilsRequestTCP) ExpectedResponseLength() int {
 	return 6 + 6
 }
 
-// ParseWriteMultipleCoilsRequestTCP parses given bytes into WriteMultipleCoilsRequestTCP
-func ParseWriteMultipleCoilsRequestTCP(data []byte) (*WriteMultipleCoilsRequestTCP, error) {
+// ParseWriteMultipleCoilsRequestTCP parses given bytes into WriteMultipleCoilsRequestTCP. Coils byte count must match
+// coil count and padding bits in the last coils byte must be zero (unless WithLenientCoilsPadding option is used).
+func ParseWriteMultipleCoilsRequestTCP(data []byte, opts ...ParseOption) (*WriteMultipleCoilsRequestTCP, error) {
 	header, err := ParseMBAPHeader(data)
 	if err != nil {
 		return nil, err
@@ -114,6 +114,13 @@ func ParseWriteMultipleCoilsRequestTCP(data []byte) (*WriteMultipleCoilsRequestT
 		tmpErr.Packet.Function = FunctionWriteMultipleCoils
 		return nil, tmpErr
 	}
+	if msg := validateCoilsData(coilCount, data[13:13+int(coilsBytesCount)], newParseOptions(opts)); msg != "" {
+		tmpErr := NewErrorParseTCP(ErrIllegalDataValue, msg)
+		tmpErr.Packet.TransactionID = header.TransactionID
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionWriteMultipleCoils
+		return nil, tmpErr
+	}
 	var coilsData []byte
 	if coilsBytesCount > 0 {
 		coilsData = make([]byte, coilsBytesCount)
@@ -168,8 +175,9 @@ func (r WriteMultipleCoilsRequestRTU) ExpectedResponseLength() int {
 	return 6 + 2
 }
 
-// ParseWriteMultipleCoilsRequestRTU parses given bytes into WriteMultipleCoilsRequestRTU
-func ParseWriteMultipleCoilsRequestRTU(data []byte) (*WriteMultipleCoilsRequestRTU, error) {
+// ParseWriteMultipleCoilsRequestRTU parses given bytes into WriteMultipleCoilsRequestRTU. Coils byte count must match
+// coil count and padding bits in the last coils byte must be zero (unless WithLenientCoilsPadding option is used).
+func ParseWriteMultipleCoilsRequestRTU(data []byte, opts ...ParseOption) (*WriteMultipleCoilsRequestRTU, error) {
 	dLen := len(data)
 	if dLen < 7 {
 		return nil, NewErrorParseRTU(ErrServerFailure, "received data length too short to be valid packet")
@@ -196,6 +204,12 @@ func ParseWriteMultipleCoilsRequestRTU(data []byte) (*WriteMultipleCoilsRequestR
 		tmpErr.Packet.Function = FunctionWriteMultipleCoils
 		return nil, tmpErr
 	}
+	if msg := validateCoilsData(coilCount, data[7:7+int(coilsBytesCount)], newParseOptions(opts)); msg != "" {
+		tmpErr := NewErrorParseRTU(ErrIllegalDataValue, msg)
+		tmpErr.Packet.UnitID = unitID
+		tmpErr.Packet.Function = FunctionWriteMultipleCoils
+		return nil, tmpErr
+	}
 	var coilsData []byte
 	if coilsBytesCount > 0 {
 		coilsData = make([]byte, coilsBytesCount)
@@ -236,6 +250,24 @@ func (r WriteMultipleCoilsRequest) bytes(bytes []byte) []byte {
 	return bytes
 }
 
+// validateCoilsData checks that coils data length matches coil count and unused bits in the last byte are zero.
+// Returns error message or empty string when data is valid.
+func validateCoilsData(coilCount uint16, coilsData []byte, opts parseOptions) string {
+	expectedBytes := (int(coilCount) + 7) / 8
+	if len(coilsData) != expectedBytes {
+		return fmt.Sprintf("coils byte count %v does not match coil count %v, expected %v bytes", len(coilsData), coilCount, expectedBytes)
+	}
+	unusedBits := coilCount % 8
+	if opts.lenientCoilsPadding || unusedBits == 0 {
+		return ""
+	}
+	paddingMask := ^byte(0) << unusedBits
+	if coilsData[expectedBytes-1]&paddingMask != 0 {
+		return fmt.Sprintf("coils data has non-zero padding bits after coil count %v", coilCount)
+	}
+	return ""
+}
+
 // CoilsToBytes converts slice of coil states (as bool values) to byte slice.
 func CoilsToBytes(coils []bool) []byte {
 	cLen := len(coils)
@@ -254,3 +286,28 @@ func CoilsToBytes(coils []bool) []byte {
 	}
 	return result
 }
+
+// WriteMultipleCoilsRequestASCII is ASCII Request for Write Multiple Coils (FC=15). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteMultipleCoilsRequestASCII struct {
+	WriteMultipleCoilsRequestRTU
+}
+
+// NewWriteMultipleCoilsRequestASCII creates new instance of Write Multiple Coils ASCII request
+func NewWriteMultipleCoilsRequestASCII(unitID uint8, startAddress uint16, coils []bool) (*WriteMultipleCoilsRequestASCII, error) {
+	r, err := NewWriteMultipleCoilsRequestRTU(unitID, startAddress, coils)
+	if err != nil {
+		return nil, err
+	}
+	return &WriteMultipleCoilsRequestASCII{WriteMultipleCoilsRequestRTU: *r}, nil
+}
+
+// Bytes returns WriteMultipleCoilsRequestASCII packet as bytes form
+func (r WriteMultipleCoilsRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteMultipleCoilsRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r WriteMultipleCoilsRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.WriteMultipleCoilsRequestRTU.ExpectedResponseLength())
+}
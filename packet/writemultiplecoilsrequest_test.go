@@ -355,6 +355,7 @@ func TestParseWriteMultipleCoilsRequestTCP(t *testing.T) {
 	var testCases = []struct {
 		name        string
 		when        []byte
+		whenOpts    []ParseOption
 		expect      *WriteMultipleCoilsRequestTCP
 		expectError string
 	}{
@@ -404,11 +405,37 @@ func TestParseWriteMultipleCoilsRequestTCP(t *testing.T) {
 			expect:      nil,
 			expectError: "received data coils bytes length does not match write data length",
 		},
+		{
+			name:        "nok, coils byte count does not match coil count",
+			when:        []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x09, 0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x02, 0x05, 0x00},
+			expect:      nil,
+			expectError: "coils byte count 2 does not match coil count 3, expected 1 bytes",
+		},
+		{
+			name:        "nok, non-zero padding bits",
+			when:        []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x08, 0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d},
+			expect:      nil,
+			expectError: "coils data has non-zero padding bits after coil count 3",
+		},
+		{
+			name:     "ok, non-zero padding bits in lenient mode",
+			when:     []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x08, 0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d},
+			whenOpts: []ParseOption{WithLenientCoilsPadding()},
+			expect: &WriteMultipleCoilsRequestTCP{
+				MBAPHeader: MBAPHeader{TransactionID: 0x0138},
+				WriteMultipleCoilsRequest: WriteMultipleCoilsRequest{
+					UnitID:       0x11,
+					StartAddress: 0x0410,
+					CoilCount:    0x03,
+					Data:         []byte{0x0d},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := ParseWriteMultipleCoilsRequestTCP(tc.when)
+			result, err := ParseWriteMultipleCoilsRequestTCP(tc.when, tc.whenOpts...)
 
 			assert.Equal(t, tc.expect, result)
 			if tc.expectError != "" {
@@ -424,6 +451,7 @@ func TestParseWriteMultipleCoilsRequestRTU(t *testing.T) {
 	var testCases = []struct {
 		name        string
 		when        []byte
+		whenOpts    []ParseOption
 		expect      *WriteMultipleCoilsRequestRTU
 		expectError string
 	}{
@@ -481,11 +509,36 @@ func TestParseWriteMultipleCoilsRequestRTU(t *testing.T) {
 			expect:      nil,
 			expectError: "received data coils bytes length does not match write data length",
 		},
+		{
+			name:        "nok, coils byte count does not match coil count",
+			when:        []byte{0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x02, 0x05, 0x00, 0xFF, 0xFF},
+			expect:      nil,
+			expectError: "coils byte count 2 does not match coil count 3, expected 1 bytes",
+		},
+		{
+			name:        "nok, non-zero padding bits",
+			when:        []byte{0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d, 0xFF, 0xFF},
+			expect:      nil,
+			expectError: "coils data has non-zero padding bits after coil count 3",
+		},
+		{
+			name:     "ok, non-zero padding bits in lenient mode",
+			when:     []byte{0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d, 0xFF, 0xFF},
+			whenOpts: []ParseOption{WithLenientCoilsPadding()},
+			expect: &WriteMultipleCoilsRequestRTU{
+				WriteMultipleCoilsRequest: WriteMultipleCoilsRequest{
+					UnitID:       0x11,
+					StartAddress: 0x0410,
+					CoilCount:    0x03,
+					Data:         []byte{0x0d},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := ParseWriteMultipleCoilsRequestRTU(tc.when)
+			result, err := ParseWriteMultipleCoilsRequestRTU(tc.when, tc.whenOpts...)
 
 			assert.Equal(t, tc.expect, result)
 			if tc.expectError != "" {
@@ -117,3 +117,14 @@ func (r WriteMultipleCoilsResponse) bytes(bytes []byte) []byte {
 	binary.BigEndian.PutUint16(bytes[4:6], r.CoilCount)
 	return bytes
 }
+
+// WriteMultipleCoilsResponseASCII is ASCII Response for Write Multiple Coils (FC=15). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteMultipleCoilsResponseASCII struct {
+	WriteMultipleCoilsResponseRTU
+}
+
+// Bytes returns WriteMultipleCoilsResponseASCII packet as bytes form
+func (r WriteMultipleCoilsResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteMultipleCoilsResponseRTU.Bytes())
+}
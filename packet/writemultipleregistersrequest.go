@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"math/rand"
 )
 
 // WriteMultipleRegistersRequestTCP is TCP Request for Write Multiple Registers (FC=16)
@@ -61,7 +60,7 @@ func NewWriteMultipleRegistersRequestTCP(unitID uint8, startAddress uint16, data
 
 	return &WriteMultipleRegistersRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		WriteMultipleRegistersRequest: WriteMultipleRegistersRequest{
@@ -243,3 +242,28 @@ func (r WriteMultipleRegistersRequest) bytes(bytes []byte) []byte {
 	copy(bytes[7:], r.Data)
 	return bytes
 }
+
+// WriteMultipleRegistersRequestASCII is ASCII Request for Write Multiple Registers (FC=16). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteMultipleRegistersRequestASCII struct {
+	WriteMultipleRegistersRequestRTU
+}
+
+// NewWriteMultipleRegistersRequestASCII creates new instance of Write Multiple Registers ASCII request
+func NewWriteMultipleRegistersRequestASCII(unitID uint8, startAddress uint16, data []byte) (*WriteMultipleRegistersRequestASCII, error) {
+	r, err := NewWriteMultipleRegistersRequestRTU(unitID, startAddress, data)
+	if err != nil {
+		return nil, err
+	}
+	return &WriteMultipleRegistersRequestASCII{WriteMultipleRegistersRequestRTU: *r}, nil
+}
+
+// Bytes returns WriteMultipleRegistersRequestASCII packet as bytes form
+func (r WriteMultipleRegistersRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteMultipleRegistersRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r WriteMultipleRegistersRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.WriteMultipleRegistersRequestRTU.ExpectedResponseLength())
+}
@@ -117,3 +117,14 @@ func (r WriteMultipleRegistersResponse) bytes(bytes []byte) []byte {
 	binary.BigEndian.PutUint16(bytes[4:6], r.RegisterCount)
 	return bytes
 }
+
+// WriteMultipleRegistersResponseASCII is ASCII Response for Write Multiple Registers (FC=16). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteMultipleRegistersResponseASCII struct {
+	WriteMultipleRegistersResponseRTU
+}
+
+// Bytes returns WriteMultipleRegistersResponseASCII packet as bytes form
+func (r WriteMultipleRegistersResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteMultipleRegistersResponseRTU.Bytes())
+}
@@ -2,7 +2,6 @@ package packet
 
 import (
 	"encoding/binary"
-	"math/rand"
 )
 
 // WriteSingleCoilRequestTCP is TCP Request for Write Single Coil (FC=05)
@@ -51,7 +50,7 @@ type WriteSingleCoilRequest struct {
 func NewWriteSingleCoilRequestTCP(unitID uint8, address uint16, coilState bool) (*WriteSingleCoilRequestTCP, error) {
 	return &WriteSingleCoilRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		WriteSingleCoilRequest: WriteSingleCoilRequest{
@@ -74,8 +73,8 @@ func (r WriteSingleCoilRequestTCP) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r WriteSingleCoilRequestTCP) ExpectedResponseLength() int {
-	// response = 6 header len + 1 unitID + 1 fc + 1 coil byte count + 2 data len
-	return 6 + 3 + 2
+	// response = 6 header len + 1 unitID + 1 fc + 2 address + 2 coil data
+	return 6 + 6
 }
 
 // ParseWriteSingleCoilRequestTCP parses given bytes into WriteSingleCoilRequestTCP
@@ -135,8 +134,8 @@ func (r WriteSingleCoilRequestRTU) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r WriteSingleCoilRequestRTU) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode + 2 coils byte count + 2 coils data
-	return 6
+	// response = 1 UnitID + 1 functionCode + 2 address + 2 coil data + 2 CRC
+	return 6 + 2
 }
 
 // ParseWriteSingleCoilRequestRTU parses given bytes into WriteSingleCoilRequestRTU
@@ -196,3 +195,28 @@ func (r WriteSingleCoilRequest) bytes(bytes []byte) []byte {
 	binary.BigEndian.PutUint16(bytes[4:6], coilState)
 	return bytes
 }
+
+// WriteSingleCoilRequestASCII is ASCII Request for Write Single Coil (FC=05). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteSingleCoilRequestASCII struct {
+	WriteSingleCoilRequestRTU
+}
+
+// NewWriteSingleCoilRequestASCII creates new instance of Write Single Coil ASCII request
+func NewWriteSingleCoilRequestASCII(unitID uint8, address uint16, coilState bool) (*WriteSingleCoilRequestASCII, error) {
+	r, err := NewWriteSingleCoilRequestRTU(unitID, address, coilState)
+	if err != nil {
+		return nil, err
+	}
+	return &WriteSingleCoilRequestASCII{WriteSingleCoilRequestRTU: *r}, nil
+}
+
+// Bytes returns WriteSingleCoilRequestASCII packet as bytes form
+func (r WriteSingleCoilRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteSingleCoilRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r WriteSingleCoilRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.WriteSingleCoilRequestRTU.ExpectedResponseLength())
+}
@@ -130,3 +130,14 @@ func (r WriteSingleCoilResponse) bytes(bytes []byte) []byte {
 	binary.BigEndian.PutUint16(bytes[4:6], coilState)
 	return bytes
 }
+
+// WriteSingleCoilResponseASCII is ASCII Response for Write Single Coil (FC=05). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteSingleCoilResponseASCII struct {
+	WriteSingleCoilResponseRTU
+}
+
+// Bytes returns WriteSingleCoilResponseASCII packet as bytes form
+func (r WriteSingleCoilResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteSingleCoilResponseRTU.Bytes())
+}
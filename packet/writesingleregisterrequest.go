@@ -2,7 +2,6 @@ package packet
 
 import (
 	"encoding/binary"
-	"math/rand"
 )
 
 // WriteSingleRegisterRequestTCP is TCP Request for Write Single Register (FC=06)
@@ -45,7 +44,7 @@ type WriteSingleRegisterRequest struct {
 func NewWriteSingleRegisterRequestTCP(unitID uint8, address uint16, data []byte) (*WriteSingleRegisterRequestTCP, error) {
 	w := &WriteSingleRegisterRequestTCP{
 		MBAPHeader: MBAPHeader{
-			TransactionID: uint16(1 + rand.Intn(65534)),
+			TransactionID: RandomTransactionID(),
 			ProtocolID:    0,
 		},
 		WriteSingleRegisterRequest: WriteSingleRegisterRequest{
@@ -124,8 +123,8 @@ func (r WriteSingleRegisterRequestRTU) Bytes() []byte {
 
 // ExpectedResponseLength returns length of bytes that valid response to this request would be
 func (r WriteSingleRegisterRequestRTU) ExpectedResponseLength() int {
-	// response = 1 UnitID + 1 functionCode + 2 address + 2 register data
-	return 6
+	// response = 1 UnitID + 1 functionCode + 2 address + 2 register data + 2 CRC
+	return 6 + 2
 }
 
 // ParseWriteSingleRegisterRequestRTU parses given bytes into WriteSingleRegisterRequestRTU
@@ -168,3 +167,28 @@ func (r WriteSingleRegisterRequest) bytes(bytes []byte) []byte {
 	copy(bytes[4:6], r.Data[:])
 	return bytes
 }
+
+// WriteSingleRegisterRequestASCII is ASCII Request for Write Single Register (FC=06). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteSingleRegisterRequestASCII struct {
+	WriteSingleRegisterRequestRTU
+}
+
+// NewWriteSingleRegisterRequestASCII creates new instance of Write Single Register ASCII request
+func NewWriteSingleRegisterRequestASCII(unitID uint8, address uint16, data []byte) (*WriteSingleRegisterRequestASCII, error) {
+	r, err := NewWriteSingleRegisterRequestRTU(unitID, address, data)
+	if err != nil {
+		return nil, err
+	}
+	return &WriteSingleRegisterRequestASCII{WriteSingleRegisterRequestRTU: *r}, nil
+}
+
+// Bytes returns WriteSingleRegisterRequestASCII packet as bytes form
+func (r WriteSingleRegisterRequestASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteSingleRegisterRequestRTU.Bytes())
+}
+
+// ExpectedResponseLength returns length of bytes that valid response to this request would be
+func (r WriteSingleRegisterRequestASCII) ExpectedResponseLength() int {
+	return asciiLength(r.WriteSingleRegisterRequestRTU.ExpectedResponseLength())
+}
@@ -123,3 +123,14 @@ func (r WriteSingleRegisterResponse) bytes(bytes []byte) []byte {
 func (r WriteSingleRegisterResponse) AsRegisters(address uint16) (*Registers, error) {
 	return NewRegisters(r.Data[:], address)
 }
+
+// WriteSingleRegisterResponseASCII is ASCII Response for Write Single Register (FC=06). Packet is RTU packet without CRC encoded as
+// hexadecimal characters, started with ':', checked with LRC and ended with CRLF.
+type WriteSingleRegisterResponseASCII struct {
+	WriteSingleRegisterResponseRTU
+}
+
+// Bytes returns WriteSingleRegisterResponseASCII packet as bytes form
+func (r WriteSingleRegisterResponseASCII) Bytes() []byte {
+	return RTUToASCII(r.WriteSingleRegisterResponseRTU.Bytes())
+}
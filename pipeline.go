@@ -0,0 +1,190 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"io"
+	"os"
+	"time"
+)
+
+// PipelineResult is result of single request sent with Client.DoPipelined
+type PipelineResult struct {
+	Response packet.Response
+	Err      error
+}
+
+type pipelinedRequest struct {
+	index        int
+	data         []byte
+	functionCode uint8
+	sentAt       time.Time
+}
+
+// DoPipelined sends given Modbus requests to modbus server keeping up to ClientConfig.PipelineWindow requests in
+// flight at the same time and returns results in same order as requests were given. Pipelining cuts total duration of
+// many requests over high-latency links (i.e. cellular, satellite) roughly by window factor. Responses are matched to
+// requests by MBAP transaction ID so requests in the same window should have different transaction IDs.
+//
+// Requests are sent one by one (as with Do) when window is 1 or client is Modbus RTU client as RTU packets have no
+// transaction ID to match responses with.
+func (c *Client) DoPipelined(ctx context.Context, requests []packet.Request) []PipelineResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results := make([]PipelineResult, len(requests))
+	if c.pipelineWindow <= 1 || c.isRTU {
+		for i, req := range requests {
+			results[i].Response, results[i].Err = c.doRequest(ctx, req)
+		}
+		return results
+	}
+	if c.conn == nil {
+		for i := range results {
+			results[i].Err = &ErrClientNotConnected
+		}
+		return results
+	}
+
+	failRest := func(pending []pipelinedRequest, next int, err error) []PipelineResult {
+		for _, p := range pending {
+			results[p.index].Err = err
+			c.requestDone(p.data, p.functionCode, p.sentAt, err)
+		}
+		for i := next; i < len(requests); i++ {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	var buffer []byte
+	pending := make([]pipelinedRequest, 0, c.pipelineWindow)
+	next := 0
+	for next < len(requests) || len(pending) > 0 {
+		for next < len(requests) && len(pending) < c.pipelineWindow {
+			req := requests[next]
+			if req == nil {
+				results[next].Err = errors.New("request can not be nil")
+				next++
+				continue
+			}
+			if c.requestTransformer != nil {
+				var err error
+				if req, err = transformRequest(c.requestTransformer, req); err != nil {
+					results[next].Err = err
+					next++
+					continue
+				}
+			}
+			data := req.Bytes()
+			c.assignTransactionID(data)
+			c.requestStart(data, req)
+			if err := c.write(data); err != nil {
+				c.requestDone(data, req.FunctionCode(), c.timeNow(), err)
+				return failRest(pending, next, err)
+			}
+			pending = append(pending, pipelinedRequest{
+				index:        next,
+				data:         data,
+				functionCode: req.FunctionCode(),
+				sentAt:       c.timeNow(),
+			})
+			next++
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		frame, err := c.readFrame(ctx, &buffer)
+		if err != nil {
+			return failRest(pending, next, err)
+		}
+		for i, p := range pending {
+			if !c.matchesTransactionID(p.data, frame) {
+				continue // with tolerated transaction ID 0 responses are matched to requests in order they were sent
+			}
+			c.restoreTransactionID(p.data, frame)
+			results[p.index].Response, results[p.index].Err = c.parseFrame(p.data, frame)
+			c.requestDone(p.data, p.functionCode, p.sentAt, results[p.index].Err)
+			pending = append(pending[:i], pending[i+1:]...)
+			break
+		}
+		// frames with unknown transaction ID are late responses to earlier requests and are ignored
+	}
+	return results
+}
+
+func (c *Client) write(data []byte) error {
+	if err := c.conn.SetWriteDeadline(c.timeNow().Add(c.writeTimeout)); err != nil {
+		return err
+	}
+	if c.hooks != nil {
+		c.hooks.BeforeWrite(data)
+	}
+	if _, err := c.conn.Write(data); err != nil {
+		return &ClientError{Err: err}
+	}
+	c.lastActivity = c.timeNow()
+	return nil
+}
+
+// readFrame reads from connection until buffer contains complete Modbus TCP frame and returns it. Bytes following the
+// frame are left into buffer.
+func (c *Client) readFrame(ctx context.Context, buffer *[]byte) ([]byte, error) {
+	received := [tcpPacketMaxLen]byte{}
+	readTimeout := time.After(c.readTimeout)
+	for {
+		if len(*buffer) >= 6 {
+			frameLen := 6 + int(binary.BigEndian.Uint16((*buffer)[4:6]))
+			if frameLen > tcpPacketMaxLen {
+				return nil, &ErrPacketTooLong
+			}
+			if len(*buffer) >= frameLen {
+				frame := append([]byte(nil), (*buffer)[:frameLen]...)
+				*buffer = (*buffer)[frameLen:]
+				return frame, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-readTimeout:
+			return nil, &ClientError{Err: errReadTimeoutExceeded}
+		default:
+		}
+
+		_ = c.conn.SetReadDeadline(c.timeNow().Add(500 * time.Microsecond)) // max 0.5ms block time for read per iteration
+		n, err := c.conn.Read(received[:])
+		if c.hooks != nil {
+			c.hooks.AfterEachRead(received[:n], n, err)
+		}
+		*buffer = append(*buffer, received[:n]...)
+		if errors.Is(err, io.EOF) && n == 0 {
+			return nil, &ClientError{Err: errors.New("connection closed before complete packet was received")}
+		}
+		if err != nil && !(errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, io.EOF)) {
+			return nil, &ClientError{Err: err}
+		}
+	}
+}
+
+func (c *Client) parseFrame(request []byte, frame []byte) (packet.Response, error) {
+	if c.hooks != nil {
+		c.hooks.BeforeParse(frame)
+	}
+	if c.validateResponses {
+		if err := validateResponse(request, frame, false); err != nil {
+			return nil, err
+		}
+	}
+	if c.rawResponses {
+		return newRawResponse(frame, c.parseResponseFunc), nil
+	}
+	if errPacket := c.asProtocolErrorFunc(frame); errPacket != nil {
+		return nil, newExceptionError(errPacket)
+	}
+	return c.parseResponseFunc(frame)
+}
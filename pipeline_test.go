@@ -0,0 +1,74 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestClient_DoPipelined(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	req1 := exampleFC1Request()
+	req2 := exampleFC1Request()
+	req2.(*packet.ReadCoilsRequestTCP).TransactionID = 0x1235
+	req3 := exampleFC1Request()
+	req3.(*packet.ReadCoilsRequestTCP).TransactionID = 0x1236
+
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Times(3).Return(nil)
+	conn.On("Write", req1.Bytes()).Once().Return(0, nil)
+	conn.On("Write", req2.Bytes()).Once().Return(0, nil)
+	conn.On("Write", req3.Bytes()).Once().Return(0, nil)
+
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(21, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{
+				0x12, 0x35, 0x0, 0x0, 0x0, 0x3, 0x1, 0x81, 0x2, // exception for second request comes first
+				0x12, 0x34, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1,
+				0x12, // first byte of third response
+			})
+		}).Once()
+	conn.On("Read", mock.Anything).
+		Return(10, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x36, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1})
+		}).Once()
+
+	client := NewTCPClientWithConfig(ClientConfig{PipelineWindow: 2})
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	results := client.DoPipelined(context.Background(), []packet.Request{req1, req2, req3})
+
+	assert.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, exampleFC1Response(), results[0].Response)
+
+	assert.EqualError(t, results[1].Err, "Illegal data address")
+	assert.Nil(t, results[1].Response)
+
+	assert.NoError(t, results[2].Err)
+	expect := exampleFC1Response().(*packet.ReadCoilsResponseTCP)
+	expect.TransactionID = 0x1236
+	assert.Equal(t, expect, results[2].Response)
+
+	conn.AssertExpectations(t)
+}
+
+func TestClient_DoPipelined_notConnected(t *testing.T) {
+	client := NewTCPClientWithConfig(ClientConfig{PipelineWindow: 2})
+
+	results := client.DoPipelined(context.Background(), []packet.Request{exampleFC1Request()})
+
+	assert.Equal(t, []PipelineResult{{Err: &ErrClientNotConnected}}, results)
+}
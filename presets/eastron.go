@@ -0,0 +1,62 @@
+package presets
+
+import (
+	"github.com/aldas/go-modbus-client"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// EastronSDM630 is preset name of Eastron SDM630 three phase energy meter (input registers)
+const EastronSDM630 = "eastron_sdm630"
+
+// eastronField creates Eastron meter field. Measurements are float32 input registers sent high word first.
+func eastronField(name string, address uint16, unit string) modbus.Field {
+	f := field(name, address, modbus.FieldTypeFloat32, unit)
+	f.ByteOrder = packet.BigEndianHighWordFirst
+	f.FunctionCode = packet.FunctionReadInputRegisters
+	return f
+}
+
+func eastronSDM630Preset() Preset {
+	return Preset{
+		Name:        EastronSDM630,
+		Description: "Eastron SDM630 three phase energy meter measurements",
+		Fields: modbus.Fields{
+			eastronField("l1_voltage", 0x0000, "V"),
+			eastronField("l2_voltage", 0x0002, "V"),
+			eastronField("l3_voltage", 0x0004, "V"),
+			eastronField("l1_current", 0x0006, "A"),
+			eastronField("l2_current", 0x0008, "A"),
+			eastronField("l3_current", 0x000A, "A"),
+			eastronField("l1_power", 0x000C, "W"),
+			eastronField("l2_power", 0x000E, "W"),
+			eastronField("l3_power", 0x0010, "W"),
+			eastronField("l1_apparent_power", 0x0012, "VA"),
+			eastronField("l2_apparent_power", 0x0014, "VA"),
+			eastronField("l3_apparent_power", 0x0016, "VA"),
+			eastronField("l1_reactive_power", 0x0018, "var"),
+			eastronField("l2_reactive_power", 0x001A, "var"),
+			eastronField("l3_reactive_power", 0x001C, "var"),
+			eastronField("l1_power_factor", 0x001E, ""),
+			eastronField("l2_power_factor", 0x0020, ""),
+			eastronField("l3_power_factor", 0x0022, ""),
+			eastronField("average_voltage", 0x002A, "V"),
+			eastronField("average_current", 0x002E, "A"),
+			eastronField("sum_current", 0x0030, "A"),
+			eastronField("total_power", 0x0034, "W"),
+			eastronField("total_apparent_power", 0x0038, "VA"),
+			eastronField("total_reactive_power", 0x003C, "var"),
+			eastronField("total_power_factor", 0x003E, ""),
+			eastronField("frequency", 0x0046, "Hz"),
+			eastronField("import_energy", 0x0048, "kWh"),
+			eastronField("export_energy", 0x004A, "kWh"),
+			eastronField("import_reactive_energy", 0x004C, "kvarh"),
+			eastronField("export_reactive_energy", 0x004E, "kvarh"),
+			eastronField("l1_l2_voltage", 0x00C8, "V"),
+			eastronField("l2_l3_voltage", 0x00CA, "V"),
+			eastronField("l3_l1_voltage", 0x00CC, "V"),
+			eastronField("neutral_current", 0x00E0, "A"),
+			eastronField("total_energy", 0x0156, "kWh"),
+			eastronField("total_reactive_energy", 0x0158, "kvarh"),
+		},
+	}
+}
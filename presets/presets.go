@@ -0,0 +1,91 @@
+// Package presets contains built-in field definitions (register maps) for common device families that can be loaded
+// into modbus.Builder by name so the same register maps do not have to be re-typed for every project.
+//
+// Preset fields do not have server address and unit ID set. Load fills them from Builder defaults.
+//
+//	b := modbus.NewRequestBuilder("tcp://192.168.0.10:502", 1)
+//	if err := presets.Load(b, presets.EastronSDM630); err != nil {
+//		return err
+//	}
+//	requests, err := b.SplitTCP()
+package presets
+
+import (
+	"fmt"
+	"github.com/aldas/go-modbus-client"
+	"sort"
+	"sync"
+)
+
+// Preset is named set of field definitions for a device family
+type Preset struct {
+	// Name is unique name preset is registered and loaded with
+	Name string
+	// Description describes device family and register map version the preset is based on
+	Description string
+	// Fields are field definitions of the preset. Fields have no server address and unit ID set.
+	Fields modbus.Fields
+}
+
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string]Preset{}
+)
+
+func init() {
+	for _, p := range []Preset{
+		sunSpecCommonPreset(),
+		sunSpecInverterThreePhasePreset(),
+		victronVM3P75CTPreset(),
+		eastronSDM630Preset(),
+	} {
+		Register(p)
+	}
+}
+
+// Register registers preset by its name. Registering preset with existing name replaces that preset.
+func Register(preset Preset) {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	presets[preset.Name] = preset
+}
+
+// Lookup returns preset registered with given name. Returned preset fields are copy and can be modified.
+func Lookup(name string) (Preset, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	p, ok := presets[name]
+	if !ok {
+		return Preset{}, false
+	}
+	p.Fields = append(modbus.Fields(nil), p.Fields...)
+	return p, true
+}
+
+// Names returns names of all registered presets in sorted order
+func Names() []string {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	result := make([]string, 0, len(presets))
+	for name := range presets {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Load adds fields of preset with given name into Builder. Server address and unit ID of fields are set to Builder
+// defaults.
+func Load(b *modbus.Builder, name string) error {
+	p, ok := Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown preset: %v", name)
+	}
+	b.AddAllWithDefaults(p.Fields)
+	return nil
+}
+
+// field creates field definition with given name, address, type and unit
+func field(name string, address uint16, fieldType modbus.FieldType, unit string) modbus.Field {
+	return modbus.Field{Name: name, Address: address, Type: fieldType, Unit: unit}
+}
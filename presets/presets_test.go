@@ -0,0 +1,85 @@
+package presets
+
+import (
+	"github.com/aldas/go-modbus-client"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuiltInPresets(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		expectRequests int
+	}{
+		{name: SunSpecCommon, expectRequests: 1},
+		{name: SunSpecInverterThreePhase, expectRequests: 1},
+		{name: VictronVM3P75CT, expectRequests: 2},
+		{name: EastronSDM630, expectRequests: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, ok := Lookup(tc.name)
+			assert.True(t, ok)
+			assert.NotEmpty(t, p.Description)
+
+			fields := make(modbus.Fields, len(p.Fields))
+			for i, f := range p.Fields {
+				assert.Empty(t, f.ServerAddress)
+				f.ServerAddress = "localhost:502"
+				fields[i] = f
+			}
+			assert.NoError(t, fields.Validate())
+
+			b := modbus.NewRequestBuilder("localhost:502", 1)
+			assert.NoError(t, Load(b, tc.name))
+			reqs, err := b.SplitTCP()
+			assert.NoError(t, err)
+			assert.Len(t, reqs, tc.expectRequests)
+			total := 0
+			for _, r := range reqs {
+				assert.Equal(t, "localhost:502", r.ServerAddress)
+				assert.Equal(t, uint8(1), r.UnitID)
+				total += len(r.Fields)
+			}
+			assert.Equal(t, len(p.Fields), total)
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+
+	assert.Subset(t, names, []string{EastronSDM630, SunSpecCommon, SunSpecInverterThreePhase, VictronVM3P75CT})
+	assert.IsNonDecreasing(t, names)
+}
+
+func TestRegister(t *testing.T) {
+	Register(Preset{
+		Name:   "test_device",
+		Fields: modbus.Fields{{Name: "power", Address: 10, Type: modbus.FieldTypeUint16, UnitID: 2}},
+	})
+	t.Cleanup(func() {
+		presetsMu.Lock()
+		delete(presets, "test_device")
+		presetsMu.Unlock()
+	})
+
+	p, ok := Lookup("test_device")
+	assert.True(t, ok)
+	p.Fields[0].Address = 99 // lookup returns copy
+
+	b := modbus.NewRequestBuilder("localhost:502", 1)
+	assert.NoError(t, Load(b, "test_device"))
+	reqs, err := b.SplitTCP()
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 1)
+	assert.Equal(t, uint16(10), reqs[0].StartAddress)
+	assert.Equal(t, uint8(2), reqs[0].UnitID) // unit ID of preset field is kept
+}
+
+func TestLoad_unknownPreset(t *testing.T) {
+	err := Load(modbus.NewRequestBuilder("localhost:502", 1), "unknown")
+
+	assert.EqualError(t, err, "unknown preset: unknown")
+}
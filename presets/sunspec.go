@@ -0,0 +1,37 @@
+package presets
+
+import (
+	"github.com/aldas/go-modbus-client"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+const (
+	// SunSpecCommon is preset name of SunSpec Common model (model 1) located at base address 40000
+	SunSpecCommon = "sunspec_common"
+	// SunSpecInverterThreePhase is preset name of SunSpec three phase inverter model (model 103) following Common
+	// model of length 66 at base address 40000. Values are raw, apply `*_sf` scale factor fields (value*10^sf) to them.
+	// Use modbus.DiscoverSunSpecTCP to find models of devices with different model chain.
+	SunSpecInverterThreePhase = "sunspec_inverter_three_phase"
+)
+
+// sunSpecBaseAddress is register address of `SunS` marker SunSpec register map starts with
+const sunSpecBaseAddress = 40000
+
+func sunSpecCommonPreset() Preset {
+	marker := field("sunspec_id", sunSpecBaseAddress, modbus.FieldTypeUint32, "")
+	marker.ByteOrder = packet.BigEndianHighWordFirst
+
+	return Preset{
+		Name:        SunSpecCommon,
+		Description: "SunSpec Common model (model 1) at base address 40000",
+		Fields:      append(modbus.Fields{marker}, modbus.SunSpecModelFields(1, sunSpecBaseAddress+2)...),
+	}
+}
+
+func sunSpecInverterThreePhasePreset() Preset {
+	return Preset{
+		Name:        SunSpecInverterThreePhase,
+		Description: "SunSpec three phase inverter model (model 103) at address 40070 following Common model",
+		Fields:      modbus.SunSpecModelFields(103, sunSpecBaseAddress+70),
+	}
+}
@@ -0,0 +1,44 @@
+package presets
+
+import (
+	"fmt"
+	"github.com/aldas/go-modbus-client"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// VictronVM3P75CT is preset name of Victron Energy Meter VM-3P75CT (Modbus TCP, holding registers)
+const VictronVM3P75CT = "victron_vm_3p75ct"
+
+// victronField creates Victron energy meter field. 32bit values are sent low word first.
+func victronField(name string, address uint16, fieldType modbus.FieldType, unit string, scale float64) modbus.Field {
+	f := field(name, address, fieldType, unit)
+	f.ByteOrder = packet.BigEndianLowWordFirst
+	f.Scale = scale
+	return f
+}
+
+func victronVM3P75CTPreset() Preset {
+	fields := modbus.Fields{
+		victronField("product_id", 0x1000, modbus.FieldTypeUint16, "", 0),
+		victronField("frequency", 0x3032, modbus.FieldTypeUint16, "Hz", 0.01),
+		victronField("energy_forward", 0x3034, modbus.FieldTypeUint32, "kWh", 0.01),
+		victronField("energy_reverse", 0x3036, modbus.FieldTypeUint32, "kWh", 0.01),
+	}
+	for phase := uint16(1); phase <= 3; phase++ {
+		base := 0x3040 + 8*(phase-1)
+		fields = append(fields,
+			victronField(fmt.Sprintf("l%d_voltage", phase), base, modbus.FieldTypeInt16, "V", 0.01),
+			victronField(fmt.Sprintf("l%d_current", phase), base+1, modbus.FieldTypeInt16, "A", 0.01),
+			victronField(fmt.Sprintf("l%d_energy_forward", phase), base+2, modbus.FieldTypeUint32, "kWh", 0.01),
+			victronField(fmt.Sprintf("l%d_energy_reverse", phase), base+4, modbus.FieldTypeUint32, "kWh", 0.01),
+			victronField(fmt.Sprintf("l%d_power", phase), base+6, modbus.FieldTypeInt32, "W", 0),
+		)
+	}
+	fields = append(fields, victronField("power", 0x3080, modbus.FieldTypeInt32, "W", 0))
+
+	return Preset{
+		Name:        VictronVM3P75CT,
+		Description: "Victron Energy Meter VM-3P75CT (product ID 0xA1B1) total and per phase measurements",
+		Fields:      fields,
+	}
+}
@@ -0,0 +1,283 @@
+package modbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/modbustest"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// runTestProxy runs proxy accepting connections on random port. handshake reads proxy request and returns address
+// connection is tunneled to.
+func runTestProxy(t *testing.T, handshake func(conn net.Conn, br *bufio.Reader) (string, error)) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				br := bufio.NewReader(conn)
+				target, err := handshake(conn, br)
+				if err != nil {
+					return
+				}
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				go func() { _, _ = io.Copy(upstream, br) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return "127.0.0.1:" + strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
+
+func socks5TestHandshake(user string, pass string) func(conn net.Conn, br *bufio.Reader) (string, error) {
+	return func(conn net.Conn, br *bufio.Reader) (string, error) {
+		greeting := make([]byte, 2)
+		if _, err := io.ReadFull(br, greeting); err != nil {
+			return "", err
+		}
+		if _, err := io.ReadFull(br, make([]byte, greeting[1])); err != nil {
+			return "", err
+		}
+		if user == "" {
+			_, _ = conn.Write([]byte{0x05, 0x00})
+		} else {
+			_, _ = conn.Write([]byte{0x05, 0x02})
+			l := make([]byte, 2)
+			if _, err := io.ReadFull(br, l); err != nil {
+				return "", err
+			}
+			u := make([]byte, l[1])
+			_, _ = io.ReadFull(br, u)
+			_, _ = io.ReadFull(br, l[:1])
+			p := make([]byte, l[0])
+			_, _ = io.ReadFull(br, p)
+			if string(u) != user || string(p) != pass {
+				_, _ = conn.Write([]byte{0x01, 0x01})
+				return "", errors.New("invalid credentials")
+			}
+			_, _ = conn.Write([]byte{0x01, 0x00})
+		}
+
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(br, header); err != nil {
+			return "", err
+		}
+		var host string
+		switch header[3] {
+		case 0x01:
+			ip := make([]byte, 4)
+			_, _ = io.ReadFull(br, ip)
+			host = net.IP(ip).String()
+		case 0x04:
+			ip := make([]byte, 16)
+			_, _ = io.ReadFull(br, ip)
+			host = net.IP(ip).String()
+		case 0x03:
+			l, _ := br.ReadByte()
+			name := make([]byte, l)
+			_, _ = io.ReadFull(br, name)
+			host = string(name)
+		}
+		port := make([]byte, 2)
+		_, _ = io.ReadFull(br, port)
+		_, _ = conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 127, 0, 0, 1, 0, 0})
+		return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+	}
+}
+
+func httpConnectTestHandshake(conn net.Conn, br *bufio.Reader) (string, error) {
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		return "", err
+	}
+	if req.Method != http.MethodConnect {
+		_, _ = conn.Write([]byte("HTTP/1.1 405 Method Not Allowed\r\n\r\n"))
+		return "", errors.New("not connect")
+	}
+	if req.Header.Get("Proxy-Authorization") != "Basic dXNlcjpzZWNyZXQ=" { // user:secret
+		_, _ = conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+		return "", errors.New("invalid credentials")
+	}
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	return req.Host, nil
+}
+
+func runTestModbusServer(t *testing.T, ctx context.Context) string {
+	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
+		resp := packet.ReadHoldingRegistersResponseTCP{
+			MBAPHeader: packet.MBAPHeader{TransactionID: binary.BigEndian.Uint16(received), ProtocolID: 0},
+			ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+				UnitID:          1,
+				RegisterByteLen: 2,
+				Data:            []byte{0xca, 0xfe},
+			},
+		}
+		return resp.Bytes(), false
+	}
+	addr, err := modbustest.RunServerOnRandomPort(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func TestClient_Connect_throughProxy(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenProxy   func(t *testing.T) string
+		expectError string
+	}{
+		{
+			name: "ok, socks5",
+			whenProxy: func(t *testing.T) string {
+				return "socks5://" + runTestProxy(t, socks5TestHandshake("", ""))
+			},
+		},
+		{
+			name: "ok, socks5 with username and password",
+			whenProxy: func(t *testing.T) string {
+				return "socks5://user:secret@" + runTestProxy(t, socks5TestHandshake("user", "secret"))
+			},
+		},
+		{
+			name: "nok, socks5 with invalid password",
+			whenProxy: func(t *testing.T) string {
+				return "socks5://user:wrong@" + runTestProxy(t, socks5TestHandshake("user", "secret"))
+			},
+			expectError: "proxy handshake failed: socks authentication failed",
+		},
+		{
+			name: "ok, http connect",
+			whenProxy: func(t *testing.T) string {
+				return "http://user:secret@" + runTestProxy(t, httpConnectTestHandshake)
+			},
+		},
+		{
+			name: "nok, http connect without credentials",
+			whenProxy: func(t *testing.T) string {
+				return "http://" + runTestProxy(t, httpConnectTestHandshake)
+			},
+			expectError: "proxy handshake failed: http proxy responded with status: 407 Proxy Authentication Required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			serverAddr := runTestModbusServer(t, ctx)
+			proxy := tc.whenProxy(t)
+
+			client := NewTCPClient()
+			err := client.Connect(ctx, "tcp://"+serverAddr+"?proxy="+proxy)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			defer client.Close()
+
+			req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+			resp, err := client.Do(ctx, req)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte{0xca, 0xfe}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+		})
+	}
+}
+
+func TestClient_Connect_proxyFromConfig(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	serverAddr := runTestModbusServer(t, ctx)
+
+	client := NewTCPClientWithConfig(ClientConfig{
+		Proxy:        "socks5://" + runTestProxy(t, socks5TestHandshake("", "")),
+		LocalAddress: "127.0.0.1",
+	})
+	err := client.Connect(ctx, serverAddr)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, "127.0.0.1", client.conn.LocalAddr().(*net.TCPAddr).IP.String())
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err = client.Do(ctx, req)
+	assert.NoError(t, err)
+}
+
+func TestClient_Connect_localAddress(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	serverAddr := runTestModbusServer(t, ctx)
+
+	client := NewTCPClient()
+	err := client.Connect(ctx, serverAddr+"?local_address=127.0.0.1:0")
+	assert.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, "127.0.0.1", client.conn.LocalAddr().(*net.TCPAddr).IP.String())
+}
+
+func TestClient_Connect_proxyErrors(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenConfig  ClientConfig
+		whenAddress string
+		expectError string
+	}{
+		{
+			name:        "nok, invalid proxy in config",
+			whenConfig:  ClientConfig{Proxy: "socks5://127.0.0.1"},
+			whenAddress: "127.0.0.1:502",
+			expectError: "invalid proxy: proxy address must contain host and port",
+		},
+		{
+			name:        "nok, invalid local address in config",
+			whenConfig:  ClientConfig{LocalAddress: "127.0.0.1:x"},
+			whenAddress: "127.0.0.1:502",
+			expectError: "invalid local address: local address has invalid port",
+		},
+		{
+			name: "nok, custom dial function",
+			whenConfig: ClientConfig{DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+				return nil, errors.New("should not be called")
+			}},
+			whenAddress: "127.0.0.1:502?local_address=127.0.0.1",
+			expectError: "proxy and local address can not be used with custom DialContextFunc",
+		},
+		{
+			name:        "nok, proxy with udp",
+			whenAddress: "udp://127.0.0.1:502?proxy=socks5://127.0.0.1:1080",
+			expectError: "proxy can not be used with network: udp",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTCPClientWithConfig(tc.whenConfig)
+			err := client.Connect(context.Background(), tc.whenAddress)
+			assert.ErrorContains(t, err, tc.expectError)
+		})
+	}
+}
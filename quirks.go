@@ -0,0 +1,132 @@
+package modbus
+
+import (
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"sync"
+	"time"
+)
+
+const (
+	// QuirksProfileOneBasedAddressing is quirks profile for devices that document register addresses starting from 1
+	// instead of 0. Addresses of requests are decremented by one before sending.
+	QuirksProfileOneBasedAddressing = "one_based_addressing"
+	// QuirksProfileNoWriteMultipleRegisters is quirks profile for devices that do not support Write Multiple Registers
+	// (FC16) function.
+	QuirksProfileNoWriteMultipleRegisters = "no_write_multiple_registers"
+)
+
+// DeviceQuirks describes non-standard behaviour of device model. Quirks are registered by profile name with
+// RegisterDeviceQuirks and applied to client and request splitting settings when configuration references that profile
+// (see ClientConfig.QuirksProfile and RequestDefaults.QuirksProfile) so workarounds are not repeated for every device.
+type DeviceQuirks struct {
+	// NoWriteMultipleRegisters marks device that does not support Write Multiple Registers (FC16). FC16 requests
+	// writing single register are sent as Write Single Register (FC6) requests and device responds with FC6 response.
+	// FC16 requests writing more than one register result an error.
+	NoWriteMultipleRegisters bool
+	// AddressOffset is added to addresses of every request (i.e. -1 for devices with off-by-one addressing)
+	AddressOffset int
+	// DelayAfterWrite is minimal delay after write request before next request is sent to the device
+	DelayAfterWrite time.Duration
+	// MaxRegistersPerRead is maximum quantity of registers in single read request. 0 means protocol maximum (125).
+	MaxRegistersPerRead uint16
+	// AllowedGap is maximum amount of unused registers (or coils) between fields read with the same request for devices
+	// that misbehave when unmapped registers are read. 0 means no limit, AllowedGapNone allows only contiguous fields.
+	// Used by FieldsToRequests.
+	AllowedGap int
+}
+
+var (
+	quirksMu       sync.RWMutex
+	quirksProfiles = map[string]DeviceQuirks{
+		QuirksProfileOneBasedAddressing:       {AddressOffset: -1},
+		QuirksProfileNoWriteMultipleRegisters: {NoWriteMultipleRegisters: true},
+	}
+)
+
+// RegisterDeviceQuirks registers quirks for given profile name. Registering existing profile replaces its quirks.
+func RegisterDeviceQuirks(profile string, quirks DeviceQuirks) {
+	quirksMu.Lock()
+	defer quirksMu.Unlock()
+	quirksProfiles[profile] = quirks
+}
+
+// LookupDeviceQuirks returns quirks registered for given profile name
+func LookupDeviceQuirks(profile string) (DeviceQuirks, bool) {
+	quirksMu.RLock()
+	defer quirksMu.RUnlock()
+	q, ok := quirksProfiles[profile]
+	return q, ok
+}
+
+func lookupQuirksProfile(profile string) (DeviceQuirks, error) {
+	q, ok := LookupDeviceQuirks(profile)
+	if !ok {
+		return DeviceQuirks{}, fmt.Errorf("unknown device quirks profile: %v", profile)
+	}
+	return q, nil
+}
+
+// RequestTransformer returns RequestTransformer applying request related quirks or nil when there are none
+func (q DeviceQuirks) RequestTransformer() RequestTransformer {
+	var transformers []RequestTransformer
+	if q.NoWriteMultipleRegisters {
+		transformers = append(transformers, writeMultipleRegistersAsSingle)
+	}
+	if q.AddressOffset != 0 {
+		transformers = append(transformers, OffsetAddress(q.AddressOffset))
+	}
+	switch len(transformers) {
+	case 0:
+		return nil
+	case 1:
+		return transformers[0]
+	}
+	return ChainRequestTransformers(transformers...)
+}
+
+// withRequestTransformer returns given transformer chained with quirks request transformer. Quirks are applied last
+// as they describe how device expects requests to be.
+func (q DeviceQuirks) withRequestTransformer(transformer RequestTransformer) RequestTransformer {
+	quirksTransformer := q.RequestTransformer()
+	if quirksTransformer == nil {
+		return transformer
+	}
+	if transformer == nil {
+		return quirksTransformer
+	}
+	return ChainRequestTransformers(transformer, quirksTransformer)
+}
+
+// writeMultipleRegistersAsSingle converts Write Multiple Registers (FC16) request writing single register to Write
+// Single Register (FC6) request
+func writeMultipleRegistersAsSingle(req packet.Request) (packet.Request, error) {
+	var r packet.WriteMultipleRegistersRequest
+	switch fc16 := req.(type) {
+	case *packet.WriteMultipleRegistersRequestTCP:
+		r = fc16.WriteMultipleRegistersRequest
+	case *packet.WriteMultipleRegistersRequestRTU:
+		r = fc16.WriteMultipleRegistersRequest
+	case *packet.WriteMultipleRegistersRequestASCII:
+		r = fc16.WriteMultipleRegistersRequest
+	default:
+		return req, nil
+	}
+	if r.RegisterCount != 1 || len(r.Data) != 2 {
+		return nil, fmt.Errorf("device does not support writing multiple registers (FC16), register count: %v", r.RegisterCount)
+	}
+	single := packet.WriteSingleRegisterRequest{
+		UnitID:  r.UnitID,
+		Address: r.StartAddress,
+		Data:    [2]byte{r.Data[0], r.Data[1]},
+	}
+	switch fc16 := req.(type) {
+	case *packet.WriteMultipleRegistersRequestTCP:
+		return &packet.WriteSingleRegisterRequestTCP{MBAPHeader: fc16.MBAPHeader, WriteSingleRegisterRequest: single}, nil
+	case *packet.WriteMultipleRegistersRequestRTU:
+		return &packet.WriteSingleRegisterRequestRTU{WriteSingleRegisterRequest: single}, nil
+	}
+	return &packet.WriteSingleRegisterRequestASCII{
+		WriteSingleRegisterRequestRTU: packet.WriteSingleRegisterRequestRTU{WriteSingleRegisterRequest: single},
+	}, nil
+}
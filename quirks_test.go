@@ -0,0 +1,165 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLookupDeviceQuirks(t *testing.T) {
+	q, ok := LookupDeviceQuirks(QuirksProfileOneBasedAddressing)
+	assert.True(t, ok)
+	assert.Equal(t, DeviceQuirks{AddressOffset: -1}, q)
+
+	_, ok = LookupDeviceQuirks("test_unknown")
+	assert.False(t, ok)
+
+	RegisterDeviceQuirks("test_lookup", DeviceQuirks{MaxRegistersPerRead: 60})
+	q, ok = LookupDeviceQuirks("test_lookup")
+	assert.True(t, ok)
+	assert.Equal(t, DeviceQuirks{MaxRegistersPerRead: 60}, q)
+}
+
+func TestDeviceQuirks_RequestTransformer(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenQuirks DeviceQuirks
+		given       packet.Request
+		expect      packet.Request
+		expectError string
+	}{
+		{
+			name:        "ok, FC16 with single register is sent as FC6",
+			givenQuirks: DeviceQuirks{NoWriteMultipleRegisters: true, AddressOffset: -1},
+			given: &packet.WriteMultipleRegistersRequestTCP{
+				MBAPHeader: packet.MBAPHeader{TransactionID: 123},
+				WriteMultipleRegistersRequest: packet.WriteMultipleRegistersRequest{
+					UnitID:        1,
+					StartAddress:  10,
+					RegisterCount: 1,
+					Data:          []byte{0xca, 0xfe},
+				},
+			},
+			expect: &packet.WriteSingleRegisterRequestTCP{
+				MBAPHeader: packet.MBAPHeader{TransactionID: 123},
+				WriteSingleRegisterRequest: packet.WriteSingleRegisterRequest{
+					UnitID:  1,
+					Address: 9,
+					Data:    [2]byte{0xca, 0xfe},
+				},
+			},
+		},
+		{
+			name:        "ok, FC16 RTU with single register is sent as FC6",
+			givenQuirks: DeviceQuirks{NoWriteMultipleRegisters: true},
+			given: &packet.WriteMultipleRegistersRequestRTU{
+				WriteMultipleRegistersRequest: packet.WriteMultipleRegistersRequest{
+					UnitID:        1,
+					StartAddress:  10,
+					RegisterCount: 1,
+					Data:          []byte{0xca, 0xfe},
+				},
+			},
+			expect: &packet.WriteSingleRegisterRequestRTU{
+				WriteSingleRegisterRequest: packet.WriteSingleRegisterRequest{
+					UnitID:  1,
+					Address: 10,
+					Data:    [2]byte{0xca, 0xfe},
+				},
+			},
+		},
+		{
+			name:        "ok, other requests are not converted",
+			givenQuirks: DeviceQuirks{NoWriteMultipleRegisters: true},
+			given:       &packet.ReadHoldingRegistersRequestTCP{ReadHoldingRegistersRequest: packet.ReadHoldingRegistersRequest{UnitID: 1, StartAddress: 10, Quantity: 2}},
+			expect:      &packet.ReadHoldingRegistersRequestTCP{ReadHoldingRegistersRequest: packet.ReadHoldingRegistersRequest{UnitID: 1, StartAddress: 10, Quantity: 2}},
+		},
+		{
+			name:        "nok, FC16 with multiple registers",
+			givenQuirks: DeviceQuirks{NoWriteMultipleRegisters: true},
+			given: &packet.WriteMultipleRegistersRequestTCP{
+				WriteMultipleRegistersRequest: packet.WriteMultipleRegistersRequest{
+					UnitID:        1,
+					StartAddress:  10,
+					RegisterCount: 2,
+					Data:          []byte{0xca, 0xfe, 0xba, 0xbe},
+				},
+			},
+			expectError: "device does not support writing multiple registers (FC16), register count: 2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.givenQuirks.RequestTransformer()(tc.given)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeviceQuirks_RequestTransformerNone(t *testing.T) {
+	assert.Nil(t, DeviceQuirks{DelayAfterWrite: time.Second}.RequestTransformer())
+}
+
+func TestNewTCPClientWithConfig_QuirksProfile(t *testing.T) {
+	RegisterDeviceQuirks("test_client", DeviceQuirks{AddressOffset: -1, DelayAfterWrite: 50 * time.Millisecond})
+
+	client := NewTCPClientWithConfig(ClientConfig{
+		QuirksProfile: "test_client",
+		DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+			c, _ := net.Pipe()
+			return c, nil
+		},
+	})
+	assert.NoError(t, client.Connect(context.Background(), "localhost:502"))
+	assert.Equal(t, 50*time.Millisecond, client.requestDelay.delayAfterWrite)
+
+	req, _ := packet.NewReadCoilsRequestTCP(1, 10, 1)
+	result, err := client.requestTransformer(req)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(9), result.(*packet.ReadCoilsRequestTCP).StartAddress)
+}
+
+func TestNewTCPClientWithConfig_unknownQuirksProfile(t *testing.T) {
+	client := NewTCPClientWithConfig(ClientConfig{QuirksProfile: "test_unknown"})
+
+	err := client.Connect(context.Background(), "localhost:502")
+	assert.EqualError(t, err, "unknown device quirks profile: test_unknown")
+}
+
+func TestWithSerialDeviceQuirks(t *testing.T) {
+	client := NewSerialClient(new(serialMock), WithSerialDeviceQuirks(DeviceQuirks{AddressOffset: 1}))
+
+	req, _ := packet.NewReadCoilsRequestRTU(1, 10, 1)
+	result, err := client.requestTransformer(req)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(11), result.(*packet.ReadCoilsRequestRTU).StartAddress)
+}
+
+func TestFieldsToRequests_QuirksProfile(t *testing.T) {
+	RegisterDeviceQuirks("test_splitter", DeviceQuirks{MaxRegistersPerRead: 60})
+	fields := Fields{
+		{Name: "a", Address: 0, Type: FieldTypeUint16},
+		{Name: "b", Address: 100, Type: FieldTypeUint16},
+	}
+
+	reqs, err := FieldsToRequests(fields, RequestDefaults{ServerAddress: "localhost:502", UnitID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 1)
+
+	reqs, err = FieldsToRequests(fields, RequestDefaults{ServerAddress: "localhost:502", UnitID: 1, QuirksProfile: "test_splitter"})
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+
+	_, err = FieldsToRequests(fields, RequestDefaults{QuirksProfile: "test_unknown"})
+	assert.EqualError(t, err, "unknown device quirks profile: test_unknown")
+}
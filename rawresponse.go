@@ -0,0 +1,37 @@
+package modbus
+
+import "github.com/aldas/go-modbus-client/packet"
+
+// RawResponse is response returned by Client and SerialClient when raw responses are enabled. It contains received
+// frame as is and result of parsing that frame. This is useful for pass-through proxies and diagnostics tooling.
+type RawResponse struct {
+	// Frame is received response frame bytes
+	Frame []byte
+	// Response is parsed response. Nil when frame could not be parsed or frame is Modbus exception.
+	Response packet.Response
+	// ParseErr is error returned by parsing the frame. For Modbus exceptions value is of type packet.ErrorResponseTCP,
+	// packet.ErrorResponseRTU or packet.ErrorResponseASCII.
+	ParseErr error
+}
+
+func newRawResponse(frame []byte, parseFunc func(data []byte) (packet.Response, error)) *RawResponse {
+	resp, err := parseFunc(frame)
+	return &RawResponse{
+		Frame:    frame,
+		Response: resp,
+		ParseErr: err,
+	}
+}
+
+// FunctionCode returns function code of parsed response or 0 when frame could not be parsed
+func (r *RawResponse) FunctionCode() uint8 {
+	if r.Response == nil {
+		return 0
+	}
+	return r.Response.FunctionCode()
+}
+
+// Bytes returns received frame bytes
+func (r *RawResponse) Bytes() []byte {
+	return r.Frame
+}
@@ -0,0 +1,229 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// RecordBuffer describes device-side circular log (ring buffer) of fixed size records. Power quality meters and data
+// loggers usually expose these logs as pointer register (index of the slot that will be written next) and a data block.
+//
+// Data block can be accessed in two ways:
+//   - mapped: all record slots are laid out sequentially in address space starting from DataAddress
+//   - windowed: slot index is written to SelectAddress (FC6) and selected record is read from DataAddress
+//
+// Tag `mapstructure` allows you to marshal https://github.com/spf13/viper supported configuration format to the RecordBuffer
+type RecordBuffer struct {
+	UnitID uint8 `json:"unit_id" mapstructure:"unit_id"`
+	// FunctionCode is function code used to read pointer and record registers. Either packet.FunctionReadHoldingRegisters
+	// (used when left empty) or packet.FunctionReadInputRegisters.
+	FunctionCode uint8 `json:"function_code" mapstructure:"function_code"`
+
+	// PointerAddress is address of register containing 0-based index of the slot that will be written next.
+	PointerAddress uint16 `json:"pointer_address" mapstructure:"pointer_address"`
+	// DataAddress is address of first register of the first slot (mapped mode) or address of record window (windowed mode).
+	DataAddress uint16 `json:"data_address" mapstructure:"data_address"`
+	// IsWindowed indicates that record is selected by writing slot index to SelectAddress before reading DataAddress.
+	IsWindowed bool `json:"is_windowed" mapstructure:"is_windowed"`
+	// SelectAddress is address of register where slot index is written to select record in windowed mode.
+	SelectAddress uint16 `json:"select_address" mapstructure:"select_address"`
+
+	// Slots is total amount of record slots in device buffer
+	Slots uint16 `json:"slots" mapstructure:"slots"`
+	// RecordSize is size of single record in registers
+	RecordSize uint16 `json:"record_size" mapstructure:"record_size"`
+	// Fields describe values in single record. Field addresses are offsets from the start of the record (0-based).
+	Fields Fields `json:"fields" mapstructure:"fields"`
+}
+
+// BufferRecord is single record read from device record buffer
+type BufferRecord struct {
+	// Slot is index of the slot in device buffer where record was read from
+	Slot   uint16
+	Values []FieldValue
+}
+
+// Validate checks if RecordBuffer is values are correctly filled
+func (b RecordBuffer) Validate() error {
+	if b.FunctionCode != 0 &&
+		b.FunctionCode != packet.FunctionReadHoldingRegisters &&
+		b.FunctionCode != packet.FunctionReadInputRegisters {
+		return errors.New("record buffer function code must be FC3 or FC4")
+	}
+	if b.Slots == 0 {
+		return errors.New("record buffer slots count can not be 0")
+	}
+	if b.RecordSize == 0 || b.RecordSize > packet.MaxRegistersInReadResponse {
+		return fmt.Errorf("record buffer record size is out of range (1-%v)", packet.MaxRegistersInReadResponse)
+	}
+	dataEnd := uint32(b.DataAddress) + uint32(b.Slots)*uint32(b.RecordSize)
+	if b.IsWindowed {
+		dataEnd = uint32(b.DataAddress) + uint32(b.RecordSize)
+	}
+	if dataEnd > 0x10000 {
+		return errors.New("record buffer data exceeds register address space")
+	}
+	for i, f := range b.Fields {
+		if f.Type == 0 || f.Type == FieldTypeCoil {
+			return fmt.Errorf("record buffer field at index %v has invalid type", i)
+		}
+		if uint32(f.Address)+uint32(f.registerSize()) > uint32(b.RecordSize) {
+			return fmt.Errorf("record buffer field at index %v does not fit into record", i)
+		}
+		tmp := f
+		if tmp.ServerAddress == "" {
+			tmp.ServerAddress = "record" // record fields are read from the buffer device and need no server address
+		}
+		if err := tmp.Validate(); err != nil {
+			return fmt.Errorf("record buffer field at index %v is invalid: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ReadRecordBufferTCP reads `count` newest records from device record buffer using Modbus TCP requests.
+// Records are returned in order from oldest to newest. When count is 0 all slots are read.
+func ReadRecordBufferTCP(ctx context.Context, client Doer, buffer RecordBuffer, count uint16) ([]BufferRecord, error) {
+	return readRecordBuffer(ctx, client, buffer, count, false)
+}
+
+// ReadRecordBufferRTU reads `count` newest records from device record buffer using Modbus RTU requests.
+// Records are returned in order from oldest to newest. When count is 0 all slots are read.
+func ReadRecordBufferRTU(ctx context.Context, client Doer, buffer RecordBuffer, count uint16) ([]BufferRecord, error) {
+	return readRecordBuffer(ctx, client, buffer, count, true)
+}
+
+func readRecordBuffer(ctx context.Context, client Doer, buffer RecordBuffer, count uint16, isRTU bool) ([]BufferRecord, error) {
+	if err := buffer.Validate(); err != nil {
+		return nil, err
+	}
+	if count == 0 || count > buffer.Slots {
+		count = buffer.Slots
+	}
+
+	regs, err := buffer.readRegisters(ctx, client, buffer.PointerAddress, 1, isRTU)
+	if err != nil {
+		return nil, fmt.Errorf("record buffer pointer read failed: %w", err)
+	}
+	pointer, err := regs.Uint16(buffer.PointerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if pointer >= buffer.Slots {
+		return nil, fmt.Errorf("record buffer pointer value is out of range: %v", pointer)
+	}
+
+	// oldest of requested records is `count` slots before the pointer
+	first := (uint32(pointer) + uint32(buffer.Slots) - uint32(count)) % uint32(buffer.Slots)
+	slots := make([]uint16, count)
+	for i := range slots {
+		slots[i] = uint16((first + uint32(i)) % uint32(buffer.Slots))
+	}
+
+	if buffer.IsWindowed {
+		return buffer.readWindowed(ctx, client, slots, isRTU)
+	}
+	return buffer.readMapped(ctx, client, slots, isRTU)
+}
+
+func (b RecordBuffer) readMapped(ctx context.Context, client Doer, slots []uint16, isRTU bool) ([]BufferRecord, error) {
+	maxRecordsPerRequest := int(packet.MaxRegistersInReadResponse / b.RecordSize)
+
+	result := make([]BufferRecord, 0, len(slots))
+	for len(slots) > 0 {
+		// take as many consecutive slots as fit into single request. Run is broken when buffer wraps around.
+		n := 1
+		for n < len(slots) && n < maxRecordsPerRequest && slots[n] == slots[n-1]+1 {
+			n++
+		}
+		startAddress := b.DataAddress + slots[0]*b.RecordSize
+		regs, err := b.readRegisters(ctx, client, startAddress, uint16(n)*b.RecordSize, isRTU)
+		if err != nil {
+			return nil, fmt.Errorf("record buffer data read failed: %w", err)
+		}
+		for i := 0; i < n; i++ {
+			record, err := b.extractRecord(regs, slots[i], startAddress+uint16(i)*b.RecordSize)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, record)
+		}
+		slots = slots[n:]
+	}
+	return result, nil
+}
+
+func (b RecordBuffer) readWindowed(ctx context.Context, client Doer, slots []uint16, isRTU bool) ([]BufferRecord, error) {
+	result := make([]BufferRecord, 0, len(slots))
+	for _, slot := range slots {
+		data := binary.BigEndian.AppendUint16(nil, slot)
+		var req packet.Request
+		var err error
+		if isRTU {
+			req, err = packet.NewWriteSingleRegisterRequestRTU(b.UnitID, b.SelectAddress, data)
+		} else {
+			req, err = packet.NewWriteSingleRegisterRequestTCP(b.UnitID, b.SelectAddress, data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := client.Do(ctx, req); err != nil {
+			return nil, fmt.Errorf("record buffer slot select failed: %w", err)
+		}
+
+		regs, err := b.readRegisters(ctx, client, b.DataAddress, b.RecordSize, isRTU)
+		if err != nil {
+			return nil, fmt.Errorf("record buffer data read failed: %w", err)
+		}
+		record, err := b.extractRecord(regs, slot, b.DataAddress)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+func (b RecordBuffer) extractRecord(regs *packet.Registers, slot uint16, recordAddress uint16) (BufferRecord, error) {
+	values := make([]FieldValue, 0, len(b.Fields))
+	for _, f := range b.Fields {
+		tmp := f
+		tmp.Address = recordAddress + f.Address
+		v, err := tmp.ExtractFrom(regs)
+		if err != nil {
+			return BufferRecord{}, fmt.Errorf("record buffer field extraction failed. slot: %v, name: %v, err: %w", slot, f.Name, err)
+		}
+		values = append(values, FieldValue{Field: f, Value: v})
+	}
+	return BufferRecord{Slot: slot, Values: values}, nil
+}
+
+func (b RecordBuffer) readRegisters(ctx context.Context, client Doer, startAddress uint16, quantity uint16, isRTU bool) (*packet.Registers, error) {
+	var req packet.Request
+	var err error
+	switch {
+	case b.FunctionCode == packet.FunctionReadInputRegisters && isRTU:
+		req, err = packet.NewReadInputRegistersRequestRTU(b.UnitID, startAddress, quantity)
+	case b.FunctionCode == packet.FunctionReadInputRegisters:
+		req, err = packet.NewReadInputRegistersRequestTCP(b.UnitID, startAddress, quantity)
+	case isRTU:
+		req, err = packet.NewReadHoldingRegistersRequestRTU(b.UnitID, startAddress, quantity)
+	default:
+		req, err = packet.NewReadHoldingRegistersRequestTCP(b.UnitID, startAddress, quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	regResp, ok := resp.(RegistersResponse)
+	if !ok {
+		return nil, errors.New("record buffer received unsupported response type")
+	}
+	return regResp.AsRegisters(startAddress)
+}
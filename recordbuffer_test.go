@@ -0,0 +1,223 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fakeRegistersDevice emulates device holding registers for FC3, FC6 and FC16 TCP requests
+type fakeRegistersDevice struct {
+	registers map[uint16]uint16
+	requests  []packet.Request
+}
+
+func (d *fakeRegistersDevice) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	d.requests = append(d.requests, req)
+	switch r := req.(type) {
+	case *packet.ReadHoldingRegistersRequestTCP:
+		data := make([]byte, 0, r.Quantity*2)
+		for i := uint16(0); i < r.Quantity; i++ {
+			data = binary.BigEndian.AppendUint16(data, d.registers[r.StartAddress+i])
+		}
+		return &packet.ReadHoldingRegistersResponseTCP{
+			MBAPHeader: r.MBAPHeader,
+			ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+				UnitID:          r.UnitID,
+				RegisterByteLen: uint8(len(data)),
+				Data:            data,
+			},
+		}, nil
+	case *packet.WriteSingleRegisterRequestTCP:
+		d.registers[r.Address] = binary.BigEndian.Uint16(r.Data[:])
+		return &packet.WriteSingleRegisterResponseTCP{
+			MBAPHeader: r.MBAPHeader,
+			WriteSingleRegisterResponse: packet.WriteSingleRegisterResponse{
+				UnitID:  r.UnitID,
+				Address: r.Address,
+				Data:    r.Data,
+			},
+		}, nil
+	case *packet.WriteMultipleRegistersRequestTCP:
+		for i := uint16(0); i < r.RegisterCount; i++ {
+			d.registers[r.StartAddress+i] = binary.BigEndian.Uint16(r.Data[i*2:])
+		}
+		return &packet.WriteMultipleRegistersResponseTCP{
+			MBAPHeader: r.MBAPHeader,
+			WriteMultipleRegistersResponse: packet.WriteMultipleRegistersResponse{
+				UnitID:        r.UnitID,
+				StartAddress:  r.StartAddress,
+				RegisterCount: r.RegisterCount,
+			},
+		}, nil
+	}
+	return nil, errors.New("unsupported request")
+}
+
+func TestReadRecordBufferTCP_mapped(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{
+		100: 1, // pointer. next record is written to slot 1, so slot 0 is newest and slot 2 is oldest
+		// slot 0
+		200: 10,
+		201: 11,
+		// slot 1
+		202: 20,
+		203: 21,
+		// slot 2
+		204: 30,
+		205: 31,
+	}}
+	buffer := RecordBuffer{
+		UnitID:         1,
+		PointerAddress: 100,
+		DataAddress:    200,
+		Slots:          3,
+		RecordSize:     2,
+		Fields: Fields{
+			{Name: "a", Address: 0, Type: FieldTypeUint16},
+			{Name: "b", Address: 1, Type: FieldTypeUint16},
+		},
+	}
+
+	records, err := ReadRecordBufferTCP(context.Background(), device, buffer, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []BufferRecord{
+		{
+			Slot: 2,
+			Values: []FieldValue{
+				{Field: buffer.Fields[0], Value: uint16(30)},
+				{Field: buffer.Fields[1], Value: uint16(31)},
+			},
+		},
+		{
+			Slot: 0,
+			Values: []FieldValue{
+				{Field: buffer.Fields[0], Value: uint16(10)},
+				{Field: buffer.Fields[1], Value: uint16(11)},
+			},
+		},
+	}, records)
+	// 1 pointer read + 2 data reads as buffer wraps around between records
+	assert.Len(t, device.requests, 3)
+}
+
+func TestReadRecordBufferTCP_windowed(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{
+		100: 0, // pointer
+		300: 0, // select register
+	}}
+	records := map[uint16]uint16{0: 0xA, 1: 0xB}
+	buffer := RecordBuffer{
+		UnitID:         1,
+		PointerAddress: 100,
+		DataAddress:    200,
+		IsWindowed:     true,
+		SelectAddress:  300,
+		Slots:          2,
+		RecordSize:     1,
+		Fields:         Fields{{Name: "a", Address: 0, Type: FieldTypeUint16}},
+	}
+	doer := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		// emulate device placing selected record into window
+		device.registers[200] = records[device.registers[300]]
+		return device.Do(ctx, req)
+	})
+
+	result, err := ReadRecordBufferTCP(context.Background(), doer, buffer, 0)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, uint16(0), result[0].Slot)
+	assert.Equal(t, uint16(0xA), result[0].Values[0].Value)
+	assert.Equal(t, uint16(1), result[1].Slot)
+	assert.Equal(t, uint16(0xB), result[1].Values[0].Value)
+}
+
+func TestReadRecordBufferTCP_pointerOutOfRange(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{100: 5}}
+	buffer := RecordBuffer{PointerAddress: 100, Slots: 3, RecordSize: 1}
+
+	records, err := ReadRecordBufferTCP(context.Background(), device, buffer, 0)
+	assert.EqualError(t, err, "record buffer pointer value is out of range: 5")
+	assert.Nil(t, records)
+}
+
+func TestRecordBuffer_Validate(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       RecordBuffer
+		expectError string
+	}{
+		{
+			name:  "ok",
+			given: RecordBuffer{Slots: 1, RecordSize: 2, Fields: Fields{{Address: 0, Type: FieldTypeUint32}}},
+		},
+		{
+			name:        "nok, invalid function code",
+			given:       RecordBuffer{FunctionCode: packet.FunctionReadCoils, Slots: 1, RecordSize: 1},
+			expectError: "record buffer function code must be FC3 or FC4",
+		},
+		{
+			name:        "nok, no slots",
+			given:       RecordBuffer{RecordSize: 1},
+			expectError: "record buffer slots count can not be 0",
+		},
+		{
+			name:        "nok, record too large",
+			given:       RecordBuffer{Slots: 1, RecordSize: 126},
+			expectError: "record buffer record size is out of range (1-125)",
+		},
+		{
+			name:  "ok, mapped slots end at last register",
+			given: RecordBuffer{DataAddress: 0xFFFC, Slots: 2, RecordSize: 2},
+		},
+		{
+			name:        "nok, mapped slots exceed address space",
+			given:       RecordBuffer{DataAddress: 0xFFFC, Slots: 3, RecordSize: 2},
+			expectError: "record buffer data exceeds register address space",
+		},
+		{
+			name:  "ok, windowed record is only at data address",
+			given: RecordBuffer{DataAddress: 0xFFFC, IsWindowed: true, Slots: 3, RecordSize: 2},
+		},
+		{
+			name:        "nok, windowed record exceeds address space",
+			given:       RecordBuffer{DataAddress: 0xFFFF, IsWindowed: true, Slots: 1, RecordSize: 2},
+			expectError: "record buffer data exceeds register address space",
+		},
+		{
+			name:        "nok, field does not fit",
+			given:       RecordBuffer{Slots: 1, RecordSize: 2, Fields: Fields{{Address: 1, Type: FieldTypeUint32}}},
+			expectError: "record buffer field at index 0 does not fit into record",
+		},
+		{
+			name:        "nok, field address overflows record size",
+			given:       RecordBuffer{Slots: 1, RecordSize: 2, Fields: Fields{{Address: 0xFFFF, Type: FieldTypeUint32}}},
+			expectError: "record buffer field at index 0 does not fit into record",
+		},
+		{
+			name:        "nok, invalid field",
+			given:       RecordBuffer{Slots: 1, RecordSize: 2, Fields: Fields{{Address: 0, Type: FieldTypeString}}},
+			expectError: "record buffer field at index 0 is invalid: field with type string must have length set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.given.Validate()
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type doerFunc func(ctx context.Context, req packet.Request) (packet.Response, error)
+
+func (f doerFunc) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	return f(ctx, req)
+}
@@ -0,0 +1,213 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"time"
+)
+
+const defaultRegisterScanBlockSize = 16
+
+// RegisterScanConfig is configuration for ScanRegistersTCP and ScanRegistersRTU
+type RegisterScanConfig struct {
+	// UnitIDs are unit IDs to scan. Defaults to unit ID 1.
+	UnitIDs []uint8
+	// FunctionCode is read function code (FC1/FC2/FC3/FC4) used to probe addresses. Defaults to FC3.
+	FunctionCode uint8
+	// Start is first address to scan
+	Start uint16
+	// End is last address to scan (inclusive)
+	End uint16
+	// BlockSize is amount of addresses read with single probe request. Blocks with invalid addresses are split in
+	// halves until invalid addresses are found. Defaults to 16.
+	BlockSize uint16
+	// ProbeInterval is minimal delay between probe requests. 0 means no delay.
+	ProbeInterval time.Duration
+}
+
+// RegisterScanResult is address availability map of single unit
+type RegisterScanResult struct {
+	UnitID       uint8 `json:"unit_id"`
+	FunctionCode uint8 `json:"function_code"`
+	// Valid are address ranges that device responded to
+	Valid []AddressRange `json:"valid"`
+	// Invalid are address ranges that device responded with Illegal Data Address exception to
+	Invalid []AddressRange `json:"invalid"`
+	// Error is error that stopped scanning of the unit (i.e. unit does not respond or does not support function)
+	Error string `json:"error,omitempty"`
+}
+
+// InvalidRanges returns invalid address ranges of the result for given server address (see NewCoverageMap)
+func (r RegisterScanResult) InvalidRanges(serverAddress string) []CoverageInvalidRange {
+	result := make([]CoverageInvalidRange, 0, len(r.Invalid))
+	for _, ar := range r.Invalid {
+		result = append(result, CoverageInvalidRange{
+			ServerAddress: serverAddress,
+			UnitID:        r.UnitID,
+			FunctionCode:  r.FunctionCode,
+			AddressRange:  ar,
+		})
+	}
+	return result
+}
+
+// ScanRegistersTCP probes given address range of given units with Modbus TCP read requests and returns which
+// addresses device responds to and which result Illegal Data Address exception. This helps to find out register
+// layout of devices with incomplete documentation and build invalid address configuration for them.
+//
+// Units that do not respond or respond with other errors are reported with Error and scanning continues with the next
+// unit. Error is returned only for invalid configuration or when context is done.
+func ScanRegistersTCP(ctx context.Context, client Doer, conf RegisterScanConfig) ([]RegisterScanResult, error) {
+	return scanRegisters(ctx, client, conf, false)
+}
+
+// ScanRegistersRTU probes given address range of given units with Modbus RTU read requests. See ScanRegistersTCP.
+func ScanRegistersRTU(ctx context.Context, client Doer, conf RegisterScanConfig) ([]RegisterScanResult, error) {
+	return scanRegisters(ctx, client, conf, true)
+}
+
+type registerScanner struct {
+	client        Doer
+	isRTU         bool
+	functionCode  uint8
+	probeInterval time.Duration
+	lastProbe     time.Time
+}
+
+func scanRegisters(ctx context.Context, client Doer, conf RegisterScanConfig, isRTU bool) ([]RegisterScanResult, error) {
+	if conf.End < conf.Start {
+		return nil, errors.New("register scan end address can not be before start address")
+	}
+	functionCode := conf.FunctionCode
+	if functionCode == 0 {
+		functionCode = packet.FunctionReadHoldingRegisters
+	}
+	maxBlockSize := uint16(125)
+	switch functionCode {
+	case packet.FunctionReadCoils, packet.FunctionReadDiscreteInputs:
+		maxBlockSize = 2000
+	case packet.FunctionReadHoldingRegisters, packet.FunctionReadInputRegisters:
+	default:
+		return nil, fmt.Errorf("register scan supports only read function codes (FC1/FC2/FC3/FC4), got: %v", functionCode)
+	}
+	blockSize := conf.BlockSize
+	if blockSize == 0 {
+		blockSize = defaultRegisterScanBlockSize
+	}
+	if blockSize > maxBlockSize {
+		return nil, fmt.Errorf("register scan block size can not be larger than %v for function code %v", maxBlockSize, functionCode)
+	}
+	unitIDs := conf.UnitIDs
+	if len(unitIDs) == 0 {
+		unitIDs = []uint8{1}
+	}
+
+	s := &registerScanner{
+		client:        client,
+		isRTU:         isRTU,
+		functionCode:  functionCode,
+		probeInterval: conf.ProbeInterval,
+	}
+	results := make([]RegisterScanResult, 0, len(unitIDs))
+	for _, unitID := range unitIDs {
+		result := RegisterScanResult{
+			UnitID:       unitID,
+			FunctionCode: functionCode,
+			Valid:        []AddressRange{},
+			Invalid:      []AddressRange{},
+		}
+		for start := uint32(conf.Start); start <= uint32(conf.End); start += uint32(blockSize) {
+			quantity := min(uint32(blockSize), uint32(conf.End)-start+1)
+			if err := s.scanBlock(ctx, &result, unitID, uint16(start), uint16(quantity)); err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return nil, ctxErr
+				}
+				result.Error = err.Error()
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// scanBlock probes given block and splits it in halves when block contains invalid addresses
+func (s *registerScanner) scanBlock(ctx context.Context, result *RegisterScanResult, unitID uint8, start uint16, quantity uint16) error {
+	err := s.probe(ctx, unitID, start, quantity)
+	if err == nil {
+		result.Valid = appendAddressRange(result.Valid, AddressRange{Start: start, End: start + quantity - 1})
+		return nil
+	}
+	if !isIllegalDataAddress(err) {
+		return err
+	}
+	if quantity == 1 {
+		result.Invalid = appendAddressRange(result.Invalid, AddressRange{Start: start, End: start})
+		return nil
+	}
+	half := quantity / 2
+	if err := s.scanBlock(ctx, result, unitID, start, half); err != nil {
+		return err
+	}
+	return s.scanBlock(ctx, result, unitID, start+half, quantity-half)
+}
+
+func (s *registerScanner) probe(ctx context.Context, unitID uint8, start uint16, quantity uint16) error {
+	if s.probeInterval > 0 && !s.lastProbe.IsZero() {
+		if wait := s.probeInterval - time.Since(s.lastProbe); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+	}
+	s.lastProbe = time.Now()
+
+	req, err := newReadRequest(s.isRTU, s.functionCode, unitID, start, quantity)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Do(ctx, req)
+	return err
+}
+
+func newReadRequest(isRTU bool, functionCode uint8, unitID uint8, start uint16, quantity uint16) (packet.Request, error) {
+	switch functionCode {
+	case packet.FunctionReadCoils:
+		if isRTU {
+			return packet.NewReadCoilsRequestRTU(unitID, start, quantity)
+		}
+		return packet.NewReadCoilsRequestTCP(unitID, start, quantity)
+	case packet.FunctionReadDiscreteInputs:
+		if isRTU {
+			return packet.NewReadDiscreteInputsRequestRTU(unitID, start, quantity)
+		}
+		return packet.NewReadDiscreteInputsRequestTCP(unitID, start, quantity)
+	case packet.FunctionReadInputRegisters:
+		if isRTU {
+			return packet.NewReadInputRegistersRequestRTU(unitID, start, quantity)
+		}
+		return packet.NewReadInputRegistersRequestTCP(unitID, start, quantity)
+	default:
+		if isRTU {
+			return packet.NewReadHoldingRegistersRequestRTU(unitID, start, quantity)
+		}
+		return packet.NewReadHoldingRegistersRequestTCP(unitID, start, quantity)
+	}
+}
+
+func isIllegalDataAddress(err error) bool {
+	return errors.Is(err, packet.ErrIllegalDataAddress)
+}
+
+// appendAddressRange appends range to ranges merging it with the last range when they are adjacent
+func appendAddressRange(ranges []AddressRange, r AddressRange) []AddressRange {
+	if n := len(ranges); n > 0 && uint32(ranges[n-1].End)+1 == uint32(r.Start) {
+		ranges[n-1].End = r.End
+		return ranges
+	}
+	return append(ranges, r)
+}
@@ -0,0 +1,101 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScanRegistersTCP(t *testing.T) {
+	isValid := func(address uint16) bool {
+		return address < 10 || (address >= 20 && address < 30)
+	}
+	probes := 0
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		probes++
+		r := req.(*packet.ReadHoldingRegistersRequestTCP)
+		switch r.UnitID {
+		case 2:
+			return nil, &ClientError{Err: errors.New("total read timeout exceeded")}
+		case 3:
+			return nil, &packet.ErrorResponseTCP{UnitID: r.UnitID, Function: r.FunctionCode(), Code: packet.ErrIllegalFunction}
+		}
+		for a := r.StartAddress; a < r.StartAddress+r.Quantity; a++ {
+			if !isValid(a) {
+				return nil, &packet.ErrorResponseTCP{UnitID: r.UnitID, Function: r.FunctionCode(), Code: packet.ErrIllegalDataAddress}
+			}
+		}
+		return &packet.ReadHoldingRegistersResponseTCP{}, nil
+	})
+
+	results, err := ScanRegistersTCP(context.Background(), client, RegisterScanConfig{
+		UnitIDs:   []uint8{1, 2, 3},
+		Start:     0,
+		End:       31,
+		BlockSize: 8,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []RegisterScanResult{
+		{
+			UnitID:       1,
+			FunctionCode: packet.FunctionReadHoldingRegisters,
+			Valid:        []AddressRange{{Start: 0, End: 9}, {Start: 20, End: 29}},
+			Invalid:      []AddressRange{{Start: 10, End: 19}, {Start: 30, End: 31}},
+		},
+		{
+			UnitID:       2,
+			FunctionCode: packet.FunctionReadHoldingRegisters,
+			Valid:        []AddressRange{},
+			Invalid:      []AddressRange{},
+			Error:        "total read timeout exceeded",
+		},
+		{
+			UnitID:       3,
+			FunctionCode: packet.FunctionReadHoldingRegisters,
+			Valid:        []AddressRange{},
+			Invalid:      []AddressRange{},
+			Error:        "Illegal function",
+		},
+	}, results)
+	assert.Equal(t, 32, probes)
+
+	assert.Equal(t, []CoverageInvalidRange{
+		{ServerAddress: ":502", UnitID: 1, FunctionCode: 3, AddressRange: AddressRange{Start: 10, End: 19}},
+		{ServerAddress: ":502", UnitID: 1, FunctionCode: 3, AddressRange: AddressRange{Start: 30, End: 31}},
+	}, results[0].InvalidRanges(":502"))
+}
+
+func TestScanRegistersRTU_invalidConfig(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        RegisterScanConfig
+		expectError string
+	}{
+		{
+			name:        "nok, end before start",
+			when:        RegisterScanConfig{Start: 10, End: 9},
+			expectError: "register scan end address can not be before start address",
+		},
+		{
+			name:        "nok, write function code",
+			when:        RegisterScanConfig{FunctionCode: packet.FunctionWriteSingleRegister},
+			expectError: "register scan supports only read function codes (FC1/FC2/FC3/FC4), got: 6",
+		},
+		{
+			name:        "nok, block size too large",
+			when:        RegisterScanConfig{BlockSize: 126},
+			expectError: "register scan block size can not be larger than 125 for function code 3",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := ScanRegistersRTU(context.Background(), nil, tc.when)
+
+			assert.EqualError(t, err, tc.expectError)
+			assert.Nil(t, results)
+		})
+	}
+}
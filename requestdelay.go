@@ -0,0 +1,113 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ServerAddressOptionDelayBetweenRequests is server address query option to set minimal delay between consecutive
+// requests sent to the server (i.e. `tcp://192.168.0.1:502?delay_between_requests=50ms`). Value is in time.ParseDuration
+// format. This is useful for fragile devices that can not handle requests sent back to back.
+const ServerAddressOptionDelayBetweenRequests = "delay_between_requests"
+
+// serverAddressOptions are options given in query part of server address
+type serverAddressOptions struct {
+	delayBetweenRequests time.Duration
+	hasDelay             bool
+	dial                 dialOptions
+}
+
+// parseServerAddress splits server address into address that is used to connect to the server and options given in
+// query part of the address.
+func parseServerAddress(address string) (string, serverAddressOptions, error) {
+	opts := serverAddressOptions{}
+	addr, query, ok := strings.Cut(address, "?")
+	if !ok {
+		return address, opts, nil
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", opts, fmt.Errorf("invalid server address options: %w", err)
+	}
+	for key, v := range values {
+		switch key {
+		case ServerAddressOptionDelayBetweenRequests:
+			delay, err := time.ParseDuration(v[len(v)-1])
+			if err != nil || delay < 0 {
+				return "", opts, fmt.Errorf("invalid server address option %v value: %v", key, v[len(v)-1])
+			}
+			opts.delayBetweenRequests = delay
+			opts.hasDelay = true
+		case ServerAddressOptionProxy:
+			proxy, err := parseProxyURL(v[len(v)-1])
+			if err != nil {
+				return "", opts, fmt.Errorf("invalid server address option %v value: %w", key, err)
+			}
+			opts.dial.proxy = proxy
+		case ServerAddressOptionLocalAddress:
+			local, err := parseLocalAddress(v[len(v)-1])
+			if err != nil {
+				return "", opts, fmt.Errorf("invalid server address option %v value: %w", key, err)
+			}
+			opts.dial.localAddress = local
+		default:
+			return "", opts, fmt.Errorf("unknown server address option: %v", key)
+		}
+	}
+	return addr, opts, nil
+}
+
+// requestDelayer spaces consecutive requests at least delay apart. Requests following write request are spaced at least
+// delayAfterWrite apart when it is longer than delay.
+type requestDelayer struct {
+	delay           time.Duration
+	delayAfterWrite time.Duration
+	lastRequestAt   time.Time
+	lastWasWrite    bool
+}
+
+// wait blocks until delay has passed since previous request ended or context is cancelled
+func (d *requestDelayer) wait(ctx context.Context, now time.Time) error {
+	delay := d.delay
+	if d.lastWasWrite && d.delayAfterWrite > delay {
+		delay = d.delayAfterWrite
+	}
+	if delay <= 0 || d.lastRequestAt.IsZero() {
+		return nil
+	}
+	remaining := d.lastRequestAt.Add(delay).Sub(now)
+	if remaining <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// done marks end of the request
+func (d *requestDelayer) done(now time.Time, isWrite bool) {
+	d.lastRequestAt = now
+	d.lastWasWrite = isWrite
+}
+
+func isWriteFunctionCode(functionCode uint8) bool {
+	switch functionCode {
+	case packet.FunctionWriteSingleCoil,
+		packet.FunctionWriteSingleRegister,
+		packet.FunctionWriteMultipleCoils,
+		packet.FunctionWriteMultipleRegisters,
+		packet.FunctionMaskWriteRegister,
+		packet.FunctionReadWriteMultipleRegisters:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package modbus
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseServerAddress(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		given         string
+		expectAddress string
+		expectOptions serverAddressOptions
+		expectError   string
+	}{
+		{
+			name:          "ok, no options",
+			given:         "tcp://127.0.0.1:502",
+			expectAddress: "tcp://127.0.0.1:502",
+		},
+		{
+			name:          "ok, delay between requests",
+			given:         "tcp://127.0.0.1:502?delay_between_requests=50ms",
+			expectAddress: "tcp://127.0.0.1:502",
+			expectOptions: serverAddressOptions{delayBetweenRequests: 50 * time.Millisecond, hasDelay: true},
+		},
+		{
+			name:        "nok, invalid delay",
+			given:       "127.0.0.1:502?delay_between_requests=x",
+			expectError: "invalid server address option delay_between_requests value: x",
+		},
+		{
+			name:          "ok, proxy and local address",
+			given:         "tcp://127.0.0.1:502?proxy=socks5://127.0.0.1:1080&local_address=10.0.0.1",
+			expectAddress: "tcp://127.0.0.1:502",
+			expectOptions: serverAddressOptions{dial: dialOptions{
+				proxy:        &url.URL{Scheme: "socks5", Host: "127.0.0.1:1080"},
+				localAddress: "10.0.0.1:0",
+			}},
+		},
+		{
+			name:        "nok, invalid proxy scheme",
+			given:       "127.0.0.1:502?proxy=ftp://127.0.0.1:21",
+			expectError: "invalid server address option proxy value: unsupported proxy scheme: ftp",
+		},
+		{
+			name:        "nok, invalid local address",
+			given:       "127.0.0.1:502?local_address=eth0",
+			expectError: "invalid server address option local_address value: local address must be IP address optionally with port",
+		},
+		{
+			name:        "nok, unknown option",
+			given:       "127.0.0.1:502?timeout=1s",
+			expectError: "unknown server address option: timeout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			address, opts, err := parseServerAddress(tc.given)
+
+			assert.Equal(t, tc.expectAddress, address)
+			assert.Equal(t, tc.expectOptions, opts)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRequestDelayer_wait(t *testing.T) {
+	d := requestDelayer{delay: 20 * time.Millisecond}
+	assert.NoError(t, d.wait(context.Background(), time.Now())) // first request is not delayed
+
+	d.done(time.Now(), false)
+	start := time.Now()
+	assert.NoError(t, d.wait(context.Background(), time.Now()))
+	assert.True(t, time.Since(start) >= 15*time.Millisecond)
+
+	d.done(time.Now(), false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, d.wait(ctx, time.Now()), context.Canceled)
+}
+
+func TestRequestDelayer_waitAfterWrite(t *testing.T) {
+	d := requestDelayer{delayAfterWrite: 20 * time.Millisecond}
+
+	d.done(time.Now(), false)
+	start := time.Now()
+	assert.NoError(t, d.wait(context.Background(), time.Now())) // read is not followed by delay
+	assert.True(t, time.Since(start) < 15*time.Millisecond)
+
+	d.done(time.Now(), true)
+	start = time.Now()
+	assert.NoError(t, d.wait(context.Background(), time.Now()))
+	assert.True(t, time.Since(start) >= 15*time.Millisecond)
+}
+
+func TestClient_Connect_delayBetweenRequestsOption(t *testing.T) {
+	dialedAddress := ""
+	client := NewTCPClientWithConfig(ClientConfig{
+		DelayBetweenRequests: time.Second,
+		DialContextFunc: func(ctx context.Context, address string) (net.Conn, error) {
+			dialedAddress = address
+			c, _ := net.Pipe()
+			return c, nil
+		},
+	})
+	assert.Equal(t, time.Second, client.requestDelay.delay)
+
+	err := client.Connect(context.Background(), "tcp://127.0.0.1:502?delay_between_requests=50ms")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://127.0.0.1:502", dialedAddress)
+	assert.Equal(t, 50*time.Millisecond, client.requestDelay.delay)
+}
@@ -0,0 +1,303 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RequestShaper sits in front of Client and merges overlapping or adjacent read requests (FC1/FC2/FC3/FC4) that
+// concurrent callers issue within small time window into single request sent over the wire. Response to merged
+// request is sliced back into responses for each caller. Other requests are passed through to the client as is.
+//
+// This is meant for application code that naively does lots of small reads from multiple goroutines.
+type RequestShaper struct {
+	client Doer
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[shaperKey][]*shapedCall
+}
+
+type shaperKey struct {
+	isRTU        bool
+	unitID       uint8
+	functionCode uint8
+}
+
+type shapedCall struct {
+	ctx      context.Context
+	req      readRequest
+	resultCh chan shapedResult
+}
+
+type shapedResult struct {
+	response packet.Response
+	err      error
+}
+
+// NewRequestShaper creates new instance of RequestShaper. Window is amount of time first read request waits for
+// other mergeable requests to arrive before requests are sent.
+func NewRequestShaper(client Doer, window time.Duration) *RequestShaper {
+	return &RequestShaper{
+		client:  client,
+		window:  window,
+		pending: map[shaperKey][]*shapedCall{},
+	}
+}
+
+// Do sends given Modbus request to modbus server and returns parsed Response. Read requests are delayed up to shaper
+// window to be merged with other read requests to same unit. Modbus ASCII requests are sent without merging.
+func (s *RequestShaper) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	if req == nil {
+		return nil, errors.New("request can not be nil")
+	}
+	info, ok := asReadRequest(req)
+	if !ok || info.isASCII {
+		return s.client.Do(ctx, req)
+	}
+
+	call := &shapedCall{ctx: ctx, req: info, resultCh: make(chan shapedResult, 1)}
+	key := shaperKey{isRTU: info.isRTU, unitID: info.unitID, functionCode: info.functionCode}
+
+	s.mu.Lock()
+	calls, exists := s.pending[key]
+	s.pending[key] = append(calls, call)
+	if !exists {
+		time.AfterFunc(s.window, func() { s.flush(key) })
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-call.resultCh:
+		return r.response, r.err
+	}
+}
+
+func (s *RequestShaper) flush(key shaperKey) {
+	s.mu.Lock()
+	calls := s.pending[key]
+	delete(s.pending, key)
+	s.mu.Unlock()
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].req.startAddress < calls[j].req.startAddress
+	})
+
+	limit := packet.MaxRegistersInReadResponse
+	if key.functionCode == packet.FunctionReadCoils || key.functionCode == packet.FunctionReadDiscreteInputs {
+		limit = packet.MaxCoilsInReadResponse
+	}
+
+	for len(calls) > 0 {
+		start := calls[0].req.startAddress
+		end := uint32(start) + uint32(calls[0].req.quantity)
+		n := 1
+		for ; n < len(calls); n++ {
+			c := calls[n].req
+			if uint32(c.startAddress) > end { // gap between requests, can not merge
+				break
+			}
+			newEnd := end
+			if cEnd := uint32(c.startAddress) + uint32(c.quantity); cEnd > newEnd {
+				newEnd = cEnd
+			}
+			if newEnd-uint32(start) > uint32(limit) {
+				break
+			}
+			end = newEnd
+		}
+		s.doMerged(key, calls[:n], start, uint16(end-uint32(start)))
+		calls = calls[n:]
+	}
+}
+
+func (s *RequestShaper) doMerged(key shaperKey, calls []*shapedCall, startAddress uint16, quantity uint16) {
+	if len(calls) == 1 { // nothing to merge
+		resp, err := s.client.Do(calls[0].ctx, calls[0].req.request)
+		calls[0].resultCh <- shapedResult{response: resp, err: err}
+		return
+	}
+
+	merged := readRequest{
+		isRTU:        key.isRTU,
+		unitID:       key.unitID,
+		functionCode: key.functionCode,
+		startAddress: startAddress,
+		quantity:     quantity,
+	}
+	req, err := merged.newRequest()
+	if err != nil {
+		for _, c := range calls {
+			c.resultCh <- shapedResult{err: err}
+		}
+		return
+	}
+	// merged request should not be cancelled when first caller gives up waiting for it
+	resp, err := s.client.Do(context.WithoutCancel(calls[0].ctx), req)
+	if err != nil {
+		for _, c := range calls {
+			c.resultCh <- shapedResult{err: err}
+		}
+		return
+	}
+	payload := readResponsePayload(resp, key.isRTU)
+	for _, c := range calls {
+		data, err := merged.slice(payload, c.req.startAddress, c.req.quantity)
+		if err != nil {
+			c.resultCh <- shapedResult{err: err}
+			continue
+		}
+		c.resultCh <- shapedResult{response: c.req.newResponse(data)}
+	}
+}
+
+// readRequest is protocol independent description of read request (FC1/FC2/FC3/FC4)
+type readRequest struct {
+	request packet.Request
+
+	isRTU         bool
+	isASCII       bool
+	transactionID uint16
+	unitID        uint8
+	functionCode  uint8
+	startAddress  uint16
+	quantity      uint16
+}
+
+func asReadRequest(req packet.Request) (readRequest, bool) {
+	switch r := req.(type) {
+	case *packet.ReadCoilsRequestTCP:
+		return readRequest{request: req, transactionID: r.TransactionID, unitID: r.UnitID, functionCode: packet.FunctionReadCoils, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadCoilsRequestRTU:
+		return readRequest{request: req, isRTU: true, unitID: r.UnitID, functionCode: packet.FunctionReadCoils, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadDiscreteInputsRequestTCP:
+		return readRequest{request: req, transactionID: r.TransactionID, unitID: r.UnitID, functionCode: packet.FunctionReadDiscreteInputs, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadDiscreteInputsRequestRTU:
+		return readRequest{request: req, isRTU: true, unitID: r.UnitID, functionCode: packet.FunctionReadDiscreteInputs, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadHoldingRegistersRequestTCP:
+		return readRequest{request: req, transactionID: r.TransactionID, unitID: r.UnitID, functionCode: packet.FunctionReadHoldingRegisters, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadHoldingRegistersRequestRTU:
+		return readRequest{request: req, isRTU: true, unitID: r.UnitID, functionCode: packet.FunctionReadHoldingRegisters, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadInputRegistersRequestTCP:
+		return readRequest{request: req, transactionID: r.TransactionID, unitID: r.UnitID, functionCode: packet.FunctionReadInputRegisters, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadInputRegistersRequestRTU:
+		return readRequest{request: req, isRTU: true, unitID: r.UnitID, functionCode: packet.FunctionReadInputRegisters, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadCoilsRequestASCII:
+		return readRequest{request: req, isASCII: true, unitID: r.UnitID, functionCode: packet.FunctionReadCoils, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadDiscreteInputsRequestASCII:
+		return readRequest{request: req, isASCII: true, unitID: r.UnitID, functionCode: packet.FunctionReadDiscreteInputs, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadHoldingRegistersRequestASCII:
+		return readRequest{request: req, isASCII: true, unitID: r.UnitID, functionCode: packet.FunctionReadHoldingRegisters, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	case *packet.ReadInputRegistersRequestASCII:
+		return readRequest{request: req, isASCII: true, unitID: r.UnitID, functionCode: packet.FunctionReadInputRegisters, startAddress: r.StartAddress, quantity: r.Quantity}, true
+	}
+	return readRequest{}, false
+}
+
+func (r readRequest) newRequest() (packet.Request, error) {
+	switch r.functionCode {
+	case packet.FunctionReadCoils:
+		if r.isRTU {
+			return packet.NewReadCoilsRequestRTU(r.unitID, r.startAddress, r.quantity)
+		}
+		return packet.NewReadCoilsRequestTCP(r.unitID, r.startAddress, r.quantity)
+	case packet.FunctionReadDiscreteInputs:
+		if r.isRTU {
+			return packet.NewReadDiscreteInputsRequestRTU(r.unitID, r.startAddress, r.quantity)
+		}
+		return packet.NewReadDiscreteInputsRequestTCP(r.unitID, r.startAddress, r.quantity)
+	case packet.FunctionReadHoldingRegisters:
+		if r.isRTU {
+			return packet.NewReadHoldingRegistersRequestRTU(r.unitID, r.startAddress, r.quantity)
+		}
+		return packet.NewReadHoldingRegistersRequestTCP(r.unitID, r.startAddress, r.quantity)
+	case packet.FunctionReadInputRegisters:
+		if r.isRTU {
+			return packet.NewReadInputRegistersRequestRTU(r.unitID, r.startAddress, r.quantity)
+		}
+		return packet.NewReadInputRegistersRequestTCP(r.unitID, r.startAddress, r.quantity)
+	}
+	return nil, errors.New("unsupported read request function code")
+}
+
+func (r readRequest) isCoils() bool {
+	return r.functionCode == packet.FunctionReadCoils || r.functionCode == packet.FunctionReadDiscreteInputs
+}
+
+// slice extracts part of this request response payload (data after byte count) for given sub range.
+func (r readRequest) slice(payload []byte, startAddress uint16, quantity uint16) ([]byte, error) {
+	if startAddress < r.startAddress || uint32(startAddress)+uint32(quantity) > uint32(r.startAddress)+uint32(r.quantity) {
+		return nil, errors.New("requested range is outside of response range")
+	}
+	offset := startAddress - r.startAddress
+	if !r.isCoils() {
+		from := int(offset) * 2
+		to := from + int(quantity)*2
+		if to > len(payload) {
+			return nil, errors.New("response payload is shorter than requested range")
+		}
+		return append([]byte(nil), payload[from:to]...), nil
+	}
+
+	coils := make([]bool, quantity)
+	for i := range coils {
+		bit := int(offset) + i
+		if bit/8 >= len(payload) {
+			return nil, errors.New("response payload is shorter than requested range")
+		}
+		coils[i] = payload[bit/8]&(1<<(bit%8)) != 0
+	}
+	return packet.CoilsToBytes(coils), nil
+}
+
+func (r readRequest) newResponse(data []byte) packet.Response {
+	header := packet.MBAPHeader{TransactionID: r.transactionID}
+	byteLen := uint8(len(data))
+	switch r.functionCode {
+	case packet.FunctionReadCoils:
+		resp := packet.ReadCoilsResponse{UnitID: r.unitID, CoilsByteLength: byteLen, Data: data}
+		if r.isRTU {
+			return &packet.ReadCoilsResponseRTU{ReadCoilsResponse: resp}
+		}
+		return &packet.ReadCoilsResponseTCP{MBAPHeader: header, ReadCoilsResponse: resp}
+	case packet.FunctionReadDiscreteInputs:
+		resp := packet.ReadDiscreteInputsResponse{UnitID: r.unitID, InputsByteLength: byteLen, Data: data}
+		if r.isRTU {
+			return &packet.ReadDiscreteInputsResponseRTU{ReadDiscreteInputsResponse: resp}
+		}
+		return &packet.ReadDiscreteInputsResponseTCP{MBAPHeader: header, ReadDiscreteInputsResponse: resp}
+	case packet.FunctionReadHoldingRegisters:
+		resp := packet.ReadHoldingRegistersResponse{UnitID: r.unitID, RegisterByteLen: byteLen, Data: data}
+		if r.isRTU {
+			return &packet.ReadHoldingRegistersResponseRTU{ReadHoldingRegistersResponse: resp}
+		}
+		return &packet.ReadHoldingRegistersResponseTCP{MBAPHeader: header, ReadHoldingRegistersResponse: resp}
+	default: // packet.FunctionReadInputRegisters
+		resp := packet.ReadInputRegistersResponse{UnitID: r.unitID, RegisterByteLen: byteLen, Data: data}
+		if r.isRTU {
+			return &packet.ReadInputRegistersResponseRTU{ReadInputRegistersResponse: resp}
+		}
+		return &packet.ReadInputRegistersResponseTCP{MBAPHeader: header, ReadInputRegistersResponse: resp}
+	}
+}
+
+// readResponsePayload returns data part (after byte count) of read response (FC1/FC2/FC3/FC4)
+func readResponsePayload(resp packet.Response, isRTU bool) []byte {
+	b := resp.Bytes()
+	if isRTU {
+		if len(b) < 5 {
+			return nil
+		}
+		return b[3 : len(b)-2] // 1 unit id + 1 function code + 1 byte count ... 2 crc
+	}
+	if len(b) < 9 {
+		return nil
+	}
+	return b[9:] // 6 header + 1 unit id + 1 function code + 1 byte count
+}
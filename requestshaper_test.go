@@ -0,0 +1,70 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestShaper_Do_mergesAdjacentReads(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{10: 1, 11: 2, 12: 3, 13: 4, 20: 5}}
+	shaper := NewRequestShaper(device, 20*time.Millisecond)
+
+	reqA, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 2)
+	reqB, _ := packet.NewReadHoldingRegistersRequestTCP(1, 11, 3)
+	reqC, _ := packet.NewReadHoldingRegistersRequestTCP(1, 20, 1) // gap, not merged
+
+	var wg sync.WaitGroup
+	responses := make([]packet.Response, 3)
+	for i, req := range []packet.Request{reqA, reqB, reqC} {
+		wg.Add(1)
+		go func(i int, req packet.Request) {
+			defer wg.Done()
+			resp, err := shaper.Do(context.Background(), req)
+			assert.NoError(t, err)
+			responses[i] = resp
+		}(i, req)
+	}
+	wg.Wait()
+
+	assert.Len(t, device.requests, 2)
+	merged := device.requests[0].(*packet.ReadHoldingRegistersRequestTCP)
+	assert.Equal(t, uint16(10), merged.StartAddress)
+	assert.Equal(t, uint16(4), merged.Quantity)
+
+	respA := responses[0].(*packet.ReadHoldingRegistersResponseTCP)
+	assert.Equal(t, reqA.TransactionID, respA.TransactionID)
+	assert.Equal(t, []byte{0x0, 0x1, 0x0, 0x2}, respA.Data)
+
+	respB := responses[1].(*packet.ReadHoldingRegistersResponseTCP)
+	assert.Equal(t, reqB.TransactionID, respB.TransactionID)
+	assert.Equal(t, []byte{0x0, 0x2, 0x0, 0x3, 0x0, 0x4}, respB.Data)
+
+	respC := responses[2].(*packet.ReadHoldingRegistersResponseTCP)
+	assert.Equal(t, []byte{0x0, 0x5}, respC.Data)
+}
+
+func TestRequestShaper_Do_passThroughWrites(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	shaper := NewRequestShaper(device, time.Hour)
+
+	req, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	resp, err := shaper.Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, uint16(0xcafe), device.registers[10])
+}
+
+func TestReadRequest_slice_coils(t *testing.T) {
+	merged := readRequest{functionCode: packet.FunctionReadCoils, startAddress: 10, quantity: 16}
+
+	// coils 10..25, coil 13 and coil 20 are set
+	data, err := merged.slice([]byte{0b00001000, 0b00000100}, 12, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0b00000010, 0b00000001}, data) // 13-12=1th bit, 20-12=8th bit
+}
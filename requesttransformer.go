@@ -0,0 +1,229 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// RequestTransformer is called by client with request before it is converted to bytes and sent to the server. It can
+// return modified request (i.e. with rewritten unit ID or addresses) or error to abort sending the request. This is
+// useful when talking through gateways that require fixed unit ID or apply address offsets as same field configuration
+// can be used without duplicating it for each gateway.
+//
+// Transformer must not modify given request as it is owned by the caller of Do. Return modified copy instead.
+type RequestTransformer func(req packet.Request) (packet.Request, error)
+
+// RewriteUnitID returns RequestTransformer that sets unit ID of every request to given value
+func RewriteUnitID(unitID uint8) RequestTransformer {
+	return func(req packet.Request) (packet.Request, error) {
+		clone, fields, ok := cloneRequest(req)
+		if !ok {
+			return nil, fmt.Errorf("unit ID rewrite is not supported for request type: %T", req)
+		}
+		*fields.unitID = unitID
+		return clone, nil
+	}
+}
+
+// OffsetAddress returns RequestTransformer that adds given offset to start address(es) of every request. Offset can be
+// negative. Request with address out of range (0-65535) after offsetting results an error.
+func OffsetAddress(offset int) RequestTransformer {
+	return func(req packet.Request) (packet.Request, error) {
+		clone, fields, ok := cloneRequest(req)
+		if !ok {
+			return nil, fmt.Errorf("address offset is not supported for request type: %T", req)
+		}
+		for _, addr := range fields.addresses {
+			result := int(*addr) + offset
+			if result < 0 || result > 65535 {
+				return nil, fmt.Errorf("request address with offset is out of range (0-65535): %v", result)
+			}
+			*addr = uint16(result)
+		}
+		return clone, nil
+	}
+}
+
+// ChainRequestTransformers returns RequestTransformer that applies given transformers in order
+func ChainRequestTransformers(transformers ...RequestTransformer) RequestTransformer {
+	return func(req packet.Request) (packet.Request, error) {
+		var err error
+		for _, t := range transformers {
+			req, err = t(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return req, nil
+	}
+}
+
+// transformRequest applies request transformer and checks its result
+func transformRequest(transformer RequestTransformer, req packet.Request) (packet.Request, error) {
+	result, err := transformer(req)
+	if err != nil {
+		return nil, fmt.Errorf("request transformer failed: %w", err)
+	}
+	if result == nil {
+		return nil, errors.New("request transformer returned nil request")
+	}
+	return result, nil
+}
+
+// requestFields contains pointers to fields of cloned request that transformers can modify
+type requestFields struct {
+	unitID    *uint8
+	addresses []*uint16
+}
+
+// cloneRequest returns shallow copy of given request and pointers to its unit ID and address fields
+func cloneRequest(req packet.Request) (packet.Request, requestFields, bool) {
+	switch r := req.(type) {
+	case *packet.ReadCoilsRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadCoilsRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadDiscreteInputsRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadDiscreteInputsRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadHoldingRegistersRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadHoldingRegistersRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadInputRegistersRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadInputRegistersRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.WriteSingleCoilRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.WriteSingleCoilRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.WriteSingleRegisterRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.WriteSingleRegisterRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.WriteMultipleCoilsRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.WriteMultipleCoilsRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.WriteMultipleRegistersRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.WriteMultipleRegistersRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.MaskWriteRegisterRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.ReadWriteMultipleRegistersRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.ReadStartAddress, &c.WriteStartAddress}}, true
+	case *packet.MaskWriteRegisterRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.ReadWriteMultipleRegistersRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.ReadStartAddress, &c.WriteStartAddress}}, true
+	case *packet.ReadServerIDRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadExceptionStatusRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.DiagnosticsRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadFIFOQueueRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.FIFOPointerAddress}}, true
+	case *packet.CANopenGeneralReferenceRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadDeviceIdentificationRequestTCP:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadServerIDRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadExceptionStatusRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.DiagnosticsRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadFIFOQueueRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.FIFOPointerAddress}}, true
+	case *packet.CANopenGeneralReferenceRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadDeviceIdentificationRequestRTU:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadCoilsRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadDiscreteInputsRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadHoldingRegistersRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.ReadInputRegistersRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.WriteSingleCoilRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.WriteSingleRegisterRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.WriteMultipleCoilsRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.WriteMultipleRegistersRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.StartAddress}}, true
+	case *packet.MaskWriteRegisterRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.Address}}, true
+	case *packet.ReadWriteMultipleRegistersRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.ReadStartAddress, &c.WriteStartAddress}}, true
+	case *packet.ReadServerIDRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadExceptionStatusRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.DiagnosticsRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadFIFOQueueRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID, addresses: []*uint16{&c.FIFOPointerAddress}}, true
+	case *packet.CANopenGeneralReferenceRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	case *packet.ReadDeviceIdentificationRequestASCII:
+		c := *r
+		return &c, requestFields{unitID: &c.UnitID}, true
+	}
+	return nil, requestFields{}, false
+}
@@ -0,0 +1,93 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRewriteUnitID(t *testing.T) {
+	req, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+
+	result, err := RewriteUnitID(255)(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(255), result.(*packet.WriteSingleRegisterRequestTCP).UnitID)
+	assert.Equal(t, req.TransactionID, result.(*packet.WriteSingleRegisterRequestTCP).TransactionID)
+	assert.Equal(t, uint8(1), req.UnitID)
+}
+
+func TestOffsetAddress(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       packet.Request
+		offset      int
+		expect      packet.Request
+		expectError string
+	}{
+		{
+			name:   "ok, positive offset",
+			given:  &packet.ReadHoldingRegistersRequestRTU{ReadHoldingRegistersRequest: packet.ReadHoldingRegistersRequest{UnitID: 1, StartAddress: 10, Quantity: 2}},
+			offset: 40001,
+			expect: &packet.ReadHoldingRegistersRequestRTU{ReadHoldingRegistersRequest: packet.ReadHoldingRegistersRequest{UnitID: 1, StartAddress: 40011, Quantity: 2}},
+		},
+		{
+			name:   "ok, negative offset",
+			given:  &packet.WriteSingleCoilRequestTCP{WriteSingleCoilRequest: packet.WriteSingleCoilRequest{UnitID: 1, Address: 10}},
+			offset: -1,
+			expect: &packet.WriteSingleCoilRequestTCP{WriteSingleCoilRequest: packet.WriteSingleCoilRequest{UnitID: 1, Address: 9}},
+		},
+		{
+			name:   "ok, FC23 offsets both addresses",
+			given:  &packet.ReadWriteMultipleRegistersRequestTCP{ReadWriteMultipleRegistersRequest: packet.ReadWriteMultipleRegistersRequest{ReadStartAddress: 1, WriteStartAddress: 2}},
+			offset: 100,
+			expect: &packet.ReadWriteMultipleRegistersRequestTCP{ReadWriteMultipleRegistersRequest: packet.ReadWriteMultipleRegistersRequest{ReadStartAddress: 101, WriteStartAddress: 102}},
+		},
+		{
+			name:        "nok, out of range",
+			given:       &packet.ReadCoilsRequestTCP{ReadCoilsRequest: packet.ReadCoilsRequest{StartAddress: 10}},
+			offset:      -11,
+			expectError: "request address with offset is out of range (0-65535): -1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := OffsetAddress(tc.offset)(tc.given)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChainRequestTransformers(t *testing.T) {
+	req, _ := packet.NewReadInputRegistersRequestTCP(1, 10, 1)
+
+	result, err := ChainRequestTransformers(RewriteUnitID(2), OffsetAddress(5))(req)
+
+	assert.NoError(t, err)
+	r := result.(*packet.ReadInputRegistersRequestTCP)
+	assert.Equal(t, uint8(2), r.UnitID)
+	assert.Equal(t, uint16(15), r.StartAddress)
+}
+
+func TestClient_Do_requestTransformerError(t *testing.T) {
+	client := NewTCPClientWithConfig(ClientConfig{
+		RequestTransformer: func(req packet.Request) (packet.Request, error) {
+			return nil, errors.New("no route")
+		},
+	})
+	client.conn = new(netConnMock) // nothing must be written
+
+	resp, err := client.Do(context.Background(), exampleFC1Request())
+
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, "request transformer failed: no route")
+}
@@ -0,0 +1,81 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// tcpResponseLength returns length of Modbus TCP response frame from MBAP header length field. When MBAP header is
+// not yet received expected (minimal) response length is returned.
+func tcpResponseLength(received []byte, expectedLen int) int {
+	if len(received) < 6 {
+		return expectedLen
+	}
+	return 6 + int(binary.BigEndian.Uint16(received[4:6]))
+}
+
+// rtuResponseLength returns length of Modbus RTU response frame. Length of variable length responses (FC17, FC24
+// and FC43/14) is determined from byte count or object headers of already received bytes. For other responses
+// (including FC43/13 CANopen which has no length information) expected response length is returned.
+func rtuResponseLength(received []byte, expectedLen int) int {
+	const crcLen = 2
+	if len(received) < 2 {
+		return expectedLen
+	}
+	// response = 1 UnitID + 1 functionCode + N function specific data + 2 CRC
+	switch received[1] {
+	case packet.FunctionReadServerID:
+		// 1 byte count + N server ID data
+		if len(received) < 3 {
+			return expectedLen
+		}
+		return 3 + int(received[2]) + crcLen
+	case packet.FunctionReadFIFOQueue:
+		// 2 byte count + 2 FIFO count + N*2 FIFO registers. Byte count includes FIFO count bytes
+		if len(received) < 4 {
+			return expectedLen
+		}
+		return 4 + int(binary.BigEndian.Uint16(received[2:4])) + crcLen
+	case packet.FunctionEncapsulatedInterface:
+		if len(received) < 3 || received[2] != packet.MEIReadDeviceIdentification {
+			return expectedLen
+		}
+		// 1 MEI type + 1 read device id code + 1 conformity level + 1 more follows + 1 next object id +
+		// 1 number of objects + N objects (1 object id + 1 object length + N object value)
+		const headerLen = 8
+		if len(received) < headerLen {
+			return headerLen + crcLen
+		}
+		pos := headerLen
+		for i := 0; i < int(received[7]); i++ {
+			if len(received) < pos+2 {
+				return pos + 2 + crcLen
+			}
+			pos += 2 + int(received[pos+1])
+		}
+		return pos + crcLen
+	}
+	return expectedLen
+}
+
+// asciiResponseLength returns length of Modbus ASCII response frame. Hex encoded bytes received so far are decoded
+// and length is determined same way as for Modbus RTU frames.
+func asciiResponseLength(received []byte, expectedLen int) int {
+	if len(received) < 1 || received[0] != ':' {
+		return expectedLen
+	}
+	// decoding stops at first non-hex character (i.e. `\r` of frame end)
+	decoded := make([]byte, (len(received)-1)/2)
+	n, _ := hex.Decode(decoded, received[1:1+2*len(decoded)])
+	if n < 2 {
+		return expectedLen
+	}
+	// RTU frame has 2 byte CRC, ASCII frame has ':' + 2 characters per byte + 2 character LRC + `\r\n`
+	rtuExpected := (expectedLen - 1) / 2
+	rtuLen := rtuResponseLength(decoded[:n], rtuExpected)
+	if rtuLen == rtuExpected {
+		return expectedLen
+	}
+	return 2*rtuLen + 1
+}
@@ -0,0 +1,114 @@
+package modbus
+
+import (
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTCPResponseLength(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   []byte
+		expect int
+	}{
+		{
+			name:   "ok, length from MBAP header",
+			when:   []byte{0x81, 0x80, 0x00, 0x00, 0x00, 0x0A, 0x01, 0x18, 0x00, 0x06, 0x00, 0x02},
+			expect: 16,
+		},
+		{
+			name:   "ok, MBAP header not received yet",
+			when:   []byte{0x81, 0x80, 0x00, 0x00, 0x00},
+			expect: 12,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, tcpResponseLength(tc.when, 12))
+		})
+	}
+}
+
+func TestRTUResponseLength(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   []byte
+		expect int
+	}{
+		{
+			name:   "ok, FC24 from byte count",
+			when:   []byte{0x01, 0x18, 0x00, 0x06, 0x00},
+			expect: 12,
+		},
+		{
+			name:   "ok, FC24 byte count not received yet",
+			when:   []byte{0x01, 0x18, 0x00},
+			expect: 8,
+		},
+		{
+			name:   "ok, FC17 from byte count",
+			when:   []byte{0x01, 0x11, 0x03, 0x01},
+			expect: 8,
+		},
+		{
+			name:   "ok, FC43/14 from object headers",
+			when:   []byte{0x01, 0x2B, 0x0E, 0x01, 0x01, 0x00, 0x00, 0x02, 0x00, 0x03, 'A', 'B', 'C', 0x01, 0x02, 'D', 'E'},
+			expect: 19,
+		},
+		{
+			name:   "ok, FC43/14 object header not received yet",
+			when:   []byte{0x01, 0x2B, 0x0E, 0x01, 0x01, 0x00, 0x00, 0x02, 0x00, 0x03, 'A'},
+			expect: 17,
+		},
+		{
+			name:   "ok, FC43/13 CANopen has no length information",
+			when:   []byte{0x01, 0x2B, 0x0D, 0x01, 0x01},
+			expect: 8,
+		},
+		{
+			name:   "ok, fixed length response",
+			when:   []byte{0x01, 0x03, 0x04, 0xCA, 0xFE},
+			expect: 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, rtuResponseLength(tc.when, 8))
+		})
+	}
+}
+
+func TestASCIIResponseLength(t *testing.T) {
+	frame := packet.RTUToASCII([]byte{0x01, 0x18, 0x00, 0x06, 0x00, 0x02, 0x01, 0xB8, 0x12, 0x84, 0x19, 0x18})
+
+	var testCases = []struct {
+		name   string
+		when   []byte
+		expect int
+	}{
+		{
+			name:   "ok, FC24 from byte count",
+			when:   frame[:11],
+			expect: len(frame),
+		},
+		{
+			name:   "ok, byte count not received yet",
+			when:   frame[:6],
+			expect: 15,
+		},
+		{
+			name:   "ok, complete frame",
+			when:   frame,
+			expect: len(frame),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, asciiResponseLength(tc.when, 15))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// ErrMismatchedResponse is error indicating that response does not match the request it was received for (i.e. stale
+// response from buggy gateway). Returned wrapped into ClientError when ClientConfig.ValidateResponses is enabled.
+// Check with errors.Is.
+var ErrMismatchedResponse = errors.New("response does not match request")
+
+// validateResponse checks that response frame matches request frame: function code, unit ID, byte count of read
+// responses against requested quantity and echoed address/quantity (or value) of write responses.
+func validateResponse(request []byte, response []byte, isRTU bool) error {
+	reqUnitID, req, err := framePDU(request, isRTU)
+	if err != nil || len(req) == 0 {
+		return nil // we can not validate against request we do not understand
+	}
+	respUnitID, resp, err := framePDU(response, isRTU)
+	if err != nil || len(resp) == 0 {
+		return nil // parsing reports invalid frames
+	}
+	functionCode := req[0]
+	if resp[0]&0x7f != functionCode {
+		return newMismatchedResponseError("function code %v, expected %v", resp[0]&0x7f, functionCode)
+	}
+	if respUnitID != reqUnitID {
+		return newMismatchedResponseError("unit ID %v, expected %v", respUnitID, reqUnitID)
+	}
+	if resp[0] != functionCode {
+		return nil // exception response
+	}
+
+	switch functionCode {
+	case packet.FunctionReadCoils, packet.FunctionReadDiscreteInputs:
+		if len(req) >= 5 {
+			quantity := binary.BigEndian.Uint16(req[3:5])
+			return checkByteCount(resp, (int(quantity)+7)/8)
+		}
+	case packet.FunctionReadHoldingRegisters, packet.FunctionReadInputRegisters, packet.FunctionReadWriteMultipleRegisters:
+		if len(req) >= 5 {
+			quantity := binary.BigEndian.Uint16(req[3:5])
+			return checkByteCount(resp, 2*int(quantity))
+		}
+	case packet.FunctionWriteSingleCoil, packet.FunctionWriteSingleRegister,
+		packet.FunctionWriteMultipleCoils, packet.FunctionWriteMultipleRegisters:
+		// single writes echo address and value, multiple writes echo address and quantity
+		return checkEcho(req, resp, 5)
+	case packet.FunctionMaskWriteRegister:
+		return checkEcho(req, resp, 7)
+	}
+	return nil
+}
+
+// framePDU returns unit ID and PDU (function code + data) of TCP, RTU or ASCII frame
+func framePDU(data []byte, isRTU bool) (uint8, []byte, error) {
+	if !isRTU {
+		if len(data) < 8 {
+			return 0, nil, errors.New("frame is too short")
+		}
+		return data[6], data[7:], nil
+	}
+	if len(data) > 0 && data[0] == ':' {
+		rtu, err := packet.ASCIIToRTU(data)
+		if err != nil {
+			return 0, nil, err
+		}
+		data = rtu
+	}
+	if len(data) < 4 {
+		return 0, nil, errors.New("frame is too short")
+	}
+	return data[0], data[1 : len(data)-2], nil
+}
+
+func checkByteCount(resp []byte, expected int) error {
+	if len(resp) < 2 {
+		return newMismatchedResponseError("byte count is missing, expected %v", expected)
+	}
+	if int(resp[1]) != expected {
+		return newMismatchedResponseError("byte count %v, expected %v", resp[1], expected)
+	}
+	return nil
+}
+
+func checkEcho(req []byte, resp []byte, length int) error {
+	if len(req) < length {
+		return nil
+	}
+	if len(resp) < length || !bytes.Equal(req[1:length], resp[1:length]) {
+		return newMismatchedResponseError("echoed address/quantity or value %x, expected %x", resp[1:], req[1:length])
+	}
+	return nil
+}
+
+func newMismatchedResponseError(format string, args ...interface{}) error {
+	return &ClientError{Err: fmt.Errorf("%w: "+format, append([]interface{}{ErrMismatchedResponse}, args...)...)}
+}
@@ -0,0 +1,149 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/modbustest"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValidateResponse(t *testing.T) {
+	readHolding, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 2)
+	readCoils, _ := packet.NewReadCoilsRequestTCP(1, 10, 9)
+	writeSingle, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	writeMultiple, _ := packet.NewWriteMultipleRegistersRequestTCP(1, 10, []byte{0xca, 0xfe, 0x00, 0x01})
+	readHoldingRTU, _ := packet.NewReadHoldingRegistersRequestRTU(1, 10, 1)
+	readHoldingASCII, _ := packet.NewReadHoldingRegistersRequestASCII(1, 10, 1)
+
+	var testCases = []struct {
+		name         string
+		whenRequest  []byte
+		whenResponse []byte
+		whenIsRTU    bool
+		expectError  string
+	}{
+		{
+			name:         "ok, read holding registers",
+			whenRequest:  readHolding.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x7, 0x1, 0x3, 0x4, 0xca, 0xfe, 0x0, 0x1},
+		},
+		{
+			name:         "ok, exception response",
+			whenRequest:  readHolding.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x3, 0x1, 0x83, 0x2},
+		},
+		{
+			name:         "nok, function code mismatch",
+			whenRequest:  readHolding.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x7, 0x1, 0x4, 0x4, 0xca, 0xfe, 0x0, 0x1},
+			expectError:  "response does not match request: function code 4, expected 3",
+		},
+		{
+			name:         "nok, unit ID mismatch",
+			whenRequest:  readHolding.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x7, 0x2, 0x3, 0x4, 0xca, 0xfe, 0x0, 0x1},
+			expectError:  "response does not match request: unit ID 2, expected 1",
+		},
+		{
+			name:         "nok, register byte count does not match quantity",
+			whenRequest:  readHolding.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x5, 0x1, 0x3, 0x2, 0xca, 0xfe},
+			expectError:  "response does not match request: byte count 2, expected 4",
+		},
+		{
+			name:         "ok, read coils",
+			whenRequest:  readCoils.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0xff, 0x01},
+		},
+		{
+			name:         "nok, coil byte count does not match quantity",
+			whenRequest:  readCoils.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x4, 0x1, 0x1, 0x1, 0xff},
+			expectError:  "response does not match request: byte count 1, expected 2",
+		},
+		{
+			name:         "ok, write single register echo",
+			whenRequest:  writeSingle.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x6, 0x1, 0x6, 0x0, 0xa, 0xca, 0xfe},
+		},
+		{
+			name:         "nok, write single register echoes different value",
+			whenRequest:  writeSingle.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x6, 0x1, 0x6, 0x0, 0xa, 0x0, 0x1},
+			expectError:  "response does not match request: echoed address/quantity or value 000a0001, expected 000acafe",
+		},
+		{
+			name:         "ok, write multiple registers echo",
+			whenRequest:  writeMultiple.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x6, 0x1, 0x10, 0x0, 0xa, 0x0, 0x2},
+		},
+		{
+			name:         "nok, write multiple registers echoes different address",
+			whenRequest:  writeMultiple.Bytes(),
+			whenResponse: []byte{0x0, 0x1, 0x0, 0x0, 0x0, 0x6, 0x1, 0x10, 0x0, 0xb, 0x0, 0x2},
+			expectError:  "response does not match request: echoed address/quantity or value 000b0002, expected 000a0002",
+		},
+		{
+			name:         "ok, rtu",
+			whenRequest:  readHoldingRTU.Bytes(),
+			whenResponse: []byte{0x1, 0x3, 0x2, 0xca, 0xfe, 0x0, 0x0},
+			whenIsRTU:    true,
+		},
+		{
+			name:         "nok, rtu unit ID mismatch",
+			whenRequest:  readHoldingRTU.Bytes(),
+			whenResponse: []byte{0x2, 0x3, 0x2, 0xca, 0xfe, 0x0, 0x0},
+			whenIsRTU:    true,
+			expectError:  "response does not match request: unit ID 2, expected 1",
+		},
+		{
+			name:         "nok, ascii byte count mismatch",
+			whenRequest:  readHoldingASCII.Bytes(),
+			whenResponse: []byte(":010304CAFE00012F\r\n"),
+			whenIsRTU:    true,
+			expectError:  "response does not match request: byte count 4, expected 2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateResponse(tc.whenRequest, tc.whenResponse, tc.whenIsRTU)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.True(t, errors.Is(err, ErrMismatchedResponse))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_Do_validateResponses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	handler := func(received []byte, bytesRead int) (response []byte, closeConnection bool) {
+		// stale response for different unit
+		return []byte{received[0], received[1], 0x0, 0x0, 0x0, 0x5, 0x2, 0x3, 0x2, 0xca, 0xfe}, false
+	}
+	addr, err := modbustest.RunServerOnRandomPort(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewTCPClientWithConfig(ClientConfig{ValidateResponses: true})
+	err = client.Connect(ctx, addr)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	resp, err := client.Do(ctx, req)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrMismatchedResponse))
+
+	var clientErr *ClientError
+	assert.True(t, errors.As(err, &clientErr))
+}
@@ -19,11 +19,25 @@ type SerialClient struct {
 
 	asProtocolErrorFunc func(data []byte) error
 	parseResponseFunc   func(data []byte) (packet.Response, error)
+	// responseLengthFunc returns length of response frame determined from already received bytes
+	responseLengthFunc func(received []byte, expectedLen int) int
+	// packetMaxLen is maximum length in bytes of valid response packet
+	packetMaxLen int
+
+	// rawResponses makes Do return RawResponse containing received frame instead of failing on exceptions or parse errors
+	rawResponses bool
+	// requestTransformer is called with every request before it is sent
+	requestTransformer RequestTransformer
+	requestDelay       requestDelayer
+	// frameSilence is minimal silent interval between frames (RTU t3.5)
+	frameSilence time.Duration
+	quirks       DeviceQuirks
 
 	mu         sync.RWMutex
 	isFlusher  bool
 	serialPort io.ReadWriteCloser
 	hooks      ClientHooks
+	metrics    ClientMetrics
 }
 
 // NewSerialClient creates new instance of Modbus SerialClient for Modbus RTU protocol
@@ -34,6 +48,8 @@ func NewSerialClient(serialPort io.ReadWriteCloser, opts ...SerialClientOptionFu
 		readTimeout:         defaultReadTimeout,
 		asProtocolErrorFunc: packet.AsRTUErrorPacket,
 		parseResponseFunc:   packet.ParseRTUResponseWithCRC,
+		responseLengthFunc:  rtuResponseLength,
+		packetMaxLen:        rtuPacketMaxLen,
 		serialPort:          serialPort,
 		hooks:               nil,
 		isFlusher:           isFlusher,
@@ -41,6 +57,11 @@ func NewSerialClient(serialPort io.ReadWriteCloser, opts ...SerialClientOptionFu
 	for _, o := range opts {
 		o(client)
 	}
+	client.requestTransformer = client.quirks.withRequestTransformer(client.requestTransformer)
+	client.requestDelay.delayAfterWrite = client.quirks.DelayAfterWrite
+	if client.frameSilence > client.requestDelay.delay {
+		client.requestDelay.delay = client.frameSilence
+	}
 	return client
 }
 
@@ -54,6 +75,13 @@ func WithSerialHooks(hooks ClientHooks) func(c *SerialClient) {
 	}
 }
 
+// WithSerialMetrics is option to set ClientMetrics that receives measurements of requests. See ClientStats.
+func WithSerialMetrics(metrics ClientMetrics) func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.metrics = metrics
+	}
+}
+
 // WithSerialReadTimeout is option to for setting total timeout for reading the whole packet
 func WithSerialReadTimeout(readTimeout time.Duration) func(c *SerialClient) {
 	return func(c *SerialClient) {
@@ -61,10 +89,71 @@ func WithSerialReadTimeout(readTimeout time.Duration) func(c *SerialClient) {
 	}
 }
 
+// WithSerialSkipCRCVerification is option to skip CRC verification of received packets. This is useful for gateways
+// that already verify CRC themselves and occasionally recalculate it wrongly.
+func WithSerialSkipCRCVerification() func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.parseResponseFunc = packet.ParseRTUResponse
+	}
+}
+
+// WithSerialASCII is option to use Modbus ASCII protocol instead of Modbus RTU protocol. Requests sent with
+// client must be ASCII requests (i.e. packet.ReadHoldingRegistersRequestASCII).
+func WithSerialASCII() func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.asProtocolErrorFunc = packet.AsASCIIErrorPacket
+		c.parseResponseFunc = packet.ParseASCIIResponse
+		c.responseLengthFunc = asciiResponseLength
+		c.packetMaxLen = asciiPacketMaxLen
+	}
+}
+
+// WithSerialFrameSilence is option to set minimal silent interval between end of previous response and next request.
+// Modbus RTU frames must be separated by at least 3.5 character times (see RTUFrameSilence). Delay set with
+// WithSerialDelayBetweenRequests is used when it is longer.
+func WithSerialFrameSilence(silence time.Duration) func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.frameSilence = silence
+	}
+}
+
+// WithSerialDeviceQuirks is option to apply device quirks (see DeviceQuirks and LookupDeviceQuirks) to SerialClient.
+// Quirks request transformer is applied after transformer set with WithSerialRequestTransformer.
+func WithSerialDeviceQuirks(quirks DeviceQuirks) func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.quirks = quirks
+	}
+}
+
+// WithSerialRawResponses is option to make Do return RawResponse containing received frame. Modbus exceptions and
+// responses that could not be parsed (i.e. unknown function codes) are not returned as errors but as RawResponse.
+func WithSerialRawResponses() func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.rawResponses = true
+	}
+}
+
+// WithSerialRequestTransformer is option to set RequestTransformer that is called with every request before it is
+// sent. It can rewrite i.e. unit ID or addresses of the request. See RewriteUnitID and OffsetAddress.
+func WithSerialRequestTransformer(transformer RequestTransformer) func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.requestTransformer = transformer
+	}
+}
+
+// WithSerialDelayBetweenRequests is option to set minimal delay between consecutive requests. This is useful for
+// fragile devices that can not handle requests sent back to back.
+func WithSerialDelayBetweenRequests(delay time.Duration) func(c *SerialClient) {
+	return func(c *SerialClient) {
+		c.requestDelay.delay = delay
+	}
+}
+
 // Do sends given Modbus request to modbus server and returns parsed Response.
 // ctx is to be used for to cancel connection attempt.
-// On modbus exception nil is returned as response and error wraps value of type packet.ErrorResponseRTU
-// User errors.Is and errors.As to check if error wraps packet.ErrorResponseRTU
+// On modbus exception nil is returned as response and error wraps value of type packet.ErrorResponseRTU (or
+// packet.ErrorResponseASCII when WithSerialASCII option is used)
+// User errors.Is and errors.As to check if error wraps packet.ErrorResponseRTU or packet.ErrorResponseASCII
 func (c *SerialClient) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -75,14 +164,42 @@ func (c *SerialClient) Do(ctx context.Context, req packet.Request) (packet.Respo
 	if c.serialPort == nil {
 		return nil, errors.New("serial port is not set")
 	}
+	if c.requestTransformer != nil {
+		var err error
+		if req, err = transformRequest(c.requestTransformer, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.requestDelay.wait(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+	data := req.Bytes()
+	start := time.Now()
+	resp, err := c.roundTrip(ctx, req, data)
+	if c.metrics != nil {
+		c.metrics.RequestDone(RequestMetric{
+			UnitID:       frameUnitID(data, true),
+			FunctionCode: req.FunctionCode(),
+			RoundTrip:    time.Since(start),
+			Err:          err,
+		})
+	}
+	return resp, err
+}
 
-	resp, err := c.do(ctx, req.Bytes(), req.ExpectedResponseLength())
+func (c *SerialClient) roundTrip(ctx context.Context, req packet.Request, data []byte) (packet.Response, error) {
+	resp, err := c.do(ctx, data, req.ExpectedResponseLength())
+	c.requestDelay.done(time.Now(), isWriteFunctionCode(req.FunctionCode()))
 	if err != nil {
 		return nil, err
 	}
 	if c.hooks != nil {
 		c.hooks.BeforeParse(resp)
 	}
+	if c.rawResponses {
+		return newRawResponse(resp, c.parseResponseFunc), nil
+	}
 	return c.parseResponseFunc(resp)
 }
 
@@ -102,16 +219,17 @@ func (c *SerialClient) do(ctx context.Context, data []byte, expectedLen int) ([]
 	time.Sleep(30 * time.Millisecond)
 
 	// make buffer a little bit bigger than would be valid to see problems when somehow more bytes are sent
-	const maxBytes = rtuPacketMaxLen + 10
-	received := [maxBytes]byte{}
+	const bufferLen = asciiPacketMaxLen + 10
+	received := [bufferLen]byte{}
+	maxBytes := c.packetMaxLen + 10
 	total := 0
-	readTimeout := time.After(c.readTimeout)
+	readTimeout := time.After(readTimeoutFromContext(ctx, c.readTimeout))
 	for {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-readTimeout:
-			return nil, &ClientError{Err: errors.New("total read timeout exceeded")}
+			return nil, &ClientError{Err: errReadTimeoutExceeded}
 		default:
 		}
 
@@ -129,7 +247,7 @@ func (c *SerialClient) do(ctx context.Context, data []byte, expectedLen int) ([]
 			return nil, &ClientError{Err: err}
 		}
 		total += n
-		if total > rtuPacketMaxLen {
+		if total > c.packetMaxLen {
 			if err := c.flush(); err != nil {
 				return nil, &ClientError{Err: err}
 			}
@@ -140,9 +258,12 @@ func (c *SerialClient) do(ctx context.Context, data []byte, expectedLen int) ([]
 			if err := c.flush(); err != nil {
 				return nil, &ClientError{Err: err}
 			}
-			return nil, &ClientError{Err: errPacket}
+			if c.rawResponses {
+				break
+			}
+			return nil, newExceptionError(errPacket)
 		}
-		if total >= expectedLen {
+		if total >= c.responseLengthFunc(received[:total], expectedLen) {
 			if err := c.flush(); err != nil {
 				return nil, &ClientError{Err: err}
 			}
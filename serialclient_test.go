@@ -115,6 +115,134 @@ func TestSerialClient_Do_receivePacketWith1Read(t *testing.T) {
 	logger.AssertExpectations(t)
 }
 
+func TestSerialClient_Do_skipCRCVerification(t *testing.T) {
+	serialPort := new(serialMock)
+
+	serialPort.On("Write", []byte{0x10, 0x1, 0x0, 0xc8, 0x0, 0x9, 0x7e, 0xb3}).Once().Return(0, nil)
+	serialPort.On("Flush").Once().Return(nil)
+
+	serialPort.On("Read", mock.Anything).
+		Return(7, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x10, 0x1, 0x2, 0x1, 0x2, 0xff, 0xff}) // invalid CRC
+		}).Once()
+
+	client := NewSerialClient(serialPort, WithSerialSkipCRCVerification())
+
+	response, err := client.Do(context.Background(), exampleFC1RTURequest())
+
+	assert.Equal(t, exampleFC1RTUResponse(), response)
+	assert.NoError(t, err)
+
+	serialPort.AssertExpectations(t)
+}
+
+func TestSerialClient_Do_ascii(t *testing.T) {
+	serialPort := new(serialMock)
+
+	serialPort.On("Write", []byte(":100100C800091E\r\n")).Once().Return(0, nil)
+	serialPort.On("Flush").Once().Return(nil)
+
+	serialPort.On("Read", mock.Anything).
+		Return(15, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, ":1001020102EA\r\n")
+		}).Once()
+
+	client := NewSerialClient(serialPort, WithSerialASCII())
+
+	req, err := packet.NewReadCoilsRequestASCII(0x10, 200, 9)
+	assert.NoError(t, err)
+	response, err := client.Do(context.Background(), req)
+
+	assert.Equal(t, &packet.ReadCoilsResponseASCII{ReadCoilsResponseRTU: *exampleFC1RTUResponse().(*packet.ReadCoilsResponseRTU)}, response)
+	assert.NoError(t, err)
+
+	serialPort.AssertExpectations(t)
+}
+
+func TestSerialClient_Do_asciiErrorPacket(t *testing.T) {
+	serialPort := new(serialMock)
+
+	serialPort.On("Write", []byte(":100100C800091E\r\n")).Once().Return(0, nil)
+	serialPort.On("Flush").Once().Return(nil)
+
+	serialPort.On("Read", mock.Anything).
+		Return(11, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, ":1081026D\r\n")
+		}).Once()
+
+	client := NewSerialClient(serialPort, WithSerialASCII())
+
+	req, err := packet.NewReadCoilsRequestASCII(0x10, 200, 9)
+	assert.NoError(t, err)
+	response, err := client.Do(context.Background(), req)
+
+	assert.Nil(t, response)
+	var errResp *packet.ErrorResponseASCII
+	assert.ErrorAs(t, err, &errResp)
+	assert.Equal(t, packet.ErrIllegalDataAddress, errResp.Code)
+
+	serialPort.AssertExpectations(t)
+}
+
+func TestSerialClient_Do_requestTransformer(t *testing.T) {
+	serialPort := new(serialMock)
+
+	// unit ID is rewritten from 1 to 16
+	serialPort.On("Write", []byte{0x10, 0x1, 0x0, 0xc8, 0x0, 0x9, 0x7e, 0xb3}).Once().Return(0, nil)
+	serialPort.On("Flush").Once().Return(nil)
+
+	serialPort.On("Read", mock.Anything).
+		Return(7, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x10, 0x1, 0x2, 0x1, 0x2, 0xc5, 0xae})
+		}).Once()
+
+	client := NewSerialClient(serialPort, WithSerialRequestTransformer(RewriteUnitID(16)))
+
+	req, _ := packet.NewReadCoilsRequestRTU(1, 200, 9)
+	response, err := client.Do(context.Background(), req)
+
+	assert.Equal(t, exampleFC1RTUResponse(), response)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), req.UnitID) // original request is not modified
+
+	serialPort.AssertExpectations(t)
+}
+
+func TestSerialClient_Do_rawResponses(t *testing.T) {
+	serialPort := new(serialMock)
+
+	serialPort.On("Write", []byte{0x10, 0x1, 0x0, 0xc8, 0x0, 0x9, 0x7e, 0xb3}).Once().Return(0, nil)
+	serialPort.On("Flush").Once().Return(nil)
+
+	serialPort.On("Read", mock.Anything).
+		Return(7, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x10, 0x1, 0x2, 0x1, 0x2, 0xc5, 0xae})
+		}).Once()
+
+	client := NewSerialClient(serialPort, WithSerialRawResponses())
+
+	response, err := client.Do(context.Background(), exampleFC1RTURequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &RawResponse{
+		Frame:    []byte{0x10, 0x1, 0x2, 0x1, 0x2, 0xc5, 0xae},
+		Response: exampleFC1RTUResponse(),
+	}, response)
+	assert.Equal(t, packet.FunctionReadCoils, response.FunctionCode())
+
+	serialPort.AssertExpectations(t)
+}
+
 func TestSerialClient_Do_receivePacketWith2Reads(t *testing.T) {
 	serialPort := new(serialMock)
 
@@ -146,6 +274,77 @@ func TestSerialClient_Do_receivePacketWith2Reads(t *testing.T) {
 	serialPort.AssertExpectations(t)
 }
 
+func TestSerialClient_Do_variableLengthResponseWith2Reads(t *testing.T) {
+	// full packet []byte{0x01, 0x18, 0x00, 0x06, 0x00, 0x02, 0x01, 0xB8, 0x12, 0x84, 0x19, 0x18}
+	rtu := []byte{0x01, 0x18, 0x00, 0x06, 0x00, 0x02, 0x01, 0xB8, 0x12, 0x84, 0x19, 0x18}
+	ascii := packet.RTUToASCII(rtu)
+	fifo := packet.ReadFIFOQueueResponse{UnitID: 1, FIFOCount: 2, Data: []byte{0x01, 0xB8, 0x12, 0x84}}
+
+	var testCases = []struct {
+		name       string
+		givenASCII bool
+		when       []byte
+		whenSplit  int
+		expect     packet.Response
+	}{
+		{
+			name:      "ok, RTU",
+			when:      rtu,
+			whenSplit: 8, // minimal response length
+			expect:    &packet.ReadFIFOQueueResponseRTU{ReadFIFOQueueResponse: fifo},
+		},
+		{
+			name:       "ok, ASCII",
+			givenASCII: true,
+			when:       ascii,
+			whenSplit:  15, // minimal response length
+			expect: &packet.ReadFIFOQueueResponseASCII{
+				ReadFIFOQueueResponseRTU: packet.ReadFIFOQueueResponseRTU{ReadFIFOQueueResponse: fifo},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			serialPort := new(serialMock)
+
+			serialPort.On("Write", mock.Anything).Once().Return(0, nil)
+			serialPort.On("Flush").Once().Return(nil)
+
+			serialPort.On("Read", mock.Anything).
+				Return(tc.whenSplit, nil).
+				Run(func(args mock.Arguments) {
+					b := args.Get(0).([]byte)
+					copy(b, tc.when[:tc.whenSplit])
+				}).Once()
+
+			serialPort.On("Read", mock.Anything).
+				Return(len(tc.when)-tc.whenSplit, nil).
+				Run(func(args mock.Arguments) {
+					b := args.Get(0).([]byte)
+					copy(b, tc.when[tc.whenSplit:])
+				}).Once()
+
+			var opts []SerialClientOptionFunc
+			var req packet.Request = &packet.ReadFIFOQueueRequestRTU{
+				ReadFIFOQueueRequest: packet.ReadFIFOQueueRequest{UnitID: 1, FIFOPointerAddress: 4},
+			}
+			if tc.givenASCII {
+				opts = append(opts, WithSerialASCII())
+				req = &packet.ReadFIFOQueueRequestASCII{ReadFIFOQueueRequestRTU: *req.(*packet.ReadFIFOQueueRequestRTU)}
+			}
+			client := NewSerialClient(serialPort, opts...)
+
+			response, err := client.Do(context.Background(), req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, response)
+
+			serialPort.AssertExpectations(t)
+		})
+	}
+}
+
 func TestSerialClient_Do_receiveErrorPacket(t *testing.T) {
 	serialPort := new(serialMock)
 
@@ -0,0 +1,141 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SerialParity is parity mode of serial port
+type SerialParity byte
+
+const (
+	// SerialParityNone disables parity bit
+	SerialParityNone SerialParity = 'N'
+	// SerialParityEven is even parity. This is default parity for Modbus RTU.
+	SerialParityEven SerialParity = 'E'
+	// SerialParityOdd is odd parity
+	SerialParityOdd SerialParity = 'O'
+)
+
+// SerialPortConfig is configuration of serial port opened with OpenSerialPort
+type SerialPortConfig struct {
+	// Device is path to serial device (i.e. `/dev/ttyUSB0`)
+	Device string
+	// BaudRate is speed of serial line. Defaults to 19200.
+	BaudRate int
+	// DataBits is amount of data bits in character (5-8). Defaults to 8.
+	DataBits int
+	// Parity is parity mode. Defaults to SerialParityEven.
+	Parity SerialParity
+	// StopBits is amount of stop bits (1 or 2). Defaults to 1.
+	StopBits int
+}
+
+// ParseSerialAddress parses serial port address into SerialPortConfig. Address format is
+// `serial:///dev/ttyUSB0?baud=19200&parity=E&stopbits=1&databits=8`. All query parameters are optional.
+func ParseSerialAddress(address string) (SerialPortConfig, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return SerialPortConfig{}, fmt.Errorf("invalid serial address: %w", err)
+	}
+	if u.Scheme != "serial" {
+		return SerialPortConfig{}, fmt.Errorf("invalid serial address scheme: %v", u.Scheme)
+	}
+	conf := SerialPortConfig{Device: u.Path}
+	for key, values := range u.Query() {
+		value := values[len(values)-1]
+		switch key {
+		case "baud":
+			conf.BaudRate, err = strconv.Atoi(value)
+		case "databits":
+			conf.DataBits, err = strconv.Atoi(value)
+		case "stopbits":
+			conf.StopBits, err = strconv.Atoi(value)
+		case "parity":
+			if value != "" {
+				conf.Parity = SerialParity(strings.ToUpper(value)[0])
+			}
+		default:
+			err = errors.New("unknown option")
+		}
+		if err != nil {
+			return SerialPortConfig{}, fmt.Errorf("invalid serial address option: %v, err: %w", key, err)
+		}
+	}
+	if err := conf.withDefaults().Validate(); err != nil {
+		return SerialPortConfig{}, err
+	}
+	return conf, nil
+}
+
+func (c SerialPortConfig) withDefaults() SerialPortConfig {
+	if c.BaudRate == 0 {
+		c.BaudRate = 19200
+	}
+	if c.DataBits == 0 {
+		c.DataBits = 8
+	}
+	if c.Parity == 0 {
+		c.Parity = SerialParityEven
+	}
+	if c.StopBits == 0 {
+		c.StopBits = 1
+	}
+	return c
+}
+
+// Validate checks if SerialPortConfig values are correctly filled
+func (c SerialPortConfig) Validate() error {
+	if c.Device == "" {
+		return errors.New("serial port device can not be empty")
+	}
+	if c.BaudRate < 0 {
+		return errors.New("serial port baud rate can not be negative")
+	}
+	if c.DataBits != 0 && (c.DataBits < 5 || c.DataBits > 8) {
+		return errors.New("serial port data bits must be in range (5-8)")
+	}
+	if c.StopBits != 0 && c.StopBits != 1 && c.StopBits != 2 {
+		return errors.New("serial port stop bits must be 1 or 2")
+	}
+	switch c.Parity {
+	case 0, SerialParityNone, SerialParityEven, SerialParityOdd:
+	default:
+		return errors.New("serial port parity must be N, E or O")
+	}
+	return nil
+}
+
+// FrameSilence returns Modbus RTU inter-frame silence (t3.5) for serial port baud rate. See RTUFrameSilence.
+func (c SerialPortConfig) FrameSilence() time.Duration {
+	return RTUFrameSilence(c.withDefaults().BaudRate)
+}
+
+// RTUFrameSilence returns Modbus RTU inter-frame silence (t3.5) duration for given baud rate. Frames must be separated by
+// silent interval of at least 3.5 character times (11 bits each). For baud rates greater than 19200 fixed value of
+// 1.75ms is used as recommended by Modbus over Serial Line specification.
+func RTUFrameSilence(baudRate int) time.Duration {
+	if baudRate <= 0 || baudRate > 19200 {
+		return 1750 * time.Microsecond
+	}
+	return time.Duration(3.5 * 11 * float64(time.Second) / float64(baudRate))
+}
+
+// OpenSerialClient opens serial port with given address (see ParseSerialAddress) and creates SerialClient for it.
+// Client waits for at least RTU inter-frame silence (t3.5) of the port baud rate between requests.
+func OpenSerialClient(address string, opts ...SerialClientOptionFunc) (*SerialClient, error) {
+	conf, err := ParseSerialAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := OpenSerialPort(conf)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]SerialClientOptionFunc{WithSerialFrameSilence(conf.FrameSilence())}, opts...)
+	return NewSerialClient(port, opts...), nil
+}
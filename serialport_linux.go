@@ -0,0 +1,133 @@
+//go:build linux && (386 || amd64 || arm || arm64 || riscv64 || loong64 || s390x)
+
+package modbus
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// tcflsh is ioctl request number to discard data in terminal queues
+const tcflsh = 0x540B
+
+var serialBaudRates = map[int]uint32{
+	1200:   syscall.B1200,
+	2400:   syscall.B2400,
+	4800:   syscall.B4800,
+	9600:   syscall.B9600,
+	19200:  syscall.B19200,
+	38400:  syscall.B38400,
+	57600:  syscall.B57600,
+	115200: syscall.B115200,
+	230400: syscall.B230400,
+}
+
+var serialDataBits = map[int]uint32{
+	5: syscall.CS5,
+	6: syscall.CS6,
+	7: syscall.CS7,
+	8: syscall.CS8,
+}
+
+// SerialPort is serial port opened in raw mode with termios. SerialPort implements io.ReadWriteCloser and Flusher so
+// it can be used with NewSerialClient.
+type SerialPort struct {
+	fd int
+}
+
+// OpenSerialPort opens serial device and configures it for Modbus RTU/ASCII communication. Read calls return after
+// 100ms even when no data was received so SerialClient read timeouts work.
+func OpenSerialPort(conf SerialPortConfig) (*SerialPort, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	conf = conf.withDefaults()
+	baud, ok := serialBaudRates[conf.BaudRate]
+	if !ok {
+		return nil, fmt.Errorf("serial port baud rate is not supported: %v", conf.BaudRate)
+	}
+
+	tios := syscall.Termios{
+		Cflag:  serialDataBits[conf.DataBits] | syscall.CREAD | syscall.CLOCAL | baud,
+		Ispeed: baud,
+		Ospeed: baud,
+	}
+	switch conf.Parity {
+	case SerialParityNone:
+		tios.Iflag = syscall.IGNPAR
+	case SerialParityEven:
+		tios.Iflag = syscall.INPCK
+		tios.Cflag |= syscall.PARENB
+	case SerialParityOdd:
+		tios.Iflag = syscall.INPCK
+		tios.Cflag |= syscall.PARENB | syscall.PARODD
+	}
+	if conf.StopBits == 2 {
+		tios.Cflag |= syscall.CSTOPB
+	}
+	tios.Cc[syscall.VMIN] = 0
+	tios.Cc[syscall.VTIME] = 1 // read timeout in deciseconds
+
+	// open in non-blocking mode so open does not wait for modem carrier line
+	fd, err := syscall.Open(conf.Device, syscall.O_RDWR|syscall.O_NOCTTY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open serial port: %w", err)
+	}
+	if err := ioctl(fd, syscall.TCSETS, uintptr(unsafe.Pointer(&tios))); err != nil {
+		_ = syscall.Close(fd)
+		return nil, fmt.Errorf("failed to configure serial port: %w", err)
+	}
+	if err := syscall.SetNonblock(fd, false); err != nil {
+		_ = syscall.Close(fd)
+		return nil, fmt.Errorf("failed to configure serial port: %w", err)
+	}
+	return &SerialPort{fd: fd}, nil
+}
+
+// Read reads data from serial port. Returns 0 bytes and nil error when no data was received within 100ms.
+func (p *SerialPort) Read(b []byte) (int, error) {
+	for {
+		n, err := syscall.Read(p.fd, b)
+		if err == syscall.EINTR {
+			continue
+		}
+		if n < 0 {
+			n = 0
+		}
+		return n, err
+	}
+}
+
+// Write writes data to serial port
+func (p *SerialPort) Write(b []byte) (int, error) {
+	total := 0
+	for total < len(b) {
+		n, err := syscall.Write(p.fd, b[total:])
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// Flush discards data received but not read and data written but not transmitted
+func (p *SerialPort) Flush() error {
+	return ioctl(p.fd, tcflsh, syscall.TCIOFLUSH)
+}
+
+// Close closes serial port
+func (p *SerialPort) Close() error {
+	return syscall.Close(p.fd)
+}
+
+func ioctl(fd int, request uint, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(request), arg); errno != 0 {
+		return errno
+	}
+	return nil
+}
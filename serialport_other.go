@@ -0,0 +1,36 @@
+//go:build !linux || !(386 || amd64 || arm || arm64 || riscv64 || loong64 || s390x)
+
+package modbus
+
+import (
+	"errors"
+)
+
+// SerialPort is serial port opened in raw mode. Native serial ports are supported only on Linux. On other platforms use
+// third party serial library with NewSerialClient (see serial.md).
+type SerialPort struct{}
+
+// OpenSerialPort returns error as native serial ports are not supported on this platform
+func OpenSerialPort(conf SerialPortConfig) (*SerialPort, error) {
+	return nil, errors.New("serial port is not supported on this platform")
+}
+
+// Read is not supported on this platform
+func (p *SerialPort) Read(b []byte) (int, error) {
+	return 0, errors.New("serial port is not supported on this platform")
+}
+
+// Write is not supported on this platform
+func (p *SerialPort) Write(b []byte) (int, error) {
+	return 0, errors.New("serial port is not supported on this platform")
+}
+
+// Flush is not supported on this platform
+func (p *SerialPort) Flush() error {
+	return errors.New("serial port is not supported on this platform")
+}
+
+// Close is not supported on this platform
+func (p *SerialPort) Close() error {
+	return nil
+}
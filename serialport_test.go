@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseSerialAddress(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        string
+		expect      SerialPortConfig
+		expectError string
+	}{
+		{
+			name:   "ok, all options",
+			when:   "serial:///dev/ttyUSB0?baud=9600&parity=n&stopbits=2&databits=7",
+			expect: SerialPortConfig{Device: "/dev/ttyUSB0", BaudRate: 9600, DataBits: 7, Parity: SerialParityNone, StopBits: 2},
+		},
+		{
+			name:   "ok, without options",
+			when:   "serial:///dev/ttyS1",
+			expect: SerialPortConfig{Device: "/dev/ttyS1"},
+		},
+		{
+			name:        "nok, invalid scheme",
+			when:        "tcp://localhost:502",
+			expectError: "invalid serial address scheme: tcp",
+		},
+		{
+			name:        "nok, missing device",
+			when:        "serial://?baud=9600",
+			expectError: "serial port device can not be empty",
+		},
+		{
+			name:        "nok, invalid baud",
+			when:        "serial:///dev/ttyUSB0?baud=fast",
+			expectError: `invalid serial address option: baud, err: strconv.Atoi: parsing "fast": invalid syntax`,
+		},
+		{
+			name:        "nok, unknown option",
+			when:        "serial:///dev/ttyUSB0?flow=rtscts",
+			expectError: "invalid serial address option: flow, err: unknown option",
+		},
+		{
+			name:        "nok, invalid parity",
+			when:        "serial:///dev/ttyUSB0?parity=X",
+			expectError: "serial port parity must be N, E or O",
+		},
+		{
+			name:        "nok, invalid stop bits",
+			when:        "serial:///dev/ttyUSB0?stopbits=3",
+			expectError: "serial port stop bits must be 1 or 2",
+		},
+		{
+			name:        "nok, invalid data bits",
+			when:        "serial:///dev/ttyUSB0?databits=9",
+			expectError: "serial port data bits must be in range (5-8)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf, err := ParseSerialAddress(tc.when)
+
+			assert.Equal(t, tc.expect, conf)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRTUFrameSilence(t *testing.T) {
+	assert.Equal(t, 4010416*time.Nanosecond, RTUFrameSilence(9600))
+	assert.Equal(t, 2005208*time.Nanosecond, RTUFrameSilence(19200))
+	assert.Equal(t, 1750*time.Microsecond, RTUFrameSilence(115200))
+	assert.Equal(t, 2005208*time.Nanosecond, SerialPortConfig{Device: "/dev/ttyUSB0"}.FrameSilence())
+}
+
+func TestWithSerialFrameSilence(t *testing.T) {
+	client := NewSerialClient(nil, WithSerialFrameSilence(2*time.Millisecond))
+	assert.Equal(t, 2*time.Millisecond, client.requestDelay.delay)
+
+	client = NewSerialClient(nil, WithSerialFrameSilence(2*time.Millisecond), WithSerialDelayBetweenRequests(50*time.Millisecond))
+	assert.Equal(t, 50*time.Millisecond, client.requestDelay.delay)
+}
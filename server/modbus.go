@@ -9,7 +9,11 @@ import (
 
 // ModbusTCPAssembler assembles read data into complete packets and calls ModbusHandler with assembled packet
 type ModbusTCPAssembler struct {
-	Handler  ModbusHandler
+	Handler ModbusHandler
+	// ParseOptions are options used to parse received requests (i.e. packet.WithLenientCoilsPadding to accept Write
+	// Multiple Coils requests with non-zero padding bits)
+	ParseOptions []packet.ParseOption
+
 	received bytes.Buffer
 }
 
@@ -24,7 +28,7 @@ func (m *ModbusTCPAssembler) ReceiveRead(ctx context.Context, received []byte, b
 		return err.(*packet.ErrorParseTCP).Bytes(), false
 	}
 
-	p, err := packet.ParseTCPRequest(m.received.Next(n))
+	p, err := packet.ParseTCPRequest(m.received.Next(n), m.ParseOptions...)
 	if err != nil {
 		return err.(*packet.ErrorParseTCP).Bytes(), false
 	}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// UnitIDMux is ModbusHandler that routes received Modbus TCP requests to different handlers by request unit ID.
+// This allows consolidating multiple data sources (simulators, real devices behind ForwardHandler etc.) behind
+// single Modbus TCP endpoint.
+//
+// Handlers must be registered before server is started. UnitIDMux is not goroutine safe for registration.
+type UnitIDMux struct {
+	handlers map[uint8]ModbusHandler
+
+	// Fallback is called for requests with unit ID that has no registered handler. When Fallback is nil
+	// Gateway Path Unavailable (0x0A) exception is returned.
+	Fallback ModbusHandler
+}
+
+// NewUnitIDMux creates new instance of UnitIDMux
+func NewUnitIDMux() *UnitIDMux {
+	return &UnitIDMux{
+		handlers: map[uint8]ModbusHandler{},
+	}
+}
+
+// Register registers handler for given unit ID. Registering handler for same unit ID replaces previous handler.
+func (m *UnitIDMux) Register(unitID uint8, handler ModbusHandler) *UnitIDMux {
+	m.handlers[unitID] = handler
+	return m
+}
+
+// Handle routes received request to handler registered for request unit ID.
+func (m *UnitIDMux) Handle(ctx context.Context, received packet.Request) (packet.Response, error) {
+	data := received.Bytes()
+	if len(data) < 8 {
+		return nil, packet.NewErrorParseTCP(packet.ErrServerFailure, "request is too short to be Modbus TCP packet")
+	}
+	unitID := data[6]
+	handler, ok := m.handlers[unitID]
+	if !ok {
+		handler = m.Fallback
+	}
+	if handler == nil {
+		return nil, tcpExceptionFor(data, packet.ErrGatewayPathUnavailable, fmt.Sprintf("no handler for unit ID: %v", unitID))
+	}
+	return handler.Handle(ctx, received)
+}
+
+// Doer is interface for sending Modbus request to backend device and receiving parsed response. modbus.Client
+// implements this interface.
+type Doer interface {
+	Do(ctx context.Context, req packet.Request) (packet.Response, error)
+}
+
+// ForwardHandler is ModbusHandler that forwards received Modbus TCP requests to backend device using Client and
+// returns backend response to the requester. Backend client must use Modbus TCP protocol.
+type ForwardHandler struct {
+	// Client is used to send requests to the backend device. Client must be connected and is not closed by handler.
+	Client Doer
+
+	// BackendUnitID is unit ID used for requests sent to backend when RewriteUnitID is true. Responses are rewritten
+	// back to unit ID requester used.
+	BackendUnitID uint8
+	RewriteUnitID bool
+	// ParseOptions are options used to parse request with rewritten unit ID
+	ParseOptions []packet.ParseOption
+}
+
+// Handle forwards received request to backend device
+func (h *ForwardHandler) Handle(ctx context.Context, received packet.Request) (packet.Response, error) {
+	data := received.Bytes()
+	if len(data) < 8 {
+		return nil, packet.NewErrorParseTCP(packet.ErrServerFailure, "request is too short to be Modbus TCP packet")
+	}
+	unitID := data[6]
+
+	req := received
+	if h.RewriteUnitID {
+		data[6] = h.BackendUnitID
+		tmp, err := packet.ParseTCPRequest(data, h.ParseOptions...)
+		if err != nil {
+			return nil, err
+		}
+		req = tmp
+	}
+
+	resp, err := h.Client.Do(ctx, req)
+	if err != nil {
+		var errResp *packet.ErrorResponseTCP
+		if errors.As(err, &errResp) {
+			return nil, tcpExceptionFor(received.Bytes(), errResp.Code, errResp.Error())
+		}
+		return nil, tcpExceptionFor(received.Bytes(), packet.ErrGatewayTargetedDeviceResponse, err.Error())
+	}
+	if !h.RewriteUnitID {
+		return resp, nil
+	}
+
+	respData := resp.Bytes()
+	if len(respData) < 8 {
+		return nil, tcpExceptionFor(received.Bytes(), packet.ErrGatewayTargetedDeviceResponse, "backend response too short")
+	}
+	respData[6] = unitID
+	return packet.ParseTCPResponse(respData)
+}
+
+func tcpExceptionFor(request []byte, code packet.ErrCode, message string) *packet.ErrorParseTCP {
+	tmpErr := packet.NewErrorParseTCP(code, message)
+	tmpErr.Packet.TransactionID = binary.BigEndian.Uint16(request[0:2])
+	tmpErr.Packet.UnitID = request[6]
+	tmpErr.Packet.Function = request[7]
+	return tmpErr
+}
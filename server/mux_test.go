@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type doerFunc func(ctx context.Context, req packet.Request) (packet.Response, error)
+
+func (f doerFunc) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	return f(ctx, req)
+}
+
+func exampleFC3Request(unitID uint8) *packet.ReadHoldingRegistersRequestTCP {
+	return &packet.ReadHoldingRegistersRequestTCP{
+		MBAPHeader: packet.MBAPHeader{TransactionID: 0x1234},
+		ReadHoldingRegistersRequest: packet.ReadHoldingRegistersRequest{
+			UnitID:       unitID,
+			StartAddress: 10,
+			Quantity:     2,
+		},
+	}
+}
+
+func TestUnitIDMux_Handle(t *testing.T) {
+	mux := NewUnitIDMux().Register(1, new(mbServer))
+
+	resp, err := mux.Handle(context.Background(), exampleFC3Request(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x1, 0x01, 0x02}, resp.(packet.ReadHoldingRegistersResponseTCP).Data)
+}
+
+func TestUnitIDMux_Handle_fallback(t *testing.T) {
+	mux := NewUnitIDMux()
+	mux.Fallback = new(mbServer)
+
+	resp, err := mux.Handle(context.Background(), exampleFC3Request(99))
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestUnitIDMux_Handle_noHandler(t *testing.T) {
+	mux := NewUnitIDMux().Register(1, new(mbServer))
+
+	resp, err := mux.Handle(context.Background(), exampleFC3Request(2))
+	assert.Nil(t, resp)
+
+	var target *packet.ErrorParseTCP
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, packet.ErrorResponseTCP{
+		TransactionID: 0x1234,
+		UnitID:        2,
+		Function:      packet.FunctionReadHoldingRegisters,
+		Code:          packet.ErrGatewayPathUnavailable,
+	}, target.Packet)
+}
+
+func TestForwardHandler_Handle_rewriteUnitID(t *testing.T) {
+	backend := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		r := req.(*packet.ReadHoldingRegistersRequestTCP)
+		assert.Equal(t, uint8(5), r.UnitID)
+		return new(mbServer).Handle(ctx, r)
+	})
+	h := &ForwardHandler{Client: backend, BackendUnitID: 5, RewriteUnitID: true}
+
+	resp, err := h.Handle(context.Background(), exampleFC3Request(2))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(2), resp.(*packet.ReadHoldingRegistersResponseTCP).UnitID)
+	assert.Equal(t, uint16(0x1234), resp.(*packet.ReadHoldingRegistersResponseTCP).TransactionID)
+}
+
+func TestForwardHandler_Handle_backendError(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenErr    error
+		expectCode packet.ErrCode
+	}{
+		{
+			name:       "modbus exception is passed through",
+			whenErr:    &packet.ErrorResponseTCP{Code: packet.ErrIllegalDataAddress},
+			expectCode: packet.ErrIllegalDataAddress,
+		},
+		{
+			name:       "network error is gateway target failure",
+			whenErr:    errors.New("i/o timeout"),
+			expectCode: packet.ErrGatewayTargetedDeviceResponse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+				return nil, tc.whenErr
+			})
+			h := &ForwardHandler{Client: backend}
+
+			resp, err := h.Handle(context.Background(), exampleFC3Request(1))
+			assert.Nil(t, resp)
+
+			var target *packet.ErrorParseTCP
+			assert.True(t, errors.As(err, &target))
+			assert.Equal(t, tc.expectCode, target.Packet.Code)
+			assert.Equal(t, uint8(1), target.Packet.UnitID)
+		})
+	}
+}
@@ -64,6 +64,9 @@ type Server struct {
 	// AssemblerCreatorFunc creates Assembler for each connetion to assemble different read byte fragments into complete
 	// modbus packet. Could have different implementations for TCP or RTU packets
 	AssemblerCreatorFunc func(handler ModbusHandler) PacketAssembler
+	// ParseOptions are options used by default assembler (ModbusTCPAssembler) to parse received requests. For example
+	// packet.WithLenientCoilsPadding accepts Write Multiple Coils requests from masters sending non-zero padding bits.
+	ParseOptions []packet.ParseOption
 
 	// OnServeFunc allows capturing listener address just before server starts to accepting connections. This is useful
 	// for testing when listener is started with random port `:0`.
@@ -114,7 +117,7 @@ type ContextRemoteAddr struct{}
 func (s *Server) serve(ctx context.Context, listener net.Listener, handler ModbusHandler) error {
 	if s.AssemblerCreatorFunc == nil {
 		s.AssemblerCreatorFunc = func(handler ModbusHandler) PacketAssembler {
-			return &ModbusTCPAssembler{Handler: handler}
+			return &ModbusTCPAssembler{Handler: handler, ParseOptions: s.ParseOptions}
 		}
 	}
 	onErrorFunc := s.OnErrorFunc
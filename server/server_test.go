@@ -119,3 +119,57 @@ func TestServer_Addr(t *testing.T) {
 	}
 	assert.Equal(t, lAddr, s.Addr().String())
 }
+
+func TestModbusTCPAssembler_ParseOptions(t *testing.T) {
+	// FC15 request for 3 coils with non-zero padding bits in coils data byte
+	writeCoils := []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x08, 0x11, 0x0F, 0x04, 0x10, 0x00, 0x03, 0x01, 0x0d}
+
+	var testCases = []struct {
+		name       string
+		whenOpts   []packet.ParseOption
+		expect     []byte
+		expectCall bool
+	}{
+		{
+			name:   "nok, non-zero padding bits are rejected by default",
+			expect: []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x03, 0x11, 0x8f, 0x03},
+		},
+		{
+			name:       "ok, non-zero padding bits are accepted with lenient option",
+			whenOpts:   []packet.ParseOption{packet.WithLenientCoilsPadding()},
+			expect:     []byte{0x01, 0x38, 0x00, 0x00, 0x00, 0x06, 0x11, 0x0f, 0x04, 0x10, 0x00, 0x03},
+			expectCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := handlerFunc(func(ctx context.Context, received packet.Request) (packet.Response, error) {
+				called = true
+				req := received.(*packet.WriteMultipleCoilsRequestTCP)
+				return packet.WriteMultipleCoilsResponseTCP{
+					MBAPHeader: req.MBAPHeader,
+					WriteMultipleCoilsResponse: packet.WriteMultipleCoilsResponse{
+						UnitID:       req.UnitID,
+						StartAddress: req.StartAddress,
+						CoilCount:    req.CoilCount,
+					},
+				}, nil
+			})
+			assembler := &ModbusTCPAssembler{Handler: handler, ParseOptions: tc.whenOpts}
+
+			resp, closeConn := assembler.ReceiveRead(context.Background(), writeCoils, len(writeCoils))
+
+			assert.False(t, closeConn)
+			assert.Equal(t, tc.expect, resp)
+			assert.Equal(t, tc.expectCall, called)
+		})
+	}
+}
+
+type handlerFunc func(ctx context.Context, received packet.Request) (packet.Response, error)
+
+func (f handlerFunc) Handle(ctx context.Context, received packet.Request) (packet.Response, error) {
+	return f(ctx, received)
+}
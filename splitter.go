@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/aldas/go-modbus-client/packet"
 	"sort"
+	"time"
 )
 
 type splitToFuncType uint8
@@ -17,203 +18,412 @@ const (
 	splitToFC3RTU
 	splitToFC4TCP
 	splitToFC4RTU
+	splitToFC1ASCII
+	splitToFC2ASCII
+	splitToFC3ASCII
+	splitToFC4ASCII
+	splitToMixedTCP
+	splitToMixedRTU
+	splitToMixedASCII
 )
 
+// functionCode returns function code requests are created for. Zero means that function code is decided by fields
+// (mixed split).
+func (t splitToFuncType) functionCode() uint8 {
+	switch t {
+	case splitToFC1TCP, splitToFC1RTU, splitToFC1ASCII:
+		return packet.FunctionReadCoils
+	case splitToFC2TCP, splitToFC2RTU, splitToFC2ASCII:
+		return packet.FunctionReadDiscreteInputs
+	case splitToFC3TCP, splitToFC3RTU, splitToFC3ASCII:
+		return packet.FunctionReadHoldingRegisters
+	case splitToFC4TCP, splitToFC4RTU, splitToFC4ASCII:
+		return packet.FunctionReadInputRegisters
+	}
+	return 0
+}
+
+// withFunctionCode returns function specific split type with the same protocol as mixed split type
+func (t splitToFuncType) withFunctionCode(functionCode uint8) (splitToFuncType, error) {
+	var tcp, ascii splitToFuncType
+	switch functionCode {
+	case packet.FunctionReadCoils:
+		tcp, ascii = splitToFC1TCP, splitToFC1ASCII
+	case packet.FunctionReadDiscreteInputs:
+		tcp, ascii = splitToFC2TCP, splitToFC2ASCII
+	case packet.FunctionReadHoldingRegisters:
+		tcp, ascii = splitToFC3TCP, splitToFC3ASCII
+	case packet.FunctionReadInputRegisters:
+		tcp, ascii = splitToFC4TCP, splitToFC4ASCII
+	default:
+		return 0, fmt.Errorf("unsupported function code for split: %v", functionCode)
+	}
+	switch t {
+	case splitToMixedRTU:
+		return tcp + 1, nil // RTU variant always follows TCP variant
+	case splitToMixedASCII:
+		return ascii, nil
+	}
+	return tcp, nil
+}
+
+// RequestDefaults are default values used by FieldsToRequests
+type RequestDefaults struct {
+	// ServerAddress is used for fields that have empty ServerAddress
+	ServerAddress string
+	// UnitID is used for fields that have UnitID set to 0
+	UnitID uint8
+	// FunctionCode is read function code requests are created for. Supported values are 1 (FC1), 2 (FC2),
+	// 3 (FC3) and 4 (FC4). When not set Read Holding Registers (FC3) requests are created. Fields that can not be read
+	// with the function (coil fields for register functions and vice versa, fields with different Field.FunctionCode)
+	// are silently left out.
+	FunctionCode uint8
+	// IsRTU creates RTU requests instead of TCP requests
+	IsRTU bool
+	// QuirksProfile is name of device quirks profile (see RegisterDeviceQuirks). Profile MaxRegistersPerRead limits
+	// quantity of register read requests and profile AllowedGap is used when AllowedGap is not set.
+	QuirksProfile string
+	// AllowedGap is maximum amount of unused registers (or coils) between fields that are read with the same request.
+	// Fields further apart are read with separate requests. 0 means gaps are limited only by maximum request quantity,
+	// AllowedGapNone means that only contiguous fields are read with the same request.
+	AllowedGap int
+	// TransactionIDGenerator is used to set transaction IDs of TCP requests. When nil transaction IDs are random
+	// (packet.RandomTransactionID).
+	TransactionIDGenerator packet.TransactionIDGenerator
+}
+
+// FieldsToRequests groups fields into read requests the same way as Builder does. This is stateless alternative to
+// Builder for cases when fields are received from elsewhere (i.e. over the network) and are already complete.
+// Given fields slice is not modified.
+func FieldsToRequests(fields Fields, defaults RequestDefaults) ([]BuilderRequest, error) {
+	result := make([]BuilderRequest, 0)
+	err := FieldsToRequestsFunc(fields, defaults, func(req BuilderRequest) error {
+		result = append(result, req)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FieldsToRequestsFunc groups fields into read requests the same way as FieldsToRequests does but instead of returning
+// all requests at once, requests are created lazily and given to `fn` one by one. This keeps memory usage bounded for
+// very large configurations (tens of thousands of fields) when requests are processed as they are created. Error
+// returned by `fn` stops creating requests and is returned as is.
+func FieldsToRequestsFunc(fields Fields, defaults RequestDefaults, fn func(req BuilderRequest) error) error {
+	var funcType splitToFuncType
+	switch defaults.FunctionCode {
+	case packet.FunctionReadCoils:
+		funcType = splitToFC1TCP
+	case packet.FunctionReadDiscreteInputs:
+		funcType = splitToFC2TCP
+	case 0, packet.FunctionReadHoldingRegisters:
+		funcType = splitToFC3TCP
+	case packet.FunctionReadInputRegisters:
+		funcType = splitToFC4TCP
+	default:
+		return fmt.Errorf("unsupported function code for fields to requests: %v", defaults.FunctionCode)
+	}
+	if defaults.IsRTU {
+		funcType++ // RTU variant always follows TCP variant
+	}
+	var quirks DeviceQuirks
+	if defaults.QuirksProfile != "" {
+		var err error
+		if quirks, err = lookupQuirksProfile(defaults.QuirksProfile); err != nil {
+			return err
+		}
+	}
+
+	tmp := make(Fields, len(fields))
+	for i, f := range fields {
+		if f.ServerAddress == "" {
+			f.ServerAddress = defaults.ServerAddress
+		}
+		if f.UnitID == 0 {
+			f.UnitID = defaults.UnitID
+		}
+		tmp[i] = f
+	}
+	limits := splitLimits{maxRegisters: quirks.MaxRegistersPerRead, allowedGap: quirks.AllowedGap}
+	if defaults.AllowedGap != 0 {
+		limits.allowedGap = defaults.AllowedGap
+	}
+	return splitEach(tmp, funcType, limits, func(req BuilderRequest) error {
+		if defaults.TransactionIDGenerator != nil {
+			setTransactionIDs([]BuilderRequest{req}, defaults.TransactionIDGenerator)
+		}
+		return fn(req)
+	})
+}
+
+// setTransactionIDs sets transaction IDs of TCP requests with given generator. Nil generator does nothing.
+func setTransactionIDs(requests []BuilderRequest, generator packet.TransactionIDGenerator) {
+	if generator == nil {
+		return
+	}
+	for _, r := range requests {
+		switch req := r.Request.(type) {
+		case *packet.ReadCoilsRequestTCP:
+			req.TransactionID = generator()
+		case *packet.ReadDiscreteInputsRequestTCP:
+			req.TransactionID = generator()
+		case *packet.ReadHoldingRegistersRequestTCP:
+			req.TransactionID = generator()
+		case *packet.ReadInputRegistersRequestTCP:
+			req.TransactionID = generator()
+		case *packet.WriteMultipleCoilsRequestTCP:
+			req.TransactionID = generator()
+		case *packet.WriteMultipleRegistersRequestTCP:
+			req.TransactionID = generator()
+		}
+	}
+}
+
 // split groups (by host:port+UnitID, "optimized" max amount of fields for max quantity) fields into packets
 func split(fields []Field, funcType splitToFuncType) ([]BuilderRequest, error) {
-	onlyCoils := funcType == splitToFC1TCP || funcType == splitToFC1RTU || funcType == splitToFC2TCP || funcType == splitToFC2RTU
-	connectionGroup, err := groupForSingleConnection(fields, onlyCoils)
+	return splitWithLimits(fields, funcType, splitLimits{})
+}
+
+func splitWithLimits(fields []Field, funcType splitToFuncType, limits splitLimits) ([]BuilderRequest, error) {
+	result := make([]BuilderRequest, 0)
+	err := splitEach(fields, funcType, limits, func(req BuilderRequest) error {
+		result = append(result, req)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	batches := batchToRequests(connectionGroup)
+	return result, nil
+}
 
-	result := make([]BuilderRequest, 0, len(batches))
-	for _, b := range batches {
-		var req packet.Request
-		var err error
-		switch funcType {
-		case splitToFC1TCP:
-			req, err = packet.NewReadCoilsRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
-		case splitToFC1RTU:
-			req, err = packet.NewReadCoilsRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
-
-		case splitToFC2TCP:
-			req, err = packet.NewReadDiscreteInputsRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
-		case splitToFC2RTU:
-			req, err = packet.NewReadDiscreteInputsRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
-
-		case splitToFC3TCP:
-			req, err = packet.NewReadHoldingRegistersRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
-		case splitToFC3RTU:
-			req, err = packet.NewReadHoldingRegistersRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
-
-		case splitToFC4TCP:
-			req, err = packet.NewReadInputRegistersRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
-		case splitToFC4RTU:
-			req, err = packet.NewReadInputRegistersRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
+// AllowedGapNone is AllowedGap value that allows only contiguous fields to be read with the same request
+const AllowedGapNone = -1
+
+// splitLimits limits how fields are grouped into single request
+type splitLimits struct {
+	// maxRegisters is maximum register quantity of single request. When 0 protocol maximum is used.
+	maxRegisters uint16
+	// allowedGap is maximum amount of unused registers/coils between fields of single request. When 0 gaps are not
+	// limited, negative value allows no gaps.
+	allowedGap int
+}
+
+// splitEach groups fields into packets within given limits and calls fn with each created request.
+func splitEach(fields []Field, funcType splitToFuncType, limits splitLimits, fn func(req BuilderRequest) error) error {
+	functionCode := funcType.functionCode()
+	connectionGroup, err := groupForSingleConnection(fields, functionCode)
+	if err != nil {
+		return err
+	}
+	batchID := 0
+	for _, group := range connectionGroup {
+		groupFuncType := funcType
+		if functionCode == 0 {
+			if groupFuncType, err = funcType.withFunctionCode(group.functionCode); err != nil {
+				return err
+			}
 		}
+		err := group.eachBatch(limits, func(b requestBatch) error {
+			req, err := newBatchRequest(groupFuncType, b)
+			if err != nil {
+				return err
+			}
+			batchID++
+			return fn(BuilderRequest{
+				Request: req,
+				BatchID: batchID - 1,
+
+				ServerAddress: b.Address,
+				UnitID:        b.UnitID,
+				StartAddress:  b.StartAddress,
+				Fields:        b.fields,
+				ReadTimeout:   b.ReadTimeout,
+			})
+		})
 		if err != nil {
-			return nil, err
+			return err
 		}
-		result = append(result, BuilderRequest{
-			Request: req,
+	}
+	return nil
+}
 
-			ServerAddress: b.Address,
-			UnitID:        b.UnitID,
-			StartAddress:  b.StartAddress,
-			Fields:        b.fields,
-		})
+func newBatchRequest(funcType splitToFuncType, b requestBatch) (packet.Request, error) {
+	switch funcType {
+	case splitToFC1TCP:
+		return packet.NewReadCoilsRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC1RTU:
+		return packet.NewReadCoilsRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC1ASCII:
+		return packet.NewReadCoilsRequestASCII(b.UnitID, b.StartAddress, b.Quantity)
+
+	case splitToFC2TCP:
+		return packet.NewReadDiscreteInputsRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC2RTU:
+		return packet.NewReadDiscreteInputsRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC2ASCII:
+		return packet.NewReadDiscreteInputsRequestASCII(b.UnitID, b.StartAddress, b.Quantity)
+
+	case splitToFC3TCP:
+		return packet.NewReadHoldingRegistersRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC3RTU:
+		return packet.NewReadHoldingRegistersRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC3ASCII:
+		return packet.NewReadHoldingRegistersRequestASCII(b.UnitID, b.StartAddress, b.Quantity)
+
+	case splitToFC4TCP:
+		return packet.NewReadInputRegistersRequestTCP(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC4RTU:
+		return packet.NewReadInputRegistersRequestRTU(b.UnitID, b.StartAddress, b.Quantity)
+	case splitToFC4ASCII:
+		return packet.NewReadInputRegistersRequestASCII(b.UnitID, b.StartAddress, b.Quantity)
 	}
-	return result, nil
+	return nil, fmt.Errorf("unknown split function type: %v", funcType)
 }
 
-// groupForSingleConnection groups fields into groups what can be requested potentially by same request (same server + unit ID + function)
-func groupForSingleConnection(fields []Field, onlyCoils bool) ([]builderSlotGroup, error) {
-	groups := map[string]builderSlotGroup{}
+type builderGroupKey struct {
+	serverAddress string
+	unitID        uint8
+	functionCode  uint8
+	readTimeout   time.Duration
+}
+
+// groupForSingleConnection groups fields into groups what can be requested potentially by same request (same server +
+// unit ID + function). When functionCode is set only fields that can be read with that function are grouped, fields
+// with explicitly set different function code are left out. When functionCode is 0 all fields are grouped by function
+// code they are read with.
+func groupForSingleConnection(fields []Field, functionCode uint8) ([]*builderSlotGroup, error) {
+	groups := map[builderGroupKey]*builderSlotGroup{}
+	result := make([]*builderSlotGroup, 0)
 	for _, f := range fields {
 		if err := f.Validate(); err != nil {
 			return nil, err
 		}
-		// create groups by modbus server Address + unitID + isCoil
+		// create groups by modbus server Address + unitID + function code + read timeout
 		isCoil := f.Type == FieldTypeCoil
-		if onlyCoils && !isCoil {
-			continue
-		} else if !onlyCoils && isCoil {
-			continue
+		fc := f.readFunctionCode()
+		if functionCode != 0 {
+			if f.FunctionCode != 0 && f.FunctionCode != functionCode {
+				continue
+			}
+			isCoilFunction := functionCode == packet.FunctionReadCoils || functionCode == packet.FunctionReadDiscreteInputs
+			if isCoil != isCoilFunction {
+				continue
+			}
+			fc = functionCode
 		}
 
-		gID := fmt.Sprintf("%v_%v_%v", f.ServerAddress, f.UnitID, isCoil)
-		group, ok := groups[gID]
+		key := builderGroupKey{
+			serverAddress: f.ServerAddress,
+			unitID:        f.UnitID,
+			functionCode:  fc,
+			readTimeout:   f.ReadTimeout,
+		}
+		group, ok := groups[key]
 		if !ok {
-			group = builderSlotGroup{
+			group = &builderSlotGroup{
 				serverAddress: f.ServerAddress,
 				unitID:        f.UnitID,
+				functionCode:  fc,
 				isForCoils:    isCoil,
-				slots:         make([]builderSlot, 0),
+				readTimeout:   f.ReadTimeout,
 			}
-			groups[gID] = group
+			groups[key] = group
+			result = append(result, group)
 		}
-
-		group.AddField(f)
-		groups[gID] = group
-	}
-	// TODO: as of Go 1.23 this could be shortened to `slices.Collect(maps.Values(groups))`
-	result := make([]builderSlotGroup, 0, len(groups))
-	for _, g := range groups {
-		result = append(result, g)
+		group.fields = append(group.fields, f)
 	}
 	return result, nil
 }
 
-func batchToRequests(connectionGroup []builderSlotGroup) []requestBatch {
-	// Coils are always grouped to separate requests (fc1/fc2) from fields suitable for registers (fc3/fc4)
-	//
-	// NB: is batching/grouping algorithm is very naive. It just sorts fields by register and creates N number
-	// of requests of them by limiting quantity to MaxRegistersInReadResponse. It does not try to optimise long caps
-	// between fields
-	// assumes that UnitID is same for all fields within group
-
-	var result = make([]requestBatch, 0)
-	for _, slotGroup := range connectionGroup {
-		address := slotGroup.serverAddress
-		unitID := slotGroup.unitID
-		addressLimit := packet.MaxRegistersInReadResponse
-		if slotGroup.isForCoils {
-			addressLimit = packet.MaxCoilsInReadResponse
-		}
-		sort.Sort(slotsSorter(slotGroup.slots))
-
-		batch := requestBatch{}
-		isFirstSeen := false
-		var firstAddress uint16
-		for _, slot := range slotGroup.slots {
-			slotAddress := slot.address
-			if !isFirstSeen {
-				firstAddress = slotAddress
-				isFirstSeen = true
-
-				batch.StartAddress = firstAddress
-				batch.Address = address
-				batch.UnitID = unitID
+// eachBatch sorts group fields by address and calls fn with each batch of fields that fit into single request. Batch
+// fields are sub-slices of sorted group fields so no copies of fields are made.
+//
+// NB: is batching/grouping algorithm is very naive. It just sorts fields by register and creates N number
+// of requests of them by limiting quantity to MaxRegistersInReadResponse. It does not try to optimise long caps
+// between fields unless allowed gap is limited.
+func (g *builderSlotGroup) eachBatch(limits splitLimits, fn func(b requestBatch) error) error {
+	addressLimit := packet.MaxRegistersInReadResponse
+	if limits.maxRegisters > 0 && limits.maxRegisters < addressLimit {
+		addressLimit = limits.maxRegisters
+	}
+	if g.isForCoils {
+		addressLimit = packet.MaxCoilsInReadResponse
+	}
+	// stable sort keeps fields with same address in order they were added
+	sort.Stable(fieldsByAddress(g.fields))
+
+	batchStart := 0
+	batch := requestBatch{Address: g.serverAddress, UnitID: g.unitID, ReadTimeout: g.readTimeout}
+	for slotStart := 0; slotStart < len(g.fields); {
+		// fields with same address form a "slot" that always belongs to the same batch
+		slotAddress := g.fields[slotStart].Address
+		slotSize := uint16(0)
+		slotEnd := slotStart
+		for ; slotEnd < len(g.fields) && g.fields[slotEnd].Address == slotAddress; slotEnd++ {
+			if size := g.fields[slotEnd].registerSize(); size > slotSize {
+				slotSize = size
 			}
+		}
+		if slotStart == 0 {
+			batch.StartAddress = slotAddress
+		}
 
-			slotEndAddress := slotAddress + slot.size
-			addressDiff := slotEndAddress - firstAddress
-			if addressDiff > addressLimit {
-				result = append(result, batch)
-
-				batch = requestBatch{
-					Address:      address,
-					UnitID:       unitID,
-					StartAddress: slotAddress,
-				}
-				firstAddress = slotAddress
-				addressDiff = slot.size
-			}
-			if batch.Quantity < addressDiff {
-				batch.Quantity = addressDiff
+		addressDiff := slotAddress + slotSize - batch.StartAddress
+		if addressDiff > addressLimit || isGapTooLarge(batch, slotAddress, limits.allowedGap) {
+			batch.fields = g.fields[batchStart:slotStart:slotStart]
+			if err := fn(batch); err != nil {
+				return err
 			}
 
-			batch.fields = append(batch.fields, slot.fields...)
+			batchStart = slotStart
+			batch = requestBatch{
+				Address:      g.serverAddress,
+				UnitID:       g.unitID,
+				StartAddress: slotAddress,
+				ReadTimeout:  g.readTimeout,
+			}
+			addressDiff = slotSize
 		}
-		result = append(result, batch)
+		if batch.Quantity < addressDiff {
+			batch.Quantity = addressDiff
+		}
+		slotStart = slotEnd
 	}
-	return result
-}
-
-type builderSlot struct {
-	address uint16
-	size    uint16
-	fields  Fields
+	batch.fields = g.fields[batchStart:len(g.fields):len(g.fields)]
+	return fn(batch)
 }
 
-type builderSlots []builderSlot
-
-func (bs *builderSlots) IndexOf(address uint16) int {
-	for i, b := range *bs {
-		if b.address == address {
-			return i
-		}
+// isGapTooLarge checks if amount of unused addresses between batch end and given address exceeds allowed gap
+func isGapTooLarge(batch requestBatch, address uint16, allowedGap int) bool {
+	if allowedGap == 0 || batch.Quantity == 0 {
+		return false
 	}
-	return -1
+	batchEnd := int(batch.StartAddress) + int(batch.Quantity)
+	gap := int(address) - batchEnd
+	if allowedGap < 0 {
+		return gap > 0
+	}
+	return gap > allowedGap
 }
 
-type slotsSorter builderSlots
+type fieldsByAddress Fields
 
-func (a slotsSorter) Len() int      { return len(a) }
-func (a slotsSorter) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
-func (a slotsSorter) Less(i, j int) bool {
-	return a[i].address < a[j].address
+func (a fieldsByAddress) Len() int      { return len(a) }
+func (a fieldsByAddress) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a fieldsByAddress) Less(i, j int) bool {
+	return a[i].Address < a[j].Address
 }
 
 type builderSlotGroup struct {
 	serverAddress string
 	unitID        uint8
+	functionCode  uint8
 	isForCoils    bool
+	readTimeout   time.Duration
 
-	slots builderSlots
-}
-
-func (g *builderSlotGroup) AddField(f Field) {
-	registerSize := f.registerSize()
-	i := g.slots.IndexOf(f.Address)
-	if i == -1 {
-		g.slots = append(g.slots, builderSlot{
-			address: f.Address,
-			size:    registerSize,
-			fields:  Fields{f},
-		})
-		return
-	}
-
-	slot := g.slots[i]
-
-	slot.fields = append(slot.fields, f)
-	if registerSize > slot.size {
-		slot.size = registerSize
-	}
-	g.slots[i] = slot
+	fields Fields
 }
 
 type requestBatch struct {
@@ -222,7 +432,8 @@ type requestBatch struct {
 	StartAddress uint16
 	Quantity     uint16
 
-	IsForCoils bool
+	IsForCoils  bool
+	ReadTimeout time.Duration
 
 	fields Fields
 }
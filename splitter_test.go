@@ -1,9 +1,12 @@
 package modbus
 
 import (
+	"errors"
+	"fmt"
 	"github.com/aldas/go-modbus-client/packet"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestSplit_validationError(t *testing.T) {
@@ -176,3 +179,321 @@ func TestSplit_to2CoilsBatches(t *testing.T) {
 	assert.Equal(t, expect2, secondBatch.Request)
 	assert.Len(t, secondBatch.Fields, 1)
 }
+
+func TestSplit_mixedFunctionCodes(t *testing.T) {
+	given := []Field{
+		{ServerAddress: ":502", Address: 1, Type: FieldTypeUint16},
+		{ServerAddress: ":502", Address: 1, Type: FieldTypeCoil},
+		{ServerAddress: ":502", Address: 2, Type: FieldTypeInt16, FunctionCode: packet.FunctionReadInputRegisters},
+		{ServerAddress: ":502", Address: 3, Type: FieldTypeCoil, FunctionCode: packet.FunctionReadDiscreteInputs},
+		{ServerAddress: ":502", Address: 5, Type: FieldTypeCoil, FunctionCode: packet.FunctionReadCoils},
+	}
+
+	batched, err := split(given, splitToMixedASCII)
+	assert.NoError(t, err)
+	assert.Len(t, batched, 4)
+
+	expectFC3, _ := packet.NewReadHoldingRegistersRequestASCII(0, 1, 1)
+	assert.Equal(t, expectFC3, batched[0].Request)
+	assert.Equal(t, Fields{given[0]}, batched[0].Fields)
+
+	expectFC1, _ := packet.NewReadCoilsRequestASCII(0, 1, 5)
+	assert.Equal(t, expectFC1, batched[1].Request)
+	assert.Equal(t, Fields{given[1], given[4]}, batched[1].Fields)
+
+	expectFC4, _ := packet.NewReadInputRegistersRequestASCII(0, 2, 1)
+	assert.Equal(t, expectFC4, batched[2].Request)
+	assert.Equal(t, Fields{given[2]}, batched[2].Fields)
+
+	expectFC2, _ := packet.NewReadDiscreteInputsRequestASCII(0, 3, 1)
+	assert.Equal(t, expectFC2, batched[3].Request)
+	assert.Equal(t, Fields{given[3]}, batched[3].Fields)
+}
+
+func TestSplit_functionSpecificSkipsFieldsOfOtherFunction(t *testing.T) {
+	given := []Field{
+		{ServerAddress: ":502", Address: 1, Type: FieldTypeUint16},
+		{ServerAddress: ":502", Address: 2, Type: FieldTypeUint16, FunctionCode: packet.FunctionReadInputRegisters},
+		{ServerAddress: ":502", Address: 3, Type: FieldTypeUint16, FunctionCode: packet.FunctionReadHoldingRegisters},
+	}
+
+	batched, err := split(given, splitToFC4RTU)
+	assert.NoError(t, err)
+	assert.Len(t, batched, 1)
+	expect, _ := packet.NewReadInputRegistersRequestRTU(0, 1, 2)
+	assert.Equal(t, expect, batched[0].Request)
+	assert.Equal(t, Fields{given[0], given[1]}, batched[0].Fields)
+}
+
+func TestFieldsToRequests(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenFields   Fields
+		whenDefaults RequestDefaults
+		expectType   packet.Request
+		expectServer string
+		expectUnitID uint8
+		expectError  string
+	}{
+		{
+			name: "ok, defaults to FC3 TCP",
+			whenFields: Fields{
+				{Address: 1, Type: FieldTypeInt16},
+				{Address: 2, Type: FieldTypeUint16},
+			},
+			whenDefaults: RequestDefaults{ServerAddress: ":502", UnitID: 3},
+			expectType:   &packet.ReadHoldingRegistersRequestTCP{},
+			expectServer: ":502",
+			expectUnitID: 3,
+		},
+		{
+			name: "ok, FC4 RTU with field values overriding defaults",
+			whenFields: Fields{
+				{ServerAddress: ":5020", UnitID: 1, Address: 1, Type: FieldTypeInt16},
+			},
+			whenDefaults: RequestDefaults{ServerAddress: ":502", UnitID: 3, FunctionCode: packet.FunctionReadInputRegisters, IsRTU: true},
+			expectType:   &packet.ReadInputRegistersRequestRTU{},
+			expectServer: ":5020",
+			expectUnitID: 1,
+		},
+		{
+			name:         "ok, FC2 TCP",
+			whenFields:   Fields{{Address: 1, Type: FieldTypeCoil}},
+			whenDefaults: RequestDefaults{ServerAddress: ":502", FunctionCode: packet.FunctionReadDiscreteInputs},
+			expectType:   &packet.ReadDiscreteInputsRequestTCP{},
+			expectServer: ":502",
+		},
+		{
+			name:         "nok, unsupported function code",
+			whenFields:   Fields{{Address: 1, Type: FieldTypeInt16}},
+			whenDefaults: RequestDefaults{ServerAddress: ":502", FunctionCode: packet.FunctionWriteSingleRegister},
+			expectError:  "unsupported function code for fields to requests: 6",
+		},
+		{
+			name:         "nok, validation error",
+			whenFields:   Fields{{Address: 1, Type: FieldTypeInt16}},
+			whenDefaults: RequestDefaults{},
+			expectError:  "field server address can not be empty",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := append(Fields(nil), tc.whenFields...)
+
+			reqs, err := FieldsToRequests(tc.whenFields, tc.whenDefaults)
+
+			assert.Equal(t, original, tc.whenFields) // given fields are not modified
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, reqs, 1)
+			assert.IsType(t, tc.expectType, reqs[0].Request)
+			assert.Equal(t, tc.expectServer, reqs[0].ServerAddress)
+			assert.Equal(t, tc.expectUnitID, reqs[0].UnitID)
+		})
+	}
+}
+
+func TestFieldsToRequests_TransactionIDGenerator(t *testing.T) {
+	fields := Fields{{Address: 1, Type: FieldTypeCoil}}
+
+	reqs, err := FieldsToRequests(fields, RequestDefaults{
+		ServerAddress:          ":502",
+		FunctionCode:           packet.FunctionReadCoils,
+		TransactionIDGenerator: func() uint16 { return 0x1234 },
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 1)
+	assert.Equal(t, uint16(0x1234), reqs[0].Request.(*packet.ReadCoilsRequestTCP).TransactionID)
+}
+
+func TestFieldsToRequestsFunc(t *testing.T) {
+	fields := Fields{
+		{Address: 1, Type: FieldTypeUint16},
+		{Address: 300, Type: FieldTypeUint16},
+		{Address: 600, Type: FieldTypeUint16},
+	}
+
+	var starts []uint16
+	err := FieldsToRequestsFunc(fields, RequestDefaults{ServerAddress: ":502"}, func(req BuilderRequest) error {
+		starts = append(starts, req.StartAddress)
+		if len(starts) == 2 {
+			return errors.New("stop")
+		}
+		return nil
+	})
+
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, []uint16{1, 300}, starts)
+}
+
+func largeSyntheticFields(servers int, fieldsPerServer int) Fields {
+	fields := make(Fields, 0, servers*fieldsPerServer)
+	for s := 0; s < servers; s++ {
+		for i := 0; i < fieldsPerServer; i++ {
+			fields = append(fields, Field{
+				Name:          fmt.Sprintf("s%v_f%v", s, i),
+				ServerAddress: fmt.Sprintf("192.168.0.%v:502", s),
+				UnitID:        1,
+				Address:       uint16(i * 3),
+				Type:          FieldTypeUint32,
+			})
+		}
+	}
+	return fields
+}
+
+func TestFieldsToRequests_largeConfig(t *testing.T) {
+	fields := largeSyntheticFields(10, 5000)
+
+	reqs, err := FieldsToRequests(fields, RequestDefaults{})
+
+	assert.NoError(t, err)
+	total := 0
+	for _, r := range reqs {
+		total += len(r.Fields)
+	}
+	assert.Equal(t, len(fields), total)
+}
+
+func BenchmarkFieldsToRequests_50kFields(b *testing.B) {
+	fields := largeSyntheticFields(10, 5000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := FieldsToRequests(fields, RequestDefaults{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFieldsToRequestsFunc_50kFields(b *testing.B) {
+	fields := largeSyntheticFields(10, 5000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := FieldsToRequestsFunc(fields, RequestDefaults{}, func(req BuilderRequest) error {
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFieldsToRequests_sameAddressFieldsStayInSameRequest(t *testing.T) {
+	fields := Fields{
+		{Name: "a", Address: 0, Type: FieldTypeUint16},
+		{Name: "b", Address: 122, Type: FieldTypeUint16},
+		{Name: "c", Address: 122, Type: FieldTypeUint64},
+	}
+
+	reqs, err := FieldsToRequests(fields, RequestDefaults{ServerAddress: ":502"})
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+	assert.Equal(t, uint16(0), reqs[0].StartAddress)
+	assert.Len(t, reqs[0].Fields, 1)
+	assert.Equal(t, uint16(122), reqs[1].StartAddress)
+	assert.Equal(t, uint16(4), reqs[1].Request.(*packet.ReadHoldingRegistersRequestTCP).Quantity)
+	assert.Len(t, reqs[1].Fields, 2)
+}
+
+func TestFieldsToRequests_slowFieldsAreIsolated(t *testing.T) {
+	fields := Fields{
+		{Name: "a", Address: 0, Type: FieldTypeUint16},
+		{Name: "demand", Address: 1, Type: FieldTypeUint16, ReadTimeout: 10 * time.Second},
+		{Name: "b", Address: 2, Type: FieldTypeUint16},
+	}
+
+	reqs, err := FieldsToRequests(fields, RequestDefaults{ServerAddress: ":502"})
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+
+	assert.Equal(t, uint16(0), reqs[0].StartAddress)
+	assert.Equal(t, time.Duration(0), reqs[0].ReadTimeout)
+	assert.Equal(t, uint16(3), reqs[0].Request.(*packet.ReadHoldingRegistersRequestTCP).Quantity)
+	assert.Len(t, reqs[0].Fields, 2)
+
+	assert.Equal(t, uint16(1), reqs[1].StartAddress)
+	assert.Equal(t, 10*time.Second, reqs[1].ReadTimeout)
+	assert.Equal(t, uint16(1), reqs[1].Request.(*packet.ReadHoldingRegistersRequestTCP).Quantity)
+	assert.Equal(t, "demand", reqs[1].Fields[0].Name)
+}
+
+func TestFieldsToRequests_allowedGap(t *testing.T) {
+	fields := Fields{
+		{Name: "a", Address: 0, Type: FieldTypeUint32},   // 0,1
+		{Name: "b", Address: 2, Type: FieldTypeUint16},   // 2, contiguous
+		{Name: "c", Address: 5, Type: FieldTypeUint16},   // gap of 2 registers (3,4)
+		{Name: "d", Address: 100, Type: FieldTypeUint16}, // gap of 94 registers
+	}
+	RegisterDeviceQuirks("test_gap", DeviceQuirks{AllowedGap: 2})
+
+	var testCases = []struct {
+		name             string
+		givenDefaults    RequestDefaults
+		expectAddresses  []uint16
+		expectQuantities []uint16
+	}{
+		{
+			name:             "ok, gaps are not limited by default",
+			givenDefaults:    RequestDefaults{ServerAddress: ":502"},
+			expectAddresses:  []uint16{0},
+			expectQuantities: []uint16{101},
+		},
+		{
+			name:             "ok, only contiguous fields",
+			givenDefaults:    RequestDefaults{ServerAddress: ":502", AllowedGap: AllowedGapNone},
+			expectAddresses:  []uint16{0, 5, 100},
+			expectQuantities: []uint16{3, 1, 1},
+		},
+		{
+			name:             "ok, gap from quirks profile",
+			givenDefaults:    RequestDefaults{ServerAddress: ":502", QuirksProfile: "test_gap"},
+			expectAddresses:  []uint16{0, 100},
+			expectQuantities: []uint16{6, 1},
+		},
+		{
+			name:             "ok, defaults override quirks profile gap",
+			givenDefaults:    RequestDefaults{ServerAddress: ":502", QuirksProfile: "test_gap", AllowedGap: 1},
+			expectAddresses:  []uint16{0, 5, 100},
+			expectQuantities: []uint16{3, 1, 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqs, err := FieldsToRequests(fields, tc.givenDefaults)
+			assert.NoError(t, err)
+
+			addresses := make([]uint16, len(reqs))
+			quantities := make([]uint16, len(reqs))
+			for i, r := range reqs {
+				addresses[i] = r.StartAddress
+				quantities[i] = r.Request.(*packet.ReadHoldingRegistersRequestTCP).Quantity
+			}
+			assert.Equal(t, tc.expectAddresses, addresses)
+			assert.Equal(t, tc.expectQuantities, quantities)
+		})
+	}
+}
+
+func TestBuilder_AllowedGap(t *testing.T) {
+	b := NewRequestBuilder(":502", 1).AllowedGap(AllowedGapNone)
+	b.Add(b.Coil(1))
+	b.Add(b.Coil(2))
+	b.Add(b.Coil(4))
+
+	reqs, err := b.ReadCoilsTCP()
+
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 2)
+	assert.Equal(t, uint16(2), reqs[0].Request.(*packet.ReadCoilsRequestTCP).Quantity)
+	assert.Equal(t, uint16(4), reqs[1].StartAddress)
+}
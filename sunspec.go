@@ -0,0 +1,269 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+const (
+	// sunSpecMarker is `SunS` marker SunSpec register map starts with
+	sunSpecMarker = uint32(0x53756e53)
+	// sunSpecEndModelID is model ID of end marker of SunSpec model chain
+	sunSpecEndModelID = uint16(0xFFFF)
+	// maxSunSpecModels limits length of the model chain walked to protect against devices with broken model headers
+	maxSunSpecModels = 256
+)
+
+// ErrSunSpecNotFound is error returned when SunSpec `SunS` marker is not found at any of the base addresses
+var ErrSunSpecNotFound = errors.New("sunspec marker not found")
+
+// SunSpecConfig is configuration for DiscoverSunSpecTCP and DiscoverSunSpecRTU
+type SunSpecConfig struct {
+	// UnitID is unit ID of the device
+	UnitID uint8
+	// BaseAddresses are addresses `SunS` marker is looked for in given order. Defaults to 40000, 50000 and 0.
+	BaseAddresses []uint16
+}
+
+// SunSpecDevice is SunSpec register map discovered from the device
+type SunSpecDevice struct {
+	UnitID uint8
+	// BaseAddress is address of `SunS` marker
+	BaseAddress uint16
+	// Models are models in order they are in device model chain
+	Models []SunSpecModel
+}
+
+// SunSpecModel is single model (block) in SunSpec model chain
+type SunSpecModel struct {
+	// ID is SunSpec model ID (i.e. 1 for Common model, 103 for three phase inverter)
+	ID uint16
+	// Address is address of the model header (model ID register)
+	Address uint16
+	// Length is length of the model block in registers excluding 2 register header
+	Length uint16
+	// Name is name of known model. Empty for models this library has no field definitions for.
+	Name string
+	// Fields are field definitions of known model. Nil for unknown models. Fields have no server address set.
+	Fields Fields
+}
+
+// Fields returns field definitions of all known models of the device. Fields have no server address set so they can
+// be added into Builder with Builder.AddAllWithDefaults. Fields of repeated models have model occurrence number
+// suffix (i.e. `ac_power_2`) to keep field names unique.
+func (d SunSpecDevice) Fields() Fields {
+	result := Fields{}
+	seen := map[uint16]int{}
+	for _, m := range d.Models {
+		seen[m.ID]++
+		for _, f := range m.Fields {
+			if n := seen[m.ID]; n > 1 {
+				f.Name = fmt.Sprintf("%v_%d", f.Name, n)
+			}
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// DiscoverSunSpecTCP finds SunSpec `SunS` marker from base addresses, walks model headers of the model chain with
+// Modbus TCP Read Holding Registers (FC3) requests and returns models with field definitions for known models (Common
+// model 1 and inverter models 101, 102 and 103). Field values are raw, apply `*_sf` scale factor fields to them.
+//
+// ErrSunSpecNotFound is returned when none of the base addresses contains `SunS` marker.
+func DiscoverSunSpecTCP(ctx context.Context, client Doer, conf SunSpecConfig) (SunSpecDevice, error) {
+	return discoverSunSpec(ctx, client, conf, false)
+}
+
+// DiscoverSunSpecRTU finds SunSpec models with Modbus RTU requests. See DiscoverSunSpecTCP.
+func DiscoverSunSpecRTU(ctx context.Context, client Doer, conf SunSpecConfig) (SunSpecDevice, error) {
+	return discoverSunSpec(ctx, client, conf, true)
+}
+
+func discoverSunSpec(ctx context.Context, client Doer, conf SunSpecConfig, isRTU bool) (SunSpecDevice, error) {
+	baseAddresses := conf.BaseAddresses
+	if len(baseAddresses) == 0 {
+		baseAddresses = []uint16{40000, 50000, 0}
+	}
+	device := SunSpecDevice{UnitID: conf.UnitID, Models: []SunSpecModel{}}
+
+	found := false
+	for _, base := range baseAddresses {
+		hi, lo, err := readSunSpecRegisterPair(ctx, client, conf.UnitID, base, isRTU)
+		if err != nil {
+			var exception packet.ErrCode
+			if errors.As(err, &exception) {
+				continue // address is not mapped in this device
+			}
+			return SunSpecDevice{}, err
+		}
+		if uint32(hi)<<16|uint32(lo) == sunSpecMarker {
+			device.BaseAddress = base
+			found = true
+			break
+		}
+	}
+	if !found {
+		return SunSpecDevice{}, ErrSunSpecNotFound
+	}
+
+	address := uint32(device.BaseAddress) + 2
+	for len(device.Models) < maxSunSpecModels {
+		if address+1 > 0xFFFF {
+			return SunSpecDevice{}, errors.New("sunspec model chain exceeds register address space")
+		}
+		id, length, err := readSunSpecRegisterPair(ctx, client, conf.UnitID, uint16(address), isRTU)
+		if err != nil {
+			if isIllegalDataAddress(err) {
+				break // device does not have end marker after last model
+			}
+			return SunSpecDevice{}, err
+		}
+		if id == sunSpecEndModelID {
+			break
+		}
+		model := SunSpecModel{ID: id, Address: uint16(address), Length: length}
+		model.Name, model.Fields = sunSpecModel(id, uint16(address))
+		for i := range model.Fields {
+			model.Fields[i].UnitID = conf.UnitID
+		}
+		device.Models = append(device.Models, model)
+		address += 2 + uint32(length)
+	}
+	return device, nil
+}
+
+func readSunSpecRegisterPair(ctx context.Context, client Doer, unitID uint8, address uint16, isRTU bool) (uint16, uint16, error) {
+	req, err := newReadRequest(isRTU, packet.FunctionReadHoldingRegisters, unitID, address, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return 0, 0, err
+	}
+	regResp, ok := resp.(RegistersResponse)
+	if !ok {
+		return 0, 0, errors.New("sunspec discovery received unsupported response type")
+	}
+	registers, err := regResp.AsRegisters(address)
+	if err != nil {
+		return 0, 0, err
+	}
+	first, err := registers.Uint16(address)
+	if err != nil {
+		return 0, 0, err
+	}
+	second, err := registers.Uint16(address + 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return first, second, nil
+}
+
+// SunSpecModelFields returns field definitions of known SunSpec model (Common model 1 and inverter models 101, 102
+// and 103) with model header (model ID register) at given address. Nil is returned for unknown models. Fields have no
+// server address and unit ID set.
+func SunSpecModelFields(modelID uint16, address uint16) Fields {
+	_, fields := sunSpecModel(modelID, address)
+	return fields
+}
+
+func sunSpecModel(modelID uint16, address uint16) (string, Fields) {
+	switch modelID {
+	case 1:
+		return "common", sunSpecCommonFields(address)
+	case 101:
+		return "inverter_single_phase", sunSpecInverterFields(address)
+	case 102:
+		return "inverter_split_phase", sunSpecInverterFields(address)
+	case 103:
+		return "inverter_three_phase", sunSpecInverterFields(address)
+	}
+	return "", nil
+}
+
+func sunSpecField(name string, address uint16, fieldType FieldType, unit string) Field {
+	return Field{Name: name, Address: address, Type: fieldType, Unit: unit, ByteOrder: packet.BigEndianHighWordFirst}
+}
+
+func sunSpecString(name string, address uint16, registers uint8) Field {
+	f := sunSpecField(name, address, FieldTypeString, "")
+	f.Length = registers * 2
+	// SunSpec strings are sent first character in high byte. Registers.StringWithByteOrder swaps bytes for BigEndian
+	f.ByteOrder = packet.LittleEndianHighWordFirst
+	return f
+}
+
+func sunSpecCommonFields(a uint16) Fields {
+	return Fields{
+		sunSpecField("common_model_id", a, FieldTypeUint16, ""),
+		sunSpecField("common_model_length", a+1, FieldTypeUint16, ""),
+		sunSpecString("manufacturer", a+2, 16),
+		sunSpecString("model", a+18, 16),
+		sunSpecString("options", a+34, 8),
+		sunSpecString("version", a+42, 8),
+		sunSpecString("serial_number", a+50, 16),
+		sunSpecField("device_address", a+66, FieldTypeUint16, ""),
+	}
+}
+
+// sunSpecInverterFields returns fields of inverter models 101, 102 and 103 that share the same layout
+func sunSpecInverterFields(a uint16) Fields {
+	operatingState := sunSpecField("operating_state", a+38, FieldTypeUint16, "")
+	operatingState.Enum = map[int64]string{
+		1: "OFF",
+		2: "SLEEPING",
+		3: "STARTING",
+		4: "MPPT",
+		5: "THROTTLED",
+		6: "SHUTTING_DOWN",
+		7: "FAULT",
+		8: "STANDBY",
+	}
+
+	return Fields{
+		sunSpecField("inverter_model_id", a, FieldTypeUint16, ""),
+		sunSpecField("inverter_model_length", a+1, FieldTypeUint16, ""),
+		sunSpecField("ac_current", a+2, FieldTypeUint16, "A"),
+		sunSpecField("ac_current_phase_a", a+3, FieldTypeUint16, "A"),
+		sunSpecField("ac_current_phase_b", a+4, FieldTypeUint16, "A"),
+		sunSpecField("ac_current_phase_c", a+5, FieldTypeUint16, "A"),
+		sunSpecField("ac_current_sf", a+6, FieldTypeInt16, ""),
+		sunSpecField("ac_voltage_ab", a+7, FieldTypeUint16, "V"),
+		sunSpecField("ac_voltage_bc", a+8, FieldTypeUint16, "V"),
+		sunSpecField("ac_voltage_ca", a+9, FieldTypeUint16, "V"),
+		sunSpecField("ac_voltage_an", a+10, FieldTypeUint16, "V"),
+		sunSpecField("ac_voltage_bn", a+11, FieldTypeUint16, "V"),
+		sunSpecField("ac_voltage_cn", a+12, FieldTypeUint16, "V"),
+		sunSpecField("ac_voltage_sf", a+13, FieldTypeInt16, ""),
+		sunSpecField("ac_power", a+14, FieldTypeInt16, "W"),
+		sunSpecField("ac_power_sf", a+15, FieldTypeInt16, ""),
+		sunSpecField("ac_frequency", a+16, FieldTypeUint16, "Hz"),
+		sunSpecField("ac_frequency_sf", a+17, FieldTypeInt16, ""),
+		sunSpecField("ac_apparent_power", a+18, FieldTypeInt16, "VA"),
+		sunSpecField("ac_apparent_power_sf", a+19, FieldTypeInt16, ""),
+		sunSpecField("ac_reactive_power", a+20, FieldTypeInt16, "var"),
+		sunSpecField("ac_reactive_power_sf", a+21, FieldTypeInt16, ""),
+		sunSpecField("ac_power_factor", a+22, FieldTypeInt16, "%"),
+		sunSpecField("ac_power_factor_sf", a+23, FieldTypeInt16, ""),
+		sunSpecField("ac_energy", a+24, FieldTypeUint32, "Wh"),
+		sunSpecField("ac_energy_sf", a+26, FieldTypeInt16, ""),
+		sunSpecField("dc_current", a+27, FieldTypeUint16, "A"),
+		sunSpecField("dc_current_sf", a+28, FieldTypeInt16, ""),
+		sunSpecField("dc_voltage", a+29, FieldTypeUint16, "V"),
+		sunSpecField("dc_voltage_sf", a+30, FieldTypeInt16, ""),
+		sunSpecField("dc_power", a+31, FieldTypeInt16, "W"),
+		sunSpecField("dc_power_sf", a+32, FieldTypeInt16, ""),
+		sunSpecField("cabinet_temperature", a+33, FieldTypeInt16, "°C"),
+		sunSpecField("heat_sink_temperature", a+34, FieldTypeInt16, "°C"),
+		sunSpecField("transformer_temperature", a+35, FieldTypeInt16, "°C"),
+		sunSpecField("other_temperature", a+36, FieldTypeInt16, "°C"),
+		sunSpecField("temperature_sf", a+37, FieldTypeInt16, ""),
+		operatingState,
+		sunSpecField("vendor_operating_state", a+39, FieldTypeUint16, ""),
+		sunSpecField("event_1", a+40, FieldTypeUint32, ""),
+	}
+}
@@ -0,0 +1,183 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// sunSpecTestClient responds to Read Holding Registers requests from given register map. Reading unmapped registers
+// results Illegal Data Address exception.
+func sunSpecTestClient(registers map[uint16]uint16) Doer {
+	return doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		r := req.(*packet.ReadHoldingRegistersRequestTCP)
+		data := make([]byte, 0, 2*r.Quantity)
+		for a := r.StartAddress; a < r.StartAddress+r.Quantity; a++ {
+			v, ok := registers[a]
+			if !ok {
+				return nil, &packet.ErrorResponseTCP{UnitID: r.UnitID, Function: r.FunctionCode(), Code: packet.ErrIllegalDataAddress}
+			}
+			data = binary.BigEndian.AppendUint16(data, v)
+		}
+		return &packet.ReadHoldingRegistersResponseTCP{
+			ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+				UnitID:          r.UnitID,
+				RegisterByteLen: uint8(len(data)),
+				Data:            data,
+			},
+		}, nil
+	})
+}
+
+func TestDiscoverSunSpecTCP(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		when        map[uint16]uint16
+		expect      []SunSpecModel
+		expectBase  uint16
+		expectError string
+	}{
+		{
+			name: "ok, marker at 50000 with end marker",
+			when: map[uint16]uint16{
+				50000: 0x5375, 50001: 0x6e53,
+				50002: 1, 50003: 66,
+				50070: 103, 50071: 50,
+				50122: 64001, 50123: 4,
+				50128: 0xFFFF, 50129: 0,
+			},
+			expectBase: 50000,
+			expect: []SunSpecModel{
+				{ID: 1, Address: 50002, Length: 66, Name: "common"},
+				{ID: 103, Address: 50070, Length: 50, Name: "inverter_three_phase"},
+				{ID: 64001, Address: 50122, Length: 4},
+			},
+		},
+		{
+			name: "ok, model chain without end marker",
+			when: map[uint16]uint16{
+				40000: 0x5375, 40001: 0x6e53,
+				40002: 1, 40003: 66,
+			},
+			expectBase: 40000,
+			expect: []SunSpecModel{
+				{ID: 1, Address: 40002, Length: 66, Name: "common"},
+			},
+		},
+		{
+			name: "nok, marker not found",
+			when: map[uint16]uint16{
+				40000: 0x1, 40001: 0x2,
+			},
+			expectError: "sunspec marker not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device, err := DiscoverSunSpecTCP(context.Background(), sunSpecTestClient(tc.when), SunSpecConfig{UnitID: 1})
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint8(1), device.UnitID)
+			assert.Equal(t, tc.expectBase, device.BaseAddress)
+
+			models := make([]SunSpecModel, len(device.Models))
+			for i, m := range device.Models {
+				for _, f := range m.Fields {
+					assert.Equal(t, uint8(1), f.UnitID)
+				}
+				m.Fields = nil
+				models[i] = m
+			}
+			assert.Equal(t, tc.expect, models)
+		})
+	}
+}
+
+func TestDiscoverSunSpecTCP_notSunSpecDevice(t *testing.T) {
+	_, err := DiscoverSunSpecTCP(context.Background(), sunSpecTestClient(map[uint16]uint16{}), SunSpecConfig{})
+
+	assert.True(t, errors.Is(err, ErrSunSpecNotFound))
+}
+
+func TestDiscoverSunSpecTCP_clientError(t *testing.T) {
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		return nil, &ClientError{Err: errors.New("total read timeout exceeded")}
+	})
+
+	_, err := DiscoverSunSpecTCP(context.Background(), client, SunSpecConfig{UnitID: 1})
+
+	assert.EqualError(t, err, "total read timeout exceeded")
+}
+
+func TestSunSpecDevice_Fields(t *testing.T) {
+	device, err := DiscoverSunSpecTCP(context.Background(), sunSpecTestClient(map[uint16]uint16{
+		40000: 0x5375, 40001: 0x6e53,
+		40002: 1, 40003: 66,
+		40070: 103, 40071: 50,
+		40122: 103, 40123: 50,
+		40174: 0xFFFF, 40175: 0,
+	}), SunSpecConfig{UnitID: 1})
+	assert.NoError(t, err)
+
+	fields := device.Fields()
+	assert.Len(t, fields, len(sunSpecCommonFields(0))+2*len(sunSpecInverterFields(0)))
+
+	b := NewRequestBuilder("localhost:502", 1)
+	b.AddAllWithDefaults(fields)
+	assert.NoError(t, b.copyFields().Validate())
+
+	reqs, err := b.ReadHoldingRegistersTCP()
+	assert.NoError(t, err)
+	extracted := Fields{}
+	for _, r := range reqs {
+		extracted = append(extracted, r.Fields...)
+	}
+	assert.Contains(t, fieldNames(extracted), "ac_power_2")
+	assert.Contains(t, fieldNames(extracted), "manufacturer")
+}
+
+func TestSunSpecModelFields(t *testing.T) {
+	fields := SunSpecModelFields(103, 40070)
+
+	assert.Equal(t, Field{
+		Name:      "ac_power",
+		Address:   40084,
+		Type:      FieldTypeInt16,
+		Unit:      "W",
+		ByteOrder: packet.BigEndianHighWordFirst,
+	}, fields[14])
+	assert.Nil(t, SunSpecModelFields(64001, 40070))
+}
+
+func TestSunSpecModelFields_extractStrings(t *testing.T) {
+	data := make([]byte, 68*2) // common model with header
+	copy(data[2*2:], "Fronius")
+	copy(data[18*2:], "Symo 10.0-3-M")
+	registers, err := packet.NewRegisters(data, 40002)
+	assert.NoError(t, err)
+
+	fields := SunSpecModelFields(1, 40002)
+
+	manufacturer, err := fields[2].ExtractFrom(registers)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fronius", manufacturer)
+
+	model, err := fields[3].ExtractFrom(registers)
+	assert.NoError(t, err)
+	assert.Equal(t, "Symo 10.0-3-M", model)
+}
+
+func fieldNames(fields Fields) []string {
+	result := make([]string, len(fields))
+	for i, f := range fields {
+		result[i] = f.Name
+	}
+	return result
+}
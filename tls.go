@@ -0,0 +1,81 @@
+package modbus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ModbusRoleOID is object identifier of X.509 certificate extension containing Modbus role of the certificate owner as
+// defined by Modbus/TCP Security specification
+var ModbusRoleOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 50316, 802, 1}
+
+// CertificateRole returns Modbus role from X.509 certificate role extension (see ModbusRoleOID). Servers use role to
+// authorize requests of the client. Returns empty string when certificate has no role extension.
+func CertificateRole(cert *x509.Certificate) (string, error) {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(ModbusRoleOID) {
+			continue
+		}
+		var role string
+		rest, err := asn1.UnmarshalWithParams(ext.Value, &role, "utf8")
+		if err != nil {
+			return "", fmt.Errorf("invalid modbus role certificate extension: %w", err)
+		}
+		if len(rest) != 0 {
+			return "", errors.New("invalid modbus role certificate extension: trailing data")
+		}
+		return role, nil
+	}
+	return "", nil
+}
+
+// PeerCertificateRole returns Modbus role from peer leaf certificate of TLS connection. See CertificateRole.
+func PeerCertificateRole(state tls.ConnectionState) (string, error) {
+	if len(state.PeerCertificates) == 0 {
+		return "", nil
+	}
+	return CertificateRole(state.PeerCertificates[0])
+}
+
+// newTLSDialContextFunc wraps dial function so `tls://` addresses are connected over TCP and secured with TLS. Other
+// addresses are passed to dial function as is.
+func newTLSDialContextFunc(
+	dial func(ctx context.Context, address string) (net.Conn, error),
+	config *tls.Config,
+	timeout time.Duration,
+) func(ctx context.Context, address string) (net.Conn, error) {
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		network, addr := addressExtractor(address)
+		if network != "tls" {
+			return dial(ctx, address)
+		}
+		conn, err := dial(ctx, "tcp://"+addr)
+		if err != nil {
+			return nil, err
+		}
+		conf := &tls.Config{}
+		if config != nil {
+			conf = config.Clone()
+		}
+		if conf.ServerName == "" && !conf.InsecureSkipVerify {
+			if host, _, err := net.SplitHostPort(addr); err == nil {
+				conf.ServerName = host
+			}
+		}
+		hsCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		tlsConn := tls.Client(conn, conf)
+		if err := tlsConn.HandshakeContext(hsCtx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
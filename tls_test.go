@@ -0,0 +1,153 @@
+package modbus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T, role string) (tls.Certificate, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "modbus test"},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(1 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	if role != "" {
+		value, err := asn1.MarshalWithParams(role, "utf8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		template.ExtraExtensions = []pkix.Extension{{Id: ModbusRoleOID, Value: value}}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, cert
+}
+
+func TestCertificateRole(t *testing.T) {
+	_, withRole := newTestCertificate(t, "operator")
+	role, err := CertificateRole(withRole)
+	assert.NoError(t, err)
+	assert.Equal(t, "operator", role)
+
+	_, withoutRole := newTestCertificate(t, "")
+	role, err = CertificateRole(withoutRole)
+	assert.NoError(t, err)
+	assert.Equal(t, "", role)
+
+	role, err = PeerCertificateRole(tls.ConnectionState{PeerCertificates: []*x509.Certificate{withRole}})
+	assert.NoError(t, err)
+	assert.Equal(t, "operator", role)
+}
+
+func TestCertificateRole_invalidExtension(t *testing.T) {
+	_, cert := newTestCertificate(t, "")
+	cert.Extensions = append(cert.Extensions, pkix.Extension{Id: ModbusRoleOID, Value: []byte{0x1, 0x2}})
+
+	role, err := CertificateRole(cert)
+	assert.ErrorContains(t, err, "invalid modbus role certificate extension:")
+	assert.Equal(t, "", role)
+}
+
+func TestClient_Connect_TLS(t *testing.T) {
+	serverCert, serverX509 := newTestCertificate(t, "")
+	clientCert, clientX509 := newTestCertificate(t, "operator")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientX509)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	roles := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		req := make([]byte, 12)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		role, _ := PeerCertificateRole(conn.(*tls.Conn).ConnectionState())
+		roles <- role
+		_, _ = conn.Write([]byte{req[0], req[1], 0x0, 0x0, 0x0, 0x5, req[6], 0x3, 0x2, 0x1, 0x2})
+	}()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(serverX509)
+	client := NewTCPClientWithConfig(ClientConfig{
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      rootCAs,
+		},
+	})
+	err = client.Connect(context.Background(), "tls://"+listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	resp, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x1, 0x2}, resp.(*packet.ReadHoldingRegistersResponseTCP).Data)
+	assert.Equal(t, "operator", <-roles)
+}
+
+func TestClient_Connect_TLSUnknownServerCertificate(t *testing.T) {
+	serverCert, _ := newTestCertificate(t, "")
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{serverCert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		_ = conn.(*tls.Conn).Handshake()
+		_ = conn.Close()
+	}()
+
+	client := NewTCPClient()
+	err = client.Connect(context.Background(), "tls://"+listener.Addr().String())
+
+	assert.ErrorContains(t, err, "certificate")
+}
@@ -0,0 +1,100 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"io"
+)
+
+// TransactionIDStrategy is strategy how Client assigns MBAP transaction IDs to Modbus TCP requests it sends
+type TransactionIDStrategy uint8
+
+const (
+	// TransactionIDFromRequest sends requests with transaction ID they were created with. This is default strategy.
+	TransactionIDFromRequest TransactionIDStrategy = 0
+	// TransactionIDRandom sends every request with random transaction ID in range 1-65535
+	TransactionIDRandom TransactionIDStrategy = 1
+	// TransactionIDSequential sends requests with sequential transaction IDs shared by all connections of the client
+	TransactionIDSequential TransactionIDStrategy = 2
+	// TransactionIDPerConnection sends requests with sequential transaction IDs starting from 1 on every (re)connect
+	TransactionIDPerConnection TransactionIDStrategy = 3
+)
+
+// String returns name of the strategy
+func (s TransactionIDStrategy) String() string {
+	switch s {
+	case TransactionIDFromRequest:
+		return "from_request"
+	case TransactionIDRandom:
+		return "random"
+	case TransactionIDSequential:
+		return "sequential"
+	case TransactionIDPerConnection:
+		return "per_connection"
+	}
+	return "unknown"
+}
+
+// newTransactionIDGenerator returns generator for the strategy. Nil means that request transaction ID is used as is.
+func newTransactionIDGenerator(strategy TransactionIDStrategy) packet.TransactionIDGenerator {
+	switch strategy {
+	case TransactionIDRandom:
+		return packet.RandomTransactionID
+	case TransactionIDSequential, TransactionIDPerConnection:
+		return packet.NewSequentialTransactionIDGenerator(1)
+	}
+	return nil
+}
+
+// assignTransactionID sets transaction ID of TCP request frame from client transaction ID generator
+func (c *Client) assignTransactionID(data []byte) {
+	if c.isRTU || c.transactionIDGenerator == nil || len(data) < 2 {
+		return
+	}
+	binary.BigEndian.PutUint16(data[0:2], c.transactionIDGenerator())
+}
+
+// matchesTransactionID checks if response frame has transaction ID of the request frame. Responses with transaction ID
+// 0 are accepted as well when client tolerates devices that always respond with 0.
+func (c *Client) matchesTransactionID(request []byte, response []byte) bool {
+	if response[0] == request[0] && response[1] == request[1] {
+		return true
+	}
+	return c.allowZeroTransactionID && response[0] == 0 && response[1] == 0
+}
+
+// restoreTransactionID sets request transaction ID to response frame that was accepted with tolerated transaction ID 0.
+// Other responses are left as is.
+func (c *Client) restoreTransactionID(request []byte, response []byte) {
+	if c.allowZeroTransactionID && response[0] == 0 && response[1] == 0 {
+		copy(response[0:2], request[0:2])
+	}
+}
+
+// dropStaleFrames removes complete frames with transaction ID not matching the request (i.e. late responses to earlier
+// requests) from the start of received bytes and returns amount of bytes left. waitMore is true when received bytes
+// do not yet contain frame header or complete stale frame.
+func (c *Client) dropStaleFrames(request []byte, received []byte, total int, readErr error) (int, bool, error) {
+	for total >= 2 && !c.matchesTransactionID(request, received[:total]) {
+		if total >= 6 {
+			frameLen := 6 + int(binary.BigEndian.Uint16(received[4:6]))
+			if frameLen > tcpPacketMaxLen {
+				return 0, false, &ErrPacketTooLong
+			}
+			if total >= frameLen {
+				total = copy(received, received[frameLen:total])
+				continue
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			return 0, false, newMismatchedResponseError(
+				"transaction ID %v, expected %v",
+				binary.BigEndian.Uint16(received[0:2]),
+				binary.BigEndian.Uint16(request[0:2]),
+			)
+		}
+		return total, true, nil
+	}
+	return total, false, nil
+}
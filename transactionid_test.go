@@ -0,0 +1,255 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/modbustest"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Do_transactionIDStrategy(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		whenConf ClientConfig
+		expect   []uint16
+	}{
+		{
+			name:   "ok, from request",
+			expect: []uint16{0x1234, 0x1234, 0x1234, 0x1234},
+		},
+		{
+			name:     "ok, sequential",
+			whenConf: ClientConfig{TransactionIDStrategy: TransactionIDSequential},
+			expect:   []uint16{1, 2, 3, 4},
+		},
+		{
+			name:     "ok, per connection",
+			whenConf: ClientConfig{TransactionIDStrategy: TransactionIDPerConnection},
+			expect:   []uint16{1, 2, 1, 2},
+		},
+		{
+			name: "ok, custom generator",
+			whenConf: ClientConfig{
+				TransactionIDGenerator: packet.NewSequentialTransactionIDGenerator(100),
+			},
+			expect: []uint16{100, 101, 102, 103},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			var mu sync.Mutex
+			var received []uint16
+			handler := func(data []byte, bytesRead int) (response []byte, closeConnection bool) {
+				mu.Lock()
+				received = append(received, binary.BigEndian.Uint16(data))
+				mu.Unlock()
+				return []byte{data[0], data[1], 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1}, false
+			}
+			addr, err := modbustest.RunServerOnRandomPort(ctx, handler)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := NewTCPClientWithConfig(tc.whenConf)
+			for c := 0; c < 2; c++ {
+				assert.NoError(t, client.Connect(ctx, addr))
+				for r := 0; r < 2; r++ {
+					resp, err := client.Do(ctx, exampleFC1Request())
+					assert.NoError(t, err)
+					assert.Equal(t, []byte{0x0, 0x1}, resp.(*packet.ReadCoilsResponseTCP).Data)
+				}
+				assert.NoError(t, client.Close())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tc.expect, received)
+		})
+	}
+}
+
+func TestClient_Connect_transactionIDGeneratorWithStrategy(t *testing.T) {
+	client := NewTCPClientWithConfig(ClientConfig{
+		TransactionIDStrategy:  TransactionIDRandom,
+		TransactionIDGenerator: packet.NewSequentialTransactionIDGenerator(100),
+	})
+
+	err := client.Connect(context.Background(), "localhost:502")
+
+	assert.EqualError(t, err, "transaction ID generator and transaction ID strategy can not be used together")
+}
+
+func TestClient_Do_transactionIDRandom(t *testing.T) {
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", mock.Anything).Return(nil)
+	conn.On("Write", mock.Anything).Return(0, nil)
+	conn.On("SetReadDeadline", mock.Anything).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(11, nil).
+		Run(func(args mock.Arguments) {
+			written := conn.Calls[1].Arguments.Get(0).([]byte)
+			b := args.Get(0).([]byte)
+			copy(b, []byte{written[0], written[1], 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1})
+		}).Once()
+
+	client := NewTCPClientWithConfig(ClientConfig{TransactionIDStrategy: TransactionIDRandom})
+	client.conn = conn
+
+	resp, err := client.Do(context.Background(), exampleFC1Request())
+	assert.NoError(t, err)
+
+	written := conn.Calls[1].Arguments.Get(0).([]byte)
+	assert.NotEqual(t, uint16(0), binary.BigEndian.Uint16(written))
+	assert.Equal(t, binary.BigEndian.Uint16(written), resp.(*packet.ReadCoilsResponseTCP).TransactionID)
+}
+
+func TestClient_Do_ignoresStaleResponse(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC) // 2021-03-13T19:15:35+00:00
+
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Once().Return(nil)
+	conn.On("Write", exampleFC1Request().Bytes()).Once().Return(0, nil)
+	conn.On("SetReadDeadline", exampleNow.Add(500*time.Microsecond)).Return(nil)
+	conn.On("Read", mock.Anything).
+		Return(13, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{
+				0x12, 0x33, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0xff, 0xff, // late response to earlier request
+				0x12, 0x34, // start of response to this request
+			})
+		}).Once()
+	conn.On("Read", mock.Anything).
+		Return(9, nil).
+		Run(func(args mock.Arguments) {
+			b := args.Get(0).([]byte)
+			copy(b, []byte{0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1})
+		}).Once()
+
+	client := NewTCPClient()
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	resp, err := client.Do(context.Background(), exampleFC1Request())
+	assert.NoError(t, err)
+	assert.Equal(t, exampleFC1Response(), resp)
+
+	conn.AssertExpectations(t)
+}
+
+func TestClient_Do_zeroTransactionID(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenConf    ClientConfig
+		expectError string
+	}{
+		{
+			name:     "ok, tolerated",
+			whenConf: ClientConfig{AllowZeroTransactionID: true},
+		},
+		{
+			name:        "nok, rejected by default",
+			whenConf:    ClientConfig{ReadTimeout: 20 * time.Millisecond},
+			expectError: "total read timeout exceeded",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			handler := func(data []byte, bytesRead int) (response []byte, closeConnection bool) {
+				return []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1}, false
+			}
+			addr, err := modbustest.RunServerOnRandomPort(ctx, handler)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := NewTCPClientWithConfig(tc.whenConf)
+			assert.NoError(t, client.Connect(ctx, addr))
+			defer client.Close()
+
+			resp, err := client.Do(ctx, exampleFC1Request())
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.Nil(t, resp)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, exampleFC1Response(), resp)
+		})
+	}
+}
+
+func TestClient_dropStaleFrames_connectionClosed(t *testing.T) {
+	client := NewTCPClient()
+	request := exampleFC1Request().Bytes()
+	received := []byte{0x12, 0x33, 0x0, 0x0, 0x0, 0x5, 0x1} // incomplete late response
+
+	total, waitMore, err := client.dropStaleFrames(request, received, len(received), nil)
+	assert.NoError(t, err)
+	assert.True(t, waitMore)
+	assert.Equal(t, 7, total)
+
+	_, _, err = client.dropStaleFrames(request, received, len(received), io.EOF)
+	assert.EqualError(t, err, "response does not match request: transaction ID 4659, expected 4660")
+	assert.True(t, errors.Is(err, ErrMismatchedResponse))
+}
+
+func TestClient_restoreTransactionID(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenAllow bool
+		when      []byte
+		expect    []byte
+	}{
+		{
+			name:      "ok, tolerated zero transaction ID gets request transaction ID",
+			whenAllow: true,
+			when:      []byte{0x0, 0x0, 0x0, 0x0},
+			expect:    []byte{0x12, 0x34, 0x0, 0x0},
+		},
+		{
+			name:      "ok, matching transaction ID is left as is",
+			whenAllow: true,
+			when:      []byte{0x12, 0x34, 0x0, 0x0},
+			expect:    []byte{0x12, 0x34, 0x0, 0x0},
+		},
+		{
+			name:   "ok, zero transaction ID is left as is when not tolerated",
+			when:   []byte{0x0, 0x0, 0x0, 0x0},
+			expect: []byte{0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			name:      "ok, other transaction ID is left as is",
+			whenAllow: true,
+			when:      []byte{0x12, 0x33, 0x0, 0x0},
+			expect:    []byte{0x12, 0x33, 0x0, 0x0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewTCPClientWithConfig(ClientConfig{AllowZeroTransactionID: tc.whenAllow})
+
+			client.restoreTransactionID(exampleFC1Request().Bytes(), tc.when)
+
+			assert.Equal(t, tc.expect, tc.when)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClient_Do_UDPRetransmit(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer server.Close()
+
+	received := make(chan int, 1)
+	go func() {
+		buf := make([]byte, 300)
+		for i := 0; ; i++ {
+			n, addr, err := server.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if i == 0 {
+				continue // first datagram is "lost"
+			}
+			// late response to some other transaction comes first and must be ignored
+			_, _ = server.WriteTo([]byte{0x99, 0x99, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0xF, 0xF}, addr)
+			_, _ = server.WriteTo([]byte{0x12, 0x34, 0x0, 0x0, 0x0, 0x5, 0x1, 0x1, 0x2, 0x0, 0x1}, addr)
+			received <- n
+			return
+		}
+	}()
+
+	client := NewTCPClientWithConfig(ClientConfig{
+		ReadTimeout: 200 * time.Millisecond,
+		UDPRetries:  1,
+	})
+	err = client.Connect(context.Background(), "udp://"+server.LocalAddr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	resp, err := client.Do(context.Background(), exampleFC1Request())
+
+	assert.NoError(t, err)
+	assert.Equal(t, exampleFC1Response(), resp)
+	assert.Equal(t, len(exampleFC1Request().Bytes()), <-received)
+}
+
+func TestClient_Do_UDPNoRetries(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer server.Close()
+
+	client := NewTCPClientWithConfig(ClientConfig{ReadTimeout: 50 * time.Millisecond})
+	err = client.Connect(context.Background(), "udp://"+server.LocalAddr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	resp, err := client.Do(context.Background(), exampleFC1Request())
+
+	assert.EqualError(t, err, "total read timeout exceeded")
+	assert.Nil(t, resp)
+}
+
+func TestClient_Do_broadcastWrite(t *testing.T) {
+	exampleNow := time.Unix(1615662935, 0).In(time.UTC)
+
+	req, err := packet.NewWriteSingleRegisterRequestTCP(0, 10, []byte{0xCA, 0xFE})
+	if !assert.NoError(t, err) {
+		return
+	}
+	conn := new(netConnMock)
+	conn.On("SetWriteDeadline", exampleNow.Add(defaultWriteTimeout)).Once().Return(nil)
+	conn.On("Write", req.Bytes()).Once().Return(0, nil)
+
+	client := NewTCPClientWithConfig(ClientConfig{BroadcastWrites: true})
+	client.conn = conn
+	client.timeNow = func() time.Time {
+		return exampleNow
+	}
+
+	resp, err := client.Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Nil(t, resp)
+	conn.AssertExpectations(t) // no reads are done
+}
@@ -0,0 +1,108 @@
+package modbus
+
+import (
+	"fmt"
+	"sync"
+)
+
+// UnitConversionFunc converts value from one engineering unit to another
+type UnitConversionFunc func(value float64) float64
+
+type unitPair struct {
+	from string
+	to   string
+}
+
+var (
+	unitConversionsMu sync.RWMutex
+	unitConversions   = map[unitPair]UnitConversionFunc{}
+)
+
+func init() {
+	multiplier := func(from string, to string, m float64) {
+		unitConversions[unitPair{from: from, to: to}] = func(v float64) float64 { return v * m }
+		unitConversions[unitPair{from: to, to: from}] = func(v float64) float64 { return v / m }
+	}
+	// power
+	multiplier("W", "kW", 0.001)
+	multiplier("kW", "MW", 0.001)
+	multiplier("W", "MW", 0.000001)
+	multiplier("VA", "kVA", 0.001)
+	multiplier("var", "kvar", 0.001)
+	// energy
+	multiplier("Wh", "kWh", 0.001)
+	multiplier("kWh", "MWh", 0.001)
+	multiplier("Wh", "MWh", 0.000001)
+	// voltage and current
+	multiplier("mV", "V", 0.001)
+	multiplier("V", "kV", 0.001)
+	multiplier("mA", "A", 0.001)
+	// pressure
+	multiplier("Pa", "kPa", 0.001)
+	multiplier("kPa", "bar", 0.01)
+	multiplier("bar", "psi", 14.503773773)
+	// time
+	multiplier("ms", "s", 0.001)
+	multiplier("s", "min", 1.0/60)
+	multiplier("min", "h", 1.0/60)
+
+	// temperature
+	unitConversions[unitPair{from: "°C", to: "°F"}] = func(v float64) float64 { return v*9/5 + 32 }
+	unitConversions[unitPair{from: "°F", to: "°C"}] = func(v float64) float64 { return (v - 32) * 5 / 9 }
+	unitConversions[unitPair{from: "°C", to: "K"}] = func(v float64) float64 { return v + 273.15 }
+	unitConversions[unitPair{from: "K", to: "°C"}] = func(v float64) float64 { return v - 273.15 }
+}
+
+// RegisterUnitConversion registers (or replaces) conversion function from one unit to another. Use this to extend
+// built-in conversion table with site or vendor specific units.
+func RegisterUnitConversion(from string, to string, fn UnitConversionFunc) {
+	unitConversionsMu.Lock()
+	defer unitConversionsMu.Unlock()
+
+	unitConversions[unitPair{from: from, to: to}] = fn
+}
+
+// ConvertUnit converts value from one engineering unit to another using registered conversions
+func ConvertUnit(value float64, from string, to string) (float64, error) {
+	if from == to {
+		return value, nil
+	}
+	unitConversionsMu.RLock()
+	fn, ok := unitConversions[unitPair{from: from, to: to}]
+	unitConversionsMu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("unknown unit conversion from '%v' to '%v'", from, to)
+	}
+	return fn(value), nil
+}
+
+// toFloat64 converts numeric value to float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case uint8:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
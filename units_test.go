@@ -0,0 +1,74 @@
+package modbus
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConvertUnit(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenValue float64
+		whenFrom  string
+		whenTo    string
+		expect    float64
+		expectErr string
+	}{
+		{
+			name:      "ok, W to kW",
+			whenValue: 1500,
+			whenFrom:  "W",
+			whenTo:    "kW",
+			expect:    1.5,
+		},
+		{
+			name:      "ok, kWh to Wh",
+			whenValue: 1.5,
+			whenFrom:  "kWh",
+			whenTo:    "Wh",
+			expect:    1500,
+		},
+		{
+			name:      "ok, °C to °F",
+			whenValue: 100,
+			whenFrom:  "°C",
+			whenTo:    "°F",
+			expect:    212,
+		},
+		{
+			name:      "ok, same unit",
+			whenValue: 10,
+			whenFrom:  "V",
+			whenTo:    "V",
+			expect:    10,
+		},
+		{
+			name:      "nok, unknown conversion",
+			whenValue: 10,
+			whenFrom:  "V",
+			whenTo:    "W",
+			expectErr: "unknown unit conversion from 'V' to 'W'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ConvertUnit(tc.whenValue, tc.whenFrom, tc.whenTo)
+
+			assert.InDelta(t, tc.expect, result, 0.000001)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegisterUnitConversion(t *testing.T) {
+	RegisterUnitConversion("m3", "l", func(v float64) float64 { return v * 1000 })
+
+	result, err := ConvertUnit(1.2, "m3", "l")
+	assert.NoError(t, err)
+	assert.Equal(t, 1200.0, result)
+}
@@ -0,0 +1,87 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ValueProcessorFunc post-processes value extracted for the field (i.e. applies sensor calibration curve) and returns
+// processed value. Processor is called after unit conversion (see Field.TargetUnit).
+type ValueProcessorFunc func(field Field, value interface{}) (interface{}, error)
+
+var (
+	valueProcessorsMu sync.RWMutex
+	valueProcessors   = map[string]ValueProcessorFunc{}
+)
+
+// RegisterValueProcessor registers (or replaces) value processor with given name. Fields reference processors by name
+// (see Field.Processor) so site specific corrections can be applied to extracted values without changing the field
+// configuration format.
+func RegisterValueProcessor(name string, fn ValueProcessorFunc) {
+	valueProcessorsMu.Lock()
+	defer valueProcessorsMu.Unlock()
+
+	valueProcessors[name] = fn
+}
+
+// LookupValueProcessor returns value processor registered with given name
+func LookupValueProcessor(name string) (ValueProcessorFunc, bool) {
+	valueProcessorsMu.RLock()
+	defer valueProcessorsMu.RUnlock()
+
+	fn, ok := valueProcessors[name]
+	return fn, ok
+}
+
+// process applies field value processor to extracted value
+func (f *Field) process(value interface{}) (interface{}, error) {
+	if f.Processor == "" {
+		return value, nil
+	}
+	fn, ok := LookupValueProcessor(f.Processor)
+	if !ok {
+		return nil, fmt.Errorf("unknown value processor: %v", f.Processor)
+	}
+	return fn(*f, value)
+}
+
+// CalibrationPoint is single point of calibration curve mapping raw value to calibrated value
+type CalibrationPoint struct {
+	Raw   float64
+	Value float64
+}
+
+// CalibrationCurve creates value processor that maps numeric values to calibrated values by linear interpolation
+// between given points. Values outside the curve are extrapolated using the first or last segment. Processed values are
+// float64.
+func CalibrationCurve(points ...CalibrationPoint) (ValueProcessorFunc, error) {
+	if len(points) < 2 {
+		return nil, errors.New("calibration curve needs at least 2 points")
+	}
+	curve := make([]CalibrationPoint, len(points))
+	copy(curve, points)
+	sort.Slice(curve, func(i, j int) bool { return curve[i].Raw < curve[j].Raw })
+	for i := 1; i < len(curve); i++ {
+		if curve[i].Raw == curve[i-1].Raw {
+			return nil, fmt.Errorf("calibration curve has duplicate raw value: %v", curve[i].Raw)
+		}
+	}
+
+	return func(field Field, value interface{}) (interface{}, error) {
+		v, ok := toFloat64(value)
+		if !ok {
+			return nil, errors.New("calibration curve is not supported for non-numeric field type")
+		}
+		i := sort.Search(len(curve), func(i int) bool { return curve[i].Raw >= v })
+		switch {
+		case i == 0:
+			i = 1
+		case i == len(curve):
+			i = len(curve) - 1
+		}
+		a, b := curve[i-1], curve[i]
+		return a.Value + (v-a.Raw)*(b.Value-a.Value)/(b.Raw-a.Raw), nil
+	}, nil
+}
@@ -0,0 +1,101 @@
+package modbus
+
+import (
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCalibrationCurve(t *testing.T) {
+	curve, err := CalibrationCurve(
+		CalibrationPoint{Raw: 100, Value: 20},
+		CalibrationPoint{Raw: 0, Value: 0},
+		CalibrationPoint{Raw: 200, Value: 60},
+	)
+	assert.NoError(t, err)
+
+	var testCases = []struct {
+		name      string
+		whenValue interface{}
+		expect    interface{}
+		expectErr string
+	}{
+		{name: "ok, on point", whenValue: uint16(100), expect: 20.0},
+		{name: "ok, first segment", whenValue: int16(50), expect: 10.0},
+		{name: "ok, second segment", whenValue: float32(150), expect: 40.0},
+		{name: "ok, extrapolated below", whenValue: int32(-10), expect: -2.0},
+		{name: "ok, extrapolated above", whenValue: uint32(300), expect: 100.0},
+		{name: "nok, non-numeric", whenValue: "x", expectErr: "calibration curve is not supported for non-numeric field type"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := curve(Field{}, tc.whenValue)
+
+			assert.Equal(t, tc.expect, result)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, err = CalibrationCurve(CalibrationPoint{Raw: 1, Value: 1})
+	assert.EqualError(t, err, "calibration curve needs at least 2 points")
+
+	_, err = CalibrationCurve(CalibrationPoint{Raw: 1, Value: 1}, CalibrationPoint{Raw: 1, Value: 2})
+	assert.EqualError(t, err, "calibration curve has duplicate raw value: 1")
+}
+
+func TestBuilderRequest_ExtractFields_withValueProcessor(t *testing.T) {
+	RegisterValueProcessor("test_double", func(field Field, value interface{}) (interface{}, error) {
+		v, _ := toFloat64(value)
+		return v * 2, nil
+	})
+	RegisterValueProcessor("test_invert", func(field Field, value interface{}) (interface{}, error) {
+		return !value.(bool), nil
+	})
+	RegisterValueProcessor("test_fail", func(field Field, value interface{}) (interface{}, error) {
+		return nil, errors.New("processor failed")
+	})
+
+	b := NewRequestBuilder("localhost:502", 1)
+	b.Add(b.Uint16(20).Name("power").Unit("W").TargetUnit("kW").Processor("test_double"))
+	b.Add(b.Uint16(21).Name("fails").Processor("test_fail"))
+	reqs, err := b.ReadHoldingRegistersTCP()
+	assert.NoError(t, err)
+
+	resp := &packet.ReadHoldingRegistersResponseTCP{
+		ReadHoldingRegistersResponse: packet.ReadHoldingRegistersResponse{
+			UnitID:          1,
+			RegisterByteLen: 4,
+			Data:            []byte{0x04, 0xd2, 0x0, 0x1},
+		},
+	}
+	values, err := reqs[0].ExtractFields(resp, true)
+	assert.EqualError(t, err, ErrorFieldExtractHadError.Error())
+	assert.Len(t, values, 2)
+	assert.Equal(t, 2.468, values[0].Value)
+	assert.NoError(t, values[0].Error)
+	assert.EqualError(t, values[1].Error, "field 'fails' addr 21: processor failed (request 20-21)")
+
+	cb := NewRequestBuilder("localhost:502", 1)
+	cb.Add(cb.Coil(10).Name("inverted").Processor("test_invert"))
+	coilReqs, err := cb.ReadCoilsTCP()
+	assert.NoError(t, err)
+
+	coilResp := &packet.ReadCoilsResponseTCP{
+		ReadCoilsResponse: packet.ReadCoilsResponse{UnitID: 1, CoilsByteLength: 1, Data: []byte{0x1}},
+	}
+	values, err = coilReqs[0].ExtractFields(coilResp, false)
+	assert.NoError(t, err)
+	assert.Equal(t, false, values[0].Value)
+}
+
+func TestField_Validate_unknownValueProcessor(t *testing.T) {
+	f := Field{ServerAddress: "localhost:502", Type: FieldTypeUint16, Processor: "does_not_exist"}
+
+	assert.EqualError(t, f.Validate(), "field value processor is invalid: unknown value processor: does_not_exist")
+}
@@ -0,0 +1,268 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+)
+
+// maxRegistersInWriteRequest is maximum amount of registers that can be written with single FC16 request
+const maxRegistersInWriteRequest = uint16(123)
+
+// WriteFieldsTCP writes given field values to the device with single Write Multiple Registers (FC16) Modbus TCP request
+// so related fields (i.e. setpoint and its mode) are changed atomically. See WriteFieldsRTU for details.
+func WriteFieldsTCP(ctx context.Context, client Doer, values []FieldValue) error {
+	return writeFields(ctx, client, values, false)
+}
+
+// WriteFieldsRTU writes given field values to the device with single Write Multiple Registers (FC16) Modbus RTU request
+// so related fields (i.e. setpoint and its mode) are changed atomically.
+//
+// All fields must have same unit ID and server address and fit into single request (max 123 registers). When fields do
+// not cover every register of the block (there are gaps between fields or fields occupy only part of the register, i.e.
+// bits and bytes) current contents of the block is read first with Read Holding Registers (FC3) request and only
+// field bits/bytes are changed in it. Note: device could change registers between that read and write.
+func WriteFieldsRTU(ctx context.Context, client Doer, values []FieldValue) error {
+	return writeFields(ctx, client, values, true)
+}
+
+func writeFields(ctx context.Context, client Doer, values []FieldValue, isRTU bool) error {
+	startAddress, quantity, needsRead, err := fieldsWriteBlock(values)
+	if err != nil {
+		return err
+	}
+	unitID := values[0].Field.UnitID
+
+	data := make([]byte, quantity*2)
+	if needsRead {
+		read := readRequest{
+			isRTU:        isRTU,
+			unitID:       unitID,
+			functionCode: packet.FunctionReadHoldingRegisters,
+			startAddress: startAddress,
+			quantity:     quantity,
+		}
+		req, err := read.newRequest()
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(ctx, req)
+		if err != nil {
+			return fmt.Errorf("write fields current values read failed: %w", err)
+		}
+		current, err := read.slice(readResponsePayload(resp, isRTU), startAddress, quantity)
+		if err != nil {
+			return fmt.Errorf("write fields current values read failed: %w", err)
+		}
+		copy(data, current)
+	}
+
+	for i, v := range values {
+		from := (v.Field.Address - startAddress) * 2
+		to := from + v.Field.registerSize()*2
+		if err := v.Field.marshalInto(data[from:to], v.Value); err != nil {
+			return fmt.Errorf("write fields marshalling failed. index: %v name: '%v' err: %w", i, v.Field.Name, err)
+		}
+	}
+
+	var req packet.Request
+	if isRTU {
+		req, err = packet.NewWriteMultipleRegistersRequestRTU(unitID, startAddress, data)
+	} else {
+		req, err = packet.NewWriteMultipleRegistersRequestTCP(unitID, startAddress, data)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := client.Do(ctx, req); err != nil {
+		return fmt.Errorf("write fields failed: %w", err)
+	}
+	return nil
+}
+
+// ErrorFieldWriteHadError is returned when WriteFieldValues could not write some of the field values
+var ErrorFieldWriteHadError = errors.New("field write had an error. check FieldValue.Error for details")
+
+// WriteFieldValuesTCP writes given field values to the device with minimal amount of Modbus TCP write requests. See
+// WriteFieldValuesRTU for details.
+func WriteFieldValuesTCP(ctx context.Context, client Doer, values []FieldValue) ([]FieldValue, error) {
+	return writeFieldValues(ctx, client, values, false)
+}
+
+// WriteFieldValuesRTU writes given field values to the device with minimal amount of Modbus RTU write requests.
+//
+// Values are grouped by unit ID and contiguous values are written with the same request. Coil fields (values must be
+// bool) are written with Write Single Coil (FC5) or Write Multiple Coils (FC15) requests and register fields with Write
+// Single Register (FC6) or Write Multiple Registers (FC16) requests. Values are marshalled according to field byte
+// order. Fields that occupy only part of the register (bits and bytes) are written separately the same way as
+// WriteFieldsRTU does it (register is read first and only field bits/bytes are changed).
+//
+// All values are written to given client, field server address is not used. Nothing is written when any of the values
+// is invalid or can not be marshalled. Failed request does not stop writing other requests, returned values have Error
+// set for fields which request failed and ErrorFieldWriteHadError is returned.
+func WriteFieldValuesRTU(ctx context.Context, client Doer, values []FieldValue) ([]FieldValue, error) {
+	return writeFieldValues(ctx, client, values, true)
+}
+
+func writeFieldValues(ctx context.Context, client Doer, values []FieldValue, isRTU bool) ([]FieldValue, error) {
+	if len(values) == 0 {
+		return nil, errors.New("write values can not be empty")
+	}
+	protocol := builderProtocolTCP
+	if isRTU {
+		protocol = builderProtocolRTU
+	}
+
+	var coils, registers []FieldValue
+	var coilIndexes, registerIndexes, partialIndexes []int
+	for i, v := range values {
+		switch v.Field.Type {
+		case FieldTypeCoil:
+			coils = append(coils, v)
+			coilIndexes = append(coilIndexes, i)
+		case FieldTypeBit, FieldTypeByte, FieldTypeUint8, FieldTypeInt8:
+			partialIndexes = append(partialIndexes, i)
+		default:
+			registers = append(registers, v)
+			registerIndexes = append(registerIndexes, i)
+		}
+	}
+
+	type writeBatch struct {
+		request packet.Request
+		indexes []int
+	}
+	var batches []writeBatch
+	for _, group := range []struct {
+		values   []FieldValue
+		indexes  []int
+		forCoils bool
+	}{
+		{values: coils, indexes: coilIndexes, forCoils: true},
+		{values: registers, indexes: registerIndexes, forCoils: false},
+	} {
+		planned, err := batchWriteValues(group.values, group.forCoils)
+		if err != nil {
+			return nil, err
+		}
+		for _, batch := range planned {
+			batchValues := make([]FieldValue, len(batch))
+			indexes := make([]int, len(batch))
+			for i, idx := range batch {
+				batchValues[i] = group.values[idx]
+				indexes[i] = group.indexes[idx]
+			}
+			req, err := newWriteValuesRequest(batchValues, group.forCoils, protocol)
+			if err != nil {
+				return nil, err
+			}
+			batches = append(batches, writeBatch{request: req, indexes: indexes})
+		}
+	}
+	for _, idx := range partialIndexes {
+		v := values[idx]
+		if err := v.Field.marshalInto(make([]byte, 2), v.Value); err != nil {
+			return nil, fmt.Errorf("write field marshalling failed. name: '%v' err: %w", v.Field.Name, err)
+		}
+	}
+
+	result := make([]FieldValue, len(values))
+	copy(result, values)
+	hadErrors := false
+	for _, b := range batches {
+		if _, err := client.Do(ctx, b.request); err != nil {
+			hadErrors = true
+			for _, idx := range b.indexes {
+				result[idx].Error = fmt.Errorf("write field failed: %w", err)
+			}
+		}
+	}
+	for _, idx := range partialIndexes {
+		if err := writeFields(ctx, client, values[idx:idx+1], isRTU); err != nil {
+			hadErrors = true
+			result[idx].Error = err
+		}
+	}
+	if hadErrors {
+		return result, ErrorFieldWriteHadError
+	}
+	return result, nil
+}
+
+// newWriteValuesRequest creates write request for given contiguous field values. Single coil or register is written
+// with single write request (FC5, FC6) and multiple ones with multiple write request (FC15, FC16).
+func newWriteValuesRequest(values []FieldValue, forCoils bool, protocol builderProtocol) (packet.Request, error) {
+	f := values[0].Field
+	if len(values) > 1 || (!forCoils && f.registerSize() > 1) {
+		req, err := newWriteBatchRequest(values, forCoils, protocol)
+		return req.Request, err
+	}
+	if forCoils {
+		isSet, ok := values[0].Value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("write coil value must be bool. name: '%v'", f.Name)
+		}
+		if protocol == builderProtocolRTU {
+			return packet.NewWriteSingleCoilRequestRTU(f.UnitID, f.Address, isSet)
+		}
+		return packet.NewWriteSingleCoilRequestTCP(f.UnitID, f.Address, isSet)
+	}
+	data := make([]byte, 2)
+	if err := f.marshalInto(data, values[0].Value); err != nil {
+		return nil, fmt.Errorf("write field marshalling failed. name: '%v' err: %w", f.Name, err)
+	}
+	if protocol == builderProtocolRTU {
+		return packet.NewWriteSingleRegisterRequestRTU(f.UnitID, f.Address, data)
+	}
+	return packet.NewWriteSingleRegisterRequestTCP(f.UnitID, f.Address, data)
+}
+
+// fieldsWriteBlock returns register block covering all given fields and whether that block needs to be read before
+// writing because fields do not cover every bit of it.
+func fieldsWriteBlock(values []FieldValue) (uint16, uint16, bool, error) {
+	if len(values) == 0 {
+		return 0, 0, false, errors.New("write fields needs at least one field")
+	}
+	first := values[0].Field
+	start := uint32(first.Address)
+	end := start
+	for i, v := range values {
+		f := v.Field
+		if f.Type == 0 || f.Type == FieldTypeCoil || uint8(f.Type) > maxFieldTypeValue {
+			return 0, 0, false, fmt.Errorf("write fields field at index %v has invalid type", i)
+		}
+		if f.UnitID != first.UnitID || f.ServerAddress != first.ServerAddress {
+			return 0, 0, false, errors.New("write fields fields must have same server address and unit ID")
+		}
+		if uint32(f.Address) < start {
+			start = uint32(f.Address)
+		}
+		if fEnd := uint32(f.Address) + uint32(f.registerSize()); fEnd > end {
+			end = fEnd
+		}
+	}
+	if end > 65536 || end-start > uint32(maxRegistersInWriteRequest) {
+		return 0, 0, false, fmt.Errorf("write fields fields do not fit into single request (max %v registers)", maxRegistersInWriteRequest)
+	}
+
+	covered := make([]bool, end-start)
+	for _, v := range values {
+		switch v.Field.Type {
+		case FieldTypeBit, FieldTypeByte, FieldTypeUint8, FieldTypeInt8:
+			continue // only part of the register is written
+		}
+		offset := uint32(v.Field.Address) - start
+		for i := uint32(0); i < uint32(v.Field.registerSize()); i++ {
+			covered[offset+i] = true
+		}
+	}
+	needsRead := false
+	for _, c := range covered {
+		if !c {
+			needsRead = true
+			break
+		}
+	}
+	return uint16(start), uint16(end - start), needsRead, nil
+}
@@ -0,0 +1,194 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteFieldsTCP_coveredBlockIsWrittenWithoutRead(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+
+	err := WriteFieldsTCP(context.Background(), device, []FieldValue{
+		{Field: Field{Name: "mode", UnitID: 1, Address: 12, Type: FieldTypeUint16}, Value: 2},
+		{Field: Field{Name: "setpoint", UnitID: 1, Address: 10, Type: FieldTypeFloat32}, Value: float32(2.5)},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, device.requests, 1)
+	req := device.requests[0].(*packet.WriteMultipleRegistersRequestTCP)
+	assert.Equal(t, uint8(1), req.UnitID)
+	assert.Equal(t, uint16(10), req.StartAddress)
+	assert.Equal(t, uint16(3), req.RegisterCount)
+	assert.Equal(t, map[uint16]uint16{10: 0x4020, 11: 0x0, 12: 2}, device.registers)
+}
+
+func TestWriteFieldsTCP_partialBlockIsMergedWithCurrentValues(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{
+		10: 0x00ff,
+		11: 0xaaaa, // gap between fields, must stay as is
+		12: 0x1234,
+	}}
+
+	err := WriteFieldsTCP(context.Background(), device, []FieldValue{
+		{Field: Field{UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 0}, Value: false},
+		{Field: Field{UnitID: 1, Address: 10, Type: FieldTypeUint8, FromHighByte: true}, Value: 0x7},
+		{Field: Field{UnitID: 1, Address: 12, Type: FieldTypeUint16}, Value: 0xcafe},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, device.requests, 2)
+	assert.IsType(t, &packet.ReadHoldingRegistersRequestTCP{}, device.requests[0])
+	assert.Equal(t, map[uint16]uint16{10: 0x07fe, 11: 0xaaaa, 12: 0xcafe}, device.registers)
+}
+
+func TestWriteFieldsTCP_errors(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []FieldValue
+		expectError string
+	}{
+		{
+			name:        "nok, no fields",
+			expectError: "write fields needs at least one field",
+		},
+		{
+			name: "nok, different units",
+			given: []FieldValue{
+				{Field: Field{UnitID: 1, Address: 1, Type: FieldTypeUint16}, Value: 1},
+				{Field: Field{UnitID: 2, Address: 2, Type: FieldTypeUint16}, Value: 1},
+			},
+			expectError: "write fields fields must have same server address and unit ID",
+		},
+		{
+			name: "nok, does not fit into single request",
+			given: []FieldValue{
+				{Field: Field{Address: 0, Type: FieldTypeUint16}, Value: 1},
+				{Field: Field{Address: 123, Type: FieldTypeUint16}, Value: 1},
+			},
+			expectError: "write fields fields do not fit into single request (max 123 registers)",
+		},
+		{
+			name: "nok, coil",
+			given: []FieldValue{
+				{Field: Field{Address: 0, Type: FieldTypeCoil}, Value: true},
+			},
+			expectError: "write fields field at index 0 has invalid type",
+		},
+		{
+			name: "nok, invalid value",
+			given: []FieldValue{
+				{Field: Field{Name: "x", Address: 0, Type: FieldTypeUint16}, Value: "a"},
+			},
+			expectError: "write fields marshalling failed. index: 0 name: 'x' err: value is not numeric, got: string",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+
+			err := WriteFieldsTCP(context.Background(), device, tc.given)
+
+			assert.EqualError(t, err, tc.expectError)
+			assert.Len(t, device.requests, 0)
+		})
+	}
+}
+
+func TestWriteFieldValuesTCP(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{10: 0x00ff}}
+
+	results, err := WriteFieldValuesTCP(context.Background(), device, []FieldValue{
+		{Field: Field{Name: "flag", UnitID: 1, Address: 10, Type: FieldTypeBit, Bit: 0}, Value: false},
+		{Field: Field{Name: "mode", UnitID: 1, Address: 22, Type: FieldTypeUint16}, Value: uint16(2)},
+		{Field: Field{Name: "setpoint", UnitID: 1, Address: 20, Type: FieldTypeFloat32, ByteOrder: packet.BigEndianLowWordFirst}, Value: float32(2.5)},
+		{Field: Field{Name: "limit", UnitID: 1, Address: 30, Type: FieldTypeInt16}, Value: int16(-1)},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	assert.Equal(t, "flag", results[0].Field.Name)
+	assert.Len(t, device.requests, 4)
+	assert.IsType(t, &packet.WriteMultipleRegistersRequestTCP{}, device.requests[0])
+	assert.IsType(t, &packet.WriteSingleRegisterRequestTCP{}, device.requests[1])
+	assert.IsType(t, &packet.ReadHoldingRegistersRequestTCP{}, device.requests[2])
+	assert.Equal(t, map[uint16]uint16{10: 0x00fe, 20: 0x0, 21: 0x4020, 22: 2, 30: 0xffff}, device.registers)
+}
+
+func TestWriteFieldValuesRTU_coils(t *testing.T) {
+	var requests []packet.Request
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		requests = append(requests, req)
+		if r, ok := req.(*packet.WriteSingleCoilRequestRTU); ok && r.Address == 20 {
+			return nil, errors.New("device failure")
+		}
+		return nil, nil
+	})
+
+	results, err := WriteFieldValuesRTU(context.Background(), client, []FieldValue{
+		{Field: Field{Name: "alarm", UnitID: 1, Address: 20, Type: FieldTypeCoil}, Value: true},
+		{Field: Field{Name: "pump", UnitID: 1, Address: 10, Type: FieldTypeCoil}, Value: true},
+		{Field: Field{Name: "valve", UnitID: 1, Address: 11, Type: FieldTypeCoil}, Value: false},
+	})
+
+	assert.ErrorIs(t, err, ErrorFieldWriteHadError)
+	assert.Len(t, requests, 2)
+	multi := requests[0].(*packet.WriteMultipleCoilsRequestRTU)
+	assert.Equal(t, uint16(10), multi.StartAddress)
+	assert.Equal(t, uint16(2), multi.CoilCount)
+	assert.Equal(t, uint16(20), requests[1].(*packet.WriteSingleCoilRequestRTU).Address)
+
+	assert.EqualError(t, results[0].Error, "write field failed: device failure")
+	assert.NoError(t, results[1].Error)
+	assert.NoError(t, results[2].Error)
+}
+
+func TestWriteFieldValuesTCP_errors(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       []FieldValue
+		expectError string
+	}{
+		{
+			name:        "nok, no values",
+			expectError: "write values can not be empty",
+		},
+		{
+			name: "nok, coil value is not bool",
+			given: []FieldValue{
+				{Field: Field{Name: "pump", Address: 1, Type: FieldTypeCoil}, Value: 1},
+			},
+			expectError: "write coil value must be bool. name: 'pump'",
+		},
+		{
+			name: "nok, overlapping fields",
+			given: []FieldValue{
+				{Field: Field{Name: "a", Address: 1, Type: FieldTypeUint32}, Value: uint32(1)},
+				{Field: Field{Name: "b", Address: 2, Type: FieldTypeUint16}, Value: uint16(1)},
+			},
+			expectError: "write fields overlap. name: 'b'",
+		},
+		{
+			name: "nok, partial field marshalling fails",
+			given: []FieldValue{
+				{Field: Field{Name: "a", Address: 1, Type: FieldTypeUint16}, Value: uint16(1)},
+				{Field: Field{Name: "flag", Address: 2, Type: FieldTypeBit}, Value: 1},
+			},
+			expectError: "write field marshalling failed. name: 'flag' err: bit field value must be bool, got: int",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+
+			results, err := WriteFieldValuesTCP(context.Background(), device, tc.given)
+
+			assert.EqualError(t, err, tc.expectError)
+			assert.Nil(t, results)
+			assert.Len(t, device.requests, 0)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"sync"
+	"time"
+)
+
+// WriteGuard sits in front of Client and protects devices from excessive writes. Write of a value that is unchanged
+// since the previous write to the same unit, function and address within deduplication window is not sent to the
+// device and previous response (with transaction ID of the current request) is returned instead. Writes to same unit
+// are spaced by minimum write interval. This is useful to protect EEPROM backed registers from wear when upstream
+// systems retry aggressively.
+//
+// Read / Write Multiple Registers (FC23) requests are rate limited but never deduplicated as they also read registers.
+// Non-write requests are passed through to the client as is.
+type WriteGuard struct {
+	client  Doer
+	timeNow func() time.Time
+
+	dedupWindow      time.Duration
+	minWriteInterval time.Duration
+
+	mu            sync.Mutex
+	lastWrites    map[writeAddress]guardedWrite
+	nextWriteSlot map[uint8]time.Time
+}
+
+// writeAddress identifies location the write request writes to
+type writeAddress struct {
+	unitID       uint8
+	functionCode uint8
+	address      uint16
+}
+
+type guardedWrite struct {
+	at time.Time
+	// value is written value (request PDU after address)
+	value string
+	// response is response frame to the write
+	response []byte
+	// parseFunc parses response frame for current request
+	parseFunc func(data []byte) (packet.Response, error)
+}
+
+// guardedRequest is write request split into parts WriteGuard uses for deduplication
+type guardedRequest struct {
+	address writeAddress
+	value   string
+	// transactionID is TCP request transaction ID, nil for RTU and ASCII requests
+	transactionID []byte
+	parseFunc     func(data []byte) (packet.Response, error)
+	// dedup is false for writes that must always reach the device
+	dedup bool
+}
+
+// WriteGuardOptionFunc is options type for NewWriteGuard function
+type WriteGuardOptionFunc func(g *WriteGuard)
+
+// WithWriteDeduplicationWindow is option to set time window in which identical writes are deduplicated
+func WithWriteDeduplicationWindow(window time.Duration) func(g *WriteGuard) {
+	return func(g *WriteGuard) {
+		g.dedupWindow = window
+	}
+}
+
+// WithWriteMinInterval is option to set minimum amount of time between two writes to same unit
+func WithWriteMinInterval(interval time.Duration) func(g *WriteGuard) {
+	return func(g *WriteGuard) {
+		g.minWriteInterval = interval
+	}
+}
+
+// NewWriteGuard creates new instance of WriteGuard
+func NewWriteGuard(client Doer, opts ...WriteGuardOptionFunc) *WriteGuard {
+	g := &WriteGuard{
+		client:        client,
+		timeNow:       time.Now,
+		lastWrites:    map[writeAddress]guardedWrite{},
+		nextWriteSlot: map[uint8]time.Time{},
+	}
+	for _, o := range opts {
+		o(g)
+	}
+	return g
+}
+
+// Do sends given Modbus request to modbus server and returns parsed Response. Write requests are deduplicated and
+// rate limited.
+func (g *WriteGuard) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	if req == nil {
+		return nil, errors.New("request can not be nil")
+	}
+	w, ok := asGuardedRequest(req)
+	if !ok {
+		return g.client.Do(ctx, req)
+	}
+	unitID := w.address.unitID
+
+	g.mu.Lock()
+	now := g.timeNow()
+	for k, last := range g.lastWrites { // evict expired writes so map does not grow unbounded
+		if now.Sub(last.at) >= g.dedupWindow {
+			delete(g.lastWrites, k)
+		}
+	}
+	if last, ok := g.lastWrites[w.address]; ok && w.dedup && last.value == w.value {
+		g.mu.Unlock()
+		if resp, err := last.responseFor(w); err == nil {
+			return resp, nil
+		}
+		g.mu.Lock()
+	}
+	wait := time.Duration(0)
+	slot := now
+	if next, ok := g.nextWriteSlot[unitID]; ok && next.After(now) {
+		wait = next.Sub(now)
+		slot = next
+	}
+	g.nextWriteSlot[unitID] = slot.Add(g.minWriteInterval)
+	g.mu.Unlock()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	resp, err := g.client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if g.dedupWindow > 0 && w.dedup {
+		g.mu.Lock()
+		g.lastWrites[w.address] = guardedWrite{
+			at:        g.timeNow(),
+			value:     w.value,
+			response:  resp.Bytes(),
+			parseFunc: w.parseFunc,
+		}
+		g.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// responseFor returns copy of previous write response with transaction ID of given request
+func (w guardedWrite) responseFor(req guardedRequest) (packet.Response, error) {
+	frame := make([]byte, len(w.response))
+	copy(frame, w.response)
+	if req.transactionID != nil && len(frame) >= 2 {
+		copy(frame[0:2], req.transactionID)
+	}
+	return w.parseFunc(frame)
+}
+
+// asGuardedRequest splits write request into written address and value (without TCP transaction ID, RTU CRC and
+// ASCII framing)
+func asGuardedRequest(req packet.Request) (guardedRequest, bool) {
+	var pdu []byte // unit ID + function code + function specific data
+	result := guardedRequest{}
+	switch req.(type) {
+	case *packet.WriteSingleCoilRequestTCP,
+		*packet.WriteSingleRegisterRequestTCP,
+		*packet.WriteMultipleCoilsRequestTCP,
+		*packet.WriteMultipleRegistersRequestTCP,
+		*packet.MaskWriteRegisterRequestTCP,
+		*packet.ReadWriteMultipleRegistersRequestTCP:
+		b := req.Bytes()
+		pdu = b[6:]
+		result.transactionID = b[0:2]
+		result.parseFunc = packet.ParseTCPResponse
+	case *packet.WriteSingleCoilRequestRTU,
+		*packet.WriteSingleRegisterRequestRTU,
+		*packet.WriteMultipleCoilsRequestRTU,
+		*packet.WriteMultipleRegistersRequestRTU,
+		*packet.MaskWriteRegisterRequestRTU,
+		*packet.ReadWriteMultipleRegistersRequestRTU:
+		b := req.Bytes()
+		pdu = b[:len(b)-2]
+		result.parseFunc = packet.ParseRTUResponse
+	case *packet.WriteSingleCoilRequestASCII,
+		*packet.WriteSingleRegisterRequestASCII,
+		*packet.WriteMultipleCoilsRequestASCII,
+		*packet.WriteMultipleRegistersRequestASCII,
+		*packet.MaskWriteRegisterRequestASCII,
+		*packet.ReadWriteMultipleRegistersRequestASCII:
+		b, err := packet.ASCIIToRTU(req.Bytes())
+		if err != nil {
+			return guardedRequest{}, false
+		}
+		pdu = b[:len(b)-2]
+		result.parseFunc = packet.ParseASCIIResponse
+	default:
+		return guardedRequest{}, false
+	}
+	if len(pdu) < 4 {
+		return guardedRequest{}, false
+	}
+	result.address = writeAddress{
+		unitID:       pdu[0],
+		functionCode: pdu[1],
+		address:      binary.BigEndian.Uint16(pdu[2:4]),
+	}
+	result.value = string(pdu[4:])
+	result.dedup = pdu[1] != packet.FunctionReadWriteMultipleRegisters
+	return result, true
+}
@@ -0,0 +1,121 @@
+package modbus
+
+import (
+	"context"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWriteGuard_Do_deduplicatesIdenticalWrites(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	guard := NewWriteGuard(device, WithWriteDeduplicationWindow(time.Minute))
+
+	req1, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	req2, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe}) // different transaction ID
+	req3, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xba, 0xbe})
+
+	resp1, err := guard.Do(context.Background(), req1)
+	assert.NoError(t, err)
+	resp2, err := guard.Do(context.Background(), req2)
+	assert.NoError(t, err)
+	assert.Equal(t, resp1.(*packet.WriteSingleRegisterResponseTCP).WriteSingleRegisterResponse,
+		resp2.(*packet.WriteSingleRegisterResponseTCP).WriteSingleRegisterResponse)
+	assert.Equal(t, req2.TransactionID, resp2.(*packet.WriteSingleRegisterResponseTCP).TransactionID)
+	assert.NotSame(t, resp1, resp2)
+
+	_, err = guard.Do(context.Background(), req3)
+	assert.NoError(t, err)
+
+	assert.Len(t, device.requests, 2)
+	assert.Equal(t, uint16(0xbabe), device.registers[10])
+}
+
+func TestWriteGuard_Do_writesChangedValueBack(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	guard := NewWriteGuard(device, WithWriteDeduplicationWindow(time.Minute))
+
+	x, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	y, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xba, 0xbe})
+	otherAddress, _ := packet.NewWriteSingleRegisterRequestTCP(1, 11, []byte{0xca, 0xfe})
+
+	for _, req := range []packet.Request{x, y, x, otherAddress} {
+		_, err := guard.Do(context.Background(), req)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, device.requests, 4)
+	assert.Equal(t, uint16(0xcafe), device.registers[10])
+}
+
+func TestWriteGuard_Do_readWriteMultipleRegistersIsNotDeduplicated(t *testing.T) {
+	var sent int
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		sent++
+		return &packet.ReadWriteMultipleRegistersResponseTCP{
+			ReadWriteMultipleRegistersResponse: packet.ReadWriteMultipleRegistersResponse{
+				UnitID:          1,
+				RegisterByteLen: 2,
+				Data:            []byte{0x0, byte(sent)},
+			},
+		}, nil
+	})
+	guard := NewWriteGuard(client, WithWriteDeduplicationWindow(time.Minute))
+
+	req, _ := packet.NewReadWriteMultipleRegistersRequestTCP(1, 10, 1, 20, []byte{0xca, 0xfe})
+	_, err := guard.Do(context.Background(), req)
+	assert.NoError(t, err)
+	resp, err := guard.Do(context.Background(), req)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, []byte{0x0, 0x2}, resp.(*packet.ReadWriteMultipleRegistersResponseTCP).Data)
+}
+
+func TestWriteGuard_Do_dedupWindowExpires(t *testing.T) {
+	now := time.Unix(1615662935, 0)
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	guard := NewWriteGuard(device, WithWriteDeduplicationWindow(time.Second))
+	guard.timeNow = func() time.Time { return now }
+
+	req, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	_, err := guard.Do(context.Background(), req)
+	assert.NoError(t, err)
+
+	now = now.Add(time.Second)
+	_, err = guard.Do(context.Background(), req)
+	assert.NoError(t, err)
+
+	assert.Len(t, device.requests, 2)
+}
+
+func TestWriteGuard_Do_minWriteInterval(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	guard := NewWriteGuard(device, WithWriteMinInterval(30*time.Millisecond))
+
+	req1, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0x0, 0x1})
+	req2, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0x0, 0x2})
+
+	start := time.Now()
+	_, err := guard.Do(context.Background(), req1)
+	assert.NoError(t, err)
+	_, err = guard.Do(context.Background(), req2)
+	assert.NoError(t, err)
+
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	assert.Len(t, device.requests, 2)
+}
+
+func TestWriteGuard_Do_readsArePassedThrough(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{10: 1}}
+	guard := NewWriteGuard(device, WithWriteDeduplicationWindow(time.Minute))
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err := guard.Do(context.Background(), req)
+	assert.NoError(t, err)
+	_, err = guard.Do(context.Background(), req)
+	assert.NoError(t, err)
+
+	assert.Len(t, device.requests, 2)
+}
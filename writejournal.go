@@ -0,0 +1,152 @@
+package modbus
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aldas/go-modbus-client/packet"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// JournalEntryRequest is kind of journal entry written before write request is sent to the device
+	JournalEntryRequest = "request"
+	// JournalEntryResponse is kind of journal entry written after device responded successfully to write request
+	JournalEntryResponse = "response"
+	// JournalEntryException is kind of journal entry written after device responded with Modbus exception
+	JournalEntryException = "exception"
+	// JournalEntryError is kind of journal entry written when write request failed for other reasons (network etc.)
+	JournalEntryError = "error"
+)
+
+// WriteJournalEntry is single entry in write journal. Entries are written as JSON lines.
+type WriteJournalEntry struct {
+	// Sequence links request entry to its outcome (response/exception/error) entry
+	Sequence     uint64    `json:"seq"`
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind"`
+	UnitID       uint8     `json:"unit_id"`
+	FunctionCode uint8     `json:"function_code"`
+	// Request is request packet bytes as hex string. Set for request entries.
+	Request string `json:"request,omitempty"`
+	// Response is response packet bytes as hex string. Set for response entries.
+	Response string `json:"response,omitempty"`
+	// ExceptionCode is Modbus exception code. Set for exception entries.
+	ExceptionCode packet.ErrCode `json:"exception_code,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// WriteJournal sits in front of Client and records write requests (FC5/FC6/FC15/FC16/FC22/FC23) and their outcomes to
+// journal (usually file on disk) providing audit trail of write operations. Request entry is written before request is
+// sent to the device (write-ahead) and request is not sent when journal entry could not be written.
+//
+// Non-write requests are passed through to the client as is.
+type WriteJournal struct {
+	client  Doer
+	timeNow func() time.Time
+
+	mu       sync.Mutex
+	journal  io.Writer
+	sequence uint64
+}
+
+// syncer is implemented by writers that can flush written data to stable storage (i.e. os.File)
+type syncer interface {
+	Sync() error
+}
+
+// NewWriteJournal creates new instance of WriteJournal writing entries to given journal. When journal implements
+// `Sync() error` (i.e. os.File) it is synced after each entry.
+func NewWriteJournal(client Doer, journal io.Writer) *WriteJournal {
+	return &WriteJournal{
+		client:  client,
+		timeNow: time.Now,
+		journal: journal,
+	}
+}
+
+// Do sends given Modbus request to modbus server and returns parsed Response. Write requests and their outcomes are
+// recorded to the journal.
+func (j *WriteJournal) Do(ctx context.Context, req packet.Request) (packet.Response, error) {
+	if req == nil {
+		return nil, errors.New("request can not be nil")
+	}
+	w, ok := asGuardedRequest(req)
+	if !ok {
+		return j.client.Do(ctx, req)
+	}
+	unitID := w.address.unitID
+
+	j.mu.Lock()
+	j.sequence++
+	entry := WriteJournalEntry{
+		Sequence:     j.sequence,
+		Kind:         JournalEntryRequest,
+		UnitID:       unitID,
+		FunctionCode: req.FunctionCode(),
+		Request:      hex.EncodeToString(req.Bytes()),
+	}
+	err := j.write(entry)
+	j.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("write journal: failed to record request: %w", err)
+	}
+
+	resp, err := j.client.Do(ctx, req)
+
+	outcome := WriteJournalEntry{Sequence: entry.Sequence, UnitID: unitID, FunctionCode: entry.FunctionCode}
+	var tcpErr *packet.ErrorResponseTCP
+	var rtuErr *packet.ErrorResponseRTU
+	var asciiErr *packet.ErrorResponseASCII
+	switch {
+	case err == nil:
+		outcome.Kind = JournalEntryResponse
+		outcome.Response = hex.EncodeToString(resp.Bytes())
+	case errors.As(err, &tcpErr):
+		outcome.Kind = JournalEntryException
+		outcome.ExceptionCode = tcpErr.Code
+		outcome.Error = err.Error()
+	case errors.As(err, &rtuErr):
+		outcome.Kind = JournalEntryException
+		outcome.ExceptionCode = rtuErr.Code
+		outcome.Error = err.Error()
+	case errors.As(err, &asciiErr):
+		outcome.Kind = JournalEntryException
+		outcome.ExceptionCode = asciiErr.Code
+		outcome.Error = err.Error()
+	default:
+		outcome.Kind = JournalEntryError
+		outcome.Error = err.Error()
+	}
+
+	j.mu.Lock()
+	jErr := j.write(outcome)
+	j.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if jErr != nil {
+		// request was already sent to the device so we return response along with the error
+		return resp, fmt.Errorf("write journal: failed to record response: %w", jErr)
+	}
+	return resp, nil
+}
+
+func (j *WriteJournal) write(entry WriteJournalEntry) error {
+	entry.Time = j.timeNow()
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := j.journal.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if s, ok := j.journal.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package modbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aldas/go-modbus-client/packet"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readJournal(t *testing.T, journal *bytes.Buffer) []WriteJournalEntry {
+	var entries []WriteJournalEntry
+	for _, line := range strings.Split(strings.TrimSpace(journal.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		e := WriteJournalEntry{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &e))
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestWriteJournal_Do_recordsWriteAndResponse(t *testing.T) {
+	now := time.Unix(1615662935, 0).In(time.UTC)
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	journal := new(bytes.Buffer)
+	j := NewWriteJournal(device, journal)
+	j.timeNow = func() time.Time { return now }
+
+	req, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	req.TransactionID = 0x1234
+
+	resp, err := j.Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, []WriteJournalEntry{
+		{
+			Sequence:     1,
+			Time:         now,
+			Kind:         JournalEntryRequest,
+			UnitID:       1,
+			FunctionCode: packet.FunctionWriteSingleRegister,
+			Request:      "1234000000060106000acafe",
+		},
+		{
+			Sequence:     1,
+			Time:         now,
+			Kind:         JournalEntryResponse,
+			UnitID:       1,
+			FunctionCode: packet.FunctionWriteSingleRegister,
+			Response:     "1234000000060106000acafe",
+		},
+	}, readJournal(t, journal))
+}
+
+func TestWriteJournal_Do_recordsException(t *testing.T) {
+	client := doerFunc(func(ctx context.Context, req packet.Request) (packet.Response, error) {
+		return nil, &ClientError{Err: &packet.ErrorResponseTCP{UnitID: 1, Function: 6, Code: packet.ErrIllegalDataAddress}}
+	})
+	journal := new(bytes.Buffer)
+	j := NewWriteJournal(client, journal)
+
+	req, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	_, err := j.Do(context.Background(), req)
+
+	assert.Error(t, err)
+	entries := readJournal(t, journal)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, JournalEntryException, entries[1].Kind)
+	assert.Equal(t, packet.ErrIllegalDataAddress, entries[1].ExceptionCode)
+}
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteJournal_Do_requestNotSentWhenJournalFails(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{}}
+	j := NewWriteJournal(device, failingWriter{})
+
+	req, _ := packet.NewWriteSingleRegisterRequestTCP(1, 10, []byte{0xca, 0xfe})
+	_, err := j.Do(context.Background(), req)
+
+	assert.EqualError(t, err, "write journal: failed to record request: disk full")
+	assert.Len(t, device.requests, 0)
+}
+
+func TestWriteJournal_Do_passThroughReads(t *testing.T) {
+	device := &fakeRegistersDevice{registers: map[uint16]uint16{10: 1}}
+	journal := new(bytes.Buffer)
+	j := NewWriteJournal(device, journal)
+
+	req, _ := packet.NewReadHoldingRegistersRequestTCP(1, 10, 1)
+	_, err := j.Do(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, journal.Len())
+}